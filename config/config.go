@@ -4,24 +4,51 @@ import (
 	"asetku-bukan-asetmu/utils/common"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 type DBConfig struct {
 	Host, Port, Name, User, Password, Driver string
 }
 
+// APIConfig is where the API listens. TrustedProxies lists the addresses or
+// CIDR ranges of the reverse proxies whose forwarded headers are honoured.
 type APIConfig struct {
 	APIHost, APIPort string
+	TrustedProxies   []string
 }
 
 type Config struct {
 	APIConfig
 	DBConfig
 	FileConfig
+	LabelConfig
+	MaintenanceConfig
 }
 
+// FileConfig selects where uploads are kept. StorageDriver is local (the
+// default, under FilePath) or s3 for any S3-compatible server.
 type FileConfig struct {
-	FilePath string
+	FilePath        string
+	StorageDriver   string
+	SigningKey      string
+	SignedURLExpiry time.Duration
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+}
+
+type LabelConfig struct {
+	ZPLTemplatePath string
+}
+
+// MaintenanceConfig sets how often due preventive schedules are turned into
+// work orders.
+type MaintenanceConfig struct {
+	ScheduleInterval time.Duration
 }
 
 func (c *Config) ReadConfig() error {
@@ -44,8 +71,46 @@ func (c *Config) ReadConfig() error {
 		APIPort: os.Getenv("API_PORT"),
 	}
 
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			c.APIConfig.TrustedProxies = append(c.APIConfig.TrustedProxies, proxy)
+		}
+	}
+
 	c.FileConfig = FileConfig{
-		FilePath: os.Getenv("FILE_PATH"),
+		FilePath:        os.Getenv("FILE_PATH"),
+		StorageDriver:   os.Getenv("STORAGE_DRIVER"),
+		SigningKey:      os.Getenv("STORAGE_SIGNING_KEY"),
+		SignedURLExpiry: 15 * time.Minute,
+		S3Endpoint:      os.Getenv("S3_ENDPOINT"),
+		S3Region:        os.Getenv("S3_REGION"),
+		S3Bucket:        os.Getenv("S3_BUCKET"),
+		S3AccessKey:     os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:     os.Getenv("S3_SECRET_KEY"),
+	}
+
+	if expiry := os.Getenv("STORAGE_URL_EXPIRY"); expiry != "" {
+		duration, err := time.ParseDuration(expiry)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid STORAGE_URL_EXPIRY %s", expiry)
+		}
+		c.FileConfig.SignedURLExpiry = duration
+	}
+
+	c.LabelConfig = LabelConfig{
+		ZPLTemplatePath: os.Getenv("LABEL_ZPL_TEMPLATE"),
+	}
+
+	c.MaintenanceConfig = MaintenanceConfig{
+		ScheduleInterval: time.Hour,
+	}
+
+	if interval := os.Getenv("MAINTENANCE_SCHEDULE_INTERVAL"); interval != "" {
+		duration, err := time.ParseDuration(interval)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid MAINTENANCE_SCHEDULE_INTERVAL %s", interval)
+		}
+		c.MaintenanceConfig.ScheduleInterval = duration
 	}
 
 	if c.DBConfig.Host == "" || c.DBConfig.Port == "" || c.DBConfig.Name == "" || c.DBConfig.User == "" || c.DBConfig.Password == "" || c.DBConfig.Driver == "" || c.APIConfig.APIHost == "" || c.APIConfig.APIPort == "" {
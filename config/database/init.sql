@@ -10,19 +10,73 @@ CREATE TABLE employee (
 
 CREATE TABLE asset_categories (
     id VARCHAR(100) PRIMARY KEY,
-    name VARCHAR(100) NOT NULL
+    name VARCHAR(100) NOT NULL,
+    depreciation_method VARCHAR(30) NOT NULL DEFAULT 'straight-line',
+    useful_life INT NOT NULL DEFAULT 0,
+    fiscal_group VARCHAR(30) NOT NULL DEFAULT '',
+    fiscal_method VARCHAR(30) NOT NULL DEFAULT 'straight-line',
+    tag_prefix VARCHAR(30) NOT NULL DEFAULT '',
+    tag_with_year BOOLEAN NOT NULL DEFAULT FALSE,
+    tag_padding INT NOT NULL DEFAULT 4,
+    parent_id VARCHAR(100) NULL,
+    CONSTRAINT fk_category_parent_id FOREIGN KEY(parent_id) REFERENCES asset_categories(id)
+);
+
+CREATE TABLE category_attributes (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    category_id VARCHAR(100) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    CONSTRAINT uq_category_attribute_name UNIQUE(category_id, name),
+    CONSTRAINT fk_attribute_category_id FOREIGN KEY(category_id) REFERENCES asset_categories(id) ON DELETE CASCADE
 );
 
 CREATE TABLE asset_location (
     id VARCHAR(100) PRIMARY KEY,
-    name VARCHAR(100) NOT NULL
+    name VARCHAR(100) NOT NULL,
+    parent_id VARCHAR(100) NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'room',
+    code VARCHAR(30) NOT NULL DEFAULT '',
+    custodian_id VARCHAR(100) NULL,
+    storage BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_location_parent_id FOREIGN KEY(parent_id) REFERENCES asset_location(id),
+    CONSTRAINT fk_location_custodian_id FOREIGN KEY(custodian_id) REFERENCES employee(id)
 );
 
+-- locations without a code keep the empty default
+CREATE UNIQUE INDEX uq_asset_location_code ON asset_location(code) WHERE code <> '';
+CREATE INDEX idx_asset_location_parent_id ON asset_location(parent_id);
+
 CREATE TABLE vendors (
     id VARCHAR(100) NOT NULL PRIMARY KEY,
     name VARCHAR(100) NOT NULL,
     address VARCHAR(100) NOT NULL,
-    phone VARCHAR(100) NOT NULL,
+    phone VARCHAR(100) NOT NULL
+);
+
+CREATE TABLE transactions (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    vendor_id VARCHAR(100) NOT NULL,
+    invoice_number VARCHAR(100),
+    transaction_date TIMESTAMP NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    total_price NUMERIC(15,2) NOT NULL DEFAULT 0,
+    CONSTRAINT fk_transaction_vendor_id FOREIGN KEY(vendor_id) REFERENCES vendors(id)
+);
+
+CREATE TABLE transaction_detail (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    transaction_id VARCHAR(100) NOT NULL,
+    category_id VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    unit_price NUMERIC(15,2) NOT NULL,
+    qty INT NOT NULL,
+    received_qty INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_transaction_id FOREIGN KEY(transaction_id) REFERENCES transactions(id),
+    CONSTRAINT fk_transaction_category_id FOREIGN KEY(category_id) REFERENCES asset_categories(id)
 );
 
 CREATE TABLE asset (
@@ -32,7 +86,11 @@ CREATE TABLE asset (
     name VARCHAR(100) NOT NULL,
     description TEXT,
     image_url VARCHAR(100) NULL,
+    image_thumbnails BOOLEAN NOT NULL DEFAULT FALSE,
     qty INT NOT NULL,
+    cost NUMERIC(15,2) NOT NULL DEFAULT 0,
+    salvage_value NUMERIC(15,2) NOT NULL DEFAULT 0,
+    useful_life INT NOT NULL DEFAULT 0,
     created_at DATE NOT NULL,
     CONSTRAINT fk_category_id FOREIGN KEY(category_id) REFERENCES asset_categories(id),
     CONSTRAINT fk_transaction_detail_id FOREIGN KEY(transaction_detail_id) REFERENCES transaction_detail(id)
@@ -43,8 +101,370 @@ CREATE TABLE asset_details (
     asset_id VARCHAR(100) NOT NULL,
     location_id VARCHAR(100) NOT NULL,
     status int, 
+    tag VARCHAR(60) NOT NULL DEFAULT '',
     updated_at DATE NULL,
     removed_at DATE null,
     CONSTRAINT fk_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
     CONSTRAINT fk_asset_loc_id FOREIGN KEY(location_id) REFERENCES asset_location(id)
-);
\ No newline at end of file
+);
+
+-- values are stored as text and read back by the attribute type
+CREATE TABLE asset_attribute_values (
+    asset_id VARCHAR(100) NOT NULL,
+    attribute_id VARCHAR(100) NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY(asset_id, attribute_id),
+    CONSTRAINT fk_attribute_value_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id) ON DELETE CASCADE,
+    CONSTRAINT fk_attribute_value_attribute_id FOREIGN KEY(attribute_id) REFERENCES category_attributes(id) ON DELETE CASCADE
+);
+
+-- units without a tag keep the empty default
+CREATE UNIQUE INDEX uq_asset_details_tag ON asset_details(tag) WHERE tag <> '';
+
+-- last counter handed out per tag prefix, year 0 for patterns without year
+CREATE TABLE asset_tag_sequences (
+    prefix VARCHAR(30) NOT NULL,
+    year INT NOT NULL,
+    last_value INT NOT NULL,
+    PRIMARY KEY(prefix, year)
+);
+
+CREATE TABLE asset_attachments (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NULL,
+    title VARCHAR(150) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    uploaded_by VARCHAR(100) NOT NULL,
+    uploaded_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_attachment_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_attachment_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id)
+);
+
+CREATE INDEX idx_asset_attachments_asset_id ON asset_attachments(asset_id, uploaded_at);
+
+CREATE TABLE asset_reservations (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_id VARCHAR(100) NOT NULL,
+    employee_id VARCHAR(100) NOT NULL,
+    qty INT NOT NULL CHECK (qty > 0),
+    consumed_qty INT NOT NULL DEFAULT 0,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT chk_reservation_window CHECK (end_at > start_at),
+    CONSTRAINT fk_reservation_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_reservation_employee_id FOREIGN KEY(employee_id) REFERENCES employee(id)
+);
+
+CREATE INDEX idx_asset_reservations_window ON asset_reservations(asset_id, start_at, end_at);
+
+-- units of a reservation made for specific units, qty reservations have none
+CREATE TABLE asset_reservation_units (
+    reservation_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    PRIMARY KEY(reservation_id, asset_detail_id),
+    CONSTRAINT fk_reservation_unit_reservation_id FOREIGN KEY(reservation_id) REFERENCES asset_reservations(id),
+    CONSTRAINT fk_reservation_unit_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id)
+);
+
+CREATE TABLE asset_assignment (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    employee_id VARCHAR(100) NOT NULL,
+    assigned_at TIMESTAMP NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    returned_at TIMESTAMP NULL,
+    note TEXT,
+    reservation_id VARCHAR(100) NULL,
+    CONSTRAINT fk_assignment_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_assignment_employee_id FOREIGN KEY(employee_id) REFERENCES employee(id),
+    CONSTRAINT fk_assignment_reservation_id FOREIGN KEY(reservation_id) REFERENCES asset_reservations(id)
+);
+
+CREATE UNIQUE INDEX uq_asset_assignment_active ON asset_assignment(asset_detail_id) WHERE returned_at IS NULL;
+
+CREATE TABLE goods_receipt (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    transaction_detail_id VARCHAR(100) NOT NULL,
+    asset_id VARCHAR(100) NOT NULL,
+    location_id VARCHAR(100) NOT NULL,
+    qty INT NOT NULL,
+    received_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_receipt_detail_id FOREIGN KEY(transaction_detail_id) REFERENCES transaction_detail(id),
+    CONSTRAINT fk_receipt_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_receipt_location_id FOREIGN KEY(location_id) REFERENCES asset_location(id)
+);
+
+CREATE TABLE asset_movements (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    asset_id VARCHAR(100) NOT NULL,
+    from_location_id VARCHAR(100) NOT NULL,
+    to_location_id VARCHAR(100) NOT NULL,
+    from_status INT NOT NULL,
+    to_status INT NOT NULL,
+    batch_id VARCHAR(100) NOT NULL,
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    moved_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_movement_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_movement_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_movement_from_location_id FOREIGN KEY(from_location_id) REFERENCES asset_location(id),
+    CONSTRAINT fk_movement_to_location_id FOREIGN KEY(to_location_id) REFERENCES asset_location(id)
+);
+
+CREATE INDEX idx_asset_movements_asset_id ON asset_movements(asset_id, moved_at);
+CREATE INDEX idx_asset_movements_detail_id ON asset_movements(asset_detail_id, moved_at);
+
+-- movement history is append-only
+CREATE RULE asset_movements_no_update AS ON UPDATE TO asset_movements DO INSTEAD NOTHING;
+CREATE RULE asset_movements_no_delete AS ON DELETE TO asset_movements DO INSTEAD NOTHING;
+
+CREATE TABLE stock_takes (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    note TEXT,
+    status VARCHAR(20) NOT NULL,
+    started_by VARCHAR(100) NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    closed_by VARCHAR(100) NOT NULL DEFAULT '',
+    closed_at TIMESTAMP NULL
+);
+
+CREATE TABLE stock_take_locations (
+    stock_take_id VARCHAR(100) NOT NULL,
+    location_id VARCHAR(100) NOT NULL,
+    PRIMARY KEY(stock_take_id, location_id),
+    CONSTRAINT fk_stock_take_location_take_id FOREIGN KEY(stock_take_id) REFERENCES stock_takes(id),
+    CONSTRAINT fk_stock_take_location_id FOREIGN KEY(location_id) REFERENCES asset_location(id)
+);
+
+-- a tag scanned again replaces the earlier scan of the same session
+CREATE TABLE stock_take_scans (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    stock_take_id VARCHAR(100) NOT NULL,
+    tag VARCHAR(60) NOT NULL,
+    asset_detail_id VARCHAR(100) NULL,
+    location_id VARCHAR(100) NOT NULL,
+    scanned_by VARCHAR(100) NOT NULL DEFAULT '',
+    scanned_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_stock_take_scan_tag UNIQUE(stock_take_id, tag),
+    CONSTRAINT fk_stock_take_scan_take_id FOREIGN KEY(stock_take_id) REFERENCES stock_takes(id),
+    CONSTRAINT fk_stock_take_scan_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_stock_take_scan_location_id FOREIGN KEY(location_id) REFERENCES asset_location(id)
+);
+
+CREATE TABLE stock_take_results (
+    stock_take_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    asset_id VARCHAR(100) NOT NULL,
+    tag VARCHAR(60) NOT NULL DEFAULT '',
+    result VARCHAR(20) NOT NULL,
+    status INT NOT NULL,
+    expected_location_id VARCHAR(100) NOT NULL,
+    scanned_location_id VARCHAR(100) NULL,
+    action VARCHAR(20) NOT NULL DEFAULT '',
+    PRIMARY KEY(stock_take_id, asset_detail_id),
+    CONSTRAINT fk_stock_take_result_take_id FOREIGN KEY(stock_take_id) REFERENCES stock_takes(id),
+    CONSTRAINT fk_stock_take_result_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_stock_take_result_expected_id FOREIGN KEY(expected_location_id) REFERENCES asset_location(id),
+    CONSTRAINT fk_stock_take_result_scanned_id FOREIGN KEY(scanned_location_id) REFERENCES asset_location(id)
+);
+
+CREATE TABLE maintenance_schedules (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_id VARCHAR(100) NULL,
+    category_id VARCHAR(100) NULL,
+    description TEXT NOT NULL,
+    interval_days INT NOT NULL,
+    vendor_id VARCHAR(100) NULL,
+    technician VARCHAR(100) NOT NULL DEFAULT '',
+    next_due_date DATE NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT chk_schedule_interval CHECK (interval_days > 0),
+    CONSTRAINT chk_schedule_target CHECK ((asset_id IS NULL) <> (category_id IS NULL)),
+    CONSTRAINT fk_schedule_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_schedule_category_id FOREIGN KEY(category_id) REFERENCES asset_categories(id),
+    CONSTRAINT fk_schedule_vendor_id FOREIGN KEY(vendor_id) REFERENCES vendors(id)
+);
+
+CREATE INDEX idx_maintenance_schedules_due ON maintenance_schedules(next_due_date) WHERE active;
+
+-- units a due schedule couldn't service because they were busy, they get
+-- their work order once they are in storage or placed again
+CREATE TABLE maintenance_deferrals (
+    schedule_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    due_date DATE NOT NULL,
+    PRIMARY KEY(schedule_id, asset_detail_id),
+    CONSTRAINT fk_deferral_schedule_id FOREIGN KEY(schedule_id) REFERENCES maintenance_schedules(id) ON DELETE CASCADE,
+    CONSTRAINT fk_deferral_asset_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id) ON DELETE CASCADE
+);
+
+CREATE TABLE work_orders (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    asset_id VARCHAR(100) NOT NULL,
+    schedule_id VARCHAR(100) NULL,
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    cost NUMERIC(15,2) NOT NULL DEFAULT 0,
+    vendor_id VARCHAR(100) NULL,
+    technician VARCHAR(100) NOT NULL DEFAULT '',
+    prior_status INT NOT NULL,
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    opened_at TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP NULL,
+    CONSTRAINT fk_work_order_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_work_order_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_work_order_schedule_id FOREIGN KEY(schedule_id) REFERENCES maintenance_schedules(id),
+    CONSTRAINT fk_work_order_vendor_id FOREIGN KEY(vendor_id) REFERENCES vendors(id)
+);
+
+-- a unit has at most one open work order
+CREATE UNIQUE INDEX uq_work_orders_open ON work_orders(asset_detail_id) WHERE status IN ('open', 'in-progress');
+CREATE INDEX idx_work_orders_status ON work_orders(status, opened_at);
+
+CREATE TABLE warranties (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NULL,
+    vendor_id VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    coverage TEXT,
+    claim_contact VARCHAR(150) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT chk_warranty_period CHECK (end_date >= start_date),
+    CONSTRAINT fk_warranty_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id),
+    CONSTRAINT fk_warranty_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_warranty_vendor_id FOREIGN KEY(vendor_id) REFERENCES vendors(id)
+);
+
+CREATE INDEX idx_warranties_asset_id ON warranties(asset_id);
+CREATE INDEX idx_warranties_end_date ON warranties(end_date);
+
+CREATE TABLE warranty_claims (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    warranty_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NULL,
+    work_order_id VARCHAR(100) NULL,
+    issue TEXT NOT NULL,
+    claim_number VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    resolution TEXT NOT NULL DEFAULT '',
+    claimed_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP NULL,
+    CONSTRAINT fk_claim_warranty_id FOREIGN KEY(warranty_id) REFERENCES warranties(id),
+    CONSTRAINT fk_claim_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_claim_work_order_id FOREIGN KEY(work_order_id) REFERENCES work_orders(id)
+);
+
+CREATE TABLE asset_disposals (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    asset_id VARCHAR(100) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    disposed_at DATE NOT NULL,
+    proceeds NUMERIC(15,2) NOT NULL DEFAULT 0,
+    counterparty VARCHAR(150) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    requested_by VARCHAR(100) NOT NULL,
+    decided_by VARCHAR(100) NOT NULL DEFAULT '',
+    decided_at TIMESTAMP NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    book_value NUMERIC(15,2) NULL,
+    gain_loss NUMERIC(15,2) NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_disposal_asset_id FOREIGN KEY(asset_id) REFERENCES asset(id)
+);
+
+CREATE INDEX idx_asset_disposals_disposed_at ON asset_disposals(status, disposed_at);
+
+CREATE TABLE asset_disposal_units (
+    disposal_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    PRIMARY KEY(disposal_id, asset_detail_id),
+    CONSTRAINT fk_disposal_unit_disposal_id FOREIGN KEY(disposal_id) REFERENCES asset_disposals(id),
+    CONSTRAINT fk_disposal_unit_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id)
+);
+
+CREATE TABLE consumables (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    unit VARCHAR(20) NOT NULL,
+    reorder_point NUMERIC(15,3) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE consumable_stock (
+    consumable_id VARCHAR(100) NOT NULL,
+    location_id VARCHAR(100) NOT NULL,
+    qty NUMERIC(15,3) NOT NULL DEFAULT 0 CHECK (qty >= 0),
+    PRIMARY KEY(consumable_id, location_id),
+    CONSTRAINT fk_stock_consumable_id FOREIGN KEY(consumable_id) REFERENCES consumables(id),
+    CONSTRAINT fk_stock_location_id FOREIGN KEY(location_id) REFERENCES asset_location(id)
+);
+
+CREATE TABLE consumable_transactions (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    consumable_id VARCHAR(100) NOT NULL,
+    location_id VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    qty NUMERIC(15,3) NOT NULL,
+    delta NUMERIC(15,3) NOT NULL,
+    balance NUMERIC(15,3) NOT NULL,
+    employee_id VARCHAR(100) NULL,
+    note TEXT NOT NULL DEFAULT '',
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_consumable_tx_consumable_id FOREIGN KEY(consumable_id) REFERENCES consumables(id),
+    CONSTRAINT fk_consumable_tx_location_id FOREIGN KEY(location_id) REFERENCES asset_location(id),
+    CONSTRAINT fk_consumable_tx_employee_id FOREIGN KEY(employee_id) REFERENCES employee(id)
+);
+
+CREATE INDEX idx_consumable_tx_consumable_id ON consumable_transactions(consumable_id, created_at);
+
+CREATE TABLE asset_transfers (
+    id VARCHAR(100) NOT NULL PRIMARY KEY,
+    source_location_id VARCHAR(100) NOT NULL,
+    destination_location_id VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    requested_by VARCHAR(100) NOT NULL,
+    decided_by VARCHAR(100) NOT NULL DEFAULT '',
+    decided_at TIMESTAMP NULL,
+    dispatched_by VARCHAR(100) NOT NULL DEFAULT '',
+    dispatched_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_transfer_source_id FOREIGN KEY(source_location_id) REFERENCES asset_location(id),
+    CONSTRAINT fk_transfer_destination_id FOREIGN KEY(destination_location_id) REFERENCES asset_location(id)
+);
+
+CREATE TABLE asset_transfer_units (
+    transfer_id VARCHAR(100) NOT NULL,
+    asset_detail_id VARCHAR(100) NOT NULL,
+    asset_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    discrepancy TEXT NOT NULL DEFAULT '',
+    received_by VARCHAR(100) NULL,
+    received_at TIMESTAMP NULL,
+    PRIMARY KEY(transfer_id, asset_detail_id),
+    CONSTRAINT fk_transfer_unit_transfer_id FOREIGN KEY(transfer_id) REFERENCES asset_transfers(id),
+    CONSTRAINT fk_transfer_unit_detail_id FOREIGN KEY(asset_detail_id) REFERENCES asset_details(id),
+    CONSTRAINT fk_transfer_unit_received_by FOREIGN KEY(received_by) REFERENCES employee(id)
+);
+
+CREATE INDEX idx_transfer_units_asset_detail_id ON asset_transfer_units(asset_detail_id);
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"asetku-bukan-asetmu/utils/common"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssetAssignmentController struct {
+	router  *gin.Engine
+	usecase usecase.AssetAssignmentUsecase
+}
+
+func (a *AssetAssignmentController) checkoutHandler(ctx *gin.Context) {
+	var assignment model.AssetAssignment
+	assignment.Id = common.GenerateUUID()
+	assignment.AssignedAt = time.Now()
+	err := ctx.ShouldBindJSON(&assignment)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	assignment, err = a.usecase.CheckoutAsset(assignment)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success checkout asset",
+		"data":    assignment,
+	})
+}
+
+func (a *AssetAssignmentController) checkinHandler(ctx *gin.Context) {
+	var checkin model.AssetCheckin
+	// Body is optional, it only carries the storage location
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&checkin); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]any{
+				"status": http.StatusBadRequest,
+				"error":  err.Error(),
+			})
+			return
+		}
+	}
+	checkin.AssignmentId = ctx.Param("id")
+	checkin.ReturnedAt = time.Now()
+
+	if err := a.usecase.CheckinAsset(checkin); err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success checkin asset",
+	})
+}
+
+func (a *AssetAssignmentController) employeeHoldingsHandler(ctx *gin.Context) {
+	holdings, err := a.usecase.ShowEmployeeHoldings(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show employee holdings",
+		"data":    holdings,
+	})
+}
+
+func (a *AssetAssignmentController) assetHoldingsHandler(ctx *gin.Context) {
+	holdings, err := a.usecase.ShowAssetHoldings(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show asset holdings",
+		"data":    holdings,
+	})
+}
+
+func NewAssetAssignmentController(router *gin.Engine, assignmentUsecase usecase.AssetAssignmentUsecase) *AssetAssignmentController {
+	controller := &AssetAssignmentController{
+		router:  router,
+		usecase: assignmentUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/asset/assignment")
+	routerGroup.POST("/", controller.checkoutHandler)
+	routerGroup.PUT("/:id/return", controller.checkinHandler)
+	routerGroup.GET("/employee/:id", controller.employeeHoldingsHandler)
+	routerGroup.GET("/asset/:id", controller.assetHoldingsHandler)
+
+	return controller
+}
@@ -0,0 +1,85 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAssetAssignmentUsecase only answers the calls the tests below make.
+type mockAssetAssignmentUsecase struct {
+	mock.Mock
+	usecase.AssetAssignmentUsecase
+}
+
+func (u *mockAssetAssignmentUsecase) CheckoutAsset(bodyRequest model.AssetAssignment) (model.AssetAssignment, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.AssetAssignment), args.Error(1)
+}
+
+func (u *mockAssetAssignmentUsecase) CheckinAsset(bodyRequest model.AssetCheckin) error {
+	args := u.Called(bodyRequest)
+	return args.Error(0)
+}
+
+type AssetAssignmentControllerSuite struct {
+	suite.Suite
+	router            *gin.Engine
+	assignmentUsecase *mockAssetAssignmentUsecase
+}
+
+func (suite *AssetAssignmentControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.assignmentUsecase = new(mockAssetAssignmentUsecase)
+	controller.NewAssetAssignmentController(suite.router, suite.assignmentUsecase)
+}
+
+func (suite *AssetAssignmentControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *AssetAssignmentControllerSuite) TestCheckout_AlreadyAssigned() {
+	suite.assignmentUsecase.Mock.On("CheckoutAsset", mock.Anything).Return(model.AssetAssignment{}, fmt.Errorf("asset unit u1 can't be checked out : illegal status transition from assigned to assigned : %w", usecase.ErrConflict))
+
+	response := suite.serve(http.MethodPost, "/api/v1/asset/assignment/", `{"assetDetailId":"u1","employeeId":"e1","dueAt":"2030-01-01T00:00:00Z"}`)
+
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+}
+
+func (suite *AssetAssignmentControllerSuite) TestCheckin_ErrorStatus() {
+	suite.assignmentUsecase.Mock.On("CheckinAsset", mock.MatchedBy(func(checkin model.AssetCheckin) bool {
+		return checkin.AssignmentId == "as1"
+	})).Return(fmt.Errorf("asset unit u1 is in-storage, it was never checked out : %w", usecase.ErrInvalid))
+	suite.assignmentUsecase.Mock.On("CheckinAsset", mock.MatchedBy(func(checkin model.AssetCheckin) bool {
+		return checkin.AssignmentId == "as9"
+	})).Return(fmt.Errorf("assignment with id as9 is not found : %w", usecase.ErrNotFound))
+
+	cases := map[string]int{
+		"/api/v1/asset/assignment/as1/return": http.StatusBadRequest,
+		"/api/v1/asset/assignment/as9/return": http.StatusNotFound,
+	}
+	for path, status := range cases {
+		response := suite.serve(http.MethodPut, path, "")
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func TestAssetAssignmentControllerSuite(t *testing.T) {
+	suite.Run(t, new(AssetAssignmentControllerSuite))
+}
@@ -54,6 +54,21 @@ func (a *AssetCategoriesController) searchHandler(c *gin.Context) {
 	})
 }
 
+func (a *AssetCategoriesController) schemaHandler(c *gin.Context) {
+	attributes, err := a.Usecase.CategorySchema(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Success Get asset categories attributes",
+		"data":    attributes,
+	})
+}
+
 func (a *AssetCategoriesController) listHandler(ctx *gin.Context) {
 	locations, err := a.Usecase.FindAllAssetCategoriesList()
 	if err != nil {
@@ -145,6 +160,7 @@ func NewAssetCategoriesController(router *gin.Engine, assetcatagoriesUseCase use
 	routerGroup.POST("/", ctr.createHandler)
 	routerGroup.GET("/", ctr.listHandler)
 	routerGroup.GET("/:id", ctr.searchHandler)
+	routerGroup.GET("/:id/attributes", ctr.schemaHandler)
 	routerGroup.PUT("/:id", ctr.updateHandler)
 	routerGroup.DELETE("//:id", ctr.deleteHandler)
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
 	"asetku-bukan-asetmu/usecase"
 	"asetku-bukan-asetmu/utils/common"
 	"net/http"
@@ -84,11 +85,27 @@ func (a *AssetController) getHandler(ctx *gin.Context) {
 	})
 }
 
+func (a *AssetController) getByTagHandler(ctx *gin.Context) {
+	unit, err := a.usecase.GetAssetUnitByTag(ctx.Param("tag"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status": "success",
+		"data":   unit,
+	})
+}
+
 func (a *AssetController) placementHandler(ctx *gin.Context) {
 	var assetPlacement model.AssetPlacement
 	assetPlacement.UpdatedAt = time.Now()
-	assetPlacement.CurrentStatus = 1
-	assetPlacement.TargetStatus = 2
+	assetPlacement.CurrentStatus = model.StatusInStorage
+	assetPlacement.TargetStatus = model.StatusPlaced
 	err := ctx.ShouldBindJSON(&assetPlacement)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, map[string]any{
@@ -100,7 +117,7 @@ func (a *AssetController) placementHandler(ctx *gin.Context) {
 
 	available, err := a.usecase.UpdateAssetLocation(assetPlacement)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, map[string]any{
+		ctx.JSON(errorStatus(err), map[string]any{
 			"status":  "failed",
 			"message": err.Error(),
 		})
@@ -121,6 +138,206 @@ func (a *AssetController) placementHandler(ctx *gin.Context) {
 	})
 }
 
+func (a *AssetController) returnHandler(ctx *gin.Context) {
+	var assetPlacement model.AssetPlacement
+	assetPlacement.AsssetId = ctx.Param("id")
+	assetPlacement.UpdatedAt = time.Now()
+	assetPlacement.CurrentStatus = model.StatusPlaced
+	assetPlacement.TargetStatus = model.StatusInStorage
+	err := ctx.ShouldBindJSON(&assetPlacement)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+	assetPlacement.AsssetId = ctx.Param("id")
+
+	available, err := a.usecase.ReturnAssetUnits(assetPlacement)
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if len(available) == 0 {
+		ctx.JSON(http.StatusAccepted, map[string]any{
+			"status":  "failed",
+			"message": "qty is beyond more current asset",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success return asset to storage",
+		"data":    available,
+	})
+}
+
+func (a *AssetController) updateHandler(ctx *gin.Context) {
+	var asset dto.AssetUpdateDTO
+	err := ctx.ShouldBindJSON(&asset)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if err := a.usecase.UpdateAsset(ctx.Param("id"), asset); err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success update asset",
+	})
+}
+
+func (a *AssetController) patchHandler(ctx *gin.Context) {
+	var asset dto.AssetPatchDTO
+	err := ctx.ShouldBindJSON(&asset)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if err := a.usecase.PatchAsset(ctx.Param("id"), asset); err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success update asset",
+	})
+}
+
+func (a *AssetController) deleteHandler(ctx *gin.Context) {
+	if err := a.usecase.DeleteAsset(ctx.Param("id")); err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success delete asset",
+	})
+}
+
+func (a *AssetController) retireHandler(ctx *gin.Context) {
+	var retirement model.AssetRetirement
+	retirement.RemovedAt = time.Now()
+	err := ctx.ShouldBindJSON(&retirement)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+	retirement.AssetId = ctx.Param("id")
+
+	if err := a.usecase.RetireAssetUnit(retirement); err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success retire asset unit",
+	})
+}
+
+func (a *AssetController) uploadImageHandler(ctx *gin.Context) {
+	header, err := ctx.FormFile("image")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	imageUrl, err := a.usecase.UploadAssetImage(ctx.Param("id"), dto.FileUploadDTO{
+		FileName: header.Filename,
+		Size:     header.Size,
+		Content:  file,
+	})
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success upload asset image",
+		"data":    map[string]any{"imageUrl": imageUrl, "thumbnails": usecase.AssetThumbnailUrls(imageUrl, true)},
+	})
+}
+
+func (a *AssetController) removeImageHandler(ctx *gin.Context) {
+	if err := a.usecase.RemoveAssetImage(ctx.Param("id")); err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success remove asset image",
+	})
+}
+
+func (a *AssetController) imageHandler(ctx *gin.Context) {
+	link, err := a.usecase.AssetImageURL(ctx.Param("name"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, link)
+}
+
 func NewAssetController(router *gin.Engine, assetUsecase usecase.AssetUsecase) {
 	controller := &AssetController{
 		router:  router,
@@ -131,5 +348,14 @@ func NewAssetController(router *gin.Engine, assetUsecase usecase.AssetUsecase) {
 	routerGroup.POST("/", controller.createHandler)
 	routerGroup.GET("/", controller.listHandler)
 	routerGroup.GET("/detail/:id", controller.getHandler)
+	routerGroup.GET("/tag/:tag", controller.getByTagHandler)
 	routerGroup.PUT("/placement/:id", controller.placementHandler)
+	routerGroup.PUT("/:id", controller.updateHandler)
+	routerGroup.PATCH("/:id", controller.patchHandler)
+	routerGroup.DELETE("/:id", controller.deleteHandler)
+	routerGroup.PUT("/:id/retire", controller.retireHandler)
+	routerGroup.PUT("/:id/return", controller.returnHandler)
+	routerGroup.PUT("/:id/image", controller.uploadImageHandler)
+	routerGroup.DELETE("/:id/image", controller.removeImageHandler)
+	routerGroup.GET("/image/:name", controller.imageHandler)
 }
@@ -0,0 +1,121 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAssetUsecase only answers the calls the tests below make.
+type mockAssetUsecase struct {
+	mock.Mock
+	usecase.AssetUsecase
+}
+
+func (u *mockAssetUsecase) UpdateAssetLocation(bodyRequest model.AssetPlacement) ([]string, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (u *mockAssetUsecase) UpdateAsset(id string, bodyRequest dto.AssetUpdateDTO) error {
+	args := u.Called(id, bodyRequest)
+	return args.Error(0)
+}
+
+func (u *mockAssetUsecase) PatchAsset(id string, bodyRequest dto.AssetPatchDTO) error {
+	args := u.Called(id, bodyRequest)
+	return args.Error(0)
+}
+
+func (u *mockAssetUsecase) RetireAssetUnit(bodyRequest model.AssetRetirement) error {
+	args := u.Called(bodyRequest)
+	return args.Error(0)
+}
+
+type AssetControllerSuite struct {
+	suite.Suite
+	router       *gin.Engine
+	assetUsecase *mockAssetUsecase
+}
+
+func (suite *AssetControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.assetUsecase = new(mockAssetUsecase)
+	controller.NewAssetController(suite.router, suite.assetUsecase)
+}
+
+func (suite *AssetControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *AssetControllerSuite) TestUpdate_ErrorStatus() {
+	suite.assetUsecase.Mock.On("UpdateAsset", "a9", mock.Anything).Return(fmt.Errorf("asset with id a9 is not found : %w", usecase.ErrNotFound))
+	suite.assetUsecase.Mock.On("UpdateAsset", "a1", mock.Anything).Return(fmt.Errorf("attribute color is required : %w", usecase.ErrInvalid))
+
+	cases := map[string]int{
+		"/api/v1/asset/a9": http.StatusNotFound,
+		"/api/v1/asset/a1": http.StatusBadRequest,
+	}
+	for path, status := range cases {
+		response := suite.serve(http.MethodPut, path, `{"categoryId":"c1","name":"Laptop"}`)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func (suite *AssetControllerSuite) TestPatch_NotFound() {
+	suite.assetUsecase.Mock.On("PatchAsset", "a9", mock.Anything).Return(fmt.Errorf("asset with id a9 is not found : %w", usecase.ErrNotFound))
+
+	response := suite.serve(http.MethodPatch, "/api/v1/asset/a9", `{"attributes":{"color":"red"}}`)
+
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
+func (suite *AssetControllerSuite) TestRetire_NotInStorage() {
+	suite.assetUsecase.Mock.On("RetireAssetUnit", mock.MatchedBy(func(retirement model.AssetRetirement) bool {
+		return retirement.AssetId == "a1"
+	})).Return(fmt.Errorf("asset unit u1 is assigned, only units in storage can be retired : %w", usecase.ErrConflict))
+
+	response := suite.serve(http.MethodPut, "/api/v1/asset/a1/retire", `{"assetDetailIds":["u1"]}`)
+
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+}
+
+func (suite *AssetControllerSuite) TestPlacement_ErrorStatus() {
+	suite.assetUsecase.Mock.On("UpdateAssetLocation", mock.MatchedBy(func(placement model.AssetPlacement) bool {
+		return placement.LocationId == "l9"
+	})).Return([]string(nil), fmt.Errorf("location with id l9 is not found : %w", usecase.ErrNotFound))
+	suite.assetUsecase.Mock.On("UpdateAssetLocation", mock.MatchedBy(func(placement model.AssetPlacement) bool {
+		return placement.LocationId == "l1"
+	})).Return([]string(nil), fmt.Errorf("asset unit u1 is placed, not in-storage : %w", usecase.ErrConflict))
+
+	cases := map[string]int{
+		"l9": http.StatusNotFound,
+		"l1": http.StatusConflict,
+	}
+	for locationId, status := range cases {
+		response := suite.serve(http.MethodPut, "/api/v1/asset/placement/a1", `{"assetId":"a1","locationId":"`+locationId+`","assetDetailIds":["u1"]}`)
+
+		assert.Equal(suite.T(), status, response.Code, locationId)
+	}
+}
+
+func TestAssetControllerSuite(t *testing.T) {
+	suite.Run(t, new(AssetControllerSuite))
+}
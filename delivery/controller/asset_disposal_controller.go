@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssetDisposalController struct {
+	router  *gin.Engine
+	usecase usecase.AssetDisposalUsecase
+}
+
+func (a *AssetDisposalController) requestHandler(ctx *gin.Context) {
+	var disposal model.AssetDisposal
+	if err := ctx.ShouldBindJSON(&disposal); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	disposal, err := a.usecase.RequestDisposal(disposal)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success request disposal",
+		"data":    disposal,
+	})
+}
+
+func (a *AssetDisposalController) listHandler(ctx *gin.Context) {
+	disposals, err := a.usecase.ShowDisposals(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show disposals",
+		"data":    disposals,
+	})
+}
+
+func (a *AssetDisposalController) getHandler(ctx *gin.Context) {
+	disposal, err := a.usecase.GetDisposal(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get disposal",
+		"data":    disposal,
+	})
+}
+
+func (a *AssetDisposalController) approveHandler(ctx *gin.Context) {
+	a.decide(ctx, a.usecase.ApproveDisposal, "success approve disposal")
+}
+
+func (a *AssetDisposalController) rejectHandler(ctx *gin.Context) {
+	a.decide(ctx, a.usecase.RejectDisposal, "success reject disposal")
+}
+
+func (a *AssetDisposalController) decide(ctx *gin.Context, decision func(string, dto.DisposalDecisionDTO) (model.AssetDisposal, error), message string) {
+	var request dto.DisposalDecisionDTO
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	disposal, err := decision(ctx.Param("id"), request)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": message,
+		"data":    disposal,
+	})
+}
+
+func NewAssetDisposalController(router *gin.Engine, disposalUsecase usecase.AssetDisposalUsecase) *AssetDisposalController {
+	controller := &AssetDisposalController{
+		router:  router,
+		usecase: disposalUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/asset/disposal")
+	routerGroup.POST("/", controller.requestHandler)
+	routerGroup.GET("/", controller.listHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.PUT("/:id/approve", controller.approveHandler)
+	routerGroup.PUT("/:id/reject", controller.rejectHandler)
+
+	return controller
+}
@@ -0,0 +1,95 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAssetDisposalUsecase only answers the calls the tests below make.
+type mockAssetDisposalUsecase struct {
+	mock.Mock
+	usecase.AssetDisposalUsecase
+}
+
+func (u *mockAssetDisposalUsecase) RequestDisposal(bodyRequest model.AssetDisposal) (model.AssetDisposal, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.AssetDisposal), args.Error(1)
+}
+
+func (u *mockAssetDisposalUsecase) ApproveDisposal(id string, bodyRequest dto.DisposalDecisionDTO) (model.AssetDisposal, error) {
+	args := u.Called(id, bodyRequest)
+	return args.Get(0).(model.AssetDisposal), args.Error(1)
+}
+
+type AssetDisposalControllerSuite struct {
+	suite.Suite
+	router          *gin.Engine
+	disposalUsecase *mockAssetDisposalUsecase
+}
+
+func (suite *AssetDisposalControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.disposalUsecase = new(mockAssetDisposalUsecase)
+	controller.NewAssetDisposalController(suite.router, suite.disposalUsecase)
+}
+
+func (suite *AssetDisposalControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *AssetDisposalControllerSuite) TestRequest_ErrorStatus() {
+	suite.disposalUsecase.Mock.On("RequestDisposal", mock.MatchedBy(func(disposal model.AssetDisposal) bool {
+		return disposal.AssetId == "a9"
+	})).Return(model.AssetDisposal{}, fmt.Errorf("asset with id a9 is not found : %w", usecase.ErrNotFound))
+	suite.disposalUsecase.Mock.On("RequestDisposal", mock.MatchedBy(func(disposal model.AssetDisposal) bool {
+		return disposal.AssetId == "a1"
+	})).Return(model.AssetDisposal{}, fmt.Errorf("asset unit u4 doesn't belong to asset a1 : %w", usecase.ErrInvalid))
+
+	cases := map[string]int{
+		"a9": http.StatusNotFound,
+		"a1": http.StatusBadRequest,
+	}
+	for assetId, status := range cases {
+		response := suite.serve(http.MethodPost, "/api/v1/asset/disposal/", `{"assetId":"`+assetId+`","assetDetailIds":["u4"],"method":"scrap","disposedAt":"2030-01-01T00:00:00Z","requestedBy":"rina"}`)
+
+		assert.Equal(suite.T(), status, response.Code, assetId)
+	}
+}
+
+func (suite *AssetDisposalControllerSuite) TestApprove_ErrorStatus() {
+	suite.disposalUsecase.Mock.On("ApproveDisposal", "d9", mock.Anything).Return(model.AssetDisposal{}, fmt.Errorf("disposal with id d9 is not found : %w", usecase.ErrNotFound))
+	suite.disposalUsecase.Mock.On("ApproveDisposal", "d1", mock.Anything).Return(model.AssetDisposal{}, fmt.Errorf("disposal can't be decided by the one who requested it : %w", usecase.ErrForbidden))
+	suite.disposalUsecase.Mock.On("ApproveDisposal", "d2", mock.Anything).Return(model.AssetDisposal{}, fmt.Errorf("disposal d2 is already approved : %w", usecase.ErrConflict))
+
+	cases := map[string]int{
+		"d9": http.StatusNotFound,
+		"d1": http.StatusForbidden,
+		"d2": http.StatusConflict,
+	}
+	for id, status := range cases {
+		response := suite.serve(http.MethodPut, "/api/v1/asset/disposal/"+id+"/approve", `{"actor":"rina"}`)
+
+		assert.Equal(suite.T(), status, response.Code, id)
+	}
+}
+
+func TestAssetDisposalControllerSuite(t *testing.T) {
+	suite.Run(t, new(AssetDisposalControllerSuite))
+}
@@ -80,6 +80,22 @@ func (loc *AssetLocationController) searchHandler(ctx *gin.Context) {
 	})
 }
 
+func (loc *AssetLocationController) treeHandler(ctx *gin.Context) {
+	tree, err := loc.usecase.ShowLocationTree(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show location tree",
+		"data":    tree,
+	})
+}
+
 func (loc *AssetLocationController) updateHandler(ctx *gin.Context) {
 	var location model.AssetLocation
 	if err := ctx.ShouldBindJSON(&location); err != nil {
@@ -107,7 +123,7 @@ func (loc *AssetLocationController) deleteHandler(ctx *gin.Context) {
 	id := ctx.Param("id")
 	err := loc.usecase.DeleteSelectedLocation(id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, map[string]any{
+		ctx.JSON(errorStatus(err), map[string]any{
 			"error": err.Error(),
 		})
 
@@ -129,7 +145,9 @@ func NewAssetLocationController(router *gin.Engine, assetLocUsecase usecase.Asse
 	routerGroup := controller.router.Group("/api/v1/asset-location")
 	routerGroup.POST("/", controller.createHandler)
 	routerGroup.GET("/", controller.showHandler)
+	routerGroup.GET("/tree", controller.treeHandler)
 	routerGroup.GET("/:id", controller.searchHandler)
+	routerGroup.GET("/:id/tree", controller.treeHandler)
 	routerGroup.PUT("/:id", controller.updateHandler)
 	routerGroup.DELETE("/:id", controller.deleteHandler)
 
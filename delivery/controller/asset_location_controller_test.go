@@ -11,6 +11,8 @@ import (
 
 	"asetku-bukan-asetmu/delivery/controller"
 	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -49,6 +51,15 @@ func (loc *mockAssetLocationUsecase) ShowAllLocation() ([]model.AssetLocation, e
 	return nil, nil
 }
 
+func (loc *mockAssetLocationUsecase) ShowLocationTree(id string) ([]dto.LocationTreeDTO, error) {
+	args := loc.Called(id)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.LocationTreeDTO), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (loc *mockAssetLocationUsecase) EditExistedLocation(bodyRequest model.AssetLocation) error {
 	args := loc.Called(bodyRequest)
 	if args.Get(0) != nil {
@@ -193,6 +204,42 @@ func (suite *AssetLocationControllerSuite) TestListLocation_ServerError() {
 	assert.Equal(suite.T(), expectedResultBytes, response.Body.Bytes())
 }
 
+func (suite *AssetLocationControllerSuite) TestLocationTree_Success() {
+	tree := []dto.LocationTreeDTO{
+		{
+			AssetLocation: dummyBody[0],
+			Units:         1,
+			TotalUnits:    3,
+			Children: []dto.LocationTreeDTO{
+				{AssetLocation: dummyBody[1], Units: 2, TotalUnits: 2, Children: []dto.LocationTreeDTO{}},
+			},
+		},
+	}
+	suite.assetLocUsecase.Mock.On("ShowLocationTree", "1").Return(tree, nil)
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/v1/asset-location/1/tree", nil)
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+
+	expectedResult := json.RawMessage(`{"data":[{"id":"1","name":"Location 1","units":1,"totalUnits":3,"children":[{"id":"2","name":"Location 2","units":2,"totalUnits":2,"children":[]}]}],"message":"success show location tree","status":200}`)
+	expectedResultBytes, _ := expectedResult.MarshalJSON()
+
+	assert.Equal(suite.T(), http.StatusOK, response.Code)
+	assert.Equal(suite.T(), expectedResultBytes, response.Body.Bytes())
+}
+
+func (suite *AssetLocationControllerSuite) TestLocationTree_NotFound() {
+	suite.assetLocUsecase.Mock.On("ShowLocationTree", "9").Return(nil, fmt.Errorf("location with id 9 is not found : %w", usecase.ErrNotFound))
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/v1/asset-location/9/tree", nil)
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
 func (suite *AssetLocationControllerSuite) TestGetLocation_Success() {
 	bodyReq := dummyBody[0]
 
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssetMovementController struct {
+	router  *gin.Engine
+	usecase usecase.AssetMovementUsecase
+}
+
+func (a *AssetMovementController) assetHistoryHandler(ctx *gin.Context) {
+	history, err := a.usecase.ShowAssetHistory(ctx.Param("id"), ctx.Query("unit"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show asset history",
+		"data":    history,
+	})
+}
+
+func (a *AssetMovementController) locationHistoryHandler(ctx *gin.Context) {
+	history, err := a.usecase.ShowLocationHistory(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show location history",
+		"data":    history,
+	})
+}
+
+func NewAssetMovementController(router *gin.Engine, movementUsecase usecase.AssetMovementUsecase) *AssetMovementController {
+	controller := &AssetMovementController{
+		router:  router,
+		usecase: movementUsecase,
+	}
+
+	controller.router.GET("/api/v1/asset/:id/history", controller.assetHistoryHandler)
+	controller.router.GET("/api/v1/asset-location/:id/history", controller.locationHistoryHandler)
+
+	return controller
+}
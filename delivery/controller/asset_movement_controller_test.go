@@ -0,0 +1,86 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAssetMovementUsecase struct {
+	mock.Mock
+}
+
+func (u *mockAssetMovementUsecase) ShowAssetHistory(assetId, unitId string) ([]dto.AssetMovementDTO, error) {
+	args := u.Called(assetId, unitId)
+	return args.Get(0).([]dto.AssetMovementDTO), args.Error(1)
+}
+
+func (u *mockAssetMovementUsecase) ShowLocationHistory(locationId string) ([]dto.AssetMovementDTO, error) {
+	args := u.Called(locationId)
+	return args.Get(0).([]dto.AssetMovementDTO), args.Error(1)
+}
+
+type AssetMovementControllerSuite struct {
+	suite.Suite
+	router          *gin.Engine
+	movementUsecase *mockAssetMovementUsecase
+}
+
+func (suite *AssetMovementControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.movementUsecase = new(mockAssetMovementUsecase)
+	controller.NewAssetMovementController(suite.router, suite.movementUsecase)
+}
+
+func (suite *AssetMovementControllerSuite) serve(path string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(http.MethodGet, path, nil)
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *AssetMovementControllerSuite) TestAssetHistory_Success() {
+	suite.movementUsecase.Mock.On("ShowAssetHistory", "a1", "u1").Return([]dto.AssetMovementDTO{{Id: "m1", AssetId: "a1", ToStatus: "placed"}}, nil)
+
+	response := suite.serve("/api/v1/asset/a1/history?unit=u1")
+
+	assert.Equal(suite.T(), http.StatusOK, response.Code)
+	assert.Contains(suite.T(), response.Body.String(), `"toStatus":"placed"`)
+}
+
+func (suite *AssetMovementControllerSuite) TestAssetHistory_UnitOfOtherAsset() {
+	suite.movementUsecase.Mock.On("ShowAssetHistory", "a1", "u2").Return([]dto.AssetMovementDTO(nil), fmt.Errorf("asset unit u2 doesn't belong to asset a1 : %w", usecase.ErrNotFound))
+
+	response := suite.serve("/api/v1/asset/a1/history?unit=u2")
+
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
+func (suite *AssetMovementControllerSuite) TestLocationHistory_ErrorStatus() {
+	suite.movementUsecase.Mock.On("ShowLocationHistory", "l9").Return([]dto.AssetMovementDTO(nil), fmt.Errorf("location with id l9 is not found : %w", usecase.ErrNotFound))
+	suite.movementUsecase.Mock.On("ShowLocationHistory", "l1").Return([]dto.AssetMovementDTO(nil), fmt.Errorf("error get location history : connection refused"))
+
+	cases := map[string]int{
+		"/api/v1/asset-location/l9/history": http.StatusNotFound,
+		"/api/v1/asset-location/l1/history": http.StatusInternalServerError,
+	}
+	for path, status := range cases {
+		response := suite.serve(path)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func TestAssetMovementControllerSuite(t *testing.T) {
+	suite.Run(t, new(AssetMovementControllerSuite))
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssetReservationController struct {
+	router  *gin.Engine
+	usecase usecase.AssetReservationUsecase
+}
+
+func (a *AssetReservationController) reserveHandler(ctx *gin.Context) {
+	var reservation model.AssetReservation
+	if err := ctx.ShouldBindJSON(&reservation); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	reservation, err := a.usecase.ReserveAsset(reservation)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success reserve asset",
+		"data":    reservation,
+	})
+}
+
+func (a *AssetReservationController) getHandler(ctx *gin.Context) {
+	reservation, err := a.usecase.GetReservation(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get reservation",
+		"data":    reservation,
+	})
+}
+
+func (a *AssetReservationController) cancelHandler(ctx *gin.Context) {
+	if err := a.usecase.CancelReservation(ctx.Param("id")); err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success cancel reservation",
+	})
+}
+
+func (a *AssetReservationController) employeeReservationsHandler(ctx *gin.Context) {
+	reservations, err := a.usecase.ShowEmployeeReservations(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show employee reservations",
+		"data":    reservations,
+	})
+}
+
+func (a *AssetReservationController) assetReservationsHandler(ctx *gin.Context) {
+	reservations, err := a.usecase.ShowAssetReservations(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show asset reservations",
+		"data":    reservations,
+	})
+}
+
+func NewAssetReservationController(router *gin.Engine, reservationUsecase usecase.AssetReservationUsecase) *AssetReservationController {
+	controller := &AssetReservationController{
+		router:  router,
+		usecase: reservationUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/asset/reservation")
+	routerGroup.POST("/", controller.reserveHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.PUT("/:id/cancel", controller.cancelHandler)
+	routerGroup.GET("/employee/:id", controller.employeeReservationsHandler)
+	routerGroup.GET("/asset/:id", controller.assetReservationsHandler)
+
+	return controller
+}
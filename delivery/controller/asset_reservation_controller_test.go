@@ -0,0 +1,89 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAssetReservationUsecase only answers the calls the tests below make.
+type mockAssetReservationUsecase struct {
+	mock.Mock
+	usecase.AssetReservationUsecase
+}
+
+func (u *mockAssetReservationUsecase) ReserveAsset(bodyRequest model.AssetReservation) (model.AssetReservation, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.AssetReservation), args.Error(1)
+}
+
+func (u *mockAssetReservationUsecase) CancelReservation(id string) error {
+	args := u.Called(id)
+	return args.Error(0)
+}
+
+type AssetReservationControllerSuite struct {
+	suite.Suite
+	router             *gin.Engine
+	reservationUsecase *mockAssetReservationUsecase
+}
+
+func (suite *AssetReservationControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.reservationUsecase = new(mockAssetReservationUsecase)
+	controller.NewAssetReservationController(suite.router, suite.reservationUsecase)
+}
+
+func (suite *AssetReservationControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *AssetReservationControllerSuite) TestReserve_ErrorStatus() {
+	suite.reservationUsecase.Mock.On("ReserveAsset", mock.MatchedBy(func(reservation model.AssetReservation) bool {
+		return reservation.EmployeeId == "e1"
+	})).Return(model.AssetReservation{}, fmt.Errorf("failed to reserve asset : asset unit u1 is already reserved : %w", usecase.ErrConflict))
+	suite.reservationUsecase.Mock.On("ReserveAsset", mock.MatchedBy(func(reservation model.AssetReservation) bool {
+		return reservation.EmployeeId == "e2"
+	})).Return(model.AssetReservation{}, fmt.Errorf("reservation must end after it starts : %w", usecase.ErrInvalid))
+	suite.reservationUsecase.Mock.On("ReserveAsset", mock.MatchedBy(func(reservation model.AssetReservation) bool {
+		return reservation.EmployeeId == "e9"
+	})).Return(model.AssetReservation{}, fmt.Errorf("employee with id e9 is not found : %w", usecase.ErrNotFound))
+
+	cases := map[string]int{
+		"e1": http.StatusConflict,
+		"e2": http.StatusBadRequest,
+		"e9": http.StatusNotFound,
+	}
+	for employeeId, status := range cases {
+		response := suite.serve(http.MethodPost, "/api/v1/asset/reservation/", `{"assetId":"a1","employeeId":"`+employeeId+`","qty":1,"startAt":"2030-01-01T09:00:00Z","endAt":"2030-01-01T12:00:00Z"}`)
+
+		assert.Equal(suite.T(), status, response.Code, employeeId)
+	}
+}
+
+func (suite *AssetReservationControllerSuite) TestCancel_NotActive() {
+	suite.reservationUsecase.Mock.On("CancelReservation", "r1").Return(fmt.Errorf("reservation r1 is already cancelled : %w", usecase.ErrConflict))
+
+	response := suite.serve(http.MethodPut, "/api/v1/asset/reservation/r1/cancel", "")
+
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+}
+
+func TestAssetReservationControllerSuite(t *testing.T) {
+	suite.Run(t, new(AssetReservationControllerSuite))
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssetTransferController struct {
+	router  *gin.Engine
+	usecase usecase.AssetTransferUsecase
+}
+
+func (a *AssetTransferController) requestHandler(ctx *gin.Context) {
+	var transfer model.AssetTransfer
+	if err := ctx.ShouldBindJSON(&transfer); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	transfer, err := a.usecase.RequestTransfer(transfer)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success request transfer",
+		"data":    transfer,
+	})
+}
+
+func (a *AssetTransferController) getHandler(ctx *gin.Context) {
+	transfer, err := a.usecase.GetTransfer(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get transfer",
+		"data":    transfer,
+	})
+}
+
+func (a *AssetTransferController) listHandler(ctx *gin.Context) {
+	transfers, err := a.usecase.ShowTransfers(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show transfers",
+		"data":    transfers,
+	})
+}
+
+// decisionHandler handles approve, reject and cancel, they only differ in
+// the usecase method and the message.
+func (a *AssetTransferController) decisionHandler(decide func(usecase.AssetTransferUsecase, string, dto.TransferDecisionDTO) (model.AssetTransfer, error), message string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var decision dto.TransferDecisionDTO
+		if err := ctx.ShouldBindJSON(&decision); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]any{
+				"status": http.StatusBadRequest,
+				"error":  err.Error(),
+			})
+			return
+		}
+
+		transfer, err := decide(a.usecase, ctx.Param("id"), decision)
+		if err != nil {
+			status := errorStatus(err)
+			ctx.JSON(status, map[string]any{
+				"status": status,
+				"error":  err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, map[string]any{
+			"status":  http.StatusOK,
+			"message": message,
+			"data":    transfer,
+		})
+	}
+}
+
+func (a *AssetTransferController) dispatchHandler(ctx *gin.Context) {
+	var dispatch dto.TransferDispatchDTO
+	if err := ctx.ShouldBindJSON(&dispatch); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	transfer, err := a.usecase.DispatchTransfer(ctx.Param("id"), dispatch)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success dispatch transfer",
+		"data":    transfer,
+	})
+}
+
+func (a *AssetTransferController) receiveHandler(ctx *gin.Context) {
+	var receipt dto.TransferReceiptDTO
+	if err := ctx.ShouldBindJSON(&receipt); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	transfer, err := a.usecase.ReceiveTransfer(ctx.Param("id"), receipt)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success receive transfer",
+		"data":    transfer,
+	})
+}
+
+func NewAssetTransferController(router *gin.Engine, transferUsecase usecase.AssetTransferUsecase) *AssetTransferController {
+	controller := &AssetTransferController{
+		router:  router,
+		usecase: transferUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/asset/transfer")
+	routerGroup.POST("/", controller.requestHandler)
+	routerGroup.GET("/", controller.listHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.PUT("/:id/approve", controller.decisionHandler(usecase.AssetTransferUsecase.ApproveTransfer, "success approve transfer"))
+	routerGroup.PUT("/:id/reject", controller.decisionHandler(usecase.AssetTransferUsecase.RejectTransfer, "success reject transfer"))
+	routerGroup.PUT("/:id/cancel", controller.decisionHandler(usecase.AssetTransferUsecase.CancelTransfer, "success cancel transfer"))
+	routerGroup.PUT("/:id/dispatch", controller.dispatchHandler)
+	routerGroup.PUT("/:id/receive", controller.receiveHandler)
+
+	return controller
+}
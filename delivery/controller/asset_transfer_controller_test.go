@@ -0,0 +1,97 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAssetTransferUsecase only answers the calls the tests below make.
+type mockAssetTransferUsecase struct {
+	mock.Mock
+	usecase.AssetTransferUsecase
+}
+
+func (u *mockAssetTransferUsecase) RequestTransfer(bodyRequest model.AssetTransfer) (model.AssetTransfer, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.AssetTransfer), args.Error(1)
+}
+
+func (u *mockAssetTransferUsecase) ApproveTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error) {
+	args := u.Called(id, bodyRequest)
+	return args.Get(0).(model.AssetTransfer), args.Error(1)
+}
+
+func (u *mockAssetTransferUsecase) ReceiveTransfer(id string, bodyRequest dto.TransferReceiptDTO) (model.AssetTransfer, error) {
+	args := u.Called(id, bodyRequest)
+	return args.Get(0).(model.AssetTransfer), args.Error(1)
+}
+
+type AssetTransferControllerSuite struct {
+	suite.Suite
+	router          *gin.Engine
+	transferUsecase *mockAssetTransferUsecase
+}
+
+func (suite *AssetTransferControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.transferUsecase = new(mockAssetTransferUsecase)
+	controller.NewAssetTransferController(suite.router, suite.transferUsecase)
+}
+
+func (suite *AssetTransferControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *AssetTransferControllerSuite) TestRequest_UnitOnTransfer() {
+	suite.transferUsecase.Mock.On("RequestTransfer", mock.Anything).Return(model.AssetTransfer{}, fmt.Errorf("asset unit u1 is already on transfer t0 : %w", usecase.ErrConflict))
+
+	response := suite.serve(http.MethodPost, "/api/v1/asset/transfer/", `{"sourceLocationId":"l1","destinationLocationId":"l2","assetDetailIds":["u1"],"requestedBy":"Budi"}`)
+
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+}
+
+func (suite *AssetTransferControllerSuite) TestApprove_ByRequester() {
+	suite.transferUsecase.Mock.On("ApproveTransfer", "t1", mock.Anything).Return(model.AssetTransfer{}, fmt.Errorf("transfer can't be decided by the one who requested it : %w", usecase.ErrForbidden))
+
+	response := suite.serve(http.MethodPut, "/api/v1/asset/transfer/t1/approve", `{"actor":"Budi"}`)
+
+	assert.Equal(suite.T(), http.StatusForbidden, response.Code)
+}
+
+func (suite *AssetTransferControllerSuite) TestReceive_ErrorStatus() {
+	suite.transferUsecase.Mock.On("ReceiveTransfer", "t1", mock.Anything).Return(model.AssetTransfer{}, fmt.Errorf("receipt must be confirmed by the custodian of Branch : %w", usecase.ErrForbidden))
+	suite.transferUsecase.Mock.On("ReceiveTransfer", "t2", mock.Anything).Return(model.AssetTransfer{}, fmt.Errorf("asset unit u9 is not part of transfer t2 : %w", usecase.ErrInvalid))
+	suite.transferUsecase.Mock.On("ReceiveTransfer", "t3", mock.Anything).Return(model.AssetTransfer{}, fmt.Errorf("transfer t3 is completed, it can't be received : %w", usecase.ErrConflict))
+
+	cases := map[string]int{
+		"/api/v1/asset/transfer/t1/receive": http.StatusForbidden,
+		"/api/v1/asset/transfer/t2/receive": http.StatusBadRequest,
+		"/api/v1/asset/transfer/t3/receive": http.StatusConflict,
+	}
+	for path, status := range cases {
+		response := suite.serve(http.MethodPut, path, `{"custodianId":"e1","units":[{"assetDetailId":"u1"}]}`)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func TestAssetTransferControllerSuite(t *testing.T) {
+	suite.Run(t, new(AssetTransferControllerSuite))
+}
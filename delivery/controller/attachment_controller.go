@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentController struct {
+	router  *gin.Engine
+	usecase usecase.AssetAttachmentUsecase
+}
+
+func (a *AttachmentController) uploadHandler(ctx *gin.Context) {
+	var bodyRequest dto.AttachmentUploadDTO
+	if err := ctx.ShouldBind(&bodyRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	bodyRequest.AssetId = ctx.Param("id")
+	bodyRequest.File = dto.FileUploadDTO{
+		FileName: header.Filename,
+		Size:     header.Size,
+		Content:  file,
+	}
+
+	attachment, err := a.usecase.UploadAttachment(bodyRequest)
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  "success",
+		"message": "success upload attachment",
+		"data":    attachment,
+	})
+}
+
+func (a *AttachmentController) listHandler(ctx *gin.Context) {
+	attachments, err := a.usecase.ShowAttachments(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success get attachments",
+		"data":    attachments,
+	})
+}
+
+func (a *AttachmentController) downloadHandler(ctx *gin.Context) {
+	attachment, object, err := a.usecase.OpenAttachment(ctx.Param("id"), ctx.Param("attachmentId"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+	defer object.Body.Close()
+
+	ctx.DataFromReader(http.StatusOK, object.Size, attachment.ContentType, object.Body, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+	})
+}
+
+func (a *AttachmentController) deleteHandler(ctx *gin.Context) {
+	if err := a.usecase.DeleteAttachment(ctx.Param("id"), ctx.Param("attachmentId")); err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success delete attachment",
+	})
+}
+
+func NewAttachmentController(router *gin.Engine, attachmentUsecase usecase.AssetAttachmentUsecase) *AttachmentController {
+	controller := &AttachmentController{
+		router:  router,
+		usecase: attachmentUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/asset/:id/attachments")
+	routerGroup.POST("/", controller.uploadHandler)
+	routerGroup.GET("/", controller.listHandler)
+	routerGroup.GET("/:attachmentId", controller.downloadHandler)
+	routerGroup.DELETE("/:attachmentId", controller.deleteHandler)
+
+	return controller
+}
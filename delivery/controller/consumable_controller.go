@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConsumableController struct {
+	router  *gin.Engine
+	usecase usecase.ConsumableUsecase
+}
+
+func (c *ConsumableController) registerHandler(ctx *gin.Context) {
+	var consumable model.Consumable
+	if err := ctx.ShouldBindJSON(&consumable); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	consumable, err := c.usecase.RegisterConsumable(consumable)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success register consumable",
+		"data":    consumable,
+	})
+}
+
+func (c *ConsumableController) updateHandler(ctx *gin.Context) {
+	var consumable model.Consumable
+	if err := ctx.ShouldBindJSON(&consumable); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if err := c.usecase.UpdateConsumable(ctx.Param("id"), consumable); err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success update consumable",
+	})
+}
+
+func (c *ConsumableController) getHandler(ctx *gin.Context) {
+	consumable, err := c.usecase.GetConsumable(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get consumable",
+		"data":    consumable,
+	})
+}
+
+func (c *ConsumableController) listHandler(ctx *gin.Context) {
+	consumables, err := c.usecase.ShowAllConsumables()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show all consumables",
+		"data":    consumables,
+	})
+}
+
+func (c *ConsumableController) lowStockHandler(ctx *gin.Context) {
+	consumables, err := c.usecase.ShowLowStock()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show low stock consumables",
+		"data":    consumables,
+	})
+}
+
+// transactionHandler records a stock transaction of the given type, the
+// type comes from the route instead of the body.
+func (c *ConsumableController) transactionHandler(transactionType model.ConsumableTransactionType) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var transaction model.ConsumableTransaction
+		if err := ctx.ShouldBindJSON(&transaction); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]any{
+				"status": http.StatusBadRequest,
+				"error":  err.Error(),
+			})
+			return
+		}
+
+		transaction.Type = transactionType
+		transaction, err := c.usecase.RecordTransaction(ctx.Param("id"), transaction)
+		if err != nil {
+			status := errorStatus(err)
+			ctx.JSON(status, map[string]any{
+				"status": status,
+				"error":  err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, map[string]any{
+			"status":  http.StatusCreated,
+			"message": "success " + string(transactionType) + " consumable stock",
+			"data":    transaction,
+		})
+	}
+}
+
+func (c *ConsumableController) transactionsHandler(ctx *gin.Context) {
+	transactions, err := c.usecase.ShowTransactions(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show consumable transactions",
+		"data":    transactions,
+	})
+}
+
+func NewConsumableController(router *gin.Engine, consumableUsecase usecase.ConsumableUsecase) *ConsumableController {
+	controller := &ConsumableController{
+		router:  router,
+		usecase: consumableUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/consumable")
+	routerGroup.POST("/", controller.registerHandler)
+	routerGroup.GET("/", controller.listHandler)
+	routerGroup.GET("/low-stock", controller.lowStockHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.PUT("/:id", controller.updateHandler)
+	routerGroup.POST("/:id/receive", controller.transactionHandler(model.ConsumableReceive))
+	routerGroup.POST("/:id/issue", controller.transactionHandler(model.ConsumableIssue))
+	routerGroup.POST("/:id/adjust", controller.transactionHandler(model.ConsumableAdjust))
+	routerGroup.GET("/:id/transactions", controller.transactionsHandler)
+
+	return controller
+}
@@ -0,0 +1,140 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockConsumableUsecase struct {
+	mock.Mock
+}
+
+func (u *mockConsumableUsecase) RegisterConsumable(bodyRequest model.Consumable) (model.Consumable, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.Consumable), args.Error(1)
+}
+
+func (u *mockConsumableUsecase) UpdateConsumable(id string, bodyRequest model.Consumable) error {
+	args := u.Called(id, bodyRequest)
+	return args.Error(0)
+}
+
+func (u *mockConsumableUsecase) GetConsumable(id string) (dto.ConsumableDTO, error) {
+	args := u.Called(id)
+	return args.Get(0).(dto.ConsumableDTO), args.Error(1)
+}
+
+func (u *mockConsumableUsecase) ShowAllConsumables() ([]model.ConsumableLevel, error) {
+	args := u.Called()
+	return args.Get(0).([]model.ConsumableLevel), args.Error(1)
+}
+
+func (u *mockConsumableUsecase) ShowLowStock() ([]model.ConsumableLevel, error) {
+	args := u.Called()
+	return args.Get(0).([]model.ConsumableLevel), args.Error(1)
+}
+
+func (u *mockConsumableUsecase) RecordTransaction(consumableId string, bodyRequest model.ConsumableTransaction) (model.ConsumableTransaction, error) {
+	args := u.Called(consumableId, bodyRequest)
+	return args.Get(0).(model.ConsumableTransaction), args.Error(1)
+}
+
+func (u *mockConsumableUsecase) ShowTransactions(consumableId string) ([]model.ConsumableTransaction, error) {
+	args := u.Called(consumableId)
+	return args.Get(0).([]model.ConsumableTransaction), args.Error(1)
+}
+
+type ConsumableControllerSuite struct {
+	suite.Suite
+	router            *gin.Engine
+	consumableUsecase *mockConsumableUsecase
+}
+
+func (suite *ConsumableControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.consumableUsecase = new(mockConsumableUsecase)
+	controller.NewConsumableController(suite.router, suite.consumableUsecase)
+}
+
+func (suite *ConsumableControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *ConsumableControllerSuite) TestRegister_Invalid() {
+	suite.consumableUsecase.Mock.On("RegisterConsumable", mock.Anything).Return(model.Consumable{}, fmt.Errorf("unit of measure is required : %w", usecase.ErrInvalid))
+
+	response := suite.serve(http.MethodPost, "/api/v1/consumable/", `{"name":"Cable","unit":" "}`)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+	assert.Contains(suite.T(), response.Body.String(), "unit of measure is required")
+}
+
+func (suite *ConsumableControllerSuite) TestRegister_Fail() {
+	suite.consumableUsecase.Mock.On("RegisterConsumable", mock.Anything).Return(model.Consumable{}, errors.New("failed to register consumable : connection refused"))
+
+	response := suite.serve(http.MethodPost, "/api/v1/consumable/", `{"name":"Cable","unit":"pcs"}`)
+
+	assert.Equal(suite.T(), http.StatusInternalServerError, response.Code)
+}
+
+func (suite *ConsumableControllerSuite) TestUpdate_NotFound() {
+	suite.consumableUsecase.Mock.On("UpdateConsumable", "c9", mock.Anything).Return(fmt.Errorf("consumable with id c9 is %w", usecase.ErrNotFound))
+
+	response := suite.serve(http.MethodPut, "/api/v1/consumable/c9", `{"name":"Paper","unit":"sheet"}`)
+
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
+func (suite *ConsumableControllerSuite) TestTransaction_ErrorStatus() {
+	suite.consumableUsecase.Mock.On("RecordTransaction", "c1", mock.MatchedBy(func(transaction model.ConsumableTransaction) bool {
+		return transaction.Type == model.ConsumableAdjust
+	})).Return(model.ConsumableTransaction{}, fmt.Errorf("note is required for a stock adjustment : %w", usecase.ErrInvalid))
+	suite.consumableUsecase.Mock.On("RecordTransaction", "c9", mock.Anything).Return(model.ConsumableTransaction{}, fmt.Errorf("consumable with id c9 is %w", usecase.ErrNotFound))
+	suite.consumableUsecase.Mock.On("RecordTransaction", "c1", mock.MatchedBy(func(transaction model.ConsumableTransaction) bool {
+		return transaction.Type == model.ConsumableIssue
+	})).Return(model.ConsumableTransaction{}, fmt.Errorf("failed to issue stock : %w", usecase.ErrConflict))
+
+	cases := map[string]int{
+		"/api/v1/consumable/c1/adjust":  http.StatusBadRequest,
+		"/api/v1/consumable/c9/receive": http.StatusNotFound,
+		"/api/v1/consumable/c1/issue":   http.StatusConflict,
+	}
+	for path, status := range cases {
+		response := suite.serve(http.MethodPost, path, `{"locationId":"l1","qty":5}`)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func (suite *ConsumableControllerSuite) TestTransaction_Success() {
+	suite.consumableUsecase.Mock.On("RecordTransaction", "c1", mock.MatchedBy(func(transaction model.ConsumableTransaction) bool {
+		return transaction.Type == model.ConsumableReceive && transaction.Qty == 5
+	})).Return(model.ConsumableTransaction{Id: "t1", Delta: 5, Balance: 15}, nil)
+
+	response := suite.serve(http.MethodPost, "/api/v1/consumable/c1/receive", `{"locationId":"l1","qty":5}`)
+
+	assert.Equal(suite.T(), http.StatusCreated, response.Code)
+	assert.Contains(suite.T(), response.Body.String(), "success receive consumable stock")
+}
+
+func TestConsumableControllerSuite(t *testing.T) {
+	suite.Run(t, new(ConsumableControllerSuite))
+}
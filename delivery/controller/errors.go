@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/usecase"
+	"errors"
+	"net/http"
+)
+
+// errorStatus is the response status for an error returned by a usecase,
+// errors without a kind are failures of the server.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, usecase.ErrUnsupported):
+		return http.StatusUnsupportedMediaType
+	}
+
+	return http.StatusInternalServerError
+}
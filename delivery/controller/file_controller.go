@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FileController struct {
+	router  *gin.Engine
+	usecase usecase.FileUsecase
+}
+
+func (f *FileController) downloadHandler(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	object, err := f.usecase.OpenSignedFile(key, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+	defer object.Body.Close()
+
+	ctx.DataFromReader(http.StatusOK, object.Size, object.ContentType, object.Body, nil)
+}
+
+func NewFileController(router *gin.Engine, fileUsecase usecase.FileUsecase) *FileController {
+	controller := &FileController{
+		router:  router,
+		usecase: fileUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/file")
+	routerGroup.GET("/*key", controller.downloadHandler)
+
+	return controller
+}
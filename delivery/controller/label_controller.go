@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabelController struct {
+	router         *gin.Engine
+	usecase        usecase.LabelUsecase
+	trustedProxies []*net.IPNet
+}
+
+func (l *LabelController) unitHandler(ctx *gin.Context) {
+	request := dto.LabelRequestDTO{
+		AssetDetailIds: []string{ctx.Param("id")},
+		Symbology:      ctx.Query("symbology"),
+		Format:         ctx.Query("format"),
+		Content:        ctx.Query("content"),
+	}
+
+	l.render(ctx, request)
+}
+
+func (l *LabelController) batchHandler(ctx *gin.Context) {
+	var request dto.LabelRequestDTO
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	l.render(ctx, request)
+}
+
+func (l *LabelController) assetZPLHandler(ctx *gin.Context) {
+	file, err := l.usecase.RenderAssetZPL(ctx.Param("id"))
+	l.sendFile(ctx, file, err)
+}
+
+func (l *LabelController) locationZPLHandler(ctx *gin.Context) {
+	file, err := l.usecase.RenderLocationZPL(ctx.Param("id"))
+	l.sendFile(ctx, file, err)
+}
+
+func (l *LabelController) render(ctx *gin.Context, request dto.LabelRequestDTO) {
+	request.BaseUrl = l.requestBaseUrl(ctx)
+
+	file, err := l.usecase.RenderUnitLabels(request)
+	l.sendFile(ctx, file, err)
+}
+
+func (l *LabelController) sendFile(ctx *gin.Context, file dto.LabelFileDTO, err error) {
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s", file.FileName))
+	ctx.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// requestBaseUrl is the scheme and host the client used to reach the API,
+// so links printed on labels point back to this server. X-Forwarded-Proto
+// is only taken from a trusted proxy.
+func (l *LabelController) requestBaseUrl(ctx *gin.Context) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	forwarded := strings.ToLower(ctx.GetHeader("X-Forwarded-Proto"))
+	if (forwarded == "http" || forwarded == "https") && l.fromTrustedProxy(ctx) {
+		scheme = forwarded
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, ctx.Request.Host)
+}
+
+func (l *LabelController) fromTrustedProxy(ctx *gin.Context) bool {
+	ip := net.ParseIP(ctx.RemoteIP())
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range l.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxies reads proxy addresses and CIDR ranges, a bare address is a
+// range of one. Invalid entries are skipped, gin already refuses them at
+// start up.
+func parseProxies(proxies []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				continue
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func NewLabelController(router *gin.Engine, labelUsecase usecase.LabelUsecase, trustedProxies []string) *LabelController {
+	controller := &LabelController{
+		router:         router,
+		usecase:        labelUsecase,
+		trustedProxies: parseProxies(trustedProxies),
+	}
+
+	routerGroup := controller.router.Group("/api/v1/asset/label")
+	routerGroup.GET("/:id", controller.unitHandler)
+	routerGroup.POST("/", controller.batchHandler)
+	routerGroup.GET("/zpl/asset/:id", controller.assetZPLHandler)
+	routerGroup.GET("/zpl/location/:id", controller.locationZPLHandler)
+
+	return controller
+}
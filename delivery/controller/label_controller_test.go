@@ -0,0 +1,103 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockLabelUsecase struct {
+	mock.Mock
+}
+
+func (u *mockLabelUsecase) RenderUnitLabels(request dto.LabelRequestDTO) (dto.LabelFileDTO, error) {
+	args := u.Called(request)
+	return args.Get(0).(dto.LabelFileDTO), args.Error(1)
+}
+
+func (u *mockLabelUsecase) RenderAssetZPL(assetId string) (dto.LabelFileDTO, error) {
+	args := u.Called(assetId)
+	return args.Get(0).(dto.LabelFileDTO), args.Error(1)
+}
+
+func (u *mockLabelUsecase) RenderLocationZPL(locationId string) (dto.LabelFileDTO, error) {
+	args := u.Called(locationId)
+	return args.Get(0).(dto.LabelFileDTO), args.Error(1)
+}
+
+type LabelControllerSuite struct {
+	suite.Suite
+	router       *gin.Engine
+	labelUsecase *mockLabelUsecase
+}
+
+func (suite *LabelControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.labelUsecase = new(mockLabelUsecase)
+	controller.NewLabelController(suite.router, suite.labelUsecase, []string{"10.0.0.0/8"})
+}
+
+func (suite *LabelControllerSuite) withBaseUrl(baseUrl string) {
+	suite.labelUsecase.Mock.On("RenderUnitLabels", mock.MatchedBy(func(request dto.LabelRequestDTO) bool {
+		return request.BaseUrl == baseUrl
+	})).Return(dto.LabelFileDTO{FileName: "u1.png", ContentType: "image/png", Data: []byte("png")}, nil)
+}
+
+func (suite *LabelControllerSuite) TestForwardedProto_TrustedProxy() {
+	suite.withBaseUrl("https://asset.example.com")
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/v1/asset/label/u1?content=url", nil)
+	request.Host = "asset.example.com"
+	request.RemoteAddr = "10.1.2.3:41000"
+	request.Header.Set("X-Forwarded-Proto", "https")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+
+	assert.Equal(suite.T(), http.StatusOK, response.Code)
+}
+
+func (suite *LabelControllerSuite) TestForwardedProto_UntrustedClient() {
+	suite.withBaseUrl("http://asset.example.com")
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/v1/asset/label/u1?content=url", nil)
+	request.Host = "asset.example.com"
+	request.RemoteAddr = "203.0.113.7:41000"
+	request.Header.Set("X-Forwarded-Proto", "https")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+
+	assert.Equal(suite.T(), http.StatusOK, response.Code)
+}
+
+func (suite *LabelControllerSuite) TestErrorStatus() {
+	suite.labelUsecase.Mock.On("RenderAssetZPL", "a9").Return(dto.LabelFileDTO{}, fmt.Errorf("asset with id a9 is %w", usecase.ErrNotFound))
+	suite.labelUsecase.Mock.On("RenderUnitLabels", mock.Anything).Return(dto.LabelFileDTO{}, fmt.Errorf("unknown label format x : %w", usecase.ErrInvalid))
+
+	cases := map[string]int{
+		"/api/v1/asset/label/zpl/asset/a9": http.StatusNotFound,
+		"/api/v1/asset/label/u1?format=x":  http.StatusBadRequest,
+	}
+	for path, status := range cases {
+		request, _ := http.NewRequest(http.MethodGet, path, nil)
+		response := httptest.NewRecorder()
+
+		suite.router.ServeHTTP(response, request)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func TestLabelControllerSuite(t *testing.T) {
+	suite.Run(t, new(LabelControllerSuite))
+}
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceController struct {
+	router  *gin.Engine
+	usecase usecase.MaintenanceUsecase
+}
+
+func (m *MaintenanceController) openWorkOrderHandler(ctx *gin.Context) {
+	var workOrder model.WorkOrder
+	if err := ctx.ShouldBindJSON(&workOrder); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	workOrder, err := m.usecase.OpenWorkOrder(workOrder)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success open work order",
+		"data":    workOrder,
+	})
+}
+
+func (m *MaintenanceController) updateWorkOrderHandler(ctx *gin.Context) {
+	var request dto.WorkOrderUpdateDTO
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	workOrder, err := m.usecase.UpdateWorkOrder(ctx.Param("id"), request)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success update work order",
+		"data":    workOrder,
+	})
+}
+
+func (m *MaintenanceController) getWorkOrderHandler(ctx *gin.Context) {
+	workOrder, err := m.usecase.GetWorkOrder(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get work order",
+		"data":    workOrder,
+	})
+}
+
+func (m *MaintenanceController) listWorkOrderHandler(ctx *gin.Context) {
+	workOrders, err := m.usecase.ShowWorkOrders(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show work orders",
+		"data":    workOrders,
+	})
+}
+
+func (m *MaintenanceController) unitWorkOrderHandler(ctx *gin.Context) {
+	workOrders, err := m.usecase.ShowUnitWorkOrders(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show asset unit work orders",
+		"data":    workOrders,
+	})
+}
+
+func (m *MaintenanceController) createScheduleHandler(ctx *gin.Context) {
+	var schedule model.MaintenanceSchedule
+	if err := ctx.ShouldBindJSON(&schedule); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	schedule, err := m.usecase.CreateSchedule(schedule)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success create maintenance schedule",
+		"data":    schedule,
+	})
+}
+
+func (m *MaintenanceController) updateScheduleHandler(ctx *gin.Context) {
+	var schedule model.MaintenanceSchedule
+	if err := ctx.ShouldBindJSON(&schedule); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if err := m.usecase.UpdateSchedule(ctx.Param("id"), schedule); err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success update maintenance schedule",
+	})
+}
+
+func (m *MaintenanceController) getScheduleHandler(ctx *gin.Context) {
+	schedule, err := m.usecase.GetSchedule(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get maintenance schedule",
+		"data":    schedule,
+	})
+}
+
+func (m *MaintenanceController) listScheduleHandler(ctx *gin.Context) {
+	schedules, err := m.usecase.ShowSchedules()
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show maintenance schedules",
+		"data":    schedules,
+	})
+}
+
+func (m *MaintenanceController) runScheduleHandler(ctx *gin.Context) {
+	result, err := m.usecase.RunDueSchedules()
+	if err != nil {
+		// the schedules that did run are still reported
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+			"data":   result,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success run maintenance schedules",
+		"data":    result,
+	})
+}
+
+func NewMaintenanceController(router *gin.Engine, maintenanceUsecase usecase.MaintenanceUsecase) *MaintenanceController {
+	controller := &MaintenanceController{
+		router:  router,
+		usecase: maintenanceUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/maintenance")
+	routerGroup.POST("/work-order", controller.openWorkOrderHandler)
+	routerGroup.GET("/work-order", controller.listWorkOrderHandler)
+	routerGroup.GET("/work-order/unit/:id", controller.unitWorkOrderHandler)
+	routerGroup.GET("/work-order/:id", controller.getWorkOrderHandler)
+	routerGroup.PUT("/work-order/:id", controller.updateWorkOrderHandler)
+	routerGroup.POST("/schedule", controller.createScheduleHandler)
+	routerGroup.GET("/schedule", controller.listScheduleHandler)
+	routerGroup.POST("/schedule/run", controller.runScheduleHandler)
+	routerGroup.GET("/schedule/:id", controller.getScheduleHandler)
+	routerGroup.PUT("/schedule/:id", controller.updateScheduleHandler)
+
+	return controller
+}
@@ -0,0 +1,97 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockMaintenanceUsecase only answers the calls the tests below make.
+type mockMaintenanceUsecase struct {
+	mock.Mock
+	usecase.MaintenanceUsecase
+}
+
+func (u *mockMaintenanceUsecase) OpenWorkOrder(bodyRequest model.WorkOrder) (model.WorkOrder, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.WorkOrder), args.Error(1)
+}
+
+func (u *mockMaintenanceUsecase) UpdateWorkOrder(id string, bodyRequest dto.WorkOrderUpdateDTO) (model.WorkOrder, error) {
+	args := u.Called(id, bodyRequest)
+	return args.Get(0).(model.WorkOrder), args.Error(1)
+}
+
+type MaintenanceControllerSuite struct {
+	suite.Suite
+	router             *gin.Engine
+	maintenanceUsecase *mockMaintenanceUsecase
+}
+
+func (suite *MaintenanceControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.maintenanceUsecase = new(mockMaintenanceUsecase)
+	controller.NewMaintenanceController(suite.router, suite.maintenanceUsecase)
+}
+
+func (suite *MaintenanceControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *MaintenanceControllerSuite) TestOpenWorkOrder_ErrorStatus() {
+	suite.maintenanceUsecase.Mock.On("OpenWorkOrder", mock.MatchedBy(func(workOrder model.WorkOrder) bool {
+		return workOrder.AssetDetailId == "u9"
+	})).Return(model.WorkOrder{}, fmt.Errorf("asset unit with id u9 is not found : %w", usecase.ErrNotFound))
+	suite.maintenanceUsecase.Mock.On("OpenWorkOrder", mock.MatchedBy(func(workOrder model.WorkOrder) bool {
+		return workOrder.AssetDetailId == "u1"
+	})).Return(model.WorkOrder{}, fmt.Errorf("asset unit u1 is assigned and can't go into maintenance : %w", usecase.ErrConflict))
+	suite.maintenanceUsecase.Mock.On("OpenWorkOrder", mock.MatchedBy(func(workOrder model.WorkOrder) bool {
+		return workOrder.AssetDetailId == "u2"
+	})).Return(model.WorkOrder{}, fmt.Errorf("unknown work order type repair : %w", usecase.ErrInvalid))
+
+	cases := map[string]int{
+		"u9": http.StatusNotFound,
+		"u1": http.StatusConflict,
+		"u2": http.StatusBadRequest,
+	}
+	for unitId, status := range cases {
+		response := suite.serve(http.MethodPost, "/api/v1/maintenance/work-order", `{"assetDetailId":"`+unitId+`","description":"fan noise"}`)
+
+		assert.Equal(suite.T(), status, response.Code, unitId)
+	}
+}
+
+func (suite *MaintenanceControllerSuite) TestUpdateWorkOrder_ErrorStatus() {
+	suite.maintenanceUsecase.Mock.On("UpdateWorkOrder", "wo9", mock.Anything).Return(model.WorkOrder{}, fmt.Errorf("work order with id wo9 is not found : %w", usecase.ErrNotFound))
+	suite.maintenanceUsecase.Mock.On("UpdateWorkOrder", "wo1", mock.Anything).Return(model.WorkOrder{}, fmt.Errorf("work order wo1 is already completed : %w", usecase.ErrConflict))
+
+	cases := map[string]int{
+		"wo9": http.StatusNotFound,
+		"wo1": http.StatusConflict,
+	}
+	for id, status := range cases {
+		response := suite.serve(http.MethodPut, "/api/v1/maintenance/work-order/"+id, `{"status":"completed"}`)
+
+		assert.Equal(suite.T(), status, response.Code, id)
+	}
+}
+
+func TestMaintenanceControllerSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceControllerSuite))
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"asetku-bukan-asetmu/utils/common"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProcurementController struct {
+	router  *gin.Engine
+	usecase usecase.ProcurementUsecase
+}
+
+func (p *ProcurementController) createHandler(ctx *gin.Context) {
+	var transaction model.Transaction
+	transaction.Id = common.GenerateUUID()
+	err := ctx.ShouldBindJSON(&transaction)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if transaction.TransactionDate.IsZero() {
+		transaction.TransactionDate = time.Now()
+	}
+
+	transaction, err = p.usecase.CreateNewTransaction(transaction)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success register new transaction",
+		"data":    transaction,
+	})
+}
+
+func (p *ProcurementController) listHandler(ctx *gin.Context) {
+	transactions, err := p.usecase.ShowAllTransaction()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show all transactions",
+		"data":    transactions,
+	})
+}
+
+func (p *ProcurementController) getHandler(ctx *gin.Context) {
+	transaction, err := p.usecase.GetDetailTransaction(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status": "success",
+		"data":   transaction,
+	})
+}
+
+func (p *ProcurementController) receiveHandler(ctx *gin.Context) {
+	var receipts []model.GoodsReceipt
+	err := ctx.ShouldBindJSON(&receipts)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	receivedAt := time.Now()
+	for i := range receipts {
+		receipts[i].ReceivedAt = receivedAt
+	}
+
+	received, err := p.usecase.ReceiveGoods(ctx.Param("id"), receipts)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success receive goods",
+		"data":    received,
+	})
+}
+
+func (p *ProcurementController) listReceiptHandler(ctx *gin.Context) {
+	receipts, err := p.usecase.ShowAllReceipt(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show all goods receipts",
+		"data":    receipts,
+	})
+}
+
+func NewProcurementController(router *gin.Engine, procurementUsecase usecase.ProcurementUsecase) *ProcurementController {
+	controller := &ProcurementController{
+		router:  router,
+		usecase: procurementUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/procurement")
+	routerGroup.POST("/", controller.createHandler)
+	routerGroup.GET("/", controller.listHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.POST("/:id/receipt", controller.receiveHandler)
+	routerGroup.GET("/:id/receipt", controller.listReceiptHandler)
+
+	return controller
+}
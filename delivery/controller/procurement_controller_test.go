@@ -0,0 +1,83 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockProcurementUsecase only answers the calls the tests below make.
+type mockProcurementUsecase struct {
+	mock.Mock
+	usecase.ProcurementUsecase
+}
+
+func (u *mockProcurementUsecase) CreateNewTransaction(bodyRequest model.Transaction) (model.Transaction, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.Transaction), args.Error(1)
+}
+
+func (u *mockProcurementUsecase) ReceiveGoods(transactionId string, receipts []model.GoodsReceipt) ([]model.GoodsReceipt, error) {
+	args := u.Called(transactionId, receipts)
+	return args.Get(0).([]model.GoodsReceipt), args.Error(1)
+}
+
+type ProcurementControllerSuite struct {
+	suite.Suite
+	router             *gin.Engine
+	procurementUsecase *mockProcurementUsecase
+}
+
+func (suite *ProcurementControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.procurementUsecase = new(mockProcurementUsecase)
+	controller.NewProcurementController(suite.router, suite.procurementUsecase)
+}
+
+func (suite *ProcurementControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *ProcurementControllerSuite) TestCreate_UnknownVendor() {
+	suite.procurementUsecase.Mock.On("CreateNewTransaction", mock.Anything).Return(model.Transaction{}, fmt.Errorf("vendor with id v9 is not found : %w", usecase.ErrNotFound))
+
+	response := suite.serve(http.MethodPost, "/api/v1/procurement/", `{"vendorId":"v9","transactionDetail":[{"categoryId":"c1","name":"Laptop","unitPrice":100,"qty":1}]}`)
+
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
+func (suite *ProcurementControllerSuite) TestReceive_ErrorStatus() {
+	suite.procurementUsecase.Mock.On("ReceiveGoods", "t1", mock.Anything).Return([]model.GoodsReceipt(nil), fmt.Errorf("received qty for Laptop exceeds remaining qty 2 : %w", usecase.ErrInvalid))
+	suite.procurementUsecase.Mock.On("ReceiveGoods", "t2", mock.Anything).Return([]model.GoodsReceipt(nil), fmt.Errorf("failed to record goods receipt : %w", usecase.ErrConflict))
+	suite.procurementUsecase.Mock.On("ReceiveGoods", "t9", mock.Anything).Return([]model.GoodsReceipt(nil), fmt.Errorf("transaction with id t9 is not found : %w", usecase.ErrNotFound))
+
+	cases := map[string]int{
+		"/api/v1/procurement/t1/receipt": http.StatusBadRequest,
+		"/api/v1/procurement/t2/receipt": http.StatusConflict,
+		"/api/v1/procurement/t9/receipt": http.StatusNotFound,
+	}
+	for path, status := range cases {
+		response := suite.serve(http.MethodPost, path, `[{"transactionDetailId":"td1","locationId":"l1","qty":3}]`)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func TestProcurementControllerSuite(t *testing.T) {
+	suite.Run(t, new(ProcurementControllerSuite))
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	router  *gin.Engine
+	usecase usecase.ReportUsecase
+}
+
+func (r *ReportController) depreciationHandler(ctx *gin.Context) {
+	report, ok := r.depreciationReport(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success generate depreciation report",
+		"data":    report,
+	})
+}
+
+func (r *ReportController) depreciationCSVHandler(ctx *gin.Context) {
+	year, ok := fiscalYear(ctx)
+	if !ok {
+		return
+	}
+
+	content, err := r.usecase.DepreciationReportCSV(year)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=daftar-penyusutan-%d.csv", year))
+	ctx.Data(http.StatusOK, "text/csv", content)
+}
+
+func (r *ReportController) depreciationReport(ctx *gin.Context) (dto.DepreciationReportDTO, bool) {
+	year, ok := fiscalYear(ctx)
+	if !ok {
+		return dto.DepreciationReportDTO{}, false
+	}
+
+	report, err := r.usecase.DepreciationReport(year)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return dto.DepreciationReportDTO{}, false
+	}
+
+	return report, true
+}
+
+func fiscalYear(ctx *gin.Context) (int, bool) {
+	year, err := strconv.Atoi(ctx.Param("year"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"message": "fiscal year must be a number",
+		})
+		return 0, false
+	}
+
+	return year, true
+}
+
+func (r *ReportController) disposalHandler(ctx *gin.Context) {
+	from, to, ok := disposalPeriod(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := r.usecase.DisposalReport(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success generate disposal report",
+		"data":    report,
+	})
+}
+
+func (r *ReportController) disposalCSVHandler(ctx *gin.Context) {
+	from, to, ok := disposalPeriod(ctx)
+	if !ok {
+		return
+	}
+
+	content, err := r.usecase.DisposalReportCSV(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=disposal-%s-%s.csv", from.Format("20060102"), to.Format("20060102")))
+	ctx.Data(http.StatusOK, "text/csv", content)
+}
+
+// disposalPeriod reads the period from the from and to query, both
+// YYYY-MM-DD. It defaults to the current year up to today.
+func disposalPeriod(ctx *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	var err error
+	if value := ctx.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]any{
+				"message": "from must be a date formatted as YYYY-MM-DD",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if value := ctx.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]any{
+				"message": "to must be a date formatted as YYYY-MM-DD",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	return from, to, true
+}
+
+func NewReportController(router *gin.Engine, reportUsecase usecase.ReportUsecase) *ReportController {
+	controller := &ReportController{
+		router:  router,
+		usecase: reportUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/report")
+	routerGroup.GET("/depreciation/:year", controller.depreciationHandler)
+	routerGroup.GET("/depreciation/:year/csv", controller.depreciationCSVHandler)
+	routerGroup.GET("/disposal", controller.disposalHandler)
+	routerGroup.GET("/disposal/csv", controller.disposalCSVHandler)
+
+	return controller
+}
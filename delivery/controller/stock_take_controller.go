@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockTakeController struct {
+	router  *gin.Engine
+	usecase usecase.StockTakeUsecase
+}
+
+func (s *StockTakeController) startHandler(ctx *gin.Context) {
+	var stockTake model.StockTake
+	if err := ctx.ShouldBindJSON(&stockTake); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	stockTake, err := s.usecase.StartStockTake(stockTake)
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  "success",
+		"message": "success start stock take",
+		"data":    stockTake,
+	})
+}
+
+func (s *StockTakeController) listHandler(ctx *gin.Context) {
+	stockTakes, err := s.usecase.ShowStockTakes()
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success get stock takes",
+		"data":    stockTakes,
+	})
+}
+
+func (s *StockTakeController) getHandler(ctx *gin.Context) {
+	stockTake, err := s.usecase.GetStockTake(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success get stock take",
+		"data":    stockTake,
+	})
+}
+
+func (s *StockTakeController) scanHandler(ctx *gin.Context) {
+	var scan dto.StockTakeScanDTO
+	if err := ctx.ShouldBindJSON(&scan); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	result, err := s.usecase.SubmitScans(ctx.Param("id"), scan)
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success record scans",
+		"data":    result,
+	})
+}
+
+func (s *StockTakeController) reconciliationHandler(ctx *gin.Context) {
+	reconciliation, err := s.usecase.ReconcileStockTake(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success get stock take reconciliation",
+		"data":    reconciliation,
+	})
+}
+
+func (s *StockTakeController) closeHandler(ctx *gin.Context) {
+	var closeRequest model.StockTakeClose
+	// Body is optional, without it the session is closed as a report only
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&closeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]any{
+				"status": http.StatusBadRequest,
+				"error":  err.Error(),
+			})
+			return
+		}
+	}
+	closeRequest.StockTakeId = ctx.Param("id")
+	closeRequest.ClosedAt = time.Now()
+
+	reconciliation, err := s.usecase.CloseStockTake(closeRequest)
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"status":  "failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  "success",
+		"message": "success close stock take",
+		"data":    reconciliation,
+	})
+}
+
+func NewStockTakeController(router *gin.Engine, stockTakeUsecase usecase.StockTakeUsecase) *StockTakeController {
+	controller := &StockTakeController{
+		router:  router,
+		usecase: stockTakeUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/stock-take")
+	routerGroup.POST("/", controller.startHandler)
+	routerGroup.GET("/", controller.listHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.POST("/:id/scans", controller.scanHandler)
+	routerGroup.GET("/:id/reconciliation", controller.reconciliationHandler)
+	routerGroup.PUT("/:id/close", controller.closeHandler)
+
+	return controller
+}
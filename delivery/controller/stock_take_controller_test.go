@@ -0,0 +1,95 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockStockTakeUsecase only answers the calls the tests below make.
+type mockStockTakeUsecase struct {
+	mock.Mock
+	usecase.StockTakeUsecase
+}
+
+func (u *mockStockTakeUsecase) StartStockTake(bodyRequest model.StockTake) (model.StockTake, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.StockTake), args.Error(1)
+}
+
+func (u *mockStockTakeUsecase) SubmitScans(id string, bodyRequest dto.StockTakeScanDTO) (dto.StockTakeScanResultDTO, error) {
+	args := u.Called(id, bodyRequest)
+	return args.Get(0).(dto.StockTakeScanResultDTO), args.Error(1)
+}
+
+func (u *mockStockTakeUsecase) CloseStockTake(bodyRequest model.StockTakeClose) (dto.StockTakeReconciliationDTO, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(dto.StockTakeReconciliationDTO), args.Error(1)
+}
+
+type StockTakeControllerSuite struct {
+	suite.Suite
+	router           *gin.Engine
+	stockTakeUsecase *mockStockTakeUsecase
+}
+
+func (suite *StockTakeControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.stockTakeUsecase = new(mockStockTakeUsecase)
+	controller.NewStockTakeController(suite.router, suite.stockTakeUsecase)
+}
+
+func (suite *StockTakeControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *StockTakeControllerSuite) TestStart_UnknownLocation() {
+	suite.stockTakeUsecase.Mock.On("StartStockTake", mock.Anything).Return(model.StockTake{}, fmt.Errorf("location with id l9 is not found : %w", usecase.ErrNotFound))
+
+	response := suite.serve(http.MethodPost, "/api/v1/stock-take/", `{"locationIds":["l9"]}`)
+
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
+func (suite *StockTakeControllerSuite) TestScan_ErrorStatus() {
+	suite.stockTakeUsecase.Mock.On("SubmitScans", "st1", mock.Anything).Return(dto.StockTakeScanResultDTO{}, fmt.Errorf("stock take st1 is already closed : %w", usecase.ErrConflict))
+	suite.stockTakeUsecase.Mock.On("SubmitScans", "st2", mock.Anything).Return(dto.StockTakeScanResultDTO{}, fmt.Errorf("location l2 is not part of stock take st2 : %w", usecase.ErrInvalid))
+
+	cases := map[string]int{
+		"/api/v1/stock-take/st1/scans": http.StatusConflict,
+		"/api/v1/stock-take/st2/scans": http.StatusBadRequest,
+	}
+	for path, status := range cases {
+		response := suite.serve(http.MethodPost, path, `{"locationId":"l2","tags":["IT-0001"]}`)
+
+		assert.Equal(suite.T(), status, response.Code, path)
+	}
+}
+
+func (suite *StockTakeControllerSuite) TestClose_AlreadyClosed() {
+	suite.stockTakeUsecase.Mock.On("CloseStockTake", mock.Anything).Return(dto.StockTakeReconciliationDTO{}, fmt.Errorf("stock take st1 is already closed : %w", usecase.ErrConflict))
+
+	response := suite.serve(http.MethodPut, "/api/v1/stock-take/st1/close", "")
+
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+}
+
+func TestStockTakeControllerSuite(t *testing.T) {
+	suite.Run(t, new(StockTakeControllerSuite))
+}
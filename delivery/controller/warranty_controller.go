@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WarrantyController struct {
+	router  *gin.Engine
+	usecase usecase.WarrantyUsecase
+}
+
+func (w *WarrantyController) registerHandler(ctx *gin.Context) {
+	var warranty model.Warranty
+	if err := ctx.ShouldBindJSON(&warranty); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	warranty, err := w.usecase.RegisterWarranty(warranty)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success register warranty",
+		"data":    warranty,
+	})
+}
+
+func (w *WarrantyController) updateHandler(ctx *gin.Context) {
+	var warranty model.Warranty
+	if err := ctx.ShouldBindJSON(&warranty); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if err := w.usecase.UpdateWarranty(ctx.Param("id"), warranty); err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success update warranty",
+	})
+}
+
+func (w *WarrantyController) getHandler(ctx *gin.Context) {
+	warranty, err := w.usecase.GetWarranty(ctx.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success get warranty",
+		"data":    warranty,
+	})
+}
+
+func (w *WarrantyController) assetWarrantiesHandler(ctx *gin.Context) {
+	warranties, err := w.usecase.ShowAssetWarranties(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show asset warranties",
+		"data":    warranties,
+	})
+}
+
+func (w *WarrantyController) vendorWarrantiesHandler(ctx *gin.Context) {
+	warranties, err := w.usecase.ShowVendorWarranties(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show vendor warranties",
+		"data":    warranties,
+	})
+}
+
+func (w *WarrantyController) expiringHandler(ctx *gin.Context) {
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "30"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  "days must be a number",
+		})
+		return
+	}
+
+	warranties, err := w.usecase.ShowExpiringWarranties(days)
+	if err != nil {
+		ctx.JSON(errorStatus(err), map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success show expiring warranties",
+		"data":    warranties,
+	})
+}
+
+func (w *WarrantyController) fileClaimHandler(ctx *gin.Context) {
+	var claim model.WarrantyClaim
+	if err := ctx.ShouldBindJSON(&claim); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	claim, err := w.usecase.FileClaim(ctx.Param("id"), claim)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"status":  http.StatusCreated,
+		"message": "success file warranty claim",
+		"data":    claim,
+	})
+}
+
+func (w *WarrantyController) updateClaimHandler(ctx *gin.Context) {
+	var request dto.WarrantyClaimUpdateDTO
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]any{
+			"status": http.StatusBadRequest,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	claim, err := w.usecase.UpdateClaim(ctx.Param("claimId"), request)
+	if err != nil {
+		status := errorStatus(err)
+		ctx.JSON(status, map[string]any{
+			"status": status,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"status":  http.StatusOK,
+		"message": "success update warranty claim",
+		"data":    claim,
+	})
+}
+
+func NewWarrantyController(router *gin.Engine, warrantyUsecase usecase.WarrantyUsecase) *WarrantyController {
+	controller := &WarrantyController{
+		router:  router,
+		usecase: warrantyUsecase,
+	}
+
+	routerGroup := controller.router.Group("/api/v1/warranty")
+	routerGroup.POST("/", controller.registerHandler)
+	routerGroup.GET("/expiring", controller.expiringHandler)
+	routerGroup.PUT("/claims/:claimId", controller.updateClaimHandler)
+	routerGroup.GET("/asset/:id", controller.assetWarrantiesHandler)
+	routerGroup.GET("/vendor/:id", controller.vendorWarrantiesHandler)
+	routerGroup.GET("/:id", controller.getHandler)
+	routerGroup.PUT("/:id", controller.updateHandler)
+	routerGroup.POST("/:id/claims", controller.fileClaimHandler)
+
+	return controller
+}
@@ -0,0 +1,101 @@
+package controller_test
+
+import (
+	"asetku-bukan-asetmu/delivery/controller"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockWarrantyUsecase only answers the calls the tests below make.
+type mockWarrantyUsecase struct {
+	mock.Mock
+	usecase.WarrantyUsecase
+}
+
+func (u *mockWarrantyUsecase) RegisterWarranty(bodyRequest model.Warranty) (model.Warranty, error) {
+	args := u.Called(bodyRequest)
+	return args.Get(0).(model.Warranty), args.Error(1)
+}
+
+func (u *mockWarrantyUsecase) ShowExpiringWarranties(days int) ([]dto.WarrantyDTO, error) {
+	args := u.Called(days)
+	return args.Get(0).([]dto.WarrantyDTO), args.Error(1)
+}
+
+func (u *mockWarrantyUsecase) FileClaim(warrantyId string, bodyRequest model.WarrantyClaim) (model.WarrantyClaim, error) {
+	args := u.Called(warrantyId, bodyRequest)
+	return args.Get(0).(model.WarrantyClaim), args.Error(1)
+}
+
+type WarrantyControllerSuite struct {
+	suite.Suite
+	router          *gin.Engine
+	warrantyUsecase *mockWarrantyUsecase
+}
+
+func (suite *WarrantyControllerSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.warrantyUsecase = new(mockWarrantyUsecase)
+	controller.NewWarrantyController(suite.router, suite.warrantyUsecase)
+}
+
+func (suite *WarrantyControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(response, request)
+	return response
+}
+
+func (suite *WarrantyControllerSuite) TestRegister_ErrorStatus() {
+	suite.warrantyUsecase.Mock.On("RegisterWarranty", mock.MatchedBy(func(warranty model.Warranty) bool {
+		return warranty.VendorId == "v9"
+	})).Return(model.Warranty{}, fmt.Errorf("vendor with id v9 is not found : %w", usecase.ErrNotFound))
+	suite.warrantyUsecase.Mock.On("RegisterWarranty", mock.MatchedBy(func(warranty model.Warranty) bool {
+		return warranty.VendorId == "v1"
+	})).Return(model.Warranty{}, fmt.Errorf("warranty can't end before it starts : %w", usecase.ErrInvalid))
+
+	cases := map[string]int{
+		"v9": http.StatusNotFound,
+		"v1": http.StatusBadRequest,
+	}
+	for vendorId, status := range cases {
+		response := suite.serve(http.MethodPost, "/api/v1/warranty/", `{"assetId":"a1","vendorId":"`+vendorId+`","startDate":"2030-02-01T00:00:00Z","endDate":"2030-01-01T00:00:00Z"}`)
+
+		assert.Equal(suite.T(), status, response.Code, vendorId)
+	}
+}
+
+func (suite *WarrantyControllerSuite) TestExpiring_InvalidDays() {
+	suite.warrantyUsecase.Mock.On("ShowExpiringWarranties", 9999).Return([]dto.WarrantyDTO(nil), fmt.Errorf("days must be between 0 and 3650 : %w", usecase.ErrInvalid))
+
+	response := suite.serve(http.MethodGet, "/api/v1/warranty/expiring?days=9999", "")
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+
+	response = suite.serve(http.MethodGet, "/api/v1/warranty/expiring?days=soon", "")
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+}
+
+func (suite *WarrantyControllerSuite) TestFileClaim_Expired() {
+	suite.warrantyUsecase.Mock.On("FileClaim", "w1", mock.Anything).Return(model.WarrantyClaim{}, fmt.Errorf("warranty w1 is not in force today : %w", usecase.ErrConflict))
+
+	response := suite.serve(http.MethodPost, "/api/v1/warranty/w1/claims", `{"issue":"Screen broken"}`)
+
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+}
+
+func TestWarrantyControllerSuite(t *testing.T) {
+	suite.Run(t, new(WarrantyControllerSuite))
+}
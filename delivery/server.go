@@ -6,14 +6,17 @@ import (
 	"asetku-bukan-asetmu/manager"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type appServer struct {
-	usecaseManager manager.UseCaseManager
-	engine         *gin.Engine
-	host           string
+	usecaseManager   manager.UseCaseManager
+	engine           *gin.Engine
+	host             string
+	trustedProxies   []string
+	scheduleInterval time.Duration
 }
 
 func (a *appServer) initController() {
@@ -23,10 +26,44 @@ func (a *appServer) initController() {
 	controller.NewAssetLocationController(a.engine, a.usecaseManager.AssetLocationUsecase())
 	controller.NewAssetCategoriesController(a.engine, a.usecaseManager.AssetCategoriesUseCase())
 	controller.NewVendorController(a.engine, a.usecaseManager.VendorUseCase())
+	controller.NewAssetAssignmentController(a.engine, a.usecaseManager.AssetAssignmentUsecase())
+	controller.NewProcurementController(a.engine, a.usecaseManager.ProcurementUsecase())
+	controller.NewReportController(a.engine, a.usecaseManager.ReportUsecase())
+	controller.NewAssetMovementController(a.engine, a.usecaseManager.AssetMovementUsecase())
+	controller.NewLabelController(a.engine, a.usecaseManager.LabelUsecase(), a.trustedProxies)
+	controller.NewFileController(a.engine, a.usecaseManager.FileUsecase())
+	controller.NewAttachmentController(a.engine, a.usecaseManager.AssetAttachmentUsecase())
+	controller.NewStockTakeController(a.engine, a.usecaseManager.StockTakeUsecase())
+	controller.NewAssetReservationController(a.engine, a.usecaseManager.AssetReservationUsecase())
+	controller.NewWarrantyController(a.engine, a.usecaseManager.WarrantyUsecase())
+	controller.NewMaintenanceController(a.engine, a.usecaseManager.MaintenanceUsecase())
+	controller.NewAssetDisposalController(a.engine, a.usecaseManager.AssetDisposalUsecase())
+	controller.NewConsumableController(a.engine, a.usecaseManager.ConsumableUsecase())
+	controller.NewAssetTransferController(a.engine, a.usecaseManager.AssetTransferUsecase())
+}
+
+// runMaintenanceSchedules turns due preventive schedules into work orders
+// once at start and then every scheduleInterval.
+func (a *appServer) runMaintenanceSchedules() {
+	ticker := time.NewTicker(a.scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := a.usecaseManager.MaintenanceUsecase().RunDueSchedules()
+		if err != nil {
+			log.Println("Error Maintenance Schedule : ", err.Error())
+		}
+		if len(result.WorkOrders) > 0 || len(result.DeferredUnits) > 0 {
+			log.Printf("Maintenance Schedule : %d work orders opened and %d units deferred from %d schedules\n", len(result.WorkOrders), len(result.DeferredUnits), result.Schedules)
+		}
+
+		<-ticker.C
+	}
 }
 
 func (a *appServer) Run() {
 	a.initController()
+	go a.runMaintenanceSchedules()
 
 	err := a.engine.Run(a.host)
 	if err != nil {
@@ -42,18 +79,27 @@ func Server() *appServer {
 		log.Fatalln("Error Config : ()", err.Error())
 	}
 
+	// gin trusts every proxy by default, only the configured ones may forward
+	// the client address
+	err = engine.SetTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalln("Error Config : ", err.Error())
+	}
+
 	infraManager, err := manager.NewInfraManager(cfg)
 	if err != nil {
 		log.Fatalln("Error Conection : ", err.Error())
 	}
 
 	repoManager := manager.NewRepoManager(infraManager)
-	useCaseManager := manager.NewUseCaseManager(repoManager)
+	useCaseManager := manager.NewUseCaseManager(infraManager, repoManager)
 	host := fmt.Sprintf("%s:%s", cfg.APIHost, cfg.APIPort)
 
 	return &appServer{
-		engine:         engine,
-		host:           host,
-		usecaseManager: useCaseManager,
+		engine:           engine,
+		host:             host,
+		trustedProxies:   cfg.TrustedProxies,
+		usecaseManager:   useCaseManager,
+		scheduleInterval: cfg.ScheduleInterval,
 	}
 }
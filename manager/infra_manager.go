@@ -2,6 +2,7 @@ package manager
 
 import (
 	"asetku-bukan-asetmu/config"
+	"asetku-bukan-asetmu/utils/storage"
 	"database/sql"
 	"fmt"
 
@@ -10,11 +11,14 @@ import (
 
 type InfraManager interface {
 	Connection() *sql.DB
+	Config() *config.Config
+	Storage() storage.Storage
 }
 
 type infraManager struct {
-	db  *sql.DB
-	cfg *config.Config
+	db      *sql.DB
+	cfg     *config.Config
+	storage storage.Storage
 }
 
 func (i *infraManager) initDb() error {
@@ -39,10 +43,58 @@ func (i *infraManager) initDb() error {
 	return nil
 }
 
+// initStorage picks the upload backend. Local storage without FILE_PATH is
+// left unset, so uploads report that storage isn't configured.
+func (i *infraManager) initStorage() error {
+	var fileConf = i.cfg.FileConfig
+	switch fileConf.StorageDriver {
+	case "", "local":
+		if fileConf.FilePath == "" {
+			return nil
+		}
+
+		// Local files are served through signed links, a key made up at start
+		// would break every link on restart and differ between instances
+		if fileConf.SigningKey == "" {
+			return fmt.Errorf("STORAGE_SIGNING_KEY is required for local file storage")
+		}
+
+		local, err := storage.NewLocal(fileConf.FilePath, fileConf.SigningKey)
+		if err != nil {
+			return err
+		}
+		i.storage = local
+	case "s3":
+		s3, err := storage.NewS3(storage.S3Config{
+			Endpoint:  fileConf.S3Endpoint,
+			Region:    fileConf.S3Region,
+			Bucket:    fileConf.S3Bucket,
+			AccessKey: fileConf.S3AccessKey,
+			SecretKey: fileConf.S3SecretKey,
+		})
+		if err != nil {
+			return err
+		}
+		i.storage = s3
+	default:
+		return fmt.Errorf("unknown storage driver %s", fileConf.StorageDriver)
+	}
+
+	return nil
+}
+
 func (i *infraManager) Connection() *sql.DB {
 	return i.db
 }
 
+func (i *infraManager) Config() *config.Config {
+	return i.cfg
+}
+
+func (i *infraManager) Storage() storage.Storage {
+	return i.storage
+}
+
 func NewInfraManager(configParam *config.Config) (InfraManager, error) {
 	infra := &infraManager{
 		cfg: configParam,
@@ -53,5 +105,9 @@ func NewInfraManager(configParam *config.Config) (InfraManager, error) {
 		return nil, err
 	}
 
+	if err := infra.initStorage(); err != nil {
+		return nil, err
+	}
+
 	return infra, nil
 }
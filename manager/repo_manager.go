@@ -9,6 +9,17 @@ type RepoManager interface {
 	AssetCategoriesRepo() repository.AssetCategoriesRepository
 	AssetLocationRepo() repository.AssetLocationRepo
 	VendorRepo() repository.VendorRepository
+	AssetAssignmentRepo() repository.AssetAssignmentRepository
+	TransactionRepo() repository.TransactionRepository
+	AssetMovementRepo() repository.AssetMovementRepository
+	AssetAttachmentRepo() repository.AssetAttachmentRepository
+	StockTakeRepo() repository.StockTakeRepository
+	AssetReservationRepo() repository.AssetReservationRepository
+	WarrantyRepo() repository.WarrantyRepository
+	MaintenanceRepo() repository.MaintenanceRepository
+	AssetDisposalRepo() repository.AssetDisposalRepository
+	ConsumableRepo() repository.ConsumableRepository
+	AssetTransferRepo() repository.AssetTransferRepository
 }
 
 type repoManager struct {
@@ -39,6 +50,50 @@ func (r *repoManager) VendorRepo() repository.VendorRepository {
 	return repository.NewVendorRepository(r.infra.Connection())
 }
 
+func (r *repoManager) AssetAssignmentRepo() repository.AssetAssignmentRepository {
+	return repository.NewAssetAssignmentRepository(r.infra.Connection())
+}
+
+func (r *repoManager) TransactionRepo() repository.TransactionRepository {
+	return repository.NewTransactionRepository(r.infra.Connection())
+}
+
+func (r *repoManager) AssetMovementRepo() repository.AssetMovementRepository {
+	return repository.NewAssetMovementRepository(r.infra.Connection())
+}
+
+func (r *repoManager) AssetAttachmentRepo() repository.AssetAttachmentRepository {
+	return repository.NewAssetAttachmentRepository(r.infra.Connection())
+}
+
+func (r *repoManager) StockTakeRepo() repository.StockTakeRepository {
+	return repository.NewStockTakeRepository(r.infra.Connection())
+}
+
+func (r *repoManager) AssetReservationRepo() repository.AssetReservationRepository {
+	return repository.NewAssetReservationRepository(r.infra.Connection())
+}
+
+func (r *repoManager) WarrantyRepo() repository.WarrantyRepository {
+	return repository.NewWarrantyRepository(r.infra.Connection())
+}
+
+func (r *repoManager) MaintenanceRepo() repository.MaintenanceRepository {
+	return repository.NewMaintenanceRepository(r.infra.Connection())
+}
+
+func (r *repoManager) AssetDisposalRepo() repository.AssetDisposalRepository {
+	return repository.NewAssetDisposalRepository(r.infra.Connection())
+}
+
+func (r *repoManager) ConsumableRepo() repository.ConsumableRepository {
+	return repository.NewConsumableRepository(r.infra.Connection())
+}
+
+func (r *repoManager) AssetTransferRepo() repository.AssetTransferRepository {
+	return repository.NewAssetTransferRepository(r.infra.Connection())
+}
+
 func NewRepoManager(infraParam InfraManager) RepoManager {
 	return &repoManager{
 		infra: infraParam,
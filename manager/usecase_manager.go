@@ -11,9 +11,24 @@ type UseCaseManager interface {
 	AssetLocationUsecase() usecase.AssetLocationUsecase
 	AssetCategoriesUseCase() usecase.AssetCategoriesUseCase
 	VendorUseCase() usecase.VendorUsecase
+	AssetAssignmentUsecase() usecase.AssetAssignmentUsecase
+	ProcurementUsecase() usecase.ProcurementUsecase
+	ReportUsecase() usecase.ReportUsecase
+	AssetMovementUsecase() usecase.AssetMovementUsecase
+	LabelUsecase() usecase.LabelUsecase
+	FileUsecase() usecase.FileUsecase
+	AssetAttachmentUsecase() usecase.AssetAttachmentUsecase
+	StockTakeUsecase() usecase.StockTakeUsecase
+	AssetReservationUsecase() usecase.AssetReservationUsecase
+	WarrantyUsecase() usecase.WarrantyUsecase
+	MaintenanceUsecase() usecase.MaintenanceUsecase
+	AssetDisposalUsecase() usecase.AssetDisposalUsecase
+	ConsumableUsecase() usecase.ConsumableUsecase
+	AssetTransferUsecase() usecase.AssetTransferUsecase
 }
 
 type useCaseManager struct {
+	infra       InfraManager
 	repoManager RepoManager
 }
 
@@ -22,11 +37,11 @@ func (u *useCaseManager) TestUsecase() usecase.TestUsecase {
 }
 
 func (u *useCaseManager) EmployeeUseCase() usecase.EmployeeUseCase {
-	return usecase.NewEmployeeUseCase(u.repoManager.EmployeeRepo())
+	return usecase.NewEmployeeUseCase(u.repoManager.EmployeeRepo(), u.repoManager.AssetAssignmentRepo())
 }
 
 func (u *useCaseManager) AssetUsecase() usecase.AssetUsecase {
-	return usecase.NewAssetUsecase(u.repoManager.AssetRepo(), u.AssetLocationUsecase(), u.AssetCategoriesUseCase())
+	return usecase.NewAssetUsecase(u.repoManager.AssetRepo(), u.AssetLocationUsecase(), u.AssetCategoriesUseCase(), u.infra.Storage(), u.infra.Config().SignedURLExpiry)
 }
 
 func (u *useCaseManager) AssetCategoriesUseCase() usecase.AssetCategoriesUseCase {
@@ -34,15 +49,72 @@ func (u *useCaseManager) AssetCategoriesUseCase() usecase.AssetCategoriesUseCase
 }
 
 func (u *useCaseManager) AssetLocationUsecase() usecase.AssetLocationUsecase {
-	return usecase.NewAssetLocationUsecase(u.repoManager.AssetLocationRepo())
+	return usecase.NewAssetLocationUsecase(u.repoManager.AssetLocationRepo(), u.EmployeeUseCase())
 }
 
 func (u *useCaseManager) VendorUseCase() usecase.VendorUsecase {
 	return usecase.NewVendorUsecase(u.repoManager.VendorRepo())
 }
 
-func NewUseCaseManager(repo RepoManager) UseCaseManager {
+func (u *useCaseManager) AssetAssignmentUsecase() usecase.AssetAssignmentUsecase {
+	return usecase.NewAssetAssignmentUsecase(u.repoManager.AssetAssignmentRepo(), u.EmployeeUseCase(), u.AssetUsecase(), u.AssetLocationUsecase())
+}
+
+func (u *useCaseManager) ProcurementUsecase() usecase.ProcurementUsecase {
+	return usecase.NewProcurementUsecase(u.repoManager.TransactionRepo(), u.VendorUseCase(), u.AssetCategoriesUseCase(), u.AssetUsecase())
+}
+
+func (u *useCaseManager) ReportUsecase() usecase.ReportUsecase {
+	return usecase.NewReportUsecase(u.repoManager.AssetRepo(), u.repoManager.AssetDisposalRepo(), u.AssetCategoriesUseCase())
+}
+
+func (u *useCaseManager) AssetMovementUsecase() usecase.AssetMovementUsecase {
+	return usecase.NewAssetMovementUsecase(u.repoManager.AssetMovementRepo(), u.AssetLocationUsecase())
+}
+
+func (u *useCaseManager) LabelUsecase() usecase.LabelUsecase {
+	return usecase.NewLabelUsecase(u.repoManager.AssetRepo(), u.AssetCategoriesUseCase(), u.AssetLocationUsecase(), u.infra.Config().ZPLTemplatePath)
+}
+
+func (u *useCaseManager) FileUsecase() usecase.FileUsecase {
+	return usecase.NewFileUsecase(u.infra.Storage())
+}
+
+func (u *useCaseManager) AssetAttachmentUsecase() usecase.AssetAttachmentUsecase {
+	return usecase.NewAssetAttachmentUsecase(u.repoManager.AssetAttachmentRepo(), u.repoManager.AssetRepo(), u.infra.Storage())
+}
+
+func (u *useCaseManager) StockTakeUsecase() usecase.StockTakeUsecase {
+	return usecase.NewStockTakeUsecase(u.repoManager.StockTakeRepo(), u.repoManager.AssetRepo(), u.AssetLocationUsecase())
+}
+
+func (u *useCaseManager) AssetReservationUsecase() usecase.AssetReservationUsecase {
+	return usecase.NewAssetReservationUsecase(u.repoManager.AssetReservationRepo(), u.repoManager.AssetRepo(), u.EmployeeUseCase())
+}
+
+func (u *useCaseManager) WarrantyUsecase() usecase.WarrantyUsecase {
+	return usecase.NewWarrantyUsecase(u.repoManager.WarrantyRepo(), u.repoManager.AssetRepo(), u.repoManager.MaintenanceRepo(), u.VendorUseCase())
+}
+
+func (u *useCaseManager) MaintenanceUsecase() usecase.MaintenanceUsecase {
+	return usecase.NewMaintenanceUsecase(u.repoManager.MaintenanceRepo(), u.repoManager.AssetRepo(), u.AssetCategoriesUseCase(), u.VendorUseCase())
+}
+
+func (u *useCaseManager) AssetDisposalUsecase() usecase.AssetDisposalUsecase {
+	return usecase.NewAssetDisposalUsecase(u.repoManager.AssetDisposalRepo(), u.repoManager.AssetRepo(), u.AssetCategoriesUseCase())
+}
+
+func (u *useCaseManager) ConsumableUsecase() usecase.ConsumableUsecase {
+	return usecase.NewConsumableUsecase(u.repoManager.ConsumableRepo(), u.AssetLocationUsecase(), u.EmployeeUseCase())
+}
+
+func (u *useCaseManager) AssetTransferUsecase() usecase.AssetTransferUsecase {
+	return usecase.NewAssetTransferUsecase(u.repoManager.AssetTransferRepo(), u.repoManager.AssetRepo(), u.AssetLocationUsecase())
+}
+
+func NewUseCaseManager(infraParam InfraManager, repo RepoManager) UseCaseManager {
 	return &useCaseManager{
+		infra:       infraParam,
 		repoManager: repo,
 	}
 }
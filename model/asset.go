@@ -5,41 +5,121 @@ import "time"
 type Asset struct {
 	Id                  string    `json:"id" binding:"required"`
 	CategoryId          string    `json:"categoryId" binding:"required"`
-	TransactionDetailId any       `json:"transcationDetailId"`
+	TransactionDetailId *string   `json:"transactionDetailId"`
 	Name                string    `json:"name" binding:"required,max=100"`
 	Description         string    `json:"description"`
 	Qty                 int       `json:"qty" binding:"required"`
 	ImageUrl            string    `json:"imageUrl"`
+	Cost                float64   `json:"cost" binding:"gte=0"`
+	SalvageValue        float64   `json:"salvageValue" binding:"gte=0"`
+	UsefulLife          int       `json:"usefulLife" binding:"gte=0"`
 	LocationId          string    `json:"locationId" binding:"required"`
 	CreatedAt           time.Time `json:"createdAt" binding:"required"`
 	AssetDetail         []AssetDetail
+	// Attributes holds the values of the category attributes by name,
+	// AttributeValues is what gets stored once they are validated.
+	Attributes      map[string]any        `json:"attributes,omitempty"`
+	AttributeValues []AssetAttributeValue `json:"-"`
+	// ImageThumbnails is set once the thumbnails of an uploaded ImageUrl
+	// are stored, images from before thumbnails existed have none.
+	ImageThumbnails bool `json:"-"`
 }
 
 type AssetDetail struct {
-	Id         string `json:"id"`
-	AssetId    string `json:"assetId" binding:"required"`
-	LocationId string `json:"locationId" binding:"required"`
-	Status     int    `json:"status" binding:"required"`
-	UpdatedAt  any    `json:"updatedAt"`
-	RemovedAt  any    `json:"removedAt"`
+	Id         string      `json:"id"`
+	AssetId    string      `json:"assetId" binding:"required"`
+	LocationId string      `json:"locationId" binding:"required"`
+	Status     AssetStatus `json:"status" binding:"required"`
+	Tag        string      `json:"tag"`
+	UpdatedAt  any         `json:"updatedAt"`
+	RemovedAt  any         `json:"removedAt"`
 }
 
 type AssetPlacement struct {
-	Id            string    `json:"id"`
-	AsssetId      string    `json:"assetId" binding:"required"`
-	CurrentStatus int       `json:"currentStatus" binding:"required"`
-	TargetStatus  int       `json:"targetStatus" binding:"required"`
-	LocationId    string    `json:"locationId" binding:"required"`
-	Qty           int       `json:"qty" binding:"required"`
-	UpdatedAt     time.Time `json:"updateAt" binding:"required"`
+	Id             string      `json:"id"`
+	AsssetId       string      `json:"assetId" binding:"required"`
+	CurrentStatus  AssetStatus `json:"currentStatus" binding:"required"`
+	TargetStatus   AssetStatus `json:"targetStatus" binding:"required"`
+	LocationId     string      `json:"locationId" binding:"required"`
+	Qty            int         `json:"qty"`
+	AssetDetailIds []string    `json:"assetDetailIds"`
+	UpdatedAt      time.Time   `json:"updateAt" binding:"required"`
+	BatchId        string      `json:"batchId"`
+	Actor          string      `json:"actor" binding:"max=100"`
 }
 
+type AssetRetirement struct {
+	AssetId        string    `json:"assetId"`
+	AssetDetailIds []string  `json:"assetDetailIds" binding:"required,min=1"`
+	RemovedAt      time.Time `json:"removedAt"`
+	BatchId        string    `json:"batchId"`
+	Actor          string    `json:"actor" binding:"max=100"`
+}
+
+// AssetCategories may be nested under a parent category, Attributes are the
+// category's own custom fields without the inherited ones. On an update the
+// attributes sent are added or changed, existing ones are only dropped when
+// listed in RemovedAttributeIds.
 type AssetCategories struct {
-	Id   string `json:"id" binding:"required"`
-	Name string `json:"name" binding:"required,max=100"`
+	Id                  string              `json:"id" binding:"required"`
+	ParentId            *string             `json:"parentId,omitempty"`
+	Name                string              `json:"name" binding:"required,max=100"`
+	DepreciationMethod  string              `json:"depreciationMethod"`
+	UsefulLife          int                 `json:"usefulLife" binding:"gte=0"`
+	FiscalGroup         string              `json:"fiscalGroup"`
+	FiscalMethod        string              `json:"fiscalMethod"`
+	TagPrefix           string              `json:"tagPrefix" binding:"max=30"`
+	TagWithYear         bool                `json:"tagWithYear"`
+	TagPadding          int                 `json:"tagPadding" binding:"gte=0,lte=10"`
+	Attributes          []CategoryAttribute `json:"attributes,omitempty" binding:"dive"`
+	RemovedAttributeIds []string            `json:"removedAttributeIds,omitempty"`
 }
 
+// AssetLocation is one level of the site > building > floor > room tree,
+// top level sites have no ParentId. CustodianId is the employee who answers
+// for what arrives at the location. Storage marks the places units are
+// returned to when they are not in use.
 type AssetLocation struct {
-	Id   string `json:"id" binding:"required"`
-	Name string `json:"name" binding:"required,max=100"`
+	Id          string       `json:"id" binding:"required"`
+	Name        string       `json:"name" binding:"required,max=100"`
+	ParentId    *string      `json:"parentId,omitempty"`
+	Type        LocationType `json:"type,omitempty"`
+	Code        string       `json:"code,omitempty" binding:"max=30"`
+	CustodianId *string      `json:"custodianId,omitempty"`
+	Storage     bool         `json:"storage,omitempty"`
+}
+
+type LocationType string
+
+const (
+	LocationSite     LocationType = "site"
+	LocationBuilding LocationType = "building"
+	LocationFloor    LocationType = "floor"
+	LocationRoom     LocationType = "room"
+)
+
+var locationTypeLevels = map[LocationType]int{
+	LocationSite:     1,
+	LocationBuilding: 2,
+	LocationFloor:    3,
+	LocationRoom:     4,
+}
+
+func (t LocationType) IsValid() bool {
+	_, ok := locationTypeLevels[t]
+	return ok
+}
+
+// CanContain reports whether a location of this type can be the parent of
+// one of the child type, a parent always sits at a higher level. Levels may
+// be skipped, a site can hold rooms directly.
+func (t LocationType) CanContain(child LocationType) bool {
+	return t.IsValid() && child.IsValid() && locationTypeLevels[t] < locationTypeLevels[child]
+}
+
+// LocationUnitCount is a location with the number of active units placed
+// directly in it.
+type LocationUnitCount struct {
+	AssetLocation
+	Units int
 }
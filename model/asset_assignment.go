@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type AssetAssignment struct {
+	Id            string    `json:"id"`
+	AssetDetailId string    `json:"assetDetailId" binding:"required"`
+	AssetId       string    `json:"assetId"`
+	EmployeeId    string    `json:"employeeId" binding:"required"`
+	AssignedAt    time.Time `json:"assignedAt"`
+	DueAt         time.Time `json:"dueAt" binding:"required"`
+	ReturnedAt    any       `json:"returnedAt"`
+	Note          string    `json:"note"`
+	ReservationId *string   `json:"reservationId"`
+	Actor         string    `json:"actor" binding:"max=100"`
+}
+
+type AssetCheckin struct {
+	AssignmentId string    `json:"assignmentId"`
+	LocationId   string    `json:"locationId"`
+	ReturnedAt   time.Time `json:"returnedAt"`
+	Actor        string    `json:"actor" binding:"max=100"`
+}
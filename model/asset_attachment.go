@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type AttachmentType string
+
+const (
+	AttachmentInvoice  AttachmentType = "invoice"
+	AttachmentManual   AttachmentType = "manual"
+	AttachmentWarranty AttachmentType = "warranty"
+	AttachmentOther    AttachmentType = "other"
+)
+
+func (t AttachmentType) IsValid() bool {
+	switch t {
+	case AttachmentInvoice, AttachmentManual, AttachmentWarranty, AttachmentOther:
+		return true
+	}
+	return false
+}
+
+type AssetAttachment struct {
+	Id            string         `json:"id"`
+	AssetId       string         `json:"assetId"`
+	AssetDetailId *string        `json:"assetDetailId"`
+	Title         string         `json:"title"`
+	Type          AttachmentType `json:"type"`
+	FileName      string         `json:"fileName"`
+	ContentType   string         `json:"contentType"`
+	Size          int64          `json:"size"`
+	Checksum      string         `json:"checksum"`
+	StorageKey    string         `json:"-"`
+	UploadedBy    string         `json:"uploadedBy"`
+	UploadedAt    time.Time      `json:"uploadedAt"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type DisposalMethod string
+
+const (
+	DisposalSale     DisposalMethod = "sale"
+	DisposalDonation DisposalMethod = "donation"
+	DisposalScrap    DisposalMethod = "scrap"
+	DisposalTradeIn  DisposalMethod = "trade-in"
+)
+
+func (m DisposalMethod) IsValid() bool {
+	switch m {
+	case DisposalSale, DisposalDonation, DisposalScrap, DisposalTradeIn:
+		return true
+	}
+	return false
+}
+
+type DisposalStatus string
+
+const (
+	DisposalPending  DisposalStatus = "pending"
+	DisposalApproved DisposalStatus = "approved"
+	DisposalRejected DisposalStatus = "rejected"
+)
+
+// AssetDisposal takes one or more units of an asset out of service. It is
+// requested first and only disposes the units once approved, BookValue and
+// GainLoss are set at approval and cover all units together.
+type AssetDisposal struct {
+	Id             string         `json:"id"`
+	AssetId        string         `json:"assetId" binding:"required"`
+	AssetDetailIds []string       `json:"assetDetailIds" binding:"required,min=1"`
+	Method         DisposalMethod `json:"method" binding:"required"`
+	DisposedAt     time.Time      `json:"disposedAt" binding:"required"`
+	Proceeds       float64        `json:"proceeds" binding:"gte=0"`
+	Counterparty   string         `json:"counterparty" binding:"max=150"`
+	Reason         string         `json:"reason"`
+	Status         DisposalStatus `json:"status"`
+	RequestedBy    string         `json:"requestedBy" binding:"required,max=100"`
+	DecidedBy      string         `json:"decidedBy"`
+	DecidedAt      any            `json:"decidedAt"`
+	DecisionNote   string         `json:"decisionNote"`
+	BookValue      *float64       `json:"bookValue"`
+	GainLoss       *float64       `json:"gainLoss"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
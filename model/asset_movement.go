@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type AssetMovement struct {
+	Id             string      `json:"id"`
+	AssetDetailId  string      `json:"assetDetailId"`
+	AssetId        string      `json:"assetId"`
+	FromLocationId string      `json:"fromLocationId"`
+	ToLocationId   string      `json:"toLocationId"`
+	FromStatus     AssetStatus `json:"fromStatus"`
+	ToStatus       AssetStatus `json:"toStatus"`
+	BatchId        string      `json:"batchId"`
+	Actor          string      `json:"actor"`
+	MovedAt        time.Time   `json:"movedAt"`
+}
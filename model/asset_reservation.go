@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationFulfilled ReservationStatus = "fulfilled"
+	ReservationCancelled ReservationStatus = "cancelled"
+)
+
+// AssetReservation books Qty units of an asset, or the units listed in
+// AssetDetailIds, for an employee between StartAt and EndAt. Every checkout
+// the employee makes during the window consumes one unit of it.
+type AssetReservation struct {
+	Id             string            `json:"id"`
+	AssetId        string            `json:"assetId" binding:"required"`
+	EmployeeId     string            `json:"employeeId" binding:"required"`
+	Qty            int               `json:"qty" binding:"gte=0"`
+	AssetDetailIds []string          `json:"assetDetailIds"`
+	StartAt        time.Time         `json:"startAt" binding:"required"`
+	EndAt          time.Time         `json:"endAt" binding:"required"`
+	ConsumedQty    int               `json:"consumedQty"`
+	Status         ReservationStatus `json:"status"`
+	Note           string            `json:"note"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
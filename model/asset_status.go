@@ -0,0 +1,49 @@
+package model
+
+import "fmt"
+
+// AssetStatus is the lifecycle state of a single asset unit (asset_details row).
+type AssetStatus int
+
+const (
+	StatusInStorage AssetStatus = iota + 1
+	StatusPlaced
+	StatusAssigned
+	StatusInMaintenance
+	StatusInTransit
+	StatusLost
+	StatusDisposed
+)
+
+var assetStatusNames = map[AssetStatus]string{
+	StatusInStorage:     "in-storage",
+	StatusPlaced:        "placed",
+	StatusAssigned:      "assigned",
+	StatusInMaintenance: "in-maintenance",
+	StatusInTransit:     "in-transit",
+	StatusLost:          "lost",
+	StatusDisposed:      "disposed",
+}
+
+func (s AssetStatus) String() string {
+	if name, ok := assetStatusNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+func (s AssetStatus) IsValid() bool {
+	_, ok := assetStatusNames[s]
+	return ok
+}
+
+func ParseAssetStatus(name string) (AssetStatus, error) {
+	for status, statusName := range assetStatusNames {
+		if statusName == name {
+			return status, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown asset status %q", name)
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+type TransferStatus string
+
+const (
+	TransferPending           TransferStatus = "pending"
+	TransferApproved          TransferStatus = "approved"
+	TransferRejected          TransferStatus = "rejected"
+	TransferCancelled         TransferStatus = "cancelled"
+	TransferInTransit         TransferStatus = "in-transit"
+	TransferPartiallyReceived TransferStatus = "partially-received"
+	TransferReceived          TransferStatus = "received"
+)
+
+func (s TransferStatus) IsValid() bool {
+	switch s {
+	case TransferPending, TransferApproved, TransferRejected, TransferCancelled, TransferInTransit, TransferPartiallyReceived, TransferReceived:
+		return true
+	}
+	return false
+}
+
+// IsDispatched reports whether the units have left the source location.
+func (s TransferStatus) IsDispatched() bool {
+	return s == TransferInTransit || s == TransferPartiallyReceived || s == TransferReceived
+}
+
+type TransferUnitStatus string
+
+const (
+	TransferUnitPending   TransferUnitStatus = "pending"
+	TransferUnitInTransit TransferUnitStatus = "in-transit"
+	TransferUnitReceived  TransferUnitStatus = "received"
+	TransferUnitMissing   TransferUnitStatus = "missing"
+)
+
+// AssetTransfer moves units from one location to another. It is approved
+// first, dispatching it puts the units in-transit and the custodian of the
+// destination confirms what arrived, possibly over several receipts.
+// DecidedBy is whoever approved, rejected or cancelled it.
+type AssetTransfer struct {
+	Id                    string         `json:"id"`
+	SourceLocationId      string         `json:"sourceLocationId" binding:"required"`
+	DestinationLocationId string         `json:"destinationLocationId" binding:"required"`
+	AssetDetailIds        []string       `json:"assetDetailIds" binding:"required,min=1"`
+	Reason                string         `json:"reason"`
+	Status                TransferStatus `json:"status"`
+	RequestedBy           string         `json:"requestedBy" binding:"required,max=100"`
+	DecidedBy             string         `json:"decidedBy"`
+	DecidedAt             any            `json:"decidedAt"`
+	DispatchedBy          string         `json:"dispatchedBy"`
+	DispatchedAt          any            `json:"dispatchedAt"`
+	CompletedAt           any            `json:"completedAt"`
+	Note                  string         `json:"note"`
+	CreatedAt             time.Time      `json:"createdAt"`
+	Units                 []TransferUnit `json:"units,omitempty"`
+}
+
+// TransferUnit is one unit of a transfer. Units that never arrive are
+// recorded as missing, Discrepancy notes what was wrong with a unit on
+// receipt, like damage or a missing unit.
+type TransferUnit struct {
+	TransferId    string             `json:"transferId"`
+	AssetDetailId string             `json:"assetDetailId"`
+	AssetId       string             `json:"assetId"`
+	Status        TransferUnitStatus `json:"status"`
+	Discrepancy   string             `json:"discrepancy"`
+	ReceivedBy    *string            `json:"receivedBy"`
+	ReceivedAt    any                `json:"receivedAt"`
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type AttributeType string
+
+const (
+	AttributeText    AttributeType = "text"
+	AttributeNumber  AttributeType = "number"
+	AttributeDate    AttributeType = "date"
+	AttributeEnum    AttributeType = "enum"
+	AttributeBoolean AttributeType = "boolean"
+)
+
+// AttributeDateLayout is how date attributes are written and stored.
+const AttributeDateLayout = "2006-01-02"
+
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeText, AttributeNumber, AttributeDate, AttributeEnum, AttributeBoolean:
+		return true
+	}
+	return false
+}
+
+// CategoryAttribute is a custom field of a category, e.g. the RAM of a
+// laptop. Sub categories inherit the attributes of their parents. Options
+// lists the allowed values of an enum attribute.
+type CategoryAttribute struct {
+	Id         string        `json:"id"`
+	CategoryId string        `json:"categoryId"`
+	Name       string        `json:"name" binding:"required,max=50"`
+	Type       AttributeType `json:"type" binding:"required"`
+	Required   bool          `json:"required"`
+	Options    []string      `json:"options,omitempty"`
+}
+
+// Encode checks a value sent for the attribute and returns the text it is
+// stored as. Numbers and booleans come in as JSON numbers and booleans,
+// everything else as a string.
+func (a CategoryAttribute) Encode(value any) (string, error) {
+	switch a.Type {
+	case AttributeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return "", fmt.Errorf("attribute %s must be a number", a.Name)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case AttributeBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return "", fmt.Errorf("attribute %s must be true or false", a.Name)
+		}
+		return strconv.FormatBool(flag), nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("attribute %s must be a string", a.Name)
+	}
+
+	switch a.Type {
+	case AttributeDate:
+		if _, err := time.Parse(AttributeDateLayout, text); err != nil {
+			return "", fmt.Errorf("attribute %s must be a date in YYYY-MM-DD format", a.Name)
+		}
+	case AttributeEnum:
+		if !slices.Contains(a.Options, text) {
+			return "", fmt.Errorf("attribute %s must be one of %v", a.Name, a.Options)
+		}
+	}
+
+	return text, nil
+}
+
+// Decode turns a stored value back into the type it was sent as, values
+// that no longer parse are returned as they are.
+func (a CategoryAttribute) Decode(value string) any {
+	switch a.Type {
+	case AttributeNumber:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case AttributeBoolean:
+		if flag, err := strconv.ParseBool(value); err == nil {
+			return flag
+		}
+	}
+	return value
+}
+
+// AssetAttributeValue is the stored value of one category attribute of an
+// asset.
+type AssetAttributeValue struct {
+	AssetId     string
+	AttributeId string
+	Value       string
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Consumable is an item tracked by quantity instead of one row per piece,
+// like toner, cables or paper. Unit is the unit of measure its quantities
+// are counted in, ReorderPoint is the total stock it should not fall below.
+type Consumable struct {
+	Id           string    `json:"id"`
+	Name         string    `json:"name" binding:"required,max=100"`
+	Description  string    `json:"description"`
+	Unit         string    `json:"unit" binding:"required,max=20"`
+	ReorderPoint float64   `json:"reorderPoint" binding:"gte=0"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// ConsumableStock is the quantity of a consumable held at one location.
+type ConsumableStock struct {
+	ConsumableId string  `json:"consumableId"`
+	LocationId   string  `json:"locationId"`
+	Qty          float64 `json:"qty"`
+}
+
+// ConsumableLevel is a consumable with its stock summed over all
+// locations.
+type ConsumableLevel struct {
+	Consumable
+	TotalQty float64 `json:"totalQty"`
+}
+
+type ConsumableTransactionType string
+
+const (
+	ConsumableReceive ConsumableTransactionType = "receive"
+	ConsumableIssue   ConsumableTransactionType = "issue"
+	ConsumableAdjust  ConsumableTransactionType = "adjust"
+)
+
+func (t ConsumableTransactionType) IsValid() bool {
+	switch t {
+	case ConsumableReceive, ConsumableIssue, ConsumableAdjust:
+		return true
+	}
+	return false
+}
+
+// ConsumableTransaction changes the stock of a consumable at a location.
+// Receive and issue move Qty in and out, adjust sets the stock to the
+// counted Qty. Delta is the change that was applied and Balance the stock
+// left afterwards.
+type ConsumableTransaction struct {
+	Id           string                    `json:"id"`
+	ConsumableId string                    `json:"consumableId"`
+	LocationId   string                    `json:"locationId" binding:"required"`
+	Type         ConsumableTransactionType `json:"type"`
+	Qty          float64                   `json:"qty" binding:"gte=0"`
+	Delta        float64                   `json:"delta"`
+	Balance      float64                   `json:"balance"`
+	EmployeeId   *string                   `json:"employeeId"`
+	Note         string                    `json:"note"`
+	Actor        string                    `json:"actor" binding:"max=100"`
+	CreatedAt    time.Time                 `json:"createdAt"`
+}
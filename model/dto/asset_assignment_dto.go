@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"asetku-bukan-asetmu/model"
+	"time"
+)
+
+type AssetAssignmentDTO struct {
+	Id            string         `json:"id"`
+	AssetId       string         `json:"assetId"`
+	AssetName     string         `json:"assetName"`
+	AssetDetailId string         `json:"assetDetailId"`
+	Employee      model.Employee `json:"employee"`
+	AssignedAt    time.Time      `json:"assignedAt"`
+	DueAt         time.Time      `json:"dueAt"`
+	Overdue       bool           `json:"overdue"`
+	Note          string         `json:"note"`
+	ReservationId *string        `json:"reservationId"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// DisposalDecisionDTO approves or rejects a pending disposal.
+type DisposalDecisionDTO struct {
+	Actor string `json:"actor" binding:"required,max=100"`
+	Note  string `json:"note"`
+}
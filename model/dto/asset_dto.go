@@ -2,22 +2,79 @@ package dto
 
 import (
 	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/utils/depreciation"
 )
 
 type AssetDTO struct {
 	Id                  string                `json:"id"`
-	TransactionDetailId any                   `json:"transactionDetailId"`
+	TransactionDetailId *string               `json:"transactionDetailId"`
 	Name                string                `json:"name"`
 	Description         string                `json:"description"`
 	ImageUrl            string                `json:"imageUrl"`
+	Thumbnails          []AssetThumbnailDTO   `json:"thumbnails,omitempty"`
 	Qty                 int                   `json:"qty"`
 	Category            model.AssetCategories `json:"category"`
+	Cost                float64               `json:"cost"`
+	SalvageValue        float64               `json:"salvageValue"`
+	UsefulLife          int                   `json:"usefulLife"`
 	AssetDetail         []AssetDetailDTO      `json:"assetDetail"`
+	Attributes          map[string]any        `json:"attributes,omitempty"`
+	Depreciation        *AssetDepreciationDTO `json:"depreciation,omitempty"`
+}
+
+// AssetThumbnailDTO is a scaled down copy of the asset image, Size is its
+// longest side in pixels.
+type AssetThumbnailDTO struct {
+	Size int    `json:"size"`
+	Url  string `json:"url"`
+}
+
+type AssetDepreciationDTO struct {
+	Method         depreciation.Method  `json:"method"`
+	UsefulLife     int                  `json:"usefulLife"`
+	BookValue      float64              `json:"bookValue"`
+	TotalBookValue float64              `json:"totalBookValue"`
+	Schedule       []depreciation.Entry `json:"schedule"`
 }
 
 type AssetDetailDTO struct {
 	Id        string              `json:"id"`
-	Status    int                 `json:"status"`
+	Tag       string              `json:"tag"`
+	Status    string              `json:"status"`
 	Location  model.AssetLocation `json:"location"`
 	UpdatedAt any                 `json:"updatedAt"`
+	RemovedAt any                 `json:"removedAt"`
+}
+
+// AssetUnitDTO is a single unit together with the asset it belongs to.
+type AssetUnitDTO struct {
+	AssetId   string `json:"assetId"`
+	AssetName string `json:"assetName"`
+	AssetDetailDTO
+}
+
+// AssetUpdateDTO replaces the attribute values of the asset when Attributes
+// is sent, otherwise the stored ones are kept.
+type AssetUpdateDTO struct {
+	CategoryId   string         `json:"categoryId" binding:"required"`
+	Name         string         `json:"name" binding:"required,max=100"`
+	Description  string         `json:"description"`
+	ImageUrl     string         `json:"imageUrl"`
+	Cost         float64        `json:"cost" binding:"gte=0"`
+	SalvageValue float64        `json:"salvageValue" binding:"gte=0"`
+	UsefulLife   int            `json:"usefulLife" binding:"gte=0"`
+	Attributes   map[string]any `json:"attributes"`
+}
+
+// AssetPatchDTO only changes the attribute values named in Attributes, a
+// null value clears one.
+type AssetPatchDTO struct {
+	CategoryId   *string        `json:"categoryId"`
+	Name         *string        `json:"name" binding:"omitempty,max=100"`
+	Description  *string        `json:"description"`
+	ImageUrl     *string        `json:"imageUrl"`
+	Cost         *float64       `json:"cost" binding:"omitempty,gte=0"`
+	SalvageValue *float64       `json:"salvageValue" binding:"omitempty,gte=0"`
+	UsefulLife   *int           `json:"usefulLife" binding:"omitempty,gte=0"`
+	Attributes   map[string]any `json:"attributes"`
 }
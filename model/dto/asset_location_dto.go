@@ -0,0 +1,12 @@
+package dto
+
+import "asetku-bukan-asetmu/model"
+
+// LocationTreeDTO is a location with everything below it. Units are placed
+// directly in the location, TotalUnits includes its whole subtree.
+type LocationTreeDTO struct {
+	model.AssetLocation
+	Units      int               `json:"units"`
+	TotalUnits int               `json:"totalUnits"`
+	Children   []LocationTreeDTO `json:"children"`
+}
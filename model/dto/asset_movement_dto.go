@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type AssetMovementDTO struct {
+	Id             string    `json:"id"`
+	AssetDetailId  string    `json:"assetDetailId"`
+	AssetId        string    `json:"assetId"`
+	FromLocationId string    `json:"fromLocationId"`
+	ToLocationId   string    `json:"toLocationId"`
+	FromStatus     string    `json:"fromStatus"`
+	ToStatus       string    `json:"toStatus"`
+	BatchId        string    `json:"batchId"`
+	Actor          string    `json:"actor"`
+	MovedAt        time.Time `json:"movedAt"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// TransferDecisionDTO approves, rejects or cancels a transfer.
+type TransferDecisionDTO struct {
+	Actor string `json:"actor" binding:"required,max=100"`
+	Note  string `json:"note"`
+}
+
+type TransferDispatchDTO struct {
+	Actor string `json:"actor" binding:"required,max=100"`
+}
+
+// TransferReceiptDTO is what the custodian of the destination confirms.
+// Units left out stay in-transit for a later receipt.
+type TransferReceiptDTO struct {
+	CustodianId string                   `json:"custodianId" binding:"required"`
+	Units       []TransferReceiptUnitDTO `json:"units" binding:"required,min=1,dive"`
+}
+
+// TransferReceiptUnitDTO confirms one unit, Missing records that it never
+// arrived and Discrepancy what was wrong with it.
+type TransferReceiptUnitDTO struct {
+	AssetDetailId string `json:"assetDetailId" binding:"required"`
+	Missing       bool   `json:"missing"`
+	Discrepancy   string `json:"discrepancy"`
+}
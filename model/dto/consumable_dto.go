@@ -0,0 +1,16 @@
+package dto
+
+import "asetku-bukan-asetmu/model"
+
+// ConsumableDTO is a consumable with its stock at every location that ever
+// held it. LowStock is set once the total drops below the reorder point.
+type ConsumableDTO struct {
+	model.ConsumableLevel
+	LowStock bool                 `json:"lowStock"`
+	Stock    []ConsumableStockDTO `json:"stock"`
+}
+
+type ConsumableStockDTO struct {
+	Location model.AssetLocation `json:"location"`
+	Qty      float64             `json:"qty"`
+}
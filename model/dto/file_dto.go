@@ -0,0 +1,19 @@
+package dto
+
+import "io"
+
+// FileUploadDTO is an uploaded file handed from the controller to a usecase.
+type FileUploadDTO struct {
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
+
+type AttachmentUploadDTO struct {
+	AssetId       string
+	AssetDetailId string `form:"assetDetailId"`
+	Title         string `form:"title" binding:"required,max=150"`
+	Type          string `form:"type" binding:"required"`
+	UploadedBy    string `form:"uploadedBy" binding:"required,max=100"`
+	File          FileUploadDTO
+}
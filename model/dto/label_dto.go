@@ -0,0 +1,16 @@
+package dto
+
+type LabelRequestDTO struct {
+	AssetDetailIds []string `json:"assetDetailIds" binding:"required,min=1"`
+	Symbology      string   `json:"symbology"`
+	Format         string   `json:"format"`
+	Content        string   `json:"content"`
+	BaseUrl        string   `json:"-"`
+}
+
+// LabelFileDTO is a rendered label file ready to be sent to the client.
+type LabelFileDTO struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
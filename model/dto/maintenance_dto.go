@@ -0,0 +1,22 @@
+package dto
+
+import "asetku-bukan-asetmu/model"
+
+// WorkOrderUpdateDTO changes a work order, fields left out keep their value.
+// Moving it to completed or cancelled closes it.
+type WorkOrderUpdateDTO struct {
+	Status      model.WorkOrderStatus `json:"status" binding:"required"`
+	Description *string               `json:"description"`
+	Cost        *float64              `json:"cost" binding:"omitempty,gte=0"`
+	VendorId    *string               `json:"vendorId"`
+	Technician  *string               `json:"technician" binding:"omitempty,max=100"`
+	Actor       string                `json:"actor" binding:"max=100"`
+}
+
+// ScheduleRunDTO reports the work orders generated by due schedules and the
+// units deferred because they were busy.
+type ScheduleRunDTO struct {
+	Schedules     int               `json:"schedules"`
+	WorkOrders    []model.WorkOrder `json:"workOrders"`
+	DeferredUnits []string          `json:"deferredUnits"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+type DepreciationReportDTO struct {
+	FiscalYear             int                        `json:"fiscalYear"`
+	TotalCost              float64                    `json:"totalCost"`
+	TotalCommercialClosing float64                    `json:"totalCommercialClosing"`
+	TotalFiscalClosing     float64                    `json:"totalFiscalClosing"`
+	Rows                   []DepreciationReportRowDTO `json:"rows"`
+}
+
+// DepreciationReportRowDTO is one line of the daftar penyusutan, amounts are
+// for every active unit of the asset.
+type DepreciationReportRowDTO struct {
+	AssetId                string    `json:"assetId"`
+	AssetName              string    `json:"assetName"`
+	Category               string    `json:"category"`
+	FiscalGroup            string    `json:"fiscalGroup"`
+	AcquiredAt             time.Time `json:"acquiredAt"`
+	Qty                    int       `json:"qty"`
+	Cost                   float64   `json:"cost"`
+	CommercialMethod       string    `json:"commercialMethod"`
+	CommercialOpening      float64   `json:"commercialOpening"`
+	CommercialDepreciation float64   `json:"commercialDepreciation"`
+	CommercialClosing      float64   `json:"commercialClosing"`
+	FiscalMethod           string    `json:"fiscalMethod"`
+	FiscalOpening          float64   `json:"fiscalOpening"`
+	FiscalDepreciation     float64   `json:"fiscalDepreciation"`
+	FiscalClosing          float64   `json:"fiscalClosing"`
+}
+
+// DisposalReportDTO lists the approved disposals dated between From and To.
+// A positive GainLoss is a gain on disposal, a negative one a loss.
+type DisposalReportDTO struct {
+	From           time.Time              `json:"from"`
+	To             time.Time              `json:"to"`
+	TotalProceeds  float64                `json:"totalProceeds"`
+	TotalBookValue float64                `json:"totalBookValue"`
+	TotalGainLoss  float64                `json:"totalGainLoss"`
+	Rows           []DisposalReportRowDTO `json:"rows"`
+}
+
+type DisposalReportRowDTO struct {
+	DisposalId   string    `json:"disposalId"`
+	AssetId      string    `json:"assetId"`
+	AssetName    string    `json:"assetName"`
+	Category     string    `json:"category"`
+	Method       string    `json:"method"`
+	DisposedAt   time.Time `json:"disposedAt"`
+	Qty          int       `json:"qty"`
+	Counterparty string    `json:"counterparty"`
+	ApprovedBy   string    `json:"approvedBy"`
+	Proceeds     float64   `json:"proceeds"`
+	BookValue    float64   `json:"bookValue"`
+	GainLoss     float64   `json:"gainLoss"`
+}
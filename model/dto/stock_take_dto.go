@@ -0,0 +1,26 @@
+package dto
+
+import "asetku-bukan-asetmu/model"
+
+type StockTakeScanDTO struct {
+	LocationId string   `json:"locationId" binding:"required"`
+	Tags       []string `json:"tags" binding:"required,min=1,max=500"`
+	ScannedBy  string   `json:"scannedBy" binding:"max=100"`
+}
+
+// StockTakeScanResultDTO reports what happened to a submitted batch of tags.
+type StockTakeScanResultDTO struct {
+	Recorded    int      `json:"recorded"`
+	UnknownTags []string `json:"unknownTags"`
+}
+
+// StockTakeReconciliationDTO compares the units recorded at the session
+// locations with what was scanned. Open sessions get a preview computed from
+// the current data, closed sessions return what was stored when closing.
+type StockTakeReconciliationDTO struct {
+	StockTake     model.StockTake         `json:"stockTake"`
+	Found         []model.StockTakeResult `json:"found"`
+	Missing       []model.StockTakeResult `json:"missing"`
+	WrongLocation []model.StockTakeResult `json:"wrongLocation"`
+	UnknownTags   []string                `json:"unknownTags"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "asetku-bukan-asetmu/model"
+
+// WarrantyDTO is a warranty with the names it refers to. DaysLeft counts
+// whole days until the end date and is negative once the warranty expired.
+type WarrantyDTO struct {
+	model.Warranty
+	AssetName  string                `json:"assetName"`
+	VendorName string                `json:"vendorName"`
+	DaysLeft   int                   `json:"daysLeft"`
+	Expired    bool                  `json:"expired"`
+	Claims     []model.WarrantyClaim `json:"claims,omitempty"`
+}
+
+type WarrantyClaimUpdateDTO struct {
+	Status      model.WarrantyClaimStatus `json:"status" binding:"required"`
+	ClaimNumber *string                   `json:"claimNumber" binding:"omitempty,max=100"`
+	WorkOrderId *string                   `json:"workOrderId"`
+	Resolution  *string                   `json:"resolution"`
+}
@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+type WorkOrderStatus string
+
+const (
+	WorkOrderOpen       WorkOrderStatus = "open"
+	WorkOrderInProgress WorkOrderStatus = "in-progress"
+	WorkOrderCompleted  WorkOrderStatus = "completed"
+	WorkOrderCancelled  WorkOrderStatus = "cancelled"
+)
+
+// workOrderTransitions lists the statuses a work order may move to next,
+// completed and cancelled work orders are closed for good.
+var workOrderTransitions = map[WorkOrderStatus][]WorkOrderStatus{
+	WorkOrderOpen:       {WorkOrderInProgress, WorkOrderCompleted, WorkOrderCancelled},
+	WorkOrderInProgress: {WorkOrderCompleted, WorkOrderCancelled},
+}
+
+func (s WorkOrderStatus) IsValid() bool {
+	switch s {
+	case WorkOrderOpen, WorkOrderInProgress, WorkOrderCompleted, WorkOrderCancelled:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether the unit is still being worked on.
+func (s WorkOrderStatus) IsOpen() bool {
+	return s == WorkOrderOpen || s == WorkOrderInProgress
+}
+
+func (s WorkOrderStatus) CanMoveTo(next WorkOrderStatus) bool {
+	for _, allowed := range workOrderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type WorkOrderType string
+
+const (
+	WorkOrderCorrective WorkOrderType = "corrective"
+	WorkOrderPreventive WorkOrderType = "preventive"
+)
+
+// WorkOrder is maintenance work on one asset unit, done by a vendor or an
+// in-house technician. While it is open the unit is in-maintenance, closing
+// it puts the unit back in PriorStatus.
+type WorkOrder struct {
+	Id            string          `json:"id"`
+	AssetDetailId string          `json:"assetDetailId" binding:"required"`
+	AssetId       string          `json:"assetId"`
+	ScheduleId    *string         `json:"scheduleId"`
+	Type          WorkOrderType   `json:"type"`
+	Status        WorkOrderStatus `json:"status"`
+	Description   string          `json:"description" binding:"required"`
+	Cost          float64         `json:"cost" binding:"gte=0"`
+	VendorId      *string         `json:"vendorId"`
+	Technician    string          `json:"technician" binding:"max=100"`
+	PriorStatus   AssetStatus     `json:"-"`
+	Actor         string          `json:"actor" binding:"max=100"`
+	OpenedAt      time.Time       `json:"openedAt"`
+	ClosedAt      any             `json:"closedAt"`
+}
+
+// MaintenanceSchedule plans preventive work every IntervalDays for one asset
+// or for every asset of a category, exactly one of them is set.
+type MaintenanceSchedule struct {
+	Id           string    `json:"id"`
+	AssetId      *string   `json:"assetId"`
+	CategoryId   *string   `json:"categoryId"`
+	Description  string    `json:"description" binding:"required"`
+	IntervalDays int       `json:"intervalDays" binding:"required,gte=1"`
+	VendorId     *string   `json:"vendorId"`
+	Technician   string    `json:"technician" binding:"max=100"`
+	NextDueDate  time.Time `json:"nextDueDate" binding:"required"`
+	Active       bool      `json:"active"`
+	LastRunAt    any       `json:"lastRunAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
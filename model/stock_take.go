@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+type StockTakeStatus string
+
+const (
+	StockTakeOpen   StockTakeStatus = "open"
+	StockTakeClosed StockTakeStatus = "closed"
+)
+
+// StockTakeResultType is how a unit came out of the reconciliation.
+type StockTakeResultType string
+
+const (
+	StockTakeFound         StockTakeResultType = "found"
+	StockTakeMissing       StockTakeResultType = "missing"
+	StockTakeWrongLocation StockTakeResultType = "wrong-location"
+)
+
+// StockTakeAction is what closing the session did to a unit.
+type StockTakeAction string
+
+const (
+	StockTakeNoAction   StockTakeAction = ""
+	StockTakeRelocated  StockTakeAction = "relocated"
+	StockTakeMarkedLost StockTakeAction = "marked-lost"
+)
+
+type StockTake struct {
+	Id          string          `json:"id"`
+	LocationIds []string        `json:"locationIds" binding:"required,min=1"`
+	Note        string          `json:"note"`
+	Status      StockTakeStatus `json:"status"`
+	StartedBy   string          `json:"startedBy" binding:"max=100"`
+	StartedAt   time.Time       `json:"startedAt"`
+	ClosedBy    string          `json:"closedBy"`
+	ClosedAt    any             `json:"closedAt"`
+}
+
+// StockTakeScan is one tag read by a counter. Tags that don't match any unit
+// are kept with an empty AssetDetailId so they show up in the reconciliation.
+type StockTakeScan struct {
+	Id            string    `json:"id"`
+	StockTakeId   string    `json:"stockTakeId"`
+	Tag           string    `json:"tag"`
+	AssetDetailId *string   `json:"assetDetailId"`
+	LocationId    string    `json:"locationId"`
+	ScannedBy     string    `json:"scannedBy"`
+	ScannedAt     time.Time `json:"scannedAt"`
+}
+
+// StockTakeResult is the reconciliation line of one unit, stored when the
+// session is closed. ScannedLocationId is empty for missing units.
+type StockTakeResult struct {
+	StockTakeId        string              `json:"stockTakeId"`
+	AssetDetailId      string              `json:"assetDetailId"`
+	AssetId            string              `json:"assetId"`
+	Tag                string              `json:"tag"`
+	Result             StockTakeResultType `json:"result"`
+	Status             AssetStatus         `json:"status"`
+	ExpectedLocationId string              `json:"expectedLocationId"`
+	ScannedLocationId  string              `json:"scannedLocationId"`
+	Action             StockTakeAction     `json:"action"`
+}
+
+type StockTakeClose struct {
+	StockTakeId  string    `json:"stockTakeId"`
+	FixLocations bool      `json:"fixLocations"`
+	MarkLost     bool      `json:"markLost"`
+	Actor        string    `json:"actor" binding:"max=100"`
+	ClosedAt     time.Time `json:"closedAt"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type TransactionStatus string
+
+const (
+	TransactionOrdered           TransactionStatus = "ordered"
+	TransactionPartiallyReceived TransactionStatus = "partially-received"
+	TransactionReceived          TransactionStatus = "received"
+)
+
+type Transaction struct {
+	Id                string              `json:"id"`
+	VendorId          string              `json:"vendorId" binding:"required"`
+	InvoiceNumber     string              `json:"invoiceNumber" binding:"max=100"`
+	TransactionDate   time.Time           `json:"transactionDate"`
+	Status            TransactionStatus   `json:"status"`
+	TotalPrice        float64             `json:"totalPrice"`
+	TransactionDetail []TransactionDetail `json:"transactionDetail" binding:"required,min=1,dive"`
+}
+
+type TransactionDetail struct {
+	Id            string  `json:"id"`
+	TransactionId string  `json:"transactionId"`
+	CategoryId    string  `json:"categoryId" binding:"required"`
+	Name          string  `json:"name" binding:"required,max=100"`
+	Description   string  `json:"description"`
+	UnitPrice     float64 `json:"unitPrice" binding:"required,gt=0"`
+	Qty           int     `json:"qty" binding:"required,gt=0"`
+	ReceivedQty   int     `json:"receivedQty"`
+}
+
+type GoodsReceipt struct {
+	Id                  string    `json:"id"`
+	TransactionDetailId string    `json:"transactionDetailId" binding:"required"`
+	AssetId             string    `json:"assetId"`
+	LocationId          string    `json:"locationId" binding:"required"`
+	Qty                 int       `json:"qty" binding:"required,gt=0"`
+	ReceivedAt          time.Time `json:"receivedAt"`
+	// Attributes are passed on to the asset created for the receipt
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Warranty covers an asset, or a single unit when AssetDetailId is set,
+// between StartDate and EndDate. Claims go to the vendor through
+// ClaimContact.
+type Warranty struct {
+	Id            string    `json:"id"`
+	AssetId       string    `json:"assetId" binding:"required"`
+	AssetDetailId *string   `json:"assetDetailId"`
+	VendorId      string    `json:"vendorId" binding:"required"`
+	StartDate     time.Time `json:"startDate" binding:"required"`
+	EndDate       time.Time `json:"endDate" binding:"required"`
+	Coverage      string    `json:"coverage"`
+	ClaimContact  string    `json:"claimContact" binding:"max=150"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type WarrantyClaimStatus string
+
+const (
+	ClaimSubmitted WarrantyClaimStatus = "submitted"
+	ClaimApproved  WarrantyClaimStatus = "approved"
+	ClaimRejected  WarrantyClaimStatus = "rejected"
+	ClaimResolved  WarrantyClaimStatus = "resolved"
+)
+
+// warrantyClaimTransitions lists the statuses a claim may move to next,
+// rejected and resolved claims are final.
+var warrantyClaimTransitions = map[WarrantyClaimStatus][]WarrantyClaimStatus{
+	ClaimSubmitted: {ClaimApproved, ClaimRejected},
+	ClaimApproved:  {ClaimResolved},
+}
+
+func (s WarrantyClaimStatus) IsValid() bool {
+	switch s {
+	case ClaimSubmitted, ClaimApproved, ClaimRejected, ClaimResolved:
+		return true
+	}
+	return false
+}
+
+func (s WarrantyClaimStatus) CanMoveTo(next WarrantyClaimStatus) bool {
+	for _, allowed := range warrantyClaimTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// WarrantyClaim is a repair or replacement requested under a warranty.
+// WorkOrderId links it to the maintenance work on the unit.
+type WarrantyClaim struct {
+	Id            string              `json:"id"`
+	WarrantyId    string              `json:"warrantyId"`
+	AssetDetailId *string             `json:"assetDetailId"`
+	WorkOrderId   *string             `json:"workOrderId"`
+	Issue         string              `json:"issue" binding:"required"`
+	ClaimNumber   string              `json:"claimNumber" binding:"max=100"`
+	Status        WarrantyClaimStatus `json:"status"`
+	Resolution    string              `json:"resolution"`
+	ClaimedAt     time.Time           `json:"claimedAt"`
+	ResolvedAt    any                 `json:"resolvedAt"`
+}
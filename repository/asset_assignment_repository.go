@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+)
+
+type AssetAssignmentRepository interface {
+	Checkout(bodyRequest model.AssetAssignment, expected model.AssetDetail) (*string, error)
+	Checkin(bodyRequest model.AssetCheckin, unitId string) error
+	Get(id string) (model.AssetAssignment, error)
+	ListActiveByEmployee(employeeId string) ([]model.AssetAssignment, error)
+	ListActiveByAsset(assetId string) ([]model.AssetAssignment, error)
+	CountActiveByEmployee(employeeId string) (int, error)
+}
+
+type assetAssignmentRepository struct {
+	db *sql.DB
+}
+
+const assetAssignmentSelect = "SELECT aa.id,aa.asset_detail_id,ad.asset_id,aa.employee_id,aa.assigned_at,aa.due_at,aa.returned_at,aa.note,aa.reservation_id FROM asset_assignment aa JOIN asset_details ad ON ad.id=aa.asset_detail_id"
+
+// Checkout hands a unit out and returns the reservation it consumed, if any.
+// The unit must still be as the caller saw it when it was validated.
+func (a *assetAssignmentRepository) Checkout(bodyRequest model.AssetAssignment, expected model.AssetDetail) (*string, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	unit, err := lockUnit(tx, bodyRequest.AssetDetailId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-check under the row lock, a concurrent checkout, transfer or work
+	// order may have moved the unit since it was validated
+	if unit.Status != expected.Status || unit.RemovedAt != nil {
+		return nil, fmt.Errorf("asset unit %s %w, try again", unit.Id, ErrChanged)
+	}
+
+	reservationId, err := consumeReservation(tx, unit, bodyRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = moveUnit(tx, unit, model.AssetMovement{
+		ToLocationId: unit.LocationId,
+		ToStatus:     model.StatusAssigned,
+		BatchId:      bodyRequest.Id,
+		Actor:        bodyRequest.Actor,
+		MovedAt:      bodyRequest.AssignedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("INSERT INTO asset_assignment(id,asset_detail_id,employee_id,assigned_at,due_at,note,reservation_id) VALUES($1,$2,$3,$4,$5,$6,$7)", bodyRequest.Id, bodyRequest.AssetDetailId, bodyRequest.EmployeeId, bodyRequest.AssignedAt, bodyRequest.DueAt, bodyRequest.Note, reservationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservationId, nil
+}
+
+func (a *assetAssignmentRepository) Checkin(bodyRequest model.AssetCheckin, unitId string) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE asset_assignment SET returned_at=$1 WHERE id=$2 AND returned_at IS NULL", bodyRequest.ReturnedAt, bodyRequest.AssignmentId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("assignment %s %w, it is already returned", bodyRequest.AssignmentId, ErrChanged)
+	}
+
+	unit, err := lockUnit(tx, unitId)
+	if err != nil {
+		return err
+	}
+
+	err = moveUnit(tx, unit, model.AssetMovement{
+		ToLocationId: bodyRequest.LocationId,
+		ToStatus:     model.StatusInStorage,
+		BatchId:      bodyRequest.AssignmentId,
+		Actor:        bodyRequest.Actor,
+		MovedAt:      bodyRequest.ReturnedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *assetAssignmentRepository) Get(id string) (model.AssetAssignment, error) {
+	var assignment model.AssetAssignment
+	err := a.db.QueryRow(assetAssignmentSelect+" WHERE aa.id=$1", id).Scan(&assignment.Id, &assignment.AssetDetailId, &assignment.AssetId, &assignment.EmployeeId, &assignment.AssignedAt, &assignment.DueAt, &assignment.ReturnedAt, &assignment.Note, &assignment.ReservationId)
+	if err != nil {
+		return model.AssetAssignment{}, err
+	}
+
+	return assignment, nil
+}
+
+func (a *assetAssignmentRepository) ListActiveByEmployee(employeeId string) ([]model.AssetAssignment, error) {
+	return a.list(assetAssignmentSelect+" WHERE aa.employee_id=$1 AND aa.returned_at IS NULL ORDER BY aa.due_at", employeeId)
+}
+
+func (a *assetAssignmentRepository) ListActiveByAsset(assetId string) ([]model.AssetAssignment, error) {
+	return a.list(assetAssignmentSelect+" WHERE ad.asset_id=$1 AND aa.returned_at IS NULL ORDER BY aa.due_at", assetId)
+}
+
+func (a *assetAssignmentRepository) list(query string, args ...any) ([]model.AssetAssignment, error) {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []model.AssetAssignment
+	for rows.Next() {
+		var assignment model.AssetAssignment
+		err := rows.Scan(&assignment.Id, &assignment.AssetDetailId, &assignment.AssetId, &assignment.EmployeeId, &assignment.AssignedAt, &assignment.DueAt, &assignment.ReturnedAt, &assignment.Note, &assignment.ReservationId)
+		if err != nil {
+			return nil, err
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, nil
+}
+
+func (a *assetAssignmentRepository) CountActiveByEmployee(employeeId string) (int, error) {
+	var total int
+	err := a.db.QueryRow("SELECT count(*) FROM asset_assignment WHERE employee_id=$1 AND returned_at IS NULL", employeeId).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func NewAssetAssignmentRepository(db *sql.DB) AssetAssignmentRepository {
+	return &assetAssignmentRepository{
+		db: db,
+	}
+}
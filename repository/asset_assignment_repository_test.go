@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetAssignmentRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetAssignmentRepository
+}
+
+func (s *AssetAssignmentRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetAssignmentRepository(db)
+}
+
+func (s *AssetAssignmentRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *AssetAssignmentRepositorySuite) expectLockedUnit() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).AddRow("u1", "a1", "l1", model.StatusInStorage, nil, nil))
+	s.mock.ExpectQuery("SELECT id FROM asset WHERE id=\\$1 FOR UPDATE").WithArgs("a1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("a1"))
+}
+
+// inStorage is u1 as the usecase saw it before the checkout.
+var inStorage = model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}
+
+func (s *AssetAssignmentRepositorySuite) TestCheckout_ConsumesReservation() {
+	assignment := model.AssetAssignment{Id: "as1", AssetDetailId: "u1", EmployeeId: "e1", AssignedAt: at(9), DueAt: at(17)}
+	s.expectLockedUnit()
+	s.mock.ExpectQuery("SELECT r.id FROM asset_reservations r").WithArgs("a1", "e1", model.ReservationActive, at(9), "u1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("r1"))
+	s.mock.ExpectExec("UPDATE asset_reservations SET consumed_qty=consumed_qty\\+1").WithArgs(model.ReservationFulfilled, "r1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusAssigned, at(9), "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_assignment").WithArgs("as1", "u1", "e1", at(9), at(17), "", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	reservationId, err := s.repo.Checkout(assignment, inStorage)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "r1", *reservationId)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetAssignmentRepositorySuite) TestCheckout_UnitReservedByOther() {
+	assignment := model.AssetAssignment{Id: "as1", AssetDetailId: "u1", EmployeeId: "e2", AssignedAt: at(9), DueAt: at(17)}
+	s.expectLockedUnit()
+	s.mock.ExpectQuery("SELECT r.id FROM asset_reservations r").WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectQuery("SELECT r.end_at FROM asset_reservations r JOIN asset_reservation_units").WithArgs("u1", model.ReservationActive, at(9)).WillReturnRows(sqlmock.NewRows([]string{"end_at"}).AddRow(at(12)))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.Checkout(assignment, inStorage)
+	assert.ErrorIs(s.T(), err, repository.ErrReserved)
+	assert.ErrorContains(s.T(), err, "is reserved until")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetAssignmentRepositorySuite) TestCheckout_RemainingUnitsReserved() {
+	assignment := model.AssetAssignment{Id: "as1", AssetDetailId: "u1", EmployeeId: "e2", AssignedAt: at(9), DueAt: at(17)}
+	s.expectLockedUnit()
+	s.mock.ExpectQuery("SELECT r.id FROM asset_reservations r").WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectQuery("SELECT r.end_at FROM asset_reservations r JOIN asset_reservation_units").WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectQuery("SELECT COALESCE\\(SUM\\(qty-consumed_qty\\),0\\)").WithArgs("a1", model.ReservationActive, at(9)).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(2))
+	s.mock.ExpectQuery("SELECT count\\(\\*\\) FROM asset_details").WithArgs("a1", model.StatusInStorage, model.StatusPlaced).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.Checkout(assignment, inStorage)
+	assert.ErrorIs(s.T(), err, repository.ErrReserved)
+	assert.ErrorContains(s.T(), err, "remaining units of asset a1 are reserved")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetAssignmentRepositorySuite) TestCheckout_WithoutReservation() {
+	assignment := model.AssetAssignment{Id: "as1", AssetDetailId: "u1", EmployeeId: "e2", AssignedAt: at(9), DueAt: at(17)}
+	s.expectLockedUnit()
+	s.mock.ExpectQuery("SELECT r.id FROM asset_reservations r").WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectQuery("SELECT r.end_at FROM asset_reservations r JOIN asset_reservation_units").WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectQuery("SELECT COALESCE\\(SUM\\(qty-consumed_qty\\),0\\)").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+	s.mock.ExpectQuery("SELECT count\\(\\*\\) FROM asset_details").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_assignment").WithArgs("as1", "u1", "e2", at(9), at(17), "", (*string)(nil)).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	reservationId, err := s.repo.Checkout(assignment, inStorage)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), reservationId)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetAssignmentRepositorySuite) TestCheckout_UnitChanged() {
+	assignment := model.AssetAssignment{Id: "as1", AssetDetailId: "u1", EmployeeId: "e1", AssignedAt: at(9), DueAt: at(17)}
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).AddRow("u1", "a1", "l1", model.StatusInMaintenance, nil, nil))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.Checkout(assignment, inStorage)
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestAssetAssignmentRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetAssignmentRepositorySuite))
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+)
+
+type AssetAttachmentRepository interface {
+	Create(bodyRequest model.AssetAttachment) error
+	ListByAsset(assetId string) ([]model.AssetAttachment, error)
+	Get(id string) (model.AssetAttachment, error)
+	Delete(id string) error
+}
+
+type assetAttachmentRepository struct {
+	db *sql.DB
+}
+
+const assetAttachmentSelect = "SELECT id,asset_id,asset_detail_id,title,type,file_name,content_type,size,checksum,storage_key,uploaded_by,uploaded_at FROM asset_attachments"
+
+func (a *assetAttachmentRepository) Create(bodyRequest model.AssetAttachment) error {
+	_, err := a.db.Exec("INSERT INTO asset_attachments(id,asset_id,asset_detail_id,title,type,file_name,content_type,size,checksum,storage_key,uploaded_by,uploaded_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)", bodyRequest.Id, bodyRequest.AssetId, bodyRequest.AssetDetailId, bodyRequest.Title, bodyRequest.Type, bodyRequest.FileName, bodyRequest.ContentType, bodyRequest.Size, bodyRequest.Checksum, bodyRequest.StorageKey, bodyRequest.UploadedBy, bodyRequest.UploadedAt)
+	return err
+}
+
+func (a *assetAttachmentRepository) ListByAsset(assetId string) ([]model.AssetAttachment, error) {
+	rows, err := a.db.Query(assetAttachmentSelect+" WHERE asset_id=$1 ORDER BY uploaded_at DESC", assetId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []model.AssetAttachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (a *assetAttachmentRepository) Get(id string) (model.AssetAttachment, error) {
+	return scanAttachment(a.db.QueryRow(assetAttachmentSelect+" WHERE id=$1", id))
+}
+
+func (a *assetAttachmentRepository) Delete(id string) error {
+	_, err := a.db.Exec("DELETE FROM asset_attachments WHERE id=$1", id)
+	return err
+}
+
+func scanAttachment(row interface{ Scan(dest ...any) error }) (model.AssetAttachment, error) {
+	var attachment model.AssetAttachment
+	err := row.Scan(&attachment.Id, &attachment.AssetId, &attachment.AssetDetailId, &attachment.Title, &attachment.Type, &attachment.FileName, &attachment.ContentType, &attachment.Size, &attachment.Checksum, &attachment.StorageKey, &attachment.UploadedBy, &attachment.UploadedAt)
+	if err != nil {
+		return model.AssetAttachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func NewAssetAttachmentRepository(db *sql.DB) AssetAttachmentRepository {
+	return &assetAttachmentRepository{
+		db: db,
+	}
+}
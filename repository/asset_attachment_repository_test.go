@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var attachmentColumns = []string{"id", "asset_id", "asset_detail_id", "title", "type", "file_name", "content_type", "size", "checksum", "storage_key", "uploaded_by", "uploaded_at"}
+
+type AssetAttachmentRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetAttachmentRepository
+}
+
+func (s *AssetAttachmentRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetAttachmentRepository(db)
+}
+
+func (s *AssetAttachmentRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *AssetAttachmentRepositorySuite) TestCreate_Success() {
+	unitId := "u1"
+	attachment := model.AssetAttachment{
+		Id:            "f1",
+		AssetId:       "a1",
+		AssetDetailId: &unitId,
+		Title:         "Invoice",
+		Type:          model.AttachmentInvoice,
+		FileName:      "invoice.pdf",
+		ContentType:   "application/pdf",
+		Size:          1024,
+		Checksum:      "abc",
+		StorageKey:    "attachments/a1/f1.pdf",
+		UploadedBy:    "e1",
+		UploadedAt:    time.Now(),
+	}
+
+	s.mock.ExpectExec("INSERT INTO asset_attachments").
+		WithArgs("f1", "a1", &unitId, "Invoice", model.AttachmentInvoice, "invoice.pdf", "application/pdf", int64(1024), "abc", "attachments/a1/f1.pdf", "e1", attachment.UploadedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(s.T(), s.repo.Create(attachment))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetAttachmentRepositorySuite) TestListByAsset_Success() {
+	uploadedAt := time.Now()
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow("f2", "a1", "u1", "Manual", model.AttachmentManual, "manual.pdf", "application/pdf", 2048, "def", "attachments/a1/f2.pdf", "e1", uploadedAt).
+		AddRow("f1", "a1", nil, "Invoice", model.AttachmentInvoice, "invoice.pdf", "application/pdf", 1024, "abc", "attachments/a1/f1.pdf", "e1", uploadedAt)
+	s.mock.ExpectQuery("FROM asset_attachments WHERE asset_id=\\$1 ORDER BY uploaded_at DESC").WithArgs("a1").WillReturnRows(rows)
+
+	attachments, err := s.repo.ListByAsset("a1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), attachments, 2)
+	assert.Equal(s.T(), "u1", *attachments[0].AssetDetailId)
+	assert.Nil(s.T(), attachments[1].AssetDetailId)
+	assert.Equal(s.T(), int64(1024), attachments[1].Size)
+}
+
+func (s *AssetAttachmentRepositorySuite) TestListByAsset_Fail() {
+	s.mock.ExpectQuery("FROM asset_attachments").WillReturnError(errors.New("error"))
+
+	_, err := s.repo.ListByAsset("a1")
+	assert.Error(s.T(), err)
+}
+
+func (s *AssetAttachmentRepositorySuite) TestGet_Success() {
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow("f1", "a1", nil, "Invoice", model.AttachmentInvoice, "invoice.pdf", "application/pdf", 1024, "abc", "attachments/a1/f1.pdf", "e1", time.Now())
+	s.mock.ExpectQuery("FROM asset_attachments WHERE id=\\$1").WithArgs("f1").WillReturnRows(rows)
+
+	attachment, err := s.repo.Get("f1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "attachments/a1/f1.pdf", attachment.StorageKey)
+}
+
+func (s *AssetAttachmentRepositorySuite) TestGet_NotFound() {
+	s.mock.ExpectQuery("FROM asset_attachments WHERE id=\\$1").WithArgs("f9").WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.Get("f9")
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+}
+
+func (s *AssetAttachmentRepositorySuite) TestDelete_Success() {
+	s.mock.ExpectExec("DELETE FROM asset_attachments WHERE id=\\$1").WithArgs("f1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(s.T(), s.repo.Delete("f1"))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestAssetAttachmentRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetAttachmentRepositorySuite))
+}
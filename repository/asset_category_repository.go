@@ -4,10 +4,13 @@ import (
 	"asetku-bukan-asetmu/model"
 	"asetku-bukan-asetmu/utils/constant"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 type AssetCategoriesRepository interface {
 	BaseRepository[model.AssetCategories]
+	Attributes(categoryId string) ([]model.CategoryAttribute, error)
 	// BaseRepositoryPaging[model.AssetCategories]
 }
 
@@ -21,17 +24,28 @@ type assetcategoriesRepository struct {
 // }
 
 func (a *assetcategoriesRepository) Create(payload model.AssetCategories) error {
-	_, err := a.db.Exec(constant.ASSET_CATEGORIES_INSERT, payload.Id, payload.Name)
+	tx, err := a.db.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+
+	_, err = tx.Exec(constant.ASSET_CATEGORIES_INSERT, payload.Id, payload.Name, payload.DepreciationMethod, payload.UsefulLife, payload.FiscalGroup, payload.FiscalMethod, payload.TagPrefix, payload.TagWithYear, payload.TagPadding, payload.ParentId)
+	if err != nil {
+		return err
+	}
+
+	if err := saveAttributes(tx, payload); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (a *assetcategoriesRepository) Get(id string) (model.AssetCategories, error) {
 	var assetcategories model.AssetCategories
 	row := a.db.QueryRow(constant.ASSET_CATEGORIES_GET, id)
-	err := row.Scan(&assetcategories.Id, &assetcategories.Name)
+	err := row.Scan(&assetcategories.Id, &assetcategories.Name, &assetcategories.DepreciationMethod, &assetcategories.UsefulLife, &assetcategories.FiscalGroup, &assetcategories.FiscalMethod, &assetcategories.TagPrefix, &assetcategories.TagWithYear, &assetcategories.TagPadding, &assetcategories.ParentId)
 	if err != nil {
 		return model.AssetCategories{}, err
 	}
@@ -47,7 +61,7 @@ func (a *assetcategoriesRepository) List() ([]model.AssetCategories, error) {
 
 	for rows.Next() {
 		var assetcategories model.AssetCategories
-		err = rows.Scan(&assetcategories.Id, &assetcategories.Name)
+		err = rows.Scan(&assetcategories.Id, &assetcategories.Name, &assetcategories.DepreciationMethod, &assetcategories.UsefulLife, &assetcategories.FiscalGroup, &assetcategories.FiscalMethod, &assetcategories.TagPrefix, &assetcategories.TagWithYear, &assetcategories.TagPadding, &assetcategories.ParentId)
 		if err != nil {
 			panic(err)
 		}
@@ -58,11 +72,31 @@ func (a *assetcategoriesRepository) List() ([]model.AssetCategories, error) {
 }
 
 func (a *assetcategoriesRepository) Update(payload model.AssetCategories) error {
-	_, err := a.db.Exec(constant.ASSET_CATEGORIES_UPDATE, payload.Name, payload.Id)
+	tx, err := a.db.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+
+	_, err = tx.Exec(constant.ASSET_CATEGORIES_UPDATE, payload.Name, payload.DepreciationMethod, payload.UsefulLife, payload.FiscalGroup, payload.FiscalMethod, payload.TagPrefix, payload.TagWithYear, payload.TagPadding, payload.ParentId, payload.Id)
+	if err != nil {
+		return err
+	}
+
+	// Removed attributes are dropped together with the values assets hold
+	// for them, the ones left out of the payload stay as they are
+	if len(payload.RemovedAttributeIds) > 0 {
+		_, err = tx.Exec("DELETE FROM category_attributes WHERE category_id=$1 AND id = ANY($2)", payload.Id, pq.Array(payload.RemovedAttributeIds))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := saveAttributes(tx, payload); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (a *assetcategoriesRepository) Delete(id string) error {
@@ -73,6 +107,42 @@ func (a *assetcategoriesRepository) Delete(id string) error {
 	return nil
 }
 
+// Attributes returns the category's own attributes, inherited ones are
+// resolved by walking up the parents.
+func (a *assetcategoriesRepository) Attributes(categoryId string) ([]model.CategoryAttribute, error) {
+	rows, err := a.db.Query("SELECT id,category_id,name,type,required,options FROM category_attributes WHERE category_id=$1 ORDER BY name", categoryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attributes []model.CategoryAttribute
+	for rows.Next() {
+		var attribute model.CategoryAttribute
+		err := rows.Scan(&attribute.Id, &attribute.CategoryId, &attribute.Name, &attribute.Type, &attribute.Required, pq.Array(&attribute.Options))
+		if err != nil {
+			return nil, err
+		}
+
+		attributes = append(attributes, attribute)
+	}
+
+	return attributes, rows.Err()
+}
+
+func saveAttributes(tx *sql.Tx, payload model.AssetCategories) error {
+	for _, attribute := range payload.Attributes {
+		_, err := tx.Exec("INSERT INTO category_attributes(id,category_id,name,type,required,options) VALUES($1,$2,$3,$4,$5,$6) "+
+			"ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name, type=EXCLUDED.type, required=EXCLUDED.required, options=EXCLUDED.options",
+			attribute.Id, payload.Id, attribute.Name, attribute.Type, attribute.Required, pq.Array(attribute.Options))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func NewAssetCategoriesRepository(db *sql.DB) AssetCategoriesRepository {
 	return &assetcategoriesRepository{
 		db: db,
@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetCategoryRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetCategoriesRepository
+}
+
+func (s *AssetCategoryRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetCategoriesRepository(db)
+}
+
+func (s *AssetCategoryRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func laptopCategory() model.AssetCategories {
+	parentId := "it"
+	return model.AssetCategories{
+		Id:                 "laptop",
+		ParentId:           &parentId,
+		Name:               "Laptop",
+		DepreciationMethod: "straight-line",
+		FiscalMethod:       "straight-line",
+		TagPadding:         4,
+		Attributes: []model.CategoryAttribute{
+			{Id: "ram", Name: "ram", Type: model.AttributeNumber, Required: true},
+			{Id: "cpu", Name: "cpu", Type: model.AttributeEnum, Options: []string{"i5", "i7"}},
+		},
+	}
+}
+
+func (s *AssetCategoryRepositorySuite) TestCreate_WithAttributes() {
+	category := laptopCategory()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO asset_categories").WithArgs(category.Id, category.Name, category.DepreciationMethod, 0, "", category.FiscalMethod, "", false, 4, category.ParentId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("INSERT INTO category_attributes").WithArgs("ram", "laptop", "ram", model.AttributeNumber, true, pq.Array([]string(nil))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("INSERT INTO category_attributes").WithArgs("cpu", "laptop", "cpu", model.AttributeEnum, false, pq.Array([]string{"i5", "i7"})).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Create(category)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetCategoryRepositorySuite) TestCreate_AttributeFailRollsBack() {
+	category := laptopCategory()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO asset_categories").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("INSERT INTO category_attributes").WillReturnError(errors.New("duplicate key"))
+	s.mock.ExpectRollback()
+
+	err := s.repo.Create(category)
+	assert.Error(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetCategoryRepositorySuite) TestUpdate_KeepsOmittedAttributes() {
+	category := laptopCategory()
+	category.Attributes = nil
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("UPDATE asset_categories").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Update(category)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetCategoryRepositorySuite) TestUpdate_DropsRemovedAttributes() {
+	category := laptopCategory()
+	category.RemovedAttributeIds = []string{"gpu"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("UPDATE asset_categories").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("DELETE FROM category_attributes").WithArgs("laptop", pq.Array([]string{"gpu"})).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("INSERT INTO category_attributes").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("INSERT INTO category_attributes").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Update(category)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetCategoryRepositorySuite) TestAttributes_Success() {
+	rows := sqlmock.NewRows([]string{"id", "category_id", "name", "type", "required", "options"}).
+		AddRow("cpu", "laptop", "cpu", "enum", false, "{i5,i7}").
+		AddRow("ram", "laptop", "ram", "number", true, "{}")
+
+	s.mock.ExpectQuery("SELECT id,category_id,name,type,required,options FROM category_attributes").WithArgs("laptop").WillReturnRows(rows)
+
+	attributes, err := s.repo.Attributes("laptop")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), attributes, 2)
+	assert.Equal(s.T(), []string{"i5", "i7"}, attributes[0].Options)
+	assert.True(s.T(), attributes[1].Required)
+}
+
+func TestAssetCategoryRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetCategoryRepositorySuite))
+}
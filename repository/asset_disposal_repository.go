@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type AssetDisposalRepository interface {
+	Create(bodyRequest model.AssetDisposal) error
+	Get(id string) (model.AssetDisposal, error)
+	List(status model.DisposalStatus) ([]model.AssetDisposal, error)
+	ListApproved(from, to time.Time) ([]model.AssetDisposal, error)
+	Approve(bodyRequest model.AssetDisposal, units []model.AssetDetail) error
+	Reject(bodyRequest model.AssetDisposal) error
+}
+
+type assetDisposalRepository struct {
+	db *sql.DB
+}
+
+const assetDisposalSelect = "SELECT d.id,d.asset_id,d.method,d.disposed_at,d.proceeds,d.counterparty,d.reason,d.status,d.requested_by,d.decided_by,d.decided_at,d.decision_note,d.book_value,d.gain_loss,d.created_at,array_agg(du.asset_detail_id ORDER BY du.asset_detail_id) FROM asset_disposals d JOIN asset_disposal_units du ON du.disposal_id=d.id"
+
+func (a *assetDisposalRepository) Create(bodyRequest model.AssetDisposal) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO asset_disposals(id,asset_id,method,disposed_at,proceeds,counterparty,reason,status,requested_by,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)", bodyRequest.Id, bodyRequest.AssetId, bodyRequest.Method, bodyRequest.DisposedAt, bodyRequest.Proceeds, bodyRequest.Counterparty, bodyRequest.Reason, bodyRequest.Status, bodyRequest.RequestedBy, bodyRequest.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		_, err := tx.Exec("INSERT INTO asset_disposal_units(disposal_id,asset_detail_id) VALUES($1,$2)", bodyRequest.Id, unitId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *assetDisposalRepository) Get(id string) (model.AssetDisposal, error) {
+	return scanAssetDisposal(a.db.QueryRow(assetDisposalSelect+" WHERE d.id=$1 GROUP BY d.id", id))
+}
+
+// List lists disposals with the given status, or all of them when status is
+// empty, the newest first.
+func (a *assetDisposalRepository) List(status model.DisposalStatus) ([]model.AssetDisposal, error) {
+	if status == "" {
+		return a.list(assetDisposalSelect + " GROUP BY d.id ORDER BY d.created_at DESC")
+	}
+
+	return a.list(assetDisposalSelect+" WHERE d.status=$1 GROUP BY d.id ORDER BY d.created_at DESC", status)
+}
+
+// ListApproved lists approved disposals dated between from and to, both days
+// included, in date order.
+func (a *assetDisposalRepository) ListApproved(from, to time.Time) ([]model.AssetDisposal, error) {
+	return a.list(assetDisposalSelect+" WHERE d.status=$1 AND d.disposed_at BETWEEN $2 AND $3 GROUP BY d.id ORDER BY d.disposed_at,d.id", model.DisposalApproved, from, to)
+}
+
+// Approve disposes the units and records the decision in one transaction.
+// units are the rows the caller validated, when one of them changed since
+// nothing is disposed. Work orders still open on a unit are cancelled.
+func (a *assetDisposalRepository) Approve(bodyRequest model.AssetDisposal, units []model.AssetDetail) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPendingDisposal(tx, bodyRequest.Id); err != nil {
+		return err
+	}
+
+	for _, expected := range units {
+		unit, err := lockUnit(tx, expected.Id)
+		if err != nil {
+			return err
+		}
+
+		if unit.Status != expected.Status || unit.RemovedAt != nil {
+			return fmt.Errorf("asset unit %s %w, try again", unit.Id, ErrChanged)
+		}
+
+		err = moveUnit(tx, unit, model.AssetMovement{
+			ToLocationId: unit.LocationId,
+			ToStatus:     model.StatusDisposed,
+			BatchId:      bodyRequest.Id,
+			Actor:        bodyRequest.DecidedBy,
+			MovedAt:      bodyRequest.DisposedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("UPDATE asset_details SET removed_at=$1 WHERE id=$2", bodyRequest.DisposedAt, unit.Id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("UPDATE work_orders SET status=$1, closed_at=$2 WHERE asset_detail_id=$3 AND status IN ($4,$5)", model.WorkOrderCancelled, bodyRequest.DecidedAt, unit.Id, model.WorkOrderOpen, model.WorkOrderInProgress)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := syncAssetQty(tx, bodyRequest.AssetId); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE asset_disposals SET status=$1, decided_by=$2, decided_at=$3, decision_note=$4, book_value=$5, gain_loss=$6 WHERE id=$7", model.DisposalApproved, bodyRequest.DecidedBy, bodyRequest.DecidedAt, bodyRequest.DecisionNote, bodyRequest.BookValue, bodyRequest.GainLoss, bodyRequest.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *assetDisposalRepository) Reject(bodyRequest model.AssetDisposal) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPendingDisposal(tx, bodyRequest.Id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE asset_disposals SET status=$1, decided_by=$2, decided_at=$3, decision_note=$4 WHERE id=$5", model.DisposalRejected, bodyRequest.DecidedBy, bodyRequest.DecidedAt, bodyRequest.DecisionNote, bodyRequest.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *assetDisposalRepository) list(query string, args ...any) ([]model.AssetDisposal, error) {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disposals []model.AssetDisposal
+	for rows.Next() {
+		disposal, err := scanAssetDisposal(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		disposals = append(disposals, disposal)
+	}
+
+	return disposals, rows.Err()
+}
+
+// lockPendingDisposal locks a disposal until the transaction ends, so it is
+// decided only once.
+func lockPendingDisposal(tx *sql.Tx, id string) error {
+	var status model.DisposalStatus
+	err := tx.QueryRow("SELECT status FROM asset_disposals WHERE id=$1 FOR UPDATE", id).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	if status != model.DisposalPending {
+		return fmt.Errorf("disposal %s %w, it is already %s", id, ErrChanged, status)
+	}
+
+	return nil
+}
+
+func scanAssetDisposal(row interface{ Scan(dest ...any) error }) (model.AssetDisposal, error) {
+	var disposal model.AssetDisposal
+	var bookValue, gainLoss sql.NullFloat64
+	err := row.Scan(&disposal.Id, &disposal.AssetId, &disposal.Method, &disposal.DisposedAt, &disposal.Proceeds, &disposal.Counterparty, &disposal.Reason, &disposal.Status, &disposal.RequestedBy, &disposal.DecidedBy, &disposal.DecidedAt, &disposal.DecisionNote, &bookValue, &gainLoss, &disposal.CreatedAt, pq.Array(&disposal.AssetDetailIds))
+	if err != nil {
+		return model.AssetDisposal{}, err
+	}
+
+	if bookValue.Valid {
+		disposal.BookValue = &bookValue.Float64
+	}
+	if gainLoss.Valid {
+		disposal.GainLoss = &gainLoss.Float64
+	}
+
+	return disposal, nil
+}
+
+func NewAssetDisposalRepository(db *sql.DB) AssetDisposalRepository {
+	return &assetDisposalRepository{
+		db: db,
+	}
+}
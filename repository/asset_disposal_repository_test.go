@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetDisposalRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetDisposalRepository
+}
+
+func (s *AssetDisposalRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetDisposalRepository(db)
+}
+
+func (s *AssetDisposalRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *AssetDisposalRepositorySuite) approval() model.AssetDisposal {
+	bookValue, gainLoss := 2000.0, -500.0
+	return model.AssetDisposal{
+		Id:         "d1",
+		AssetId:    "a1",
+		DisposedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		DecidedBy:  "budi",
+		DecidedAt:  time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC),
+		BookValue:  &bookValue,
+		GainLoss:   &gainLoss,
+	}
+}
+
+func (s *AssetDisposalRepositorySuite) TestApprove_Success() {
+	disposal := s.approval()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM asset_disposals WHERE id=\\$1 FOR UPDATE").WithArgs("d1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.DisposalPending))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(unitRows("u1", "l1", model.StatusInMaintenance))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusDisposed, disposal.DisposedAt, "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), "u1", "a1", "l1", "l1", model.StatusInMaintenance, model.StatusDisposed, "d1", "budi", disposal.DisposedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_details SET removed_at").WithArgs(disposal.DisposedAt, "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE work_orders SET status").WithArgs(model.WorkOrderCancelled, disposal.DecidedAt, "u1", model.WorkOrderOpen, model.WorkOrderInProgress).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset SET qty").WithArgs("a1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_disposals SET status").WithArgs(model.DisposalApproved, "budi", disposal.DecidedAt, "", 2000.0, -500.0, "d1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Approve(disposal, []model.AssetDetail{{Id: "u1", AssetId: "a1", Status: model.StatusInMaintenance}})
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetDisposalRepositorySuite) TestApprove_UnitChanged() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM asset_disposals WHERE id=\\$1 FOR UPDATE").WithArgs("d1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.DisposalPending))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(unitRows("u1", "l1", model.StatusAssigned))
+	s.mock.ExpectRollback()
+
+	err := s.repo.Approve(s.approval(), []model.AssetDetail{{Id: "u1", AssetId: "a1", Status: model.StatusPlaced}})
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetDisposalRepositorySuite) TestReject_AlreadyDecided() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM asset_disposals WHERE id=\\$1 FOR UPDATE").WithArgs("d1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.DisposalApproved))
+	s.mock.ExpectRollback()
+
+	err := s.repo.Reject(s.approval())
+	assert.ErrorContains(s.T(), err, "already approved")
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestAssetDisposalRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetDisposalRepositorySuite))
+}
@@ -8,6 +8,9 @@ import (
 
 type AssetLocationRepo interface {
 	BaseRepository[model.AssetLocation]
+	Subtree(id string) ([]model.LocationUnitCount, error)
+	CountChildren(id string) (int, error)
+	CountUnits(id string) (int, error)
 }
 
 type assetLocationRepo struct {
@@ -15,7 +18,7 @@ type assetLocationRepo struct {
 }
 
 func (loc *assetLocationRepo) Create(bodyRequest model.AssetLocation) error {
-	_, err := loc.db.Exec(constant.ASSET_LOCATION_INSERT, bodyRequest.Id, bodyRequest.Name)
+	_, err := loc.db.Exec(constant.ASSET_LOCATION_INSERT, bodyRequest.Id, bodyRequest.Name, bodyRequest.ParentId, bodyRequest.Type, bodyRequest.Code, bodyRequest.CustodianId, bodyRequest.Storage)
 
 	if err != nil {
 		return err
@@ -33,7 +36,7 @@ func (loc *assetLocationRepo) List() ([]model.AssetLocation, error) {
 	var locations []model.AssetLocation
 	for rows.Next() {
 		var location model.AssetLocation
-		err = rows.Scan(&location.Id, &location.Name)
+		err = rows.Scan(&location.Id, &location.Name, &location.ParentId, &location.Type, &location.Code, &location.CustodianId, &location.Storage)
 
 		if err != nil {
 			return nil, err
@@ -51,6 +54,11 @@ func (loc *assetLocationRepo) Get(id string) (model.AssetLocation, error) {
 	err := loc.db.QueryRow(constant.ASSET_LOCATION_SEARCH, id).Scan(
 		&location.Id,
 		&location.Name,
+		&location.ParentId,
+		&location.Type,
+		&location.Code,
+		&location.CustodianId,
+		&location.Storage,
 	)
 
 	if err != nil {
@@ -61,7 +69,7 @@ func (loc *assetLocationRepo) Get(id string) (model.AssetLocation, error) {
 }
 
 func (loc *assetLocationRepo) Update(bodyRequest model.AssetLocation) error {
-	_, err := loc.db.Exec(constant.ASSET_LOCATION_UPDATE, bodyRequest.Id, bodyRequest.Name)
+	_, err := loc.db.Exec(constant.ASSET_LOCATION_UPDATE, bodyRequest.Id, bodyRequest.Name, bodyRequest.ParentId, bodyRequest.Type, bodyRequest.Code, bodyRequest.CustodianId, bodyRequest.Storage)
 	if err != nil {
 		return err
 	}
@@ -69,15 +77,65 @@ func (loc *assetLocationRepo) Update(bodyRequest model.AssetLocation) error {
 	return nil
 }
 
+// Delete removes a location, one that transfers or other records still point
+// at fails with ErrInUse.
 func (loc *assetLocationRepo) Delete(id string) error {
 	_, err := loc.db.Exec(constant.ASSET_LOCATION_DELETE, id)
 	if err != nil {
-		return err
+		return inUseError(err)
 	}
 
 	return nil
 }
 
+// Subtree walks down from a location, or from every top level location when
+// id is empty, and returns each location found with its direct unit count.
+// Parents always come before their children.
+func (loc *assetLocationRepo) Subtree(id string) ([]model.LocationUnitCount, error) {
+	root := "parent_id IS NULL"
+	args := []any{}
+	if id != "" {
+		root = "id=$1"
+		args = append(args, id)
+	}
+
+	rows, err := loc.db.Query("WITH RECURSIVE tree AS ("+
+		"SELECT id, name, parent_id, type, code, custodian_id, storage, 0 AS depth FROM asset_location WHERE "+root+
+		" UNION ALL SELECT l.id, l.name, l.parent_id, l.type, l.code, l.custodian_id, l.storage, t.depth+1 FROM asset_location l JOIN tree t ON l.parent_id=t.id"+
+		") SELECT t.id, t.name, t.parent_id, t.type, t.code, t.custodian_id, t.storage, (SELECT count(*) FROM asset_details d WHERE d.location_id=t.id AND d.removed_at IS NULL) FROM tree t ORDER BY t.depth, t.name", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locations []model.LocationUnitCount
+	for rows.Next() {
+		var location model.LocationUnitCount
+		err := rows.Scan(&location.Id, &location.Name, &location.ParentId, &location.Type, &location.Code, &location.CustodianId, &location.Storage, &location.Units)
+		if err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, location)
+	}
+
+	return locations, rows.Err()
+}
+
+func (loc *assetLocationRepo) CountChildren(id string) (int, error) {
+	var count int
+	err := loc.db.QueryRow("SELECT count(*) FROM asset_location WHERE parent_id=$1", id).Scan(&count)
+	return count, err
+}
+
+// CountUnits counts every unit recorded at the location, retired ones
+// included since their history still points there.
+func (loc *assetLocationRepo) CountUnits(id string) (int, error) {
+	var count int
+	err := loc.db.QueryRow("SELECT count(*) FROM asset_details WHERE location_id=$1", id).Scan(&count)
+	return count, err
+}
+
 func NewAssetLocationRepository(db *sql.DB) AssetLocationRepo {
 	return &assetLocationRepo{
 		db: db,
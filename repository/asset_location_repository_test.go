@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -35,9 +36,11 @@ func (loc *AssetLocationRepositorySuite) TestCreate_Success() {
 	bodyRequest := model.AssetLocation{
 		Id:   "1",
 		Name: "Location1",
+		Type: model.LocationRoom,
+		Code: "R-101",
 	}
 
-	loc.mock.ExpectExec("INSERT INTO asset_location").WithArgs(bodyRequest.Id, bodyRequest.Name).WillReturnResult(sqlmock.NewResult(1, 1))
+	loc.mock.ExpectExec("INSERT INTO asset_location").WithArgs(bodyRequest.Id, bodyRequest.Name, bodyRequest.ParentId, bodyRequest.Type, bodyRequest.Code, bodyRequest.CustodianId, bodyRequest.Storage).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := loc.repo.Create(bodyRequest)
 	assert.NoError(loc.T(), err)
@@ -55,11 +58,11 @@ func (loc *AssetLocationRepositorySuite) TestCreate_Fail() {
 }
 
 func (loc *AssetLocationRepositorySuite) TestList_Success() {
-	rows := sqlmock.NewRows([]string{"id", "name"}).
-		AddRow("1", "Location 1").
-		AddRow("2", "Location 2")
+	rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "type", "code", "custodian_id", "storage"}).
+		AddRow("1", "Location 1", nil, "site", "S-1", nil, false).
+		AddRow("2", "Location 2", "1", "building", "B-1", "e1", true)
 
-	loc.mock.ExpectQuery("SELECT id, name FROM asset_location").WillReturnRows(rows)
+	loc.mock.ExpectQuery("SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location").WillReturnRows(rows)
 
 	result, err := loc.repo.List()
 	assert.NoError(loc.T(), err)
@@ -67,7 +70,7 @@ func (loc *AssetLocationRepositorySuite) TestList_Success() {
 }
 
 func (loc *AssetLocationRepositorySuite) TestList_Fail() {
-	loc.mock.ExpectQuery("SELECT id, name FROM asset_location").WillReturnError(sql.ErrNoRows)
+	loc.mock.ExpectQuery("SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location").WillReturnError(sql.ErrNoRows)
 
 	result, err := loc.repo.List()
 	assert.Error(loc.T(), err)
@@ -79,7 +82,7 @@ func (loc *AssetLocationRepositorySuite) TestListScan_Fail() {
 		AddRow("1").
 		AddRow("2")
 
-	loc.mock.ExpectQuery("SELECT id, name FROM asset_location").WillReturnRows(rows)
+	loc.mock.ExpectQuery("SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location").WillReturnRows(rows)
 
 	_, err := loc.repo.List()
 	assert.Error(loc.T(), err)
@@ -87,10 +90,10 @@ func (loc *AssetLocationRepositorySuite) TestListScan_Fail() {
 
 func (loc *AssetLocationRepositorySuite) TestGet_Success() {
 	id := "1"
-	row := sqlmock.NewRows([]string{"id", "name"}).
-		AddRow("1", "Location 1")
+	row := sqlmock.NewRows([]string{"id", "name", "parent_id", "type", "code", "custodian_id", "storage"}).
+		AddRow("1", "Location 1", nil, "site", "S-1", nil, false)
 
-	loc.mock.ExpectQuery("SELECT id, name FROM asset_location WHERE id=?").WithArgs(id).WillReturnRows(row)
+	loc.mock.ExpectQuery("SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location WHERE id=?").WithArgs(id).WillReturnRows(row)
 
 	result, err := loc.repo.Get(id)
 	assert.NoError(loc.T(), err)
@@ -100,7 +103,7 @@ func (loc *AssetLocationRepositorySuite) TestGet_Success() {
 func (loc *AssetLocationRepositorySuite) TestGet_Fail() {
 	id := "1"
 
-	loc.mock.ExpectQuery("SELECT id, name FROM asset_location WHERE id=?").WithArgs(id).WillReturnError(sql.ErrNoRows)
+	loc.mock.ExpectQuery("SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location WHERE id=?").WithArgs(id).WillReturnError(sql.ErrNoRows)
 
 	result, err := loc.repo.Get(id)
 	assert.NoError(loc.T(), err)
@@ -111,9 +114,10 @@ func (loc *AssetLocationRepositorySuite) TestUpdate_Success() {
 	bodyRequest := model.AssetLocation{
 		Id:   "1",
 		Name: "Location 1",
+		Type: model.LocationRoom,
 	}
 
-	loc.mock.ExpectExec("UPDATE asset_location").WithArgs(bodyRequest.Id, bodyRequest.Name).WillReturnResult(sqlmock.NewResult(1, 1))
+	loc.mock.ExpectExec("UPDATE asset_location").WithArgs(bodyRequest.Id, bodyRequest.Name, bodyRequest.ParentId, bodyRequest.Type, bodyRequest.Code, bodyRequest.CustodianId, bodyRequest.Storage).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := loc.repo.Update(bodyRequest)
 	assert.NoError(loc.T(), err)
@@ -148,6 +152,48 @@ func (loc *AssetLocationRepositorySuite) TestDelete_Fail() {
 	assert.Error(loc.T(), err)
 }
 
+func (loc *AssetLocationRepositorySuite) TestDelete_InUse() {
+	loc.mock.ExpectExec("DELETE FROM asset_location WHERE id=?").WithArgs("1").WillReturnError(&pq.Error{Code: "23503", Table: "asset_transfers"})
+
+	err := loc.repo.Delete("1")
+	assert.ErrorIs(loc.T(), err, repository.ErrInUse)
+	assert.EqualError(loc.T(), err, "still in use by asset_transfers")
+}
+
+func (loc *AssetLocationRepositorySuite) TestSubtree_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "type", "code", "custodian_id", "storage", "count"}).
+		AddRow("1", "Site", nil, "site", "S-1", nil, false, 0).
+		AddRow("2", "Room", "1", "room", "R-1", "e1", true, 3)
+
+	loc.mock.ExpectQuery("WITH RECURSIVE tree AS").WithArgs("1").WillReturnRows(rows)
+
+	result, err := loc.repo.Subtree("1")
+	assert.NoError(loc.T(), err)
+	assert.Len(loc.T(), result, 2)
+	assert.Nil(loc.T(), result[0].ParentId)
+	assert.Equal(loc.T(), "1", *result[1].ParentId)
+	assert.Equal(loc.T(), 3, result[1].Units)
+}
+
+func (loc *AssetLocationRepositorySuite) TestSubtree_Roots() {
+	rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "type", "code", "custodian_id", "storage", "count"})
+
+	loc.mock.ExpectQuery("WHERE parent_id IS NULL").WithArgs().WillReturnRows(rows)
+
+	result, err := loc.repo.Subtree("")
+	assert.NoError(loc.T(), err)
+	assert.Len(loc.T(), result, 0)
+}
+
+func (loc *AssetLocationRepositorySuite) TestCountChildren_Success() {
+	loc.mock.ExpectQuery("SELECT count\\(\\*\\) FROM asset_location WHERE parent_id").WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := loc.repo.CountChildren("1")
+	assert.NoError(loc.T(), err)
+	assert.Equal(loc.T(), 2, count)
+}
+
 func TestAssetLocationRepositorySuite(t *testing.T) {
 	suite.Run(t, new(AssetLocationRepositorySuite))
 }
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/utils/common"
+	"database/sql"
+)
+
+type AssetMovementRepository interface {
+	ListByAsset(assetId string) ([]model.AssetMovement, error)
+	ListByUnit(assetDetailId string) ([]model.AssetMovement, error)
+	ListByLocation(locationId string) ([]model.AssetMovement, error)
+}
+
+type assetMovementRepository struct {
+	db *sql.DB
+}
+
+const assetMovementSelect = "SELECT id,asset_detail_id,asset_id,from_location_id,to_location_id,from_status,to_status,batch_id,actor,moved_at FROM asset_movements"
+
+func (a *assetMovementRepository) ListByAsset(assetId string) ([]model.AssetMovement, error) {
+	return a.list(assetMovementSelect+" WHERE asset_id=$1 ORDER BY moved_at DESC", assetId)
+}
+
+func (a *assetMovementRepository) ListByUnit(assetDetailId string) ([]model.AssetMovement, error) {
+	return a.list(assetMovementSelect+" WHERE asset_detail_id=$1 ORDER BY moved_at DESC", assetDetailId)
+}
+
+func (a *assetMovementRepository) ListByLocation(locationId string) ([]model.AssetMovement, error) {
+	return a.list(assetMovementSelect+" WHERE from_location_id=$1 OR to_location_id=$1 ORDER BY moved_at DESC", locationId)
+}
+
+func (a *assetMovementRepository) list(query string, args ...any) ([]model.AssetMovement, error) {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []model.AssetMovement
+	for rows.Next() {
+		var movement model.AssetMovement
+		err := rows.Scan(&movement.Id, &movement.AssetDetailId, &movement.AssetId, &movement.FromLocationId, &movement.ToLocationId, &movement.FromStatus, &movement.ToStatus, &movement.BatchId, &movement.Actor, &movement.MovedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// lockUnit reads one unit and holds its row lock until the transaction ends.
+func lockUnit(tx *sql.Tx, id string) (model.AssetDetail, error) {
+	var unit model.AssetDetail
+	err := tx.QueryRow("SELECT id,asset_id,location_id,status,updated_at,removed_at FROM asset_details WHERE id=$1 FOR UPDATE", id).Scan(&unit.Id, &unit.AssetId, &unit.LocationId, &unit.Status, &unit.UpdatedAt, &unit.RemovedAt)
+	if err != nil {
+		return model.AssetDetail{}, err
+	}
+
+	return unit, nil
+}
+
+// moveUnit changes the location and status of a locked unit and appends the
+// movement to its history inside the caller's transaction.
+func moveUnit(tx *sql.Tx, unit model.AssetDetail, movement model.AssetMovement) error {
+	movement.AssetDetailId = unit.Id
+	movement.AssetId = unit.AssetId
+	movement.FromLocationId = unit.LocationId
+	movement.FromStatus = unit.Status
+
+	_, err := tx.Exec("UPDATE asset_details SET location_id=$1, status=$2, updated_at=$3 WHERE id=$4", movement.ToLocationId, movement.ToStatus, movement.MovedAt, unit.Id)
+	if err != nil {
+		return err
+	}
+
+	return insertMovement(tx, movement)
+}
+
+func insertMovement(tx *sql.Tx, movement model.AssetMovement) error {
+	if movement.Id == "" {
+		movement.Id = common.GenerateUUID()
+	}
+
+	_, err := tx.Exec("INSERT INTO asset_movements(id,asset_detail_id,asset_id,from_location_id,to_location_id,from_status,to_status,batch_id,actor,moved_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)", movement.Id, movement.AssetDetailId, movement.AssetId, movement.FromLocationId, movement.ToLocationId, movement.FromStatus, movement.ToStatus, movement.BatchId, movement.Actor, movement.MovedAt)
+	return err
+}
+
+func NewAssetMovementRepository(db *sql.DB) AssetMovementRepository {
+	return &assetMovementRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetMovementRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetMovementRepository
+}
+
+func (s *AssetMovementRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetMovementRepository(db)
+}
+
+func (s *AssetMovementRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func movementRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "asset_detail_id", "asset_id", "from_location_id", "to_location_id", "from_status", "to_status", "batch_id", "actor", "moved_at"}).
+		AddRow("m2", "u1", "a1", "l1", "l2", model.StatusInStorage, model.StatusPlaced, "b2", "Budi", at(10)).
+		AddRow("m1", "u1", "a1", "l1", "l1", model.StatusInStorage, model.StatusInStorage, "b1", "", at(9))
+}
+
+func (s *AssetMovementRepositorySuite) TestListByAsset_Success() {
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_movements WHERE asset_id=\\$1 ORDER BY moved_at DESC").WithArgs("a1").WillReturnRows(movementRows())
+
+	movements, err := s.repo.ListByAsset("a1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), movements, 2)
+	assert.Equal(s.T(), model.StatusPlaced, movements[0].ToStatus)
+	assert.Equal(s.T(), "Budi", movements[0].Actor)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetMovementRepositorySuite) TestListByUnit_Success() {
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_movements WHERE asset_detail_id=\\$1").WithArgs("u1").WillReturnRows(movementRows())
+
+	movements, err := s.repo.ListByUnit("u1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "m2", movements[0].Id)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetMovementRepositorySuite) TestListByLocation_Success() {
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_movements WHERE from_location_id=\\$1 OR to_location_id=\\$1").WithArgs("l2").WillReturnRows(movementRows())
+
+	movements, err := s.repo.ListByLocation("l2")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), movements, 2)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetMovementRepositorySuite) TestList_Fail() {
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_movements").WillReturnError(errors.New("connection refused"))
+
+	_, err := s.repo.ListByAsset("a1")
+	assert.EqualError(s.T(), err, "connection refused")
+}
+
+func (s *AssetMovementRepositorySuite) TestListScan_Fail() {
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_movements").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("m1"))
+
+	_, err := s.repo.ListByAsset("a1")
+	assert.Error(s.T(), err)
+}
+
+func TestAssetMovementRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetMovementRepositorySuite))
+}
@@ -0,0 +1,179 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlaceUnits_Concurrent runs against a real PostgreSQL because row locks
+// can't be simulated with sqlmock. Set TEST_DATABASE_URL to enable it; the
+// test works in a throwaway schema and drops it afterwards.
+func TestPlaceUnits_Concurrent(t *testing.T) {
+	db := openTestSchema(t,
+		"CREATE TABLE asset_details (id VARCHAR(100) PRIMARY KEY, asset_id VARCHAR(100) NOT NULL, location_id VARCHAR(100) NOT NULL, status INT, updated_at DATE NULL, removed_at DATE NULL)",
+		"CREATE TABLE asset_movements (id VARCHAR(100) PRIMARY KEY, asset_detail_id VARCHAR(100) NOT NULL, asset_id VARCHAR(100) NOT NULL, from_location_id VARCHAR(100) NOT NULL, to_location_id VARCHAR(100) NOT NULL, from_status INT NOT NULL, to_status INT NOT NULL, batch_id VARCHAR(100) NOT NULL, actor VARCHAR(100) NOT NULL DEFAULT '', moved_at TIMESTAMP NOT NULL)",
+	)
+
+	const units = 20
+	for i := 0; i < units; i++ {
+		_, err := db.Exec("INSERT INTO asset_details(id,asset_id,location_id,status) VALUES($1,$2,$3,$4)", fmt.Sprintf("u%02d", i), "a1", "storage", model.StatusInStorage)
+		require.NoError(t, err)
+	}
+
+	repo := repository.NewAssetRepository(db)
+
+	const workers = 16
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		placed []string
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			ids, err := repo.PlaceUnits(model.AssetPlacement{
+				AsssetId:      "a1",
+				CurrentStatus: model.StatusInStorage,
+				TargetStatus:  model.StatusPlaced,
+				LocationId:    fmt.Sprintf("room-%d", worker),
+				Qty:           3,
+				UpdatedAt:     time.Now(),
+				BatchId:       fmt.Sprintf("batch-%d", worker),
+			})
+			assert.NoError(t, err)
+
+			mu.Lock()
+			placed = append(placed, ids...)
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, id := range placed {
+		assert.False(t, seen[id], "unit %s placed twice", id)
+		seen[id] = true
+	}
+	assert.LessOrEqual(t, len(placed), units)
+	assert.Zero(t, len(placed)%3)
+
+	var movements, stillInStorage int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM asset_movements").Scan(&movements))
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM asset_details WHERE status=$1", model.StatusInStorage).Scan(&stillInStorage))
+	assert.Equal(t, len(placed), movements)
+	assert.Equal(t, units-len(placed), stillInStorage)
+}
+
+// TestCreate_ConcurrentTags numbers units of two categories sharing a prefix
+// from concurrent creations, half of which roll back after numbering. The
+// surviving tags must be unique and leave no gaps.
+func TestCreate_ConcurrentTags(t *testing.T) {
+	db := openTestSchema(t,
+		"CREATE TABLE asset_categories (id VARCHAR(100) PRIMARY KEY, tag_prefix VARCHAR(30) NOT NULL DEFAULT '', tag_with_year BOOLEAN NOT NULL DEFAULT FALSE, tag_padding INT NOT NULL DEFAULT 4)",
+		"CREATE TABLE asset (id VARCHAR(100) PRIMARY KEY, category_id VARCHAR(100) NOT NULL, transaction_detail_id VARCHAR(100) NULL, name VARCHAR(100) NOT NULL, description TEXT, image_url VARCHAR(255), qty INT, cost NUMERIC NOT NULL DEFAULT 0, salvage_value NUMERIC NOT NULL DEFAULT 0, useful_life INT NOT NULL DEFAULT 0, created_at TIMESTAMP)",
+		"CREATE TABLE asset_details (id VARCHAR(100) PRIMARY KEY, asset_id VARCHAR(100) NOT NULL, location_id VARCHAR(100) NOT NULL, status INT, tag VARCHAR(60) NOT NULL DEFAULT '', updated_at DATE NULL, removed_at DATE NULL)",
+		"CREATE UNIQUE INDEX uq_asset_details_tag ON asset_details(tag) WHERE tag <> ''",
+		"CREATE TABLE asset_tag_sequences (prefix VARCHAR(30) NOT NULL, year INT NOT NULL, last_value INT NOT NULL, PRIMARY KEY(prefix, year))",
+		"INSERT INTO asset_categories(id,tag_prefix,tag_with_year,tag_padding) VALUES('laptop','IT-LAP',TRUE,4),('notebook','IT-LAP',TRUE,4)",
+	)
+
+	repo := repository.NewAssetRepository(db)
+	createdAt := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	categories := []string{"laptop", "notebook"}
+
+	const workers, qty = 12, 3
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			asset := model.Asset{
+				Id:         fmt.Sprintf("a%d", worker),
+				CategoryId: categories[worker%2],
+				Name:       "Laptop",
+				Qty:        qty,
+				CreatedAt:  createdAt,
+			}
+			for j := 0; j < qty; j++ {
+				asset.AssetDetail = append(asset.AssetDetail, model.AssetDetail{Id: fmt.Sprintf("a%d-u%d", worker, j), AssetId: asset.Id, LocationId: "storage", Status: model.StatusInStorage})
+			}
+
+			// Every third worker fails after numbering to prove rollbacks don't leave gaps
+			failing := worker%3 == 2
+			if failing {
+				asset.AssetDetail[qty-1].Id = asset.AssetDetail[0].Id
+			}
+			err := repo.Create(asset)
+			assert.Equal(t, failing, err != nil)
+		}(i)
+	}
+	wg.Wait()
+
+	rows, err := db.Query("SELECT tag FROM asset_details ORDER BY tag")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		require.NoError(t, rows.Scan(&tag))
+		tags = append(tags, tag)
+	}
+
+	require.Len(t, tags, workers/3*2*qty)
+	for i, tag := range tags {
+		assert.Equal(t, fmt.Sprintf("IT-LAP-2026-%04d", i+1), tag)
+	}
+}
+
+// openTestSchema connects to TEST_DATABASE_URL inside a throwaway schema
+// holding the given tables, the schema is dropped when the test ends.
+func openTestSchema(t *testing.T, statements ...string) *sql.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("repository_test_%d", time.Now().UnixNano())
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range statements {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	return db
+}
+
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
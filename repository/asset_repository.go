@@ -2,7 +2,12 @@ package repository
 
 import (
 	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/utils/tag"
 	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type AssetRepository interface {
@@ -10,9 +15,14 @@ type AssetRepository interface {
 	List() ([]model.Asset, error)
 	Detail(id string) (model.Asset, error)
 	AssetDetail(assetId string) ([]model.AssetDetail, error)
-	CountCurrentQty(assetId string, qty, currentStatus int) (int, error)
-	GetAvailabilityId(limit, status int, assetId string) ([]string, error)
-	UpdateLocation(bodyRequest model.AssetPlacement) error
+	AttributeValues(assetId string) ([]model.AssetAttributeValue, error)
+	GetUnit(id string) (model.AssetDetail, error)
+	GetUnitByTag(tag string) (model.AssetDetail, error)
+	LocationUnits(locationId string) ([]model.AssetDetail, error)
+	PlaceUnits(bodyRequest model.AssetPlacement) ([]string, error)
+	Update(bodyRequest model.Asset) error
+	Delete(id string) error
+	RetireUnits(bodyRequest model.AssetRetirement) error
 }
 
 type assetRepository struct {
@@ -24,29 +34,51 @@ func (a *assetRepository) Create(bodyRequest model.Asset) error {
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
+	if err := insertAsset(tx, bodyRequest); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertAsset stores a new asset with its units and attribute values inside
+// the caller's transaction.
+func insertAsset(tx *sql.Tx, bodyRequest model.Asset) error {
 	// Insert asset
-	_, err = tx.Exec("INSERT INTO asset(id,category_id,name,description,image_url,qty,created_at) VALUES($1,$2,$3,$4,$5,$6,$7)", bodyRequest.Id, bodyRequest.CategoryId, bodyRequest.Name, bodyRequest.Description, bodyRequest.ImageUrl, bodyRequest.Qty, bodyRequest.CreatedAt)
+	_, err := tx.Exec("INSERT INTO asset(id,category_id,transaction_detail_id,name,description,image_url,qty,cost,salvage_value,useful_life,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)", bodyRequest.Id, bodyRequest.CategoryId, bodyRequest.TransactionDetailId, bodyRequest.Name, bodyRequest.Description, bodyRequest.ImageUrl, bodyRequest.Qty, bodyRequest.Cost, bodyRequest.SalvageValue, bodyRequest.UsefulLife, bodyRequest.CreatedAt)
 	if err != nil {
 		return err
 	}
 
+	if err := assignTags(tx, bodyRequest.CategoryId, bodyRequest.CreatedAt, bodyRequest.AssetDetail); err != nil {
+		return err
+	}
+
 	for _, item := range bodyRequest.AssetDetail {
-		_, err := tx.Exec("INSERT INTO asset_details(id,asset_id,location_id,status) VALUES($1,$2,$3,$4)", item.Id, item.AssetId, item.LocationId, item.Status)
+		_, err := tx.Exec("INSERT INTO asset_details(id,asset_id,location_id,status,tag) VALUES($1,$2,$3,$4,$5)", item.Id, item.AssetId, item.LocationId, item.Status, item.Tag)
 		if err != nil {
 			return err
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return err
+	return insertAttributeValues(tx, bodyRequest)
+}
+
+func insertAttributeValues(tx *sql.Tx, bodyRequest model.Asset) error {
+	for _, value := range bodyRequest.AttributeValues {
+		_, err := tx.Exec("INSERT INTO asset_attribute_values(asset_id,attribute_id,value) VALUES($1,$2,$3)", bodyRequest.Id, value.AttributeId, value.Value)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (a *assetRepository) List() ([]model.Asset, error) {
-	rows, err := a.db.Query("SELECT id,category_id,transaction_detail_id,name,description,image_url,qty,created_at FROM asset")
+	rows, err := a.db.Query("SELECT id,category_id,transaction_detail_id,name,description,image_url,image_thumbnails,qty,cost,salvage_value,useful_life,created_at FROM asset")
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +86,7 @@ func (a *assetRepository) List() ([]model.Asset, error) {
 	var assets []model.Asset
 	for rows.Next() {
 		var asset model.Asset
-		err = rows.Scan(&asset.Id, &asset.CategoryId, &asset.TransactionDetailId, &asset.Name, &asset.Description, &asset.ImageUrl, &asset.Qty, &asset.CreatedAt)
+		err = rows.Scan(&asset.Id, &asset.CategoryId, &asset.TransactionDetailId, &asset.Name, &asset.Description, &asset.ImageUrl, &asset.ImageThumbnails, &asset.Qty, &asset.Cost, &asset.SalvageValue, &asset.UsefulLife, &asset.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -67,7 +99,7 @@ func (a *assetRepository) List() ([]model.Asset, error) {
 
 func (a *assetRepository) Detail(id string) (model.Asset, error) {
 	var asset model.Asset
-	err := a.db.QueryRow("SELECT id,category_id,transaction_detail_id,name,description,image_url,qty,created_at FROM asset WHERE id=$1", id).Scan(&asset.Id, &asset.CategoryId, &asset.TransactionDetailId, &asset.Name, &asset.Description, &asset.ImageUrl, &asset.Qty, &asset.CreatedAt)
+	err := a.db.QueryRow("SELECT id,category_id,transaction_detail_id,name,description,image_url,image_thumbnails,qty,cost,salvage_value,useful_life,created_at FROM asset WHERE id=$1", id).Scan(&asset.Id, &asset.CategoryId, &asset.TransactionDetailId, &asset.Name, &asset.Description, &asset.ImageUrl, &asset.ImageThumbnails, &asset.Qty, &asset.Cost, &asset.SalvageValue, &asset.UsefulLife, &asset.CreatedAt)
 	if err != nil {
 		return model.Asset{}, err
 	}
@@ -77,14 +109,14 @@ func (a *assetRepository) Detail(id string) (model.Asset, error) {
 
 func (a *assetRepository) AssetDetail(assetId string) ([]model.AssetDetail, error) {
 	var assetDetails []model.AssetDetail
-	rows, err := a.db.Query("SELECT id,location_id,status,updated_at FROM asset_details WHERE asset_id=$1", assetId)
+	rows, err := a.db.Query("SELECT id,location_id,status,tag,updated_at,removed_at FROM asset_details WHERE asset_id=$1", assetId)
 	if err != nil {
 		return nil, err
 	}
 
 	for rows.Next() {
 		var detail model.AssetDetail
-		err := rows.Scan(&detail.Id, &detail.LocationId, &detail.Status, &detail.UpdatedAt)
+		err := rows.Scan(&detail.Id, &detail.LocationId, &detail.Status, &detail.Tag, &detail.UpdatedAt, &detail.RemovedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -95,41 +127,264 @@ func (a *assetRepository) AssetDetail(assetId string) ([]model.AssetDetail, erro
 	return assetDetails, nil
 }
 
-func (a *assetRepository) CountCurrentQty(assetId string, qty int, currentStatus int) (int, error) {
-	var assetQty int
-	err := a.db.QueryRow("SELECT count(*) AS asset_available FROM asset_details WHERE status=$2 AND asset_id=$1", assetId, currentStatus).Scan(&assetQty)
+func (a *assetRepository) AttributeValues(assetId string) ([]model.AssetAttributeValue, error) {
+	rows, err := a.db.Query("SELECT asset_id,attribute_id,value FROM asset_attribute_values WHERE asset_id=$1", assetId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []model.AssetAttributeValue
+	for rows.Next() {
+		var value model.AssetAttributeValue
+		err := rows.Scan(&value.AssetId, &value.AttributeId, &value.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
+
+func (a *assetRepository) GetUnit(id string) (model.AssetDetail, error) {
+	var detail model.AssetDetail
+	err := a.db.QueryRow("SELECT id,asset_id,location_id,status,tag,updated_at,removed_at FROM asset_details WHERE id=$1", id).Scan(&detail.Id, &detail.AssetId, &detail.LocationId, &detail.Status, &detail.Tag, &detail.UpdatedAt, &detail.RemovedAt)
+	if err != nil {
+		return model.AssetDetail{}, err
+	}
+
+	return detail, nil
+}
+
+func (a *assetRepository) GetUnitByTag(tag string) (model.AssetDetail, error) {
+	var detail model.AssetDetail
+	err := a.db.QueryRow("SELECT id,asset_id,location_id,status,tag,updated_at,removed_at FROM asset_details WHERE tag=$1", tag).Scan(&detail.Id, &detail.AssetId, &detail.LocationId, &detail.Status, &detail.Tag, &detail.UpdatedAt, &detail.RemovedAt)
 	if err != nil {
-		return 0, err
+		return model.AssetDetail{}, err
 	}
 
-	return assetQty, nil
+	return detail, nil
 }
 
-func (a *assetRepository) GetAvailabilityId(limit, status int, assetId string) ([]string, error) {
-	var availableAssetId []string
-	rows, err := a.db.Query("SELECT id from asset_details WHERE status=$1 AND asset_id=$2 LIMIT $3", status, assetId, limit)
+// LocationUnits lists the units currently at a location, retired units are
+// left out.
+func (a *assetRepository) LocationUnits(locationId string) ([]model.AssetDetail, error) {
+	rows, err := a.db.Query("SELECT id,asset_id,location_id,status,tag,updated_at,removed_at FROM asset_details WHERE location_id=$1 AND removed_at IS NULL ORDER BY asset_id,tag,id", locationId)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
+	var units []model.AssetDetail
 	for rows.Next() {
-		var assetId string
-		err := rows.Scan(&assetId)
+		var unit model.AssetDetail
+		err := rows.Scan(&unit.Id, &unit.AssetId, &unit.LocationId, &unit.Status, &unit.Tag, &unit.UpdatedAt, &unit.RemovedAt)
 		if err != nil {
 			return nil, err
 		}
 
-		availableAssetId = append(availableAssetId, assetId)
+		units = append(units, unit)
 	}
 
-	return availableAssetId, nil
+	return units, rows.Err()
 }
 
-func (a *assetRepository) UpdateLocation(bodyRequest model.AssetPlacement) error {
-	_, err := a.db.Exec("UPDATE asset_details SET location_id=$1, status=$2, updated_at=$3 WHERE id=$4", bodyRequest.LocationId, bodyRequest.TargetStatus, bodyRequest.UpdatedAt, bodyRequest.Id)
+// PlaceUnits moves qty units, or the units listed in AssetDetailIds, in one
+// transaction. Candidate rows are locked with SKIP LOCKED so concurrent
+// placements never pick the same unit; rows held by another placement simply
+// don't count as available. When fewer than requested units are free nothing
+// is moved and an empty result is returned.
+func (a *assetRepository) PlaceUnits(bodyRequest model.AssetPlacement) ([]string, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if len(bodyRequest.AssetDetailIds) > 0 {
+		// Specific units wait for their lock instead of being skipped
+		rows, err = tx.Query("SELECT id,asset_id,location_id,status,updated_at,removed_at FROM asset_details WHERE id=ANY($1) AND asset_id=$2 AND status=$3 AND removed_at IS NULL ORDER BY id FOR UPDATE", pq.Array(bodyRequest.AssetDetailIds), bodyRequest.AsssetId, bodyRequest.CurrentStatus)
+	} else {
+		rows, err = tx.Query("SELECT id,asset_id,location_id,status,updated_at,removed_at FROM asset_details WHERE asset_id=$1 AND status=$2 AND removed_at IS NULL ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED", bodyRequest.AsssetId, bodyRequest.CurrentStatus, bodyRequest.Qty)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var units []model.AssetDetail
+	for rows.Next() {
+		var unit model.AssetDetail
+		err := rows.Scan(&unit.Id, &unit.AssetId, &unit.LocationId, &unit.Status, &unit.UpdatedAt, &unit.RemovedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		units = append(units, unit)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(units) < bodyRequest.Qty {
+		return nil, nil
+	}
+
+	placedId := make([]string, 0, len(units))
+	for _, unit := range units {
+		err := moveUnit(tx, unit, model.AssetMovement{
+			ToLocationId: bodyRequest.LocationId,
+			ToStatus:     bodyRequest.TargetStatus,
+			BatchId:      bodyRequest.BatchId,
+			Actor:        bodyRequest.Actor,
+			MovedAt:      bodyRequest.UpdatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		placedId = append(placedId, unit.Id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return placedId, nil
+}
+
+// Update stores an edited asset. AttributeValues replace the stored values
+// of the asset, a nil AttributeValues leaves them as they are.
+func (a *assetRepository) Update(bodyRequest model.Asset) error {
+	tx, err := a.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE asset SET category_id=$1, name=$2, description=$3, image_url=$4, image_thumbnails=$5, cost=$6, salvage_value=$7, useful_life=$8 WHERE id=$9", bodyRequest.CategoryId, bodyRequest.Name, bodyRequest.Description, bodyRequest.ImageUrl, bodyRequest.ImageThumbnails, bodyRequest.Cost, bodyRequest.SalvageValue, bodyRequest.UsefulLife, bodyRequest.Id)
+	if err != nil {
+		return err
+	}
+
+	if bodyRequest.AttributeValues != nil {
+		_, err = tx.Exec("DELETE FROM asset_attribute_values WHERE asset_id=$1", bodyRequest.Id)
+		if err != nil {
+			return err
+		}
+
+		if err := insertAttributeValues(tx, bodyRequest); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes an asset with its units. History rows such as movements
+// keep pointing at them, so an asset that has any fails with ErrInUse.
+func (a *assetRepository) Delete(id string) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM asset_details WHERE asset_id=$1", id)
+	if err != nil {
+		return inUseError(err)
+	}
+
+	_, err = tx.Exec("DELETE FROM asset WHERE id=$1", id)
+	if err != nil {
+		return inUseError(err)
+	}
+
+	return tx.Commit()
+}
+
+// RetireUnits disposes units that are in storage and records the movement
+// of each one. Units in any other state are still tied to an assignment,
+// work order or transfer, so nothing is retired when one of them is.
+func (a *assetRepository) RetireUnits(bodyRequest model.AssetRetirement) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		unit, err := lockUnit(tx, unitId)
+		if err != nil {
+			return err
+		}
+
+		if unit.AssetId != bodyRequest.AssetId || unit.RemovedAt != nil || unit.Status != model.StatusInStorage {
+			return fmt.Errorf("asset unit %s is not in storage, it can't be retired", unit.Id)
+		}
+
+		err = moveUnit(tx, unit, model.AssetMovement{
+			ToLocationId: unit.LocationId,
+			ToStatus:     model.StatusDisposed,
+			BatchId:      bodyRequest.BatchId,
+			Actor:        bodyRequest.Actor,
+			MovedAt:      bodyRequest.RemovedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("UPDATE asset_details SET removed_at=$1 WHERE id=$2", bodyRequest.RemovedAt, unit.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := syncAssetQty(tx, bodyRequest.AssetId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// syncAssetQty keeps asset.qty equal to the number of units that are not removed.
+func syncAssetQty(tx *sql.Tx, assetId string) error {
+	_, err := tx.Exec("UPDATE asset SET qty=(SELECT count(*) FROM asset_details WHERE asset_id=$1 AND removed_at IS NULL) WHERE id=$1", assetId)
+	return err
+}
+
+// assignTags numbers new units from the tag sequence of their category's
+// prefix, categories sharing a prefix share the counter so their tags can't
+// collide. The sequence row stays locked until the surrounding transaction
+// ends, so concurrent creations queue behind each other and a rollback hands
+// the numbers back, which keeps the counter free of gaps.
+func assignTags(tx *sql.Tx, categoryId string, createdAt time.Time, units []model.AssetDetail) error {
+	var pattern tag.Pattern
+	err := tx.QueryRow("SELECT tag_prefix,tag_with_year,tag_padding FROM asset_categories WHERE id=$1", categoryId).Scan(&pattern.Prefix, &pattern.WithYear, &pattern.Padding)
+	if err != nil {
+		return err
+	}
+
+	if !pattern.Enabled() || len(units) == 0 {
+		return nil
+	}
+
+	year := pattern.SequenceYear(createdAt)
+
+	var last int
+	err = tx.QueryRow("INSERT INTO asset_tag_sequences(prefix,year,last_value) VALUES($1,$2,$3) ON CONFLICT (prefix,year) DO UPDATE SET last_value=asset_tag_sequences.last_value+EXCLUDED.last_value RETURNING last_value", pattern.Prefix, year, len(units)).Scan(&last)
+	if err != nil {
+		return err
+	}
+
+	first := last - len(units) + 1
+	for i := range units {
+		units[i].Tag = pattern.Format(year, first+i)
+	}
 
 	return nil
 }
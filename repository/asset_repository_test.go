@@ -0,0 +1,353 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetRepository
+}
+
+func (s *AssetRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetRepository(db)
+}
+
+func (s *AssetRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *AssetRepositorySuite) TestUpdate_Success() {
+	payload := model.Asset{
+		Id:          "1",
+		CategoryId:  "c1",
+		Name:        "Laptop",
+		Description: "Office laptop",
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("UPDATE asset SET").WithArgs(payload.CategoryId, payload.Name, payload.Description, payload.ImageUrl, payload.ImageThumbnails, payload.Cost, payload.SalvageValue, payload.UsefulLife, payload.Id).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Update(payload)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestUpdate_ReplacesAttributeValues() {
+	payload := model.Asset{
+		Id:              "1",
+		CategoryId:      "c2",
+		Name:            "Laptop",
+		AttributeValues: []model.AssetAttributeValue{{AssetId: "1", AttributeId: "ram", Value: "16"}},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("UPDATE asset SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("DELETE FROM asset_attribute_values").WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec("INSERT INTO asset_attribute_values").WithArgs("1", "ram", "16").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Update(payload)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestDelete_WithHistory() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("DELETE FROM asset_details").WithArgs("1").WillReturnError(&pq.Error{Code: "23503", Table: "asset_movements"})
+	s.mock.ExpectRollback()
+
+	err := s.repo.Delete("1")
+	assert.ErrorIs(s.T(), err, repository.ErrInUse)
+	assert.EqualError(s.T(), err, "still in use by asset_movements")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestRetireUnits_Success() {
+	payload := model.AssetRetirement{
+		AssetId:        "1",
+		AssetDetailIds: []string{"u1", "u2"},
+		RemovedAt:      time.Now(),
+	}
+
+	s.mock.ExpectBegin()
+	for _, unitId := range payload.AssetDetailIds {
+		s.mock.ExpectQuery("SELECT id,asset_id,location_id,status,updated_at,removed_at FROM asset_details").WithArgs(unitId).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+				AddRow(unitId, payload.AssetId, "l1", model.StatusInStorage, time.Now(), nil))
+		s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusDisposed, payload.RemovedAt, unitId).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectExec("UPDATE asset_details SET removed_at").WithArgs(payload.RemovedAt, unitId).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	s.mock.ExpectExec("UPDATE asset SET qty").WithArgs(payload.AssetId).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.RetireUnits(payload)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestRetireUnits_Fail() {
+	payload := model.AssetRetirement{
+		AssetId:        "1",
+		AssetDetailIds: []string{"u1", "u2"},
+		RemovedAt:      time.Now(),
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id,asset_id,location_id,status,updated_at,removed_at FROM asset_details").WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+			AddRow("u1", payload.AssetId, "l1", model.StatusAssigned, time.Now(), nil))
+	s.mock.ExpectRollback()
+
+	err := s.repo.RetireUnits(payload)
+	assert.EqualError(s.T(), err, "asset unit u1 is not in storage, it can't be retired")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestCreate_AssignsTags() {
+	createdAt := time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC)
+	payload := model.Asset{
+		Id:         "a1",
+		CategoryId: "c1",
+		Name:       "Laptop",
+		Qty:        2,
+		CreatedAt:  createdAt,
+		AssetDetail: []model.AssetDetail{
+			{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage},
+			{Id: "u2", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage},
+		},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO asset").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT tag_prefix,tag_with_year,tag_padding FROM asset_categories").WithArgs("c1").
+		WillReturnRows(sqlmock.NewRows([]string{"tag_prefix", "tag_with_year", "tag_padding"}).AddRow("IT-LAP", true, 4))
+	s.mock.ExpectQuery("INSERT INTO asset_tag_sequences(.+) ON CONFLICT").WithArgs("IT-LAP", 2026, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"last_value"}).AddRow(5))
+	s.mock.ExpectExec("INSERT INTO asset_details").WithArgs("u1", "a1", "l1", model.StatusInStorage, "IT-LAP-2026-0004").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_details").WithArgs("u2", "a1", "l1", model.StatusInStorage, "IT-LAP-2026-0005").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.NoError(s.T(), s.repo.Create(payload))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestCreate_WithoutTagPrefix() {
+	payload := model.Asset{
+		Id:          "a1",
+		CategoryId:  "c1",
+		Qty:         1,
+		CreatedAt:   time.Now(),
+		AssetDetail: []model.AssetDetail{{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO asset").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT tag_prefix,tag_with_year,tag_padding FROM asset_categories").
+		WillReturnRows(sqlmock.NewRows([]string{"tag_prefix", "tag_with_year", "tag_padding"}).AddRow("", false, 4))
+	s.mock.ExpectExec("INSERT INTO asset_details").WithArgs("u1", "a1", "l1", model.StatusInStorage, "").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.NoError(s.T(), s.repo.Create(payload))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestLocationUnits_Success() {
+	rows := sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "tag", "updated_at", "removed_at"}).
+		AddRow("u1", "a1", "l1", model.StatusPlaced, "IT-LAP-2026-0001", nil, nil).
+		AddRow("u2", "a2", "l1", model.StatusPlaced, "", nil, nil)
+
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE location_id=\\$1 AND removed_at IS NULL").WithArgs("l1").WillReturnRows(rows)
+
+	units, err := s.repo.LocationUnits("l1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), units, 2)
+	assert.Equal(s.T(), "IT-LAP-2026-0001", units[0].Tag)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestPlaceUnits_Success() {
+	payload := model.AssetPlacement{
+		AsssetId:      "a1",
+		CurrentStatus: model.StatusInStorage,
+		TargetStatus:  model.StatusPlaced,
+		LocationId:    "l2",
+		Qty:           2,
+		UpdatedAt:     time.Now(),
+		BatchId:       "b1",
+		Actor:         "admin",
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+		AddRow("u1", "a1", "l1", model.StatusInStorage, nil, nil).
+		AddRow("u2", "a1", "l1", model.StatusInStorage, nil, nil)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details (.+) FOR UPDATE SKIP LOCKED").WithArgs("a1", model.StatusInStorage, 2).WillReturnRows(rows)
+	for _, id := range []string{"u1", "u2"} {
+		s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l2", model.StatusPlaced, payload.UpdatedAt, id).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), id, "a1", "l1", "l2", model.StatusInStorage, model.StatusPlaced, "b1", "admin", payload.UpdatedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	s.mock.ExpectCommit()
+
+	placed, err := s.repo.PlaceUnits(payload)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"u1", "u2"}, placed)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestPlaceUnits_SelectedUnits() {
+	payload := model.AssetPlacement{
+		AsssetId:       "a1",
+		CurrentStatus:  model.StatusPlaced,
+		TargetStatus:   model.StatusInStorage,
+		LocationId:     "warehouse",
+		Qty:            1,
+		AssetDetailIds: []string{"u3"},
+		UpdatedAt:      time.Now(),
+		BatchId:        "b1",
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+		AddRow("u3", "a1", "room-1", model.StatusPlaced, nil, nil)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=ANY(.+) FOR UPDATE$").WithArgs(sqlmock.AnyArg(), "a1", model.StatusPlaced).WillReturnRows(rows)
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("warehouse", model.StatusInStorage, payload.UpdatedAt, "u3").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), "u3", "a1", "room-1", "warehouse", model.StatusPlaced, model.StatusInStorage, "b1", "", payload.UpdatedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	placed, err := s.repo.PlaceUnits(payload)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"u3"}, placed)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+// TestPlaceUnits_LockOrder checks every unit is locked in one statement
+// ordered by id before the first one is moved, so two placements over the
+// same units lock them in the same order and can't deadlock.
+func (s *AssetRepositorySuite) TestPlaceUnits_LockOrder() {
+	payload := model.AssetPlacement{
+		AsssetId:       "a1",
+		CurrentStatus:  model.StatusInStorage,
+		TargetStatus:   model.StatusPlaced,
+		LocationId:     "l2",
+		AssetDetailIds: []string{"u3", "u1", "u2"},
+		UpdatedAt:      time.Now(),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+		AddRow("u1", "a1", "l1", model.StatusInStorage, nil, nil).
+		AddRow("u2", "a1", "l1", model.StatusInStorage, nil, nil).
+		AddRow("u3", "a1", "l1", model.StatusInStorage, nil, nil)
+
+	s.mock.MatchExpectationsInOrder(true)
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("WHERE id=ANY\\(\\$1\\) AND asset_id=\\$2 AND status=\\$3 AND removed_at IS NULL ORDER BY id FOR UPDATE$").
+		WithArgs(pq.Array(payload.AssetDetailIds), "a1", model.StatusInStorage).WillReturnRows(rows)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l2", model.StatusPlaced, payload.UpdatedAt, id).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	s.mock.ExpectCommit()
+
+	placed, err := s.repo.PlaceUnits(payload)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"u1", "u2", "u3"}, placed)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+// TestPlaceUnits_SkipsLockedUnits checks a placement by qty never waits for
+// units another placement holds, it skips them and places nothing when too
+// few are left.
+func (s *AssetRepositorySuite) TestPlaceUnits_SkipsLockedUnits() {
+	payload := model.AssetPlacement{
+		AsssetId:      "a1",
+		CurrentStatus: model.StatusInStorage,
+		TargetStatus:  model.StatusPlaced,
+		LocationId:    "l2",
+		Qty:           3,
+		UpdatedAt:     time.Now(),
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("WHERE asset_id=\\$1 AND status=\\$2 AND removed_at IS NULL ORDER BY id LIMIT \\$3 FOR UPDATE SKIP LOCKED$").
+		WithArgs("a1", model.StatusInStorage, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+			AddRow("u4", "a1", "l1", model.StatusInStorage, nil, nil))
+	s.mock.ExpectRollback()
+
+	placed, err := s.repo.PlaceUnits(payload)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), placed)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestPlaceUnits_NotEnoughUnits() {
+	payload := model.AssetPlacement{
+		AsssetId:      "a1",
+		CurrentStatus: model.StatusInStorage,
+		TargetStatus:  model.StatusPlaced,
+		LocationId:    "l2",
+		Qty:           2,
+		UpdatedAt:     time.Now(),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+		AddRow("u1", "a1", "l1", model.StatusInStorage, nil, nil)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details (.+) FOR UPDATE SKIP LOCKED").WillReturnRows(rows)
+	s.mock.ExpectRollback()
+
+	placed, err := s.repo.PlaceUnits(payload)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), placed)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetRepositorySuite) TestPlaceUnits_MovementFail() {
+	payload := model.AssetPlacement{
+		AsssetId:      "a1",
+		CurrentStatus: model.StatusInStorage,
+		TargetStatus:  model.StatusPlaced,
+		LocationId:    "l2",
+		Qty:           1,
+		UpdatedAt:     time.Now(),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+		AddRow("u1", "a1", "l1", model.StatusInStorage, nil, nil)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details").WillReturnRows(rows)
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnError(sql.ErrConnDone)
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.PlaceUnits(payload)
+	assert.Error(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestAssetRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetRepositorySuite))
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type AssetReservationRepository interface {
+	Create(bodyRequest model.AssetReservation) error
+	Get(id string) (model.AssetReservation, error)
+	ListByAsset(assetId string, from time.Time) ([]model.AssetReservation, error)
+	ListByEmployee(employeeId string, from time.Time) ([]model.AssetReservation, error)
+	Cancel(id string) error
+}
+
+type assetReservationRepository struct {
+	db *sql.DB
+}
+
+const assetReservationSelect = "SELECT r.id,r.asset_id,r.employee_id,r.qty,r.consumed_qty,r.start_at,r.end_at,r.status,r.note,r.created_at,array_remove(array_agg(ru.asset_detail_id ORDER BY ru.asset_detail_id),NULL) FROM asset_reservations r LEFT JOIN asset_reservation_units ru ON ru.reservation_id=r.id"
+
+// Create books the reservation after checking it against the reservations it
+// overlaps. The asset row is locked first, so concurrent bookings and
+// checkouts of the same asset are checked one after another.
+func (a *assetReservationRepository) Create(bodyRequest model.AssetReservation) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockAsset(tx, bodyRequest.AssetId); err != nil {
+		return err
+	}
+
+	// Units out on assignment come back, only lost and disposed ones don't
+	var capacity int
+	err = tx.QueryRow("SELECT count(*) FROM asset_details WHERE asset_id=$1 AND removed_at IS NULL AND status NOT IN ($2,$3)", bodyRequest.AssetId, model.StatusLost, model.StatusDisposed).Scan(&capacity)
+	if err != nil {
+		return err
+	}
+
+	overlapping, err := listReservations(tx, assetReservationSelect+" WHERE r.asset_id=$1 AND r.status IN ($2,$3) AND r.start_at<$4 AND r.end_at>$5 GROUP BY r.id ORDER BY r.start_at", bodyRequest.AssetId, model.ReservationActive, model.ReservationFulfilled, bodyRequest.EndAt, bodyRequest.StartAt)
+	if err != nil {
+		return err
+	}
+
+	if err := checkReservationConflicts(bodyRequest, overlapping, capacity); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO asset_reservations(id,asset_id,employee_id,qty,consumed_qty,start_at,end_at,status,note,created_at) VALUES($1,$2,$3,$4,0,$5,$6,$7,$8,$9)", bodyRequest.Id, bodyRequest.AssetId, bodyRequest.EmployeeId, bodyRequest.Qty, bodyRequest.StartAt, bodyRequest.EndAt, bodyRequest.Status, bodyRequest.Note, bodyRequest.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		_, err := tx.Exec("INSERT INTO asset_reservation_units(reservation_id,asset_detail_id) VALUES($1,$2)", bodyRequest.Id, unitId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *assetReservationRepository) Get(id string) (model.AssetReservation, error) {
+	return scanReservation(a.db.QueryRow(assetReservationSelect+" WHERE r.id=$1 GROUP BY r.id", id))
+}
+
+// ListByAsset lists the reservations of an asset that haven't ended at from,
+// cancelled ones included.
+func (a *assetReservationRepository) ListByAsset(assetId string, from time.Time) ([]model.AssetReservation, error) {
+	return listReservations(a.db, assetReservationSelect+" WHERE r.asset_id=$1 AND r.end_at>$2 GROUP BY r.id ORDER BY r.start_at", assetId, from)
+}
+
+func (a *assetReservationRepository) ListByEmployee(employeeId string, from time.Time) ([]model.AssetReservation, error) {
+	return listReservations(a.db, assetReservationSelect+" WHERE r.employee_id=$1 AND r.end_at>$2 GROUP BY r.id ORDER BY r.start_at", employeeId, from)
+}
+
+func (a *assetReservationRepository) Cancel(id string) error {
+	result, err := a.db.Exec("UPDATE asset_reservations SET status=$1 WHERE id=$2 AND status=$3", model.ReservationCancelled, id, model.ReservationActive)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("reservation %s %w, it is no longer active", id, ErrChanged)
+	}
+
+	return nil
+}
+
+// checkReservationConflicts rejects a booking that takes a unit already
+// reserved in the window, or that needs more units than the asset has at the
+// busiest moment of the window. Demand only rises when a reservation starts,
+// so checking the start of the window and every start inside it is enough.
+func checkReservationConflicts(bodyRequest model.AssetReservation, overlapping []model.AssetReservation, capacity int) error {
+	requested := make(map[string]bool, len(bodyRequest.AssetDetailIds))
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		requested[unitId] = true
+	}
+
+	points := []time.Time{bodyRequest.StartAt}
+	for _, reservation := range overlapping {
+		for _, unitId := range reservation.AssetDetailIds {
+			if requested[unitId] {
+				return fmt.Errorf("asset unit %s is already %w from %s to %s", unitId, ErrReserved, reservation.StartAt.Format(time.RFC3339), reservation.EndAt.Format(time.RFC3339))
+			}
+		}
+
+		if reservation.StartAt.After(bodyRequest.StartAt) {
+			points = append(points, reservation.StartAt)
+		}
+	}
+
+	for _, point := range points {
+		demand := bodyRequest.Qty
+		for _, reservation := range overlapping {
+			if !reservation.StartAt.After(point) && reservation.EndAt.After(point) {
+				demand += reservation.Qty
+			}
+		}
+
+		if demand > capacity {
+			return fmt.Errorf("only %d of %d requested units are available at %s, the others are %w", max(0, capacity-demand+bodyRequest.Qty), bodyRequest.Qty, point.Format(time.RFC3339), ErrReserved)
+		}
+	}
+
+	return nil
+}
+
+// consumeReservation runs inside a checkout. A checkout by an employee with a
+// reservation running at that moment uses one unit of it, any other checkout
+// may not take a unit reserved by someone else, nor leave fewer units than
+// the running reservations still need.
+func consumeReservation(tx *sql.Tx, unit model.AssetDetail, assignment model.AssetAssignment) (*string, error) {
+	if err := lockAsset(tx, unit.AssetId); err != nil {
+		return nil, err
+	}
+
+	// Reservations of this very unit come before reservations by qty
+	var reservationId string
+	err := tx.QueryRow("SELECT r.id FROM asset_reservations r LEFT JOIN asset_reservation_units ru ON ru.reservation_id=r.id WHERE r.asset_id=$1 AND r.employee_id=$2 AND r.status=$3 AND r.start_at<=$4 AND r.end_at>$4 GROUP BY r.id HAVING count(ru.asset_detail_id)=0 OR bool_or(ru.asset_detail_id=$5) ORDER BY count(ru.asset_detail_id) DESC, r.start_at LIMIT 1", unit.AssetId, assignment.EmployeeId, model.ReservationActive, assignment.AssignedAt, unit.Id).Scan(&reservationId)
+	if err == nil {
+		_, err = tx.Exec("UPDATE asset_reservations SET consumed_qty=consumed_qty+1, status=CASE WHEN consumed_qty+1>=qty THEN $1 ELSE status END WHERE id=$2", model.ReservationFulfilled, reservationId)
+		if err != nil {
+			return nil, err
+		}
+		return &reservationId, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var endAt time.Time
+	err = tx.QueryRow("SELECT r.end_at FROM asset_reservations r JOIN asset_reservation_units ru ON ru.reservation_id=r.id WHERE ru.asset_detail_id=$1 AND r.status=$2 AND r.start_at<=$3 AND r.end_at>$3 LIMIT 1", unit.Id, model.ReservationActive, assignment.AssignedAt).Scan(&endAt)
+	if err == nil {
+		return nil, fmt.Errorf("asset unit %s is %w until %s", unit.Id, ErrReserved, endAt.Format(time.RFC3339))
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var outstanding, available int
+	err = tx.QueryRow("SELECT COALESCE(SUM(qty-consumed_qty),0) FROM asset_reservations WHERE asset_id=$1 AND status=$2 AND start_at<=$3 AND end_at>$3", unit.AssetId, model.ReservationActive, assignment.AssignedAt).Scan(&outstanding)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow("SELECT count(*) FROM asset_details WHERE asset_id=$1 AND removed_at IS NULL AND status IN ($2,$3)", unit.AssetId, model.StatusInStorage, model.StatusPlaced).Scan(&available)
+	if err != nil {
+		return nil, err
+	}
+
+	if available-1 < outstanding {
+		return nil, fmt.Errorf("the remaining units of asset %s are %w", unit.AssetId, ErrReserved)
+	}
+
+	return nil, nil
+}
+
+// lockAsset holds the asset row lock until the transaction ends.
+func lockAsset(tx *sql.Tx, assetId string) error {
+	var id string
+	return tx.QueryRow("SELECT id FROM asset WHERE id=$1 FOR UPDATE", assetId).Scan(&id)
+}
+
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func listReservations(db queryer, query string, args ...any) ([]model.AssetReservation, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []model.AssetReservation
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+func scanReservation(row interface{ Scan(dest ...any) error }) (model.AssetReservation, error) {
+	var reservation model.AssetReservation
+	var note sql.NullString
+	err := row.Scan(&reservation.Id, &reservation.AssetId, &reservation.EmployeeId, &reservation.Qty, &reservation.ConsumedQty, &reservation.StartAt, &reservation.EndAt, &reservation.Status, &note, &reservation.CreatedAt, pq.Array(&reservation.AssetDetailIds))
+	if err != nil {
+		return model.AssetReservation{}, err
+	}
+	reservation.Note = note.String
+
+	return reservation, nil
+}
+
+func NewAssetReservationRepository(db *sql.DB) AssetReservationRepository {
+	return &assetReservationRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetReservationRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetReservationRepository
+}
+
+func (s *AssetReservationRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetReservationRepository(db)
+}
+
+func (s *AssetReservationRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+var reservationColumns = []string{"id", "asset_id", "employee_id", "qty", "consumed_qty", "start_at", "end_at", "status", "note", "created_at", "units"}
+
+func at(hour int) time.Time {
+	return time.Date(2026, 3, 2, hour, 0, 0, 0, time.UTC)
+}
+
+func (s *AssetReservationRepositorySuite) expectCapacity(capacity int, overlapping *sqlmock.Rows) {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM asset WHERE id=\\$1 FOR UPDATE").WithArgs("a1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("a1"))
+	s.mock.ExpectQuery("SELECT count\\(\\*\\) FROM asset_details").WithArgs("a1", model.StatusLost, model.StatusDisposed).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(capacity))
+	s.mock.ExpectQuery("FROM asset_reservations r (.+) WHERE r.asset_id=\\$1").WillReturnRows(overlapping)
+}
+
+func (s *AssetReservationRepositorySuite) TestCreate_Success() {
+	reservation := model.AssetReservation{Id: "r3", AssetId: "a1", EmployeeId: "e3", Qty: 1, StartAt: at(9), EndAt: at(12), Status: model.ReservationActive, CreatedAt: at(8)}
+	// 08-10 and 10-12 never overlap each other, so at most 2 of 3 units are taken
+	overlapping := sqlmock.NewRows(reservationColumns).
+		AddRow("r1", "a1", "e1", 2, 0, at(8), at(10), model.ReservationActive, nil, at(7), "{}").
+		AddRow("r2", "a1", "e2", 2, 0, at(10), at(12), model.ReservationActive, nil, at(7), "{}")
+	s.expectCapacity(3, overlapping)
+	s.mock.ExpectExec("INSERT INTO asset_reservations").WithArgs("r3", "a1", "e3", 1, at(9), at(12), model.ReservationActive, "", at(8)).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.NoError(s.T(), s.repo.Create(reservation))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetReservationRepositorySuite) TestCreate_ExceedsCapacity() {
+	reservation := model.AssetReservation{Id: "r3", AssetId: "a1", EmployeeId: "e3", Qty: 2, StartAt: at(9), EndAt: at(12)}
+	overlapping := sqlmock.NewRows(reservationColumns).
+		AddRow("r1", "a1", "e1", 1, 0, at(8), at(10), model.ReservationActive, nil, at(7), "{}").
+		AddRow("r2", "a1", "e2", 1, 1, at(9), at(11), model.ReservationFulfilled, nil, at(7), "{}")
+	s.expectCapacity(3, overlapping)
+	s.mock.ExpectRollback()
+
+	err := s.repo.Create(reservation)
+	assert.ErrorIs(s.T(), err, repository.ErrReserved)
+	assert.ErrorContains(s.T(), err, "only 1 of 2 requested units are available")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetReservationRepositorySuite) TestCreate_LaterStartExceedsCapacity() {
+	reservation := model.AssetReservation{Id: "r3", AssetId: "a1", EmployeeId: "e3", Qty: 1, StartAt: at(8), EndAt: at(12)}
+	// free at 08:00, but r1 and r2 both run at 11:00
+	overlapping := sqlmock.NewRows(reservationColumns).
+		AddRow("r1", "a1", "e1", 1, 0, at(10), at(12), model.ReservationActive, nil, at(7), "{}").
+		AddRow("r2", "a1", "e2", 1, 0, at(11), at(13), model.ReservationActive, nil, at(7), "{}")
+	s.expectCapacity(2, overlapping)
+	s.mock.ExpectRollback()
+
+	err := s.repo.Create(reservation)
+	assert.ErrorContains(s.T(), err, "are available at 2026-03-02T11:00:00Z")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetReservationRepositorySuite) TestCreate_UnitAlreadyReserved() {
+	reservation := model.AssetReservation{Id: "r3", AssetId: "a1", EmployeeId: "e3", Qty: 1, AssetDetailIds: []string{"u1"}, StartAt: at(9), EndAt: at(12)}
+	overlapping := sqlmock.NewRows(reservationColumns).
+		AddRow("r1", "a1", "e1", 1, 0, at(8), at(10), model.ReservationActive, nil, at(7), "{u1}")
+	s.expectCapacity(5, overlapping)
+	s.mock.ExpectRollback()
+
+	err := s.repo.Create(reservation)
+	assert.ErrorIs(s.T(), err, repository.ErrReserved)
+	assert.ErrorContains(s.T(), err, "asset unit u1 is already reserved")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetReservationRepositorySuite) TestCreate_SelectedUnits() {
+	reservation := model.AssetReservation{Id: "r3", AssetId: "a1", EmployeeId: "e3", Qty: 2, AssetDetailIds: []string{"u2", "u3"}, StartAt: at(9), EndAt: at(12), Status: model.ReservationActive, CreatedAt: at(8)}
+	s.expectCapacity(3, sqlmock.NewRows(reservationColumns))
+	s.mock.ExpectExec("INSERT INTO asset_reservations").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_reservation_units").WithArgs("r3", "u2").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_reservation_units").WithArgs("r3", "u3").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.NoError(s.T(), s.repo.Create(reservation))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetReservationRepositorySuite) TestCancel_NotActive() {
+	s.mock.ExpectExec("UPDATE asset_reservations SET status").WithArgs(model.ReservationCancelled, "r1", model.ReservationActive).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.Cancel("r1")
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.ErrorContains(s.T(), err, "no longer active")
+}
+
+func TestAssetReservationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetReservationRepositorySuite))
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type AssetTransferRepository interface {
+	Create(bodyRequest model.AssetTransfer) error
+	Get(id string) (model.AssetTransfer, error)
+	List(status model.TransferStatus) ([]model.AssetTransfer, error)
+	ActiveTransferOf(unitId string) (string, error)
+	Decide(bodyRequest model.AssetTransfer, from ...model.TransferStatus) error
+	Dispatch(bodyRequest model.AssetTransfer, units []model.AssetDetail, dispatchedAt time.Time) error
+	Receive(bodyRequest model.AssetTransfer, units []model.TransferUnit, receivedBy string, receivedAt time.Time) (model.TransferStatus, error)
+}
+
+type assetTransferRepository struct {
+	db *sql.DB
+}
+
+const assetTransferSelect = "SELECT t.id,t.source_location_id,t.destination_location_id,t.reason,t.status,t.requested_by,t.decided_by,t.decided_at,t.dispatched_by,t.dispatched_at,t.completed_at,t.note,t.created_at,array_agg(tu.asset_detail_id ORDER BY tu.asset_detail_id) FROM asset_transfers t JOIN asset_transfer_units tu ON tu.transfer_id=t.id"
+
+// Create opens a transfer. The units are locked in a fixed order and checked
+// for open transfers again, so two requests for the same unit can't both be
+// opened.
+func (a *assetTransferRepository) Create(bodyRequest model.AssetTransfer) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	unitIds := make([]string, 0, len(bodyRequest.Units))
+	for _, unit := range bodyRequest.Units {
+		unitIds = append(unitIds, unit.AssetDetailId)
+	}
+	sort.Strings(unitIds)
+
+	for _, unitId := range unitIds {
+		if _, err := lockUnit(tx, unitId); err != nil {
+			return err
+		}
+
+		transferId, err := activeTransferOf(tx.QueryRow, unitId)
+		if err != nil {
+			return err
+		}
+		if transferId != "" {
+			return fmt.Errorf("asset unit %s %w, it is on transfer %s now", unitId, ErrChanged, transferId)
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO asset_transfers(id,source_location_id,destination_location_id,reason,status,requested_by,created_at) VALUES($1,$2,$3,$4,$5,$6,$7)", bodyRequest.Id, bodyRequest.SourceLocationId, bodyRequest.DestinationLocationId, bodyRequest.Reason, bodyRequest.Status, bodyRequest.RequestedBy, bodyRequest.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, unit := range bodyRequest.Units {
+		_, err := tx.Exec("INSERT INTO asset_transfer_units(transfer_id,asset_detail_id,asset_id,status) VALUES($1,$2,$3,$4)", bodyRequest.Id, unit.AssetDetailId, unit.AssetId, unit.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get returns a transfer together with the state of each of its units.
+func (a *assetTransferRepository) Get(id string) (model.AssetTransfer, error) {
+	transfer, err := scanAssetTransfer(a.db.QueryRow(assetTransferSelect+" WHERE t.id=$1 GROUP BY t.id", id))
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	rows, err := a.db.Query("SELECT transfer_id,asset_detail_id,asset_id,status,discrepancy,received_by,received_at FROM asset_transfer_units WHERE transfer_id=$1 ORDER BY asset_detail_id", id)
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var unit model.TransferUnit
+		err := rows.Scan(&unit.TransferId, &unit.AssetDetailId, &unit.AssetId, &unit.Status, &unit.Discrepancy, &unit.ReceivedBy, &unit.ReceivedAt)
+		if err != nil {
+			return model.AssetTransfer{}, err
+		}
+
+		transfer.Units = append(transfer.Units, unit)
+	}
+
+	return transfer, rows.Err()
+}
+
+// List lists transfers with the given status, or all of them when status is
+// empty, the newest first.
+func (a *assetTransferRepository) List(status model.TransferStatus) ([]model.AssetTransfer, error) {
+	query := assetTransferSelect + " GROUP BY t.id ORDER BY t.created_at DESC"
+	args := []any{}
+	if status != "" {
+		query = assetTransferSelect + " WHERE t.status=$1 GROUP BY t.id ORDER BY t.created_at DESC"
+		args = append(args, status)
+	}
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []model.AssetTransfer
+	for rows.Next() {
+		transfer, err := scanAssetTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+// ActiveTransferOf returns the id of the open transfer still waiting to move
+// or deliver the unit, or an empty id when there is none.
+func (a *assetTransferRepository) ActiveTransferOf(unitId string) (string, error) {
+	return activeTransferOf(a.db.QueryRow, unitId)
+}
+
+// activeTransferOf looks up the open transfer of a unit on the database or
+// inside a transaction.
+func activeTransferOf(queryRow func(query string, args ...any) *sql.Row, unitId string) (string, error) {
+	var id string
+	err := queryRow("SELECT t.id FROM asset_transfers t JOIN asset_transfer_units tu ON tu.transfer_id=t.id WHERE tu.asset_detail_id=$1 AND tu.status IN ($2,$3) AND t.status NOT IN ($4,$5) LIMIT 1",
+		unitId, model.TransferUnitPending, model.TransferUnitInTransit, model.TransferRejected, model.TransferCancelled).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return id, err
+}
+
+// Decide records an approval, rejection or cancellation, as long as the
+// transfer is still in one of the from statuses.
+func (a *assetTransferRepository) Decide(bodyRequest model.AssetTransfer, from ...model.TransferStatus) error {
+	statuses := make([]string, 0, len(from))
+	for _, status := range from {
+		statuses = append(statuses, string(status))
+	}
+
+	result, err := a.db.Exec("UPDATE asset_transfers SET status=$1, decided_by=$2, decided_at=$3, note=$4 WHERE id=$5 AND status = ANY($6)", bodyRequest.Status, bodyRequest.DecidedBy, bodyRequest.DecidedAt, bodyRequest.Note, bodyRequest.Id, pq.Array(statuses))
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("transfer %s %w, try again", bodyRequest.Id, ErrChanged)
+	}
+
+	return nil
+}
+
+// Dispatch puts the units in-transit in one transaction. units are the rows
+// the caller validated, when one of them changed since nothing is moved.
+// The units stay at the source location until they are received.
+func (a *assetTransferRepository) Dispatch(bodyRequest model.AssetTransfer, units []model.AssetDetail, dispatchedAt time.Time) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTransfer(tx, bodyRequest.Id, model.TransferApproved); err != nil {
+		return err
+	}
+
+	for _, expected := range units {
+		unit, err := lockUnit(tx, expected.Id)
+		if err != nil {
+			return err
+		}
+
+		if unit.Status != expected.Status || unit.LocationId != bodyRequest.SourceLocationId || unit.RemovedAt != nil {
+			return fmt.Errorf("asset unit %s %w, try again", unit.Id, ErrChanged)
+		}
+
+		err = moveUnit(tx, unit, model.AssetMovement{
+			ToLocationId: unit.LocationId,
+			ToStatus:     model.StatusInTransit,
+			BatchId:      bodyRequest.Id,
+			Actor:        bodyRequest.DispatchedBy,
+			MovedAt:      dispatchedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("UPDATE asset_transfer_units SET status=$1 WHERE transfer_id=$2 AND asset_detail_id=$3", model.TransferUnitInTransit, bodyRequest.Id, unit.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE asset_transfers SET status=$1, dispatched_by=$2, dispatched_at=$3 WHERE id=$4", model.TransferInTransit, bodyRequest.DispatchedBy, dispatchedAt, bodyRequest.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Receive books the units confirmed by receivedBy, the custodian of the
+// destination. Received units move to the destination in storage, missing
+// ones are marked lost where they were dispatched from. The transfer is
+// received once no unit is in-transit anymore and partially received until
+// then.
+func (a *assetTransferRepository) Receive(bodyRequest model.AssetTransfer, units []model.TransferUnit, receivedBy string, receivedAt time.Time) (model.TransferStatus, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if err := lockTransfer(tx, bodyRequest.Id, model.TransferInTransit, model.TransferPartiallyReceived); err != nil {
+		return "", err
+	}
+
+	for _, line := range units {
+		var status model.TransferUnitStatus
+		err := tx.QueryRow("SELECT status FROM asset_transfer_units WHERE transfer_id=$1 AND asset_detail_id=$2 FOR UPDATE", bodyRequest.Id, line.AssetDetailId).Scan(&status)
+		if err != nil {
+			return "", err
+		}
+		if status != model.TransferUnitInTransit {
+			return "", fmt.Errorf("asset unit %s is already %s", line.AssetDetailId, status)
+		}
+
+		unit, err := lockUnit(tx, line.AssetDetailId)
+		if err != nil {
+			return "", err
+		}
+		if unit.Status != model.StatusInTransit {
+			return "", fmt.Errorf("asset unit %s is %s instead of in-transit", unit.Id, unit.Status)
+		}
+
+		movement := model.AssetMovement{
+			ToLocationId: bodyRequest.DestinationLocationId,
+			ToStatus:     model.StatusInStorage,
+			BatchId:      bodyRequest.Id,
+			Actor:        receivedBy,
+			MovedAt:      receivedAt,
+		}
+		if line.Status == model.TransferUnitMissing {
+			movement.ToLocationId = unit.LocationId
+			movement.ToStatus = model.StatusLost
+		}
+
+		if err := moveUnit(tx, unit, movement); err != nil {
+			return "", err
+		}
+
+		_, err = tx.Exec("UPDATE asset_transfer_units SET status=$1, discrepancy=$2, received_by=$3, received_at=$4 WHERE transfer_id=$5 AND asset_detail_id=$6", line.Status, line.Discrepancy, receivedBy, receivedAt, bodyRequest.Id, line.AssetDetailId)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var remaining int
+	err = tx.QueryRow("SELECT count(*) FROM asset_transfer_units WHERE transfer_id=$1 AND status=$2", bodyRequest.Id, model.TransferUnitInTransit).Scan(&remaining)
+	if err != nil {
+		return "", err
+	}
+
+	status := model.TransferPartiallyReceived
+	var completedAt any
+	if remaining == 0 {
+		status = model.TransferReceived
+		completedAt = receivedAt
+	}
+
+	_, err = tx.Exec("UPDATE asset_transfers SET status=$1, completed_at=$2 WHERE id=$3", status, completedAt, bodyRequest.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// lockTransfer locks a transfer until the transaction ends and checks it is
+// in one of the expected statuses.
+func lockTransfer(tx *sql.Tx, id string, expected ...model.TransferStatus) error {
+	var status model.TransferStatus
+	err := tx.QueryRow("SELECT status FROM asset_transfers WHERE id=$1 FOR UPDATE", id).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	for _, allowed := range expected {
+		if status == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("transfer %s is %s", id, status)
+}
+
+func scanAssetTransfer(row interface{ Scan(dest ...any) error }) (model.AssetTransfer, error) {
+	var transfer model.AssetTransfer
+	err := row.Scan(&transfer.Id, &transfer.SourceLocationId, &transfer.DestinationLocationId, &transfer.Reason, &transfer.Status, &transfer.RequestedBy, &transfer.DecidedBy, &transfer.DecidedAt, &transfer.DispatchedBy, &transfer.DispatchedAt, &transfer.CompletedAt, &transfer.Note, &transfer.CreatedAt, pq.Array(&transfer.AssetDetailIds))
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	return transfer, nil
+}
+
+func NewAssetTransferRepository(db *sql.DB) AssetTransferRepository {
+	return &assetTransferRepository{
+		db: db,
+	}
+}
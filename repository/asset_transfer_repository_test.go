@@ -0,0 +1,168 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetTransferRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.AssetTransferRepository
+}
+
+func (s *AssetTransferRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewAssetTransferRepository(db)
+}
+
+func (s *AssetTransferRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+var dummyTransfer = model.AssetTransfer{
+	Id:                    "t1",
+	SourceLocationId:      "l1",
+	DestinationLocationId: "l2",
+	DispatchedBy:          "Budi",
+}
+
+func (s *AssetTransferRepositorySuite) expectTransfer(status model.TransferStatus) {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM asset_transfers").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(status))
+}
+
+func (s *AssetTransferRepositorySuite) expectUnit(id, locationId string, status model.AssetStatus) {
+	s.mock.ExpectQuery("SELECT id,asset_id,location_id,status,updated_at,removed_at FROM asset_details").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+			AddRow(id, "a1", locationId, status, time.Now(), nil))
+}
+
+func (s *AssetTransferRepositorySuite) expectLine(id string, status model.TransferUnitStatus) {
+	s.mock.ExpectQuery("SELECT status FROM asset_transfer_units").WithArgs("t1", id).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(status))
+}
+
+func (s *AssetTransferRepositorySuite) expectOpenTransfer(unitId, transferId string) {
+	rows := sqlmock.NewRows([]string{"id"})
+	if transferId != "" {
+		rows.AddRow(transferId)
+	}
+	s.mock.ExpectQuery("SELECT t.id FROM asset_transfers").WithArgs(unitId, model.TransferUnitPending, model.TransferUnitInTransit, model.TransferRejected, model.TransferCancelled).
+		WillReturnRows(rows)
+}
+
+func pendingTransfer() model.AssetTransfer {
+	transfer := dummyTransfer
+	transfer.Status = model.TransferPending
+	transfer.RequestedBy = "Budi"
+	transfer.Units = []model.TransferUnit{
+		{TransferId: "t1", AssetDetailId: "u2", AssetId: "a1", Status: model.TransferUnitPending},
+		{TransferId: "t1", AssetDetailId: "u1", AssetId: "a1", Status: model.TransferUnitPending},
+	}
+	return transfer
+}
+
+func (s *AssetTransferRepositorySuite) TestCreate_LocksUnits() {
+	s.mock.ExpectBegin()
+	s.expectUnit("u1", "l1", model.StatusInStorage)
+	s.expectOpenTransfer("u1", "")
+	s.expectUnit("u2", "l1", model.StatusInStorage)
+	s.expectOpenTransfer("u2", "")
+	s.mock.ExpectExec("INSERT INTO asset_transfers").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_transfer_units").WithArgs("t1", "u2", "a1", model.TransferUnitPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_transfer_units").WithArgs("t1", "u1", "a1", model.TransferUnitPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Create(pendingTransfer())
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetTransferRepositorySuite) TestCreate_UnitTakenMeanwhile() {
+	s.mock.ExpectBegin()
+	s.expectUnit("u1", "l1", model.StatusInStorage)
+	s.expectOpenTransfer("u1", "t0")
+	s.mock.ExpectRollback()
+
+	err := s.repo.Create(pendingTransfer())
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.EqualError(s.T(), err, "asset unit u1 changed since it was checked, it is on transfer t0 now")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetTransferRepositorySuite) TestDispatch_Success() {
+	s.expectTransfer(model.TransferApproved)
+	s.expectUnit("u1", "l1", model.StatusInStorage)
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusInTransit, sqlmock.AnyArg(), "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_transfer_units SET status").WithArgs(model.TransferUnitInTransit, "t1", "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_transfers SET status").WithArgs(model.TransferInTransit, "Budi", sqlmock.AnyArg(), "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Dispatch(dummyTransfer, []model.AssetDetail{{Id: "u1", LocationId: "l1", Status: model.StatusInStorage}}, time.Now())
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetTransferRepositorySuite) TestDispatch_NotApproved() {
+	s.expectTransfer(model.TransferPending)
+	s.mock.ExpectRollback()
+
+	err := s.repo.Dispatch(dummyTransfer, []model.AssetDetail{{Id: "u1", LocationId: "l1", Status: model.StatusInStorage}}, time.Now())
+	assert.EqualError(s.T(), err, "transfer t1 is pending")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetTransferRepositorySuite) TestReceive_Partial() {
+	s.expectTransfer(model.TransferInTransit)
+	s.expectLine("u1", model.TransferUnitInTransit)
+	s.expectUnit("u1", "l1", model.StatusInTransit)
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l2", model.StatusInStorage, sqlmock.AnyArg(), "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_transfer_units SET status").WithArgs(model.TransferUnitReceived, "", "e1", sqlmock.AnyArg(), "t1", "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT count").WithArgs("t1", model.TransferUnitInTransit).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec("UPDATE asset_transfers SET status").WithArgs(model.TransferPartiallyReceived, nil, "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	status, err := s.repo.Receive(dummyTransfer, []model.TransferUnit{{AssetDetailId: "u1", Status: model.TransferUnitReceived}}, "e1", time.Now())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.TransferPartiallyReceived, status)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AssetTransferRepositorySuite) TestReceive_MissingUnitIsLost() {
+	s.expectTransfer(model.TransferPartiallyReceived)
+	s.expectLine("u2", model.TransferUnitInTransit)
+	s.expectUnit("u2", "l1", model.StatusInTransit)
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusLost, sqlmock.AnyArg(), "u2").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_transfer_units SET status").WithArgs(model.TransferUnitMissing, "box arrived empty", "e1", sqlmock.AnyArg(), "t1", "u2").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT count").WithArgs("t1", model.TransferUnitInTransit).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec("UPDATE asset_transfers SET status").WithArgs(model.TransferReceived, sqlmock.AnyArg(), "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	status, err := s.repo.Receive(dummyTransfer, []model.TransferUnit{{AssetDetailId: "u2", Status: model.TransferUnitMissing, Discrepancy: "box arrived empty"}}, "e1", time.Now())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.TransferReceived, status)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestAssetTransferRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssetTransferRepositorySuite))
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+	"math"
+)
+
+type ConsumableRepository interface {
+	Create(bodyRequest model.Consumable) error
+	Update(bodyRequest model.Consumable) error
+	Get(id string) (model.Consumable, error)
+	List() ([]model.ConsumableLevel, error)
+	LowStock() ([]model.ConsumableLevel, error)
+	Stock(consumableId string) ([]model.ConsumableStock, error)
+	ApplyTransaction(bodyRequest model.ConsumableTransaction) (model.ConsumableTransaction, error)
+	ListTransactions(consumableId string) ([]model.ConsumableTransaction, error)
+}
+
+type consumableRepository struct {
+	db *sql.DB
+}
+
+const consumableLevelSelect = "SELECT c.id,c.name,c.description,c.unit,c.reorder_point,c.created_at,COALESCE(SUM(s.qty),0) AS total_qty " +
+	"FROM consumables c LEFT JOIN consumable_stock s ON s.consumable_id=c.id GROUP BY c.id"
+
+const consumableTransactionSelect = "SELECT id,consumable_id,location_id,type,qty,delta,balance,employee_id,note,actor,created_at FROM consumable_transactions"
+
+func (c *consumableRepository) Create(bodyRequest model.Consumable) error {
+	_, err := c.db.Exec("INSERT INTO consumables(id,name,description,unit,reorder_point,created_at) VALUES($1,$2,$3,$4,$5,$6)", bodyRequest.Id, bodyRequest.Name, bodyRequest.Description, bodyRequest.Unit, bodyRequest.ReorderPoint, bodyRequest.CreatedAt)
+	return err
+}
+
+func (c *consumableRepository) Update(bodyRequest model.Consumable) error {
+	_, err := c.db.Exec("UPDATE consumables SET name=$1, description=$2, unit=$3, reorder_point=$4 WHERE id=$5", bodyRequest.Name, bodyRequest.Description, bodyRequest.Unit, bodyRequest.ReorderPoint, bodyRequest.Id)
+	return err
+}
+
+func (c *consumableRepository) Get(id string) (model.Consumable, error) {
+	var consumable model.Consumable
+	err := c.db.QueryRow("SELECT id,name,description,unit,reorder_point,created_at FROM consumables WHERE id=$1", id).Scan(&consumable.Id, &consumable.Name, &consumable.Description, &consumable.Unit, &consumable.ReorderPoint, &consumable.CreatedAt)
+	if err != nil {
+		return model.Consumable{}, err
+	}
+
+	return consumable, nil
+}
+
+func (c *consumableRepository) List() ([]model.ConsumableLevel, error) {
+	return c.listLevels(consumableLevelSelect + " ORDER BY c.name")
+}
+
+// LowStock lists consumables whose total stock is below their reorder
+// point, the furthest below first.
+func (c *consumableRepository) LowStock() ([]model.ConsumableLevel, error) {
+	return c.listLevels(consumableLevelSelect + " HAVING COALESCE(SUM(s.qty),0) < c.reorder_point ORDER BY COALESCE(SUM(s.qty),0) - c.reorder_point, c.name")
+}
+
+func (c *consumableRepository) Stock(consumableId string) ([]model.ConsumableStock, error) {
+	rows, err := c.db.Query("SELECT consumable_id,location_id,qty FROM consumable_stock WHERE consumable_id=$1 ORDER BY location_id", consumableId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stock []model.ConsumableStock
+	for rows.Next() {
+		var row model.ConsumableStock
+		if err := rows.Scan(&row.ConsumableId, &row.LocationId, &row.Qty); err != nil {
+			return nil, err
+		}
+
+		stock = append(stock, row)
+	}
+
+	return stock, rows.Err()
+}
+
+// ApplyTransaction locks the stock row of the location, works out the
+// change for the transaction type and records it. Stock can't go below zero.
+func (c *consumableRepository) ApplyTransaction(bodyRequest model.ConsumableTransaction) (model.ConsumableTransaction, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return model.ConsumableTransaction{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO consumable_stock(consumable_id,location_id,qty) VALUES($1,$2,0) ON CONFLICT DO NOTHING", bodyRequest.ConsumableId, bodyRequest.LocationId)
+	if err != nil {
+		return model.ConsumableTransaction{}, err
+	}
+
+	var current float64
+	err = tx.QueryRow("SELECT qty FROM consumable_stock WHERE consumable_id=$1 AND location_id=$2 FOR UPDATE", bodyRequest.ConsumableId, bodyRequest.LocationId).Scan(&current)
+	if err != nil {
+		return model.ConsumableTransaction{}, err
+	}
+
+	switch bodyRequest.Type {
+	case model.ConsumableReceive:
+		bodyRequest.Delta = bodyRequest.Qty
+	case model.ConsumableIssue:
+		bodyRequest.Delta = -bodyRequest.Qty
+	case model.ConsumableAdjust:
+		bodyRequest.Delta = bodyRequest.Qty - current
+	default:
+		return model.ConsumableTransaction{}, fmt.Errorf("unknown transaction type %s", bodyRequest.Type)
+	}
+
+	// Quantities are stored with three decimals
+	bodyRequest.Balance = math.Round((current+bodyRequest.Delta)*1000) / 1000
+	bodyRequest.Delta = math.Round((bodyRequest.Balance-current)*1000) / 1000
+	if bodyRequest.Balance < 0 {
+		return model.ConsumableTransaction{}, fmt.Errorf("%w, only %v left at location %s", ErrNotEnoughStock, current, bodyRequest.LocationId)
+	}
+
+	_, err = tx.Exec("UPDATE consumable_stock SET qty=$1 WHERE consumable_id=$2 AND location_id=$3", bodyRequest.Balance, bodyRequest.ConsumableId, bodyRequest.LocationId)
+	if err != nil {
+		return model.ConsumableTransaction{}, err
+	}
+
+	_, err = tx.Exec("INSERT INTO consumable_transactions(id,consumable_id,location_id,type,qty,delta,balance,employee_id,note,actor,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)",
+		bodyRequest.Id, bodyRequest.ConsumableId, bodyRequest.LocationId, bodyRequest.Type, bodyRequest.Qty, bodyRequest.Delta, bodyRequest.Balance, bodyRequest.EmployeeId, bodyRequest.Note, bodyRequest.Actor, bodyRequest.CreatedAt)
+	if err != nil {
+		return model.ConsumableTransaction{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ConsumableTransaction{}, err
+	}
+
+	return bodyRequest, nil
+}
+
+func (c *consumableRepository) ListTransactions(consumableId string) ([]model.ConsumableTransaction, error) {
+	rows, err := c.db.Query(consumableTransactionSelect+" WHERE consumable_id=$1 ORDER BY created_at DESC", consumableId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []model.ConsumableTransaction
+	for rows.Next() {
+		var transaction model.ConsumableTransaction
+		err := rows.Scan(&transaction.Id, &transaction.ConsumableId, &transaction.LocationId, &transaction.Type, &transaction.Qty, &transaction.Delta, &transaction.Balance, &transaction.EmployeeId, &transaction.Note, &transaction.Actor, &transaction.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (c *consumableRepository) listLevels(query string, args ...any) ([]model.ConsumableLevel, error) {
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []model.ConsumableLevel
+	for rows.Next() {
+		var level model.ConsumableLevel
+		err := rows.Scan(&level.Id, &level.Name, &level.Description, &level.Unit, &level.ReorderPoint, &level.CreatedAt, &level.TotalQty)
+		if err != nil {
+			return nil, err
+		}
+
+		levels = append(levels, level)
+	}
+
+	return levels, rows.Err()
+}
+
+func NewConsumableRepository(db *sql.DB) ConsumableRepository {
+	return &consumableRepository{
+		db: db,
+	}
+}
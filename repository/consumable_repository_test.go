@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsumableRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.ConsumableRepository
+}
+
+func (s *ConsumableRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewConsumableRepository(db)
+}
+
+func (s *ConsumableRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *ConsumableRepositorySuite) expectStock(qty float64) {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO consumable_stock").WithArgs("c1", "l1").WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery("SELECT qty FROM consumable_stock").WithArgs("c1", "l1").
+		WillReturnRows(sqlmock.NewRows([]string{"qty"}).AddRow(qty))
+}
+
+func (s *ConsumableRepositorySuite) TestApplyTransaction_Issue() {
+	s.expectStock(10)
+	s.mock.ExpectExec("UPDATE consumable_stock SET qty").WithArgs(7.5, "c1", "l1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO consumable_transactions").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	transaction, err := s.repo.ApplyTransaction(model.ConsumableTransaction{Id: "t1", ConsumableId: "c1", LocationId: "l1", Type: model.ConsumableIssue, Qty: 2.5, CreatedAt: time.Now()})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), -2.5, transaction.Delta)
+	assert.Equal(s.T(), 7.5, transaction.Balance)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *ConsumableRepositorySuite) TestApplyTransaction_IssueMoreThanStock() {
+	s.expectStock(3)
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.ApplyTransaction(model.ConsumableTransaction{Id: "t1", ConsumableId: "c1", LocationId: "l1", Type: model.ConsumableIssue, Qty: 5})
+	assert.ErrorIs(s.T(), err, repository.ErrNotEnoughStock)
+	assert.ErrorContains(s.T(), err, "only 3 left at location l1")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *ConsumableRepositorySuite) TestApplyTransaction_AdjustToCount() {
+	s.expectStock(0.3)
+	s.mock.ExpectExec("UPDATE consumable_stock SET qty").WithArgs(0.1, "c1", "l1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO consumable_transactions").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	transaction, err := s.repo.ApplyTransaction(model.ConsumableTransaction{Id: "t1", ConsumableId: "c1", LocationId: "l1", Type: model.ConsumableAdjust, Qty: 0.1, Note: "recount"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), -0.2, transaction.Delta)
+	assert.Equal(s.T(), 0.1, transaction.Balance)
+}
+
+func (s *ConsumableRepositorySuite) TestLowStock() {
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "unit", "reorder_point", "created_at", "total_qty"}).
+		AddRow("c1", "Toner", "", "cartridge", 5, time.Now(), 2)
+
+	s.mock.ExpectQuery("HAVING COALESCE\\(SUM\\(s.qty\\),0\\) < c.reorder_point").WillReturnRows(rows)
+
+	levels, err := s.repo.LowStock()
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), levels, 1)
+	assert.Equal(s.T(), float64(2), levels[0].TotalQty)
+}
+
+func TestConsumableRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ConsumableRepositorySuite))
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrInUse is returned when a row can't be deleted because other records
+// still point at it.
+var ErrInUse = errors.New("still in use")
+
+// ErrNotEnoughStock is returned when a stock transaction would take the
+// quantity at a location below zero.
+var ErrNotEnoughStock = errors.New("not enough stock")
+
+// ErrChanged is returned when a row changed between the checks of the caller
+// and the write, checking again may succeed.
+var ErrChanged = errors.New("changed since it was checked")
+
+// ErrReserved is returned when a booking or a checkout collides with the
+// reservations held on an asset.
+var ErrReserved = errors.New("reserved")
+
+// inUseError turns a foreign key violation into ErrInUse naming the table
+// that still points at the row, any other error is returned unchanged.
+func inUseError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w by %s", ErrInUse, pqErr.Table)
+	}
+
+	return err
+}
@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/utils/common"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ScheduleActor is recorded as the actor of work orders generated by
+// preventive schedules.
+const ScheduleActor = "maintenance-schedule"
+
+type MaintenanceRepository interface {
+	OpenWorkOrder(bodyRequest model.WorkOrder) error
+	UpdateWorkOrder(bodyRequest model.WorkOrder) error
+	CloseWorkOrder(bodyRequest model.WorkOrder, closedAt time.Time) error
+	GetWorkOrder(id string) (model.WorkOrder, error)
+	ListWorkOrders(status model.WorkOrderStatus) ([]model.WorkOrder, error)
+	ListUnitWorkOrders(assetDetailId string) ([]model.WorkOrder, error)
+	CreateSchedule(bodyRequest model.MaintenanceSchedule) error
+	UpdateSchedule(bodyRequest model.MaintenanceSchedule) error
+	GetSchedule(id string) (model.MaintenanceSchedule, error)
+	ListSchedules() ([]model.MaintenanceSchedule, error)
+	DueSchedules(today time.Time) ([]model.MaintenanceSchedule, error)
+	RunSchedule(scheduleId string, today, openedAt time.Time) ([]model.WorkOrder, []string, error)
+}
+
+type maintenanceRepository struct {
+	db *sql.DB
+}
+
+const workOrderSelect = "SELECT id,asset_detail_id,asset_id,schedule_id,type,status,description,cost,vendor_id,technician,prior_status,actor,opened_at,closed_at FROM work_orders"
+
+const maintenanceScheduleSelect = "SELECT id,asset_id,category_id,description,interval_days,vendor_id,technician,next_due_date,active,last_run_at,created_at FROM maintenance_schedules"
+
+// OpenWorkOrder records the work order and puts the unit in maintenance in
+// one transaction.
+func (m *maintenanceRepository) OpenWorkOrder(bodyRequest model.WorkOrder) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	unit, err := lockUnit(tx, bodyRequest.AssetDetailId)
+	if err != nil {
+		return err
+	}
+
+	if err := openWorkOrder(tx, unit, bodyRequest); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *maintenanceRepository) UpdateWorkOrder(bodyRequest model.WorkOrder) error {
+	result, err := m.db.Exec("UPDATE work_orders SET status=$1, description=$2, cost=$3, vendor_id=$4, technician=$5 WHERE id=$6 AND status IN ($7,$8)", bodyRequest.Status, bodyRequest.Description, bodyRequest.Cost, bodyRequest.VendorId, bodyRequest.Technician, bodyRequest.Id, model.WorkOrderOpen, model.WorkOrderInProgress)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("work order %s %w, it is already closed", bodyRequest.Id, ErrChanged)
+	}
+
+	return nil
+}
+
+// CloseWorkOrder completes or cancels an open work order and moves the unit
+// back to the status it had before. Actor is who closes it, it is only kept
+// in the movement history.
+func (m *maintenanceRepository) CloseWorkOrder(bodyRequest model.WorkOrder, closedAt time.Time) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		status      model.WorkOrderStatus
+		priorStatus model.AssetStatus
+	)
+	err = tx.QueryRow("SELECT status,prior_status FROM work_orders WHERE id=$1 FOR UPDATE", bodyRequest.Id).Scan(&status, &priorStatus)
+	if err != nil {
+		return err
+	}
+
+	if !status.IsOpen() {
+		return fmt.Errorf("work order %s %w, it is already %s", bodyRequest.Id, ErrChanged, status)
+	}
+
+	_, err = tx.Exec("UPDATE work_orders SET status=$1, description=$2, cost=$3, vendor_id=$4, technician=$5, closed_at=$6 WHERE id=$7", bodyRequest.Status, bodyRequest.Description, bodyRequest.Cost, bodyRequest.VendorId, bodyRequest.Technician, closedAt, bodyRequest.Id)
+	if err != nil {
+		return err
+	}
+
+	unit, err := lockUnit(tx, bodyRequest.AssetDetailId)
+	if err != nil {
+		return err
+	}
+
+	// a unit retired while in maintenance stays where it is
+	if unit.Status == model.StatusInMaintenance && unit.RemovedAt == nil {
+		err := moveUnit(tx, unit, model.AssetMovement{
+			ToLocationId: unit.LocationId,
+			ToStatus:     priorStatus,
+			BatchId:      bodyRequest.Id,
+			Actor:        bodyRequest.Actor,
+			MovedAt:      closedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *maintenanceRepository) GetWorkOrder(id string) (model.WorkOrder, error) {
+	return scanWorkOrder(m.db.QueryRow(workOrderSelect+" WHERE id=$1", id))
+}
+
+// ListWorkOrders lists work orders with the given status, or all of them
+// when status is empty, the newest first.
+func (m *maintenanceRepository) ListWorkOrders(status model.WorkOrderStatus) ([]model.WorkOrder, error) {
+	if status == "" {
+		return m.listWorkOrders(workOrderSelect + " ORDER BY opened_at DESC")
+	}
+
+	return m.listWorkOrders(workOrderSelect+" WHERE status=$1 ORDER BY opened_at DESC", status)
+}
+
+func (m *maintenanceRepository) ListUnitWorkOrders(assetDetailId string) ([]model.WorkOrder, error) {
+	return m.listWorkOrders(workOrderSelect+" WHERE asset_detail_id=$1 ORDER BY opened_at DESC", assetDetailId)
+}
+
+func (m *maintenanceRepository) CreateSchedule(bodyRequest model.MaintenanceSchedule) error {
+	_, err := m.db.Exec("INSERT INTO maintenance_schedules(id,asset_id,category_id,description,interval_days,vendor_id,technician,next_due_date,active,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)", bodyRequest.Id, bodyRequest.AssetId, bodyRequest.CategoryId, bodyRequest.Description, bodyRequest.IntervalDays, bodyRequest.VendorId, bodyRequest.Technician, bodyRequest.NextDueDate, bodyRequest.Active, bodyRequest.CreatedAt)
+	return err
+}
+
+func (m *maintenanceRepository) UpdateSchedule(bodyRequest model.MaintenanceSchedule) error {
+	_, err := m.db.Exec("UPDATE maintenance_schedules SET description=$1, interval_days=$2, vendor_id=$3, technician=$4, next_due_date=$5, active=$6 WHERE id=$7", bodyRequest.Description, bodyRequest.IntervalDays, bodyRequest.VendorId, bodyRequest.Technician, bodyRequest.NextDueDate, bodyRequest.Active, bodyRequest.Id)
+	return err
+}
+
+func (m *maintenanceRepository) GetSchedule(id string) (model.MaintenanceSchedule, error) {
+	return scanMaintenanceSchedule(m.db.QueryRow(maintenanceScheduleSelect+" WHERE id=$1", id))
+}
+
+func (m *maintenanceRepository) ListSchedules() ([]model.MaintenanceSchedule, error) {
+	return m.listSchedules(maintenanceScheduleSelect + " ORDER BY next_due_date,id")
+}
+
+func (m *maintenanceRepository) DueSchedules(today time.Time) ([]model.MaintenanceSchedule, error) {
+	return m.listSchedules(maintenanceScheduleSelect+" WHERE active AND (next_due_date<=$1 OR EXISTS (SELECT 1 FROM maintenance_deferrals WHERE schedule_id=maintenance_schedules.id)) ORDER BY next_due_date,id", today)
+}
+
+// RunSchedule opens a preventive work order for every unit the schedule
+// covers and moves its due date past today. Units that are assigned, in
+// transit, lost or already in maintenance are deferred and returned, they get
+// their work order on a later run once they are in storage or placed again,
+// even before the schedule is due next. The schedule row stays locked until
+// the end, so a schedule that is run twice at the same time only generates
+// work orders once.
+func (m *maintenanceRepository) RunSchedule(scheduleId string, today, openedAt time.Time) ([]model.WorkOrder, []string, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	schedule, err := scanMaintenanceSchedule(tx.QueryRow(maintenanceScheduleSelect+" WHERE id=$1 FOR UPDATE", scheduleId))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !schedule.Active {
+		return nil, nil, nil
+	}
+
+	deferredIds, err := queryIds(tx, "SELECT asset_detail_id FROM maintenance_deferrals WHERE schedule_id=$1 ORDER BY asset_detail_id", schedule.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	due := !schedule.NextDueDate.After(today)
+	if !due && len(deferredIds) == 0 {
+		return nil, nil, nil
+	}
+
+	unitIds := deferredIds
+	if due {
+		if schedule.AssetId != nil {
+			unitIds, err = queryIds(tx, "SELECT id FROM asset_details WHERE asset_id=$1 AND removed_at IS NULL ORDER BY id", *schedule.AssetId)
+		} else {
+			unitIds, err = queryIds(tx, "SELECT d.id FROM asset_details d JOIN asset a ON a.id=d.asset_id WHERE a.category_id=$1 AND d.removed_at IS NULL ORDER BY d.id", schedule.CategoryId)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	wasDeferred := make(map[string]bool, len(deferredIds))
+	for _, unitId := range deferredIds {
+		wasDeferred[unitId] = true
+	}
+
+	// deferred units are visited even when the schedule no longer lists them,
+	// removed ones only get their deferral cleared. Units are locked in id
+	// order.
+	if due && len(deferredIds) > 0 {
+		covered := make(map[string]bool, len(unitIds))
+		for _, unitId := range unitIds {
+			covered[unitId] = true
+		}
+		for _, unitId := range deferredIds {
+			if !covered[unitId] {
+				unitIds = append(unitIds, unitId)
+			}
+		}
+		sort.Strings(unitIds)
+	}
+
+	var workOrders []model.WorkOrder
+	var deferred []string
+	for _, unitId := range unitIds {
+		unit, err := lockUnit(tx, unitId)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if unit.RemovedAt == nil && !canOpenWorkOrder(unit) {
+			_, err := tx.Exec("INSERT INTO maintenance_deferrals(schedule_id,asset_detail_id,due_date) VALUES($1,$2,$3) ON CONFLICT (schedule_id,asset_detail_id) DO NOTHING", schedule.Id, unit.Id, schedule.NextDueDate)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			deferred = append(deferred, unit.Id)
+			continue
+		}
+
+		if wasDeferred[unit.Id] {
+			_, err := tx.Exec("DELETE FROM maintenance_deferrals WHERE schedule_id=$1 AND asset_detail_id=$2", schedule.Id, unit.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// removed units are no longer serviced
+		if unit.RemovedAt != nil {
+			continue
+		}
+
+		workOrder := model.WorkOrder{
+			Id:            common.GenerateUUID(),
+			AssetDetailId: unit.Id,
+			ScheduleId:    &schedule.Id,
+			Type:          model.WorkOrderPreventive,
+			Status:        model.WorkOrderOpen,
+			Description:   schedule.Description,
+			VendorId:      schedule.VendorId,
+			Technician:    schedule.Technician,
+			Actor:         ScheduleActor,
+			OpenedAt:      openedAt,
+		}
+		if err := openWorkOrder(tx, unit, workOrder); err != nil {
+			return nil, nil, err
+		}
+
+		workOrder.AssetId = unit.AssetId
+		workOrder.PriorStatus = unit.Status
+		workOrders = append(workOrders, workOrder)
+	}
+
+	if due {
+		// runs missed while the scheduler was down are not made up for
+		nextDue := schedule.NextDueDate
+		for !nextDue.After(today) {
+			nextDue = nextDue.AddDate(0, 0, schedule.IntervalDays)
+		}
+
+		_, err = tx.Exec("UPDATE maintenance_schedules SET next_due_date=$1, last_run_at=$2 WHERE id=$3", nextDue, openedAt, schedule.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return workOrders, deferred, nil
+}
+
+// queryIds reads a single id column.
+func queryIds(tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (m *maintenanceRepository) listWorkOrders(query string, args ...any) ([]model.WorkOrder, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workOrders []model.WorkOrder
+	for rows.Next() {
+		workOrder, err := scanWorkOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		workOrders = append(workOrders, workOrder)
+	}
+
+	return workOrders, rows.Err()
+}
+
+func (m *maintenanceRepository) listSchedules(query string, args ...any) ([]model.MaintenanceSchedule, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []model.MaintenanceSchedule
+	for rows.Next() {
+		schedule, err := scanMaintenanceSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// canOpenWorkOrder reports whether a unit can go into maintenance, only
+// units in storage or placed can.
+func canOpenWorkOrder(unit model.AssetDetail) bool {
+	return unit.RemovedAt == nil && (unit.Status == model.StatusInStorage || unit.Status == model.StatusPlaced)
+}
+
+// openWorkOrder inserts the work order for a locked unit and moves the unit
+// into maintenance at the same location.
+func openWorkOrder(tx *sql.Tx, unit model.AssetDetail, workOrder model.WorkOrder) error {
+	if !canOpenWorkOrder(unit) {
+		return fmt.Errorf("asset unit %s %w, it is %s and can't go into maintenance", unit.Id, ErrChanged, unit.Status)
+	}
+
+	_, err := tx.Exec("INSERT INTO work_orders(id,asset_detail_id,asset_id,schedule_id,type,status,description,cost,vendor_id,technician,prior_status,actor,opened_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)", workOrder.Id, unit.Id, unit.AssetId, workOrder.ScheduleId, workOrder.Type, workOrder.Status, workOrder.Description, workOrder.Cost, workOrder.VendorId, workOrder.Technician, unit.Status, workOrder.Actor, workOrder.OpenedAt)
+	if err != nil {
+		return err
+	}
+
+	return moveUnit(tx, unit, model.AssetMovement{
+		ToLocationId: unit.LocationId,
+		ToStatus:     model.StatusInMaintenance,
+		BatchId:      workOrder.Id,
+		Actor:        workOrder.Actor,
+		MovedAt:      workOrder.OpenedAt,
+	})
+}
+
+func scanWorkOrder(row interface{ Scan(dest ...any) error }) (model.WorkOrder, error) {
+	var workOrder model.WorkOrder
+	err := row.Scan(&workOrder.Id, &workOrder.AssetDetailId, &workOrder.AssetId, &workOrder.ScheduleId, &workOrder.Type, &workOrder.Status, &workOrder.Description, &workOrder.Cost, &workOrder.VendorId, &workOrder.Technician, &workOrder.PriorStatus, &workOrder.Actor, &workOrder.OpenedAt, &workOrder.ClosedAt)
+	if err != nil {
+		return model.WorkOrder{}, err
+	}
+
+	return workOrder, nil
+}
+
+func scanMaintenanceSchedule(row interface{ Scan(dest ...any) error }) (model.MaintenanceSchedule, error) {
+	var schedule model.MaintenanceSchedule
+	err := row.Scan(&schedule.Id, &schedule.AssetId, &schedule.CategoryId, &schedule.Description, &schedule.IntervalDays, &schedule.VendorId, &schedule.Technician, &schedule.NextDueDate, &schedule.Active, &schedule.LastRunAt, &schedule.CreatedAt)
+	if err != nil {
+		return model.MaintenanceSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func NewMaintenanceRepository(db *sql.DB) MaintenanceRepository {
+	return &maintenanceRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,161 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MaintenanceRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.MaintenanceRepository
+}
+
+func (s *MaintenanceRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewMaintenanceRepository(db)
+}
+
+func (s *MaintenanceRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+var scheduleColumns = []string{"id", "asset_id", "category_id", "description", "interval_days", "vendor_id", "technician", "next_due_date", "active", "last_run_at", "created_at"}
+
+func (s *MaintenanceRepositorySuite) TestOpenWorkOrder_Success() {
+	openedAt := time.Now()
+	workOrder := model.WorkOrder{Id: "wo1", AssetDetailId: "u1", Type: model.WorkOrderCorrective, Status: model.WorkOrderOpen, Description: "fan noise", Actor: "tech", OpenedAt: openedAt}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(unitRows("u1", "l1", model.StatusPlaced))
+	s.mock.ExpectExec("INSERT INTO work_orders").WithArgs("wo1", "u1", "a1", nil, model.WorkOrderCorrective, model.WorkOrderOpen, "fan noise", 0.0, nil, "", model.StatusPlaced, "tech", openedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusInMaintenance, openedAt, "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), "u1", "a1", "l1", "l1", model.StatusPlaced, model.StatusInMaintenance, "wo1", "tech", openedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.OpenWorkOrder(workOrder)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *MaintenanceRepositorySuite) TestOpenWorkOrder_UnitAssigned() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(unitRows("u1", "l1", model.StatusAssigned))
+	s.mock.ExpectRollback()
+
+	err := s.repo.OpenWorkOrder(model.WorkOrder{Id: "wo1", AssetDetailId: "u1"})
+	assert.ErrorContains(s.T(), err, "is assigned and can't go into maintenance")
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *MaintenanceRepositorySuite) TestCloseWorkOrder_RestoresUnit() {
+	closedAt := time.Now()
+	workOrder := model.WorkOrder{Id: "wo1", AssetDetailId: "u1", Status: model.WorkOrderCompleted, Description: "fan replaced", Cost: 250000, Actor: "tech"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status,prior_status FROM work_orders WHERE id=\\$1 FOR UPDATE").WithArgs("wo1").WillReturnRows(sqlmock.NewRows([]string{"status", "prior_status"}).AddRow(model.WorkOrderInProgress, model.StatusPlaced))
+	s.mock.ExpectExec("UPDATE work_orders SET status").WithArgs(model.WorkOrderCompleted, "fan replaced", 250000.0, nil, "", closedAt, "wo1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(unitRows("u1", "l1", model.StatusInMaintenance))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusPlaced, closedAt, "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), "u1", "a1", "l1", "l1", model.StatusInMaintenance, model.StatusPlaced, "wo1", "tech", closedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.CloseWorkOrder(workOrder, closedAt)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *MaintenanceRepositorySuite) TestCloseWorkOrder_AlreadyClosed() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status,prior_status FROM work_orders WHERE id=\\$1 FOR UPDATE").WithArgs("wo1").WillReturnRows(sqlmock.NewRows([]string{"status", "prior_status"}).AddRow(model.WorkOrderCancelled, model.StatusPlaced))
+	s.mock.ExpectRollback()
+
+	err := s.repo.CloseWorkOrder(model.WorkOrder{Id: "wo1", AssetDetailId: "u1", Status: model.WorkOrderCompleted}, time.Now())
+	assert.ErrorContains(s.T(), err, "already cancelled")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *MaintenanceRepositorySuite) TestRunSchedule_DefersBusyUnits() {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	openedAt := today.Add(9 * time.Hour)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("FROM maintenance_schedules WHERE id=\\$1 FOR UPDATE").WithArgs("ms1").WillReturnRows(sqlmock.NewRows(scheduleColumns).
+		AddRow("ms1", nil, "c1", "quarterly service", 30, nil, "budi", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), true, nil, today))
+	s.mock.ExpectQuery("SELECT asset_detail_id FROM maintenance_deferrals").WithArgs("ms1").WillReturnRows(sqlmock.NewRows([]string{"asset_detail_id"}))
+	s.mock.ExpectQuery("SELECT d.id FROM asset_details d JOIN asset a").WithArgs("c1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("u1").AddRow("u2"))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u1").WillReturnRows(unitRows("u1", "l1", model.StatusInStorage))
+	s.mock.ExpectExec("INSERT INTO work_orders").WithArgs(sqlmock.AnyArg(), "u1", "a1", "ms1", model.WorkOrderPreventive, model.WorkOrderOpen, "quarterly service", 0.0, nil, "budi", model.StatusInStorage, repository.ScheduleActor, openedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusInMaintenance, openedAt, "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u2").WillReturnRows(unitRows("u2", "l1", model.StatusInMaintenance))
+	s.mock.ExpectExec("INSERT INTO maintenance_deferrals").WithArgs("ms1", "u2", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)).WillReturnResult(sqlmock.NewResult(0, 1))
+	// due 02-01 every 30 days: 03-03 has passed too, so the next run is 04-02
+	s.mock.ExpectExec("UPDATE maintenance_schedules SET next_due_date").WithArgs(time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), openedAt, "ms1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	workOrders, deferred, err := s.repo.RunSchedule("ms1", today, openedAt)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), workOrders, 1)
+	assert.Equal(s.T(), "u1", workOrders[0].AssetDetailId)
+	assert.Equal(s.T(), []string{"u2"}, deferred)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *MaintenanceRepositorySuite) TestRunSchedule_DeferredUnitIsBack() {
+	today := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	openedAt := today.Add(9 * time.Hour)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("FROM maintenance_schedules WHERE id=\\$1 FOR UPDATE").WithArgs("ms1").WillReturnRows(sqlmock.NewRows(scheduleColumns).
+		AddRow("ms1", nil, "c1", "quarterly service", 30, nil, "budi", time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), true, nil, today))
+	s.mock.ExpectQuery("SELECT asset_detail_id FROM maintenance_deferrals").WithArgs("ms1").WillReturnRows(sqlmock.NewRows([]string{"asset_detail_id"}).AddRow("u2").AddRow("u3"))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u2").WillReturnRows(unitRows("u2", "l1", model.StatusInStorage))
+	s.mock.ExpectExec("DELETE FROM maintenance_deferrals").WithArgs("ms1", "u2").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO work_orders").WithArgs(sqlmock.AnyArg(), "u2", "a1", "ms1", model.WorkOrderPreventive, model.WorkOrderOpen, "quarterly service", 0.0, nil, "budi", model.StatusInStorage, repository.ScheduleActor, openedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusInMaintenance, openedAt, "u2").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u3").WillReturnRows(unitRows("u3", "l1", model.StatusAssigned))
+	s.mock.ExpectExec("INSERT INTO maintenance_deferrals").WithArgs("ms1", "u3", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	// not due, the due date stays as it is
+	s.mock.ExpectCommit()
+
+	workOrders, deferred, err := s.repo.RunSchedule("ms1", today, openedAt)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), workOrders, 1)
+	assert.Equal(s.T(), "u2", workOrders[0].AssetDetailId)
+	assert.Equal(s.T(), []string{"u3"}, deferred)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *MaintenanceRepositorySuite) TestRunSchedule_NotDue() {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("FROM maintenance_schedules WHERE id=\\$1 FOR UPDATE").WithArgs("ms1").WillReturnRows(sqlmock.NewRows(scheduleColumns).
+		AddRow("ms1", "a1", nil, "service", 30, nil, "", today.AddDate(0, 0, 30), true, today, today))
+	s.mock.ExpectQuery("SELECT asset_detail_id FROM maintenance_deferrals").WithArgs("ms1").WillReturnRows(sqlmock.NewRows([]string{"asset_detail_id"}))
+	s.mock.ExpectRollback()
+
+	workOrders, _, err := s.repo.RunSchedule("ms1", today, today)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), workOrders)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestMaintenanceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceRepositorySuite))
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type StockTakeRepository interface {
+	Create(bodyRequest model.StockTake) error
+	List() ([]model.StockTake, error)
+	Get(id string) (model.StockTake, error)
+	AddScans(stockTakeId string, scans []model.StockTakeScan) error
+	Scans(stockTakeId string) ([]model.StockTakeScan, error)
+	Close(bodyRequest model.StockTakeClose, results []model.StockTakeResult) error
+	Results(stockTakeId string) ([]model.StockTakeResult, error)
+}
+
+type stockTakeRepository struct {
+	db *sql.DB
+}
+
+const stockTakeSelect = "SELECT st.id,st.note,st.status,st.started_by,st.started_at,st.closed_by,st.closed_at,array_agg(stl.location_id ORDER BY stl.location_id) FROM stock_takes st JOIN stock_take_locations stl ON stl.stock_take_id=st.id"
+
+func (s *stockTakeRepository) Create(bodyRequest model.StockTake) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO stock_takes(id,note,status,started_by,started_at) VALUES($1,$2,$3,$4,$5)", bodyRequest.Id, bodyRequest.Note, bodyRequest.Status, bodyRequest.StartedBy, bodyRequest.StartedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, locationId := range bodyRequest.LocationIds {
+		_, err := tx.Exec("INSERT INTO stock_take_locations(stock_take_id,location_id) VALUES($1,$2)", bodyRequest.Id, locationId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *stockTakeRepository) List() ([]model.StockTake, error) {
+	rows, err := s.db.Query(stockTakeSelect + " GROUP BY st.id ORDER BY st.started_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stockTakes []model.StockTake
+	for rows.Next() {
+		stockTake, err := scanStockTake(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		stockTakes = append(stockTakes, stockTake)
+	}
+
+	return stockTakes, rows.Err()
+}
+
+func (s *stockTakeRepository) Get(id string) (model.StockTake, error) {
+	return scanStockTake(s.db.QueryRow(stockTakeSelect+" WHERE st.id=$1 GROUP BY st.id", id))
+}
+
+// AddScans records scanned tags. The session row is share-locked so a scan
+// can't slip in while the session is being closed.
+func (s *stockTakeRepository) AddScans(stockTakeId string, scans []model.StockTakeScan) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, stockTakeId, "FOR SHARE"); err != nil {
+		return err
+	}
+
+	for _, scan := range scans {
+		_, err := tx.Exec("INSERT INTO stock_take_scans(id,stock_take_id,tag,asset_detail_id,location_id,scanned_by,scanned_at) VALUES($1,$2,$3,$4,$5,$6,$7) ON CONFLICT (stock_take_id,tag) DO UPDATE SET asset_detail_id=EXCLUDED.asset_detail_id, location_id=EXCLUDED.location_id, scanned_by=EXCLUDED.scanned_by, scanned_at=EXCLUDED.scanned_at", scan.Id, stockTakeId, scan.Tag, scan.AssetDetailId, scan.LocationId, scan.ScannedBy, scan.ScannedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *stockTakeRepository) Scans(stockTakeId string) ([]model.StockTakeScan, error) {
+	rows, err := s.db.Query("SELECT id,stock_take_id,tag,asset_detail_id,location_id,scanned_by,scanned_at FROM stock_take_scans WHERE stock_take_id=$1 ORDER BY scanned_at,tag", stockTakeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scans []model.StockTakeScan
+	for rows.Next() {
+		var scan model.StockTakeScan
+		err := rows.Scan(&scan.Id, &scan.StockTakeId, &scan.Tag, &scan.AssetDetailId, &scan.LocationId, &scan.ScannedBy, &scan.ScannedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		scans = append(scans, scan)
+	}
+
+	return scans, rows.Err()
+}
+
+// Close stores the reconciliation and applies its actions in one
+// transaction. Every unit that gets an action is locked and checked against
+// the location and status the reconciliation saw, if another request moved
+// it in the meantime nothing is applied.
+func (s *stockTakeRepository) Close(bodyRequest model.StockTakeClose, results []model.StockTakeResult) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, bodyRequest.StockTakeId, "FOR UPDATE"); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result.Action != model.StockTakeNoAction {
+			if err := applyStockTakeAction(tx, bodyRequest, result); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec("INSERT INTO stock_take_results(stock_take_id,asset_detail_id,asset_id,tag,result,status,expected_location_id,scanned_location_id,action) VALUES($1,$2,$3,$4,$5,$6,$7,NULLIF($8,''),$9)", bodyRequest.StockTakeId, result.AssetDetailId, result.AssetId, result.Tag, result.Result, result.Status, result.ExpectedLocationId, result.ScannedLocationId, result.Action)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status=$1, closed_by=$2, closed_at=$3 WHERE id=$4", model.StockTakeClosed, bodyRequest.Actor, bodyRequest.ClosedAt, bodyRequest.StockTakeId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *stockTakeRepository) Results(stockTakeId string) ([]model.StockTakeResult, error) {
+	rows, err := s.db.Query("SELECT stock_take_id,asset_detail_id,asset_id,tag,result,status,expected_location_id,COALESCE(scanned_location_id,''),action FROM stock_take_results WHERE stock_take_id=$1 ORDER BY asset_id,tag,asset_detail_id", stockTakeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []model.StockTakeResult
+	for rows.Next() {
+		var result model.StockTakeResult
+		err := rows.Scan(&result.StockTakeId, &result.AssetDetailId, &result.AssetId, &result.Tag, &result.Result, &result.Status, &result.ExpectedLocationId, &result.ScannedLocationId, &result.Action)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// lockOpenStockTake locks the session row in the given mode and fails when
+// the session is already closed.
+func lockOpenStockTake(tx *sql.Tx, id, lockMode string) error {
+	var status model.StockTakeStatus
+	err := tx.QueryRow("SELECT status FROM stock_takes WHERE id=$1 "+lockMode, id).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	if status != model.StockTakeOpen {
+		return fmt.Errorf("stock take %s %w, it is already %s", id, ErrChanged, status)
+	}
+
+	return nil
+}
+
+func applyStockTakeAction(tx *sql.Tx, bodyRequest model.StockTakeClose, result model.StockTakeResult) error {
+	unit, err := lockUnit(tx, result.AssetDetailId)
+	if err != nil {
+		return err
+	}
+
+	if unit.LocationId != result.ExpectedLocationId || unit.Status != result.Status || unit.RemovedAt != nil {
+		return fmt.Errorf("asset unit %s %w during the stock take, reconcile again", unit.Id, ErrChanged)
+	}
+
+	movement := model.AssetMovement{
+		ToLocationId: unit.LocationId,
+		ToStatus:     unit.Status,
+		BatchId:      bodyRequest.StockTakeId,
+		Actor:        bodyRequest.Actor,
+		MovedAt:      bodyRequest.ClosedAt,
+	}
+
+	switch result.Action {
+	case model.StockTakeRelocated:
+		movement.ToLocationId = result.ScannedLocationId
+		// a lost unit that turns up goes back to storage
+		if unit.Status == model.StatusLost {
+			movement.ToStatus = model.StatusInStorage
+		}
+	case model.StockTakeMarkedLost:
+		movement.ToStatus = model.StatusLost
+	default:
+		return fmt.Errorf("unknown stock take action %s", result.Action)
+	}
+
+	return moveUnit(tx, unit, movement)
+}
+
+func scanStockTake(row interface{ Scan(dest ...any) error }) (model.StockTake, error) {
+	var stockTake model.StockTake
+	var note sql.NullString
+	err := row.Scan(&stockTake.Id, &note, &stockTake.Status, &stockTake.StartedBy, &stockTake.StartedAt, &stockTake.ClosedBy, &stockTake.ClosedAt, pq.Array(&stockTake.LocationIds))
+	if err != nil {
+		return model.StockTake{}, err
+	}
+	stockTake.Note = note.String
+
+	return stockTake, nil
+}
+
+func NewStockTakeRepository(db *sql.DB) StockTakeRepository {
+	return &stockTakeRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StockTakeRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.StockTakeRepository
+}
+
+func (s *StockTakeRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewStockTakeRepository(db)
+}
+
+func (s *StockTakeRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func unitRows(id, locationId string, status model.AssetStatus) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "asset_id", "location_id", "status", "updated_at", "removed_at"}).
+		AddRow(id, "a1", locationId, status, nil, nil)
+}
+
+func (s *StockTakeRepositorySuite) TestAddScans_Closed() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM stock_takes WHERE id=\\$1 FOR SHARE").WithArgs("st1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockTakeClosed))
+	s.mock.ExpectRollback()
+
+	err := s.repo.AddScans("st1", []model.StockTakeScan{{Id: "s1", Tag: "IT-0001", LocationId: "l1"}})
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.ErrorContains(s.T(), err, "already closed")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *StockTakeRepositorySuite) TestClose_AppliesActions() {
+	closeRequest := model.StockTakeClose{StockTakeId: "st1", FixLocations: true, MarkLost: true, Actor: "auditor", ClosedAt: time.Now()}
+	results := []model.StockTakeResult{
+		{AssetDetailId: "u1", AssetId: "a1", Tag: "IT-0001", Result: model.StockTakeFound, Status: model.StatusPlaced, ExpectedLocationId: "l1", ScannedLocationId: "l1"},
+		{AssetDetailId: "u2", AssetId: "a1", Tag: "IT-0002", Result: model.StockTakeWrongLocation, Status: model.StatusLost, ExpectedLocationId: "l9", ScannedLocationId: "l1", Action: model.StockTakeRelocated},
+		{AssetDetailId: "u3", AssetId: "a1", Tag: "IT-0003", Result: model.StockTakeMissing, Status: model.StatusInStorage, ExpectedLocationId: "l1", Action: model.StockTakeMarkedLost},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM stock_takes WHERE id=\\$1 FOR UPDATE").WithArgs("st1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockTakeOpen))
+	s.mock.ExpectExec("INSERT INTO stock_take_results").WithArgs("st1", "u1", "a1", "IT-0001", model.StockTakeFound, model.StatusPlaced, "l1", "l1", model.StockTakeNoAction).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u2").WillReturnRows(unitRows("u2", "l9", model.StatusLost))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusInStorage, closeRequest.ClosedAt, "u2").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), "u2", "a1", "l9", "l1", model.StatusLost, model.StatusInStorage, "st1", "auditor", closeRequest.ClosedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO stock_take_results").WithArgs("st1", "u2", "a1", "IT-0002", model.StockTakeWrongLocation, model.StatusLost, "l9", "l1", model.StockTakeRelocated).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u3").WillReturnRows(unitRows("u3", "l1", model.StatusInStorage))
+	s.mock.ExpectExec("UPDATE asset_details SET location_id").WithArgs("l1", model.StatusLost, closeRequest.ClosedAt, "u3").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO asset_movements").WithArgs(sqlmock.AnyArg(), "u3", "a1", "l1", "l1", model.StatusInStorage, model.StatusLost, "st1", "auditor", closeRequest.ClosedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO stock_take_results").WithArgs("st1", "u3", "a1", "IT-0003", model.StockTakeMissing, model.StatusInStorage, "l1", "", model.StockTakeMarkedLost).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec("UPDATE stock_takes SET status").WithArgs(model.StockTakeClosed, "auditor", closeRequest.ClosedAt, "st1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.Close(closeRequest, results)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *StockTakeRepositorySuite) TestClose_UnitChanged() {
+	closeRequest := model.StockTakeClose{StockTakeId: "st1", MarkLost: true, ClosedAt: time.Now()}
+	results := []model.StockTakeResult{
+		{AssetDetailId: "u3", AssetId: "a1", Result: model.StockTakeMissing, Status: model.StatusInStorage, ExpectedLocationId: "l1", Action: model.StockTakeMarkedLost},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT status FROM stock_takes").WithArgs("st1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockTakeOpen))
+	// checked out while the count was running
+	s.mock.ExpectQuery("SELECT (.+) FROM asset_details WHERE id=\\$1 FOR UPDATE").WithArgs("u3").WillReturnRows(unitRows("u3", "l1", model.StatusAssigned))
+	s.mock.ExpectRollback()
+
+	err := s.repo.Close(closeRequest, results)
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.ErrorContains(s.T(), err, "during the stock take, reconcile again")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestStockTakeRepositorySuite(t *testing.T) {
+	suite.Run(t, new(StockTakeRepositorySuite))
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"fmt"
+)
+
+type TransactionRepository interface {
+	Create(bodyRequest model.Transaction) error
+	List() ([]model.Transaction, error)
+	Get(id string) (model.Transaction, error)
+	ReceiveGoods(transactionId string, receipts []model.GoodsReceipt, assets []model.Asset) error
+	ListReceipt(transactionId string) ([]model.GoodsReceipt, error)
+}
+
+type transactionRepository struct {
+	db *sql.DB
+}
+
+func (t *transactionRepository) Create(bodyRequest model.Transaction) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO transactions(id,vendor_id,invoice_number,transaction_date,status,total_price) VALUES($1,$2,$3,$4,$5,$6)", bodyRequest.Id, bodyRequest.VendorId, bodyRequest.InvoiceNumber, bodyRequest.TransactionDate, bodyRequest.Status, bodyRequest.TotalPrice)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range bodyRequest.TransactionDetail {
+		_, err := tx.Exec("INSERT INTO transaction_detail(id,transaction_id,category_id,name,description,unit_price,qty,received_qty) VALUES($1,$2,$3,$4,$5,$6,$7,$8)", item.Id, item.TransactionId, item.CategoryId, item.Name, item.Description, item.UnitPrice, item.Qty, item.ReceivedQty)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (t *transactionRepository) List() ([]model.Transaction, error) {
+	rows, err := t.db.Query("SELECT id,vendor_id,invoice_number,transaction_date,status,total_price FROM transactions ORDER BY transaction_date DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []model.Transaction
+	for rows.Next() {
+		var transaction model.Transaction
+		err := rows.Scan(&transaction.Id, &transaction.VendorId, &transaction.InvoiceNumber, &transaction.TransactionDate, &transaction.Status, &transaction.TotalPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+func (t *transactionRepository) Get(id string) (model.Transaction, error) {
+	var transaction model.Transaction
+	err := t.db.QueryRow("SELECT id,vendor_id,invoice_number,transaction_date,status,total_price FROM transactions WHERE id=$1", id).Scan(&transaction.Id, &transaction.VendorId, &transaction.InvoiceNumber, &transaction.TransactionDate, &transaction.Status, &transaction.TotalPrice)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+
+	rows, err := t.db.Query("SELECT id,transaction_id,category_id,name,description,unit_price,qty,received_qty FROM transaction_detail WHERE transaction_id=$1", id)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var detail model.TransactionDetail
+		err := rows.Scan(&detail.Id, &detail.TransactionId, &detail.CategoryId, &detail.Name, &detail.Description, &detail.UnitPrice, &detail.Qty, &detail.ReceivedQty)
+		if err != nil {
+			return model.Transaction{}, err
+		}
+
+		transaction.TransactionDetail = append(transaction.TransactionDetail, detail)
+	}
+
+	return transaction, nil
+}
+
+// ReceiveGoods stores the receipts together with the asset created for each
+// of them, receipts[i] belongs to assets[i]. Either the whole receipt is
+// booked or nothing is, so a failed receipt can simply be sent again.
+func (t *transactionRepository) ReceiveGoods(transactionId string, receipts []model.GoodsReceipt, assets []model.Asset) error {
+	if len(receipts) == 0 {
+		return fmt.Errorf("goods receipt has no lines")
+	}
+
+	if len(receipts) != len(assets) {
+		return fmt.Errorf("every goods receipt needs exactly one asset")
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, receipt := range receipts {
+		if err := insertAsset(tx, assets[i]); err != nil {
+			return err
+		}
+
+		// The guard in WHERE keeps concurrent receipts from exceeding the ordered qty
+		result, err := tx.Exec("UPDATE transaction_detail SET received_qty=received_qty+$1 WHERE id=$2 AND transaction_id=$3 AND received_qty+$1<=qty", receipt.Qty, receipt.TransactionDetailId, transactionId)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("transaction detail %s %w, received qty exceeds ordered qty", receipt.TransactionDetailId, ErrChanged)
+		}
+
+		_, err = tx.Exec("INSERT INTO goods_receipt(id,transaction_detail_id,asset_id,location_id,qty,received_at) VALUES($1,$2,$3,$4,$5,$6)", receipt.Id, receipt.TransactionDetailId, receipt.AssetId, receipt.LocationId, receipt.Qty, receipt.ReceivedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	var ordered, received int
+	err = tx.QueryRow("SELECT COALESCE(SUM(qty),0), COALESCE(SUM(received_qty),0) FROM transaction_detail WHERE transaction_id=$1", transactionId).Scan(&ordered, &received)
+	if err != nil {
+		return err
+	}
+
+	status := model.TransactionPartiallyReceived
+	if received >= ordered {
+		status = model.TransactionReceived
+	}
+
+	_, err = tx.Exec("UPDATE transactions SET status=$1 WHERE id=$2", status, transactionId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *transactionRepository) ListReceipt(transactionId string) ([]model.GoodsReceipt, error) {
+	rows, err := t.db.Query("SELECT gr.id,gr.transaction_detail_id,gr.asset_id,gr.location_id,gr.qty,gr.received_at FROM goods_receipt gr JOIN transaction_detail td ON td.id=gr.transaction_detail_id WHERE td.transaction_id=$1 ORDER BY gr.received_at", transactionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []model.GoodsReceipt
+	for rows.Next() {
+		var receipt model.GoodsReceipt
+		err := rows.Scan(&receipt.Id, &receipt.TransactionDetailId, &receipt.AssetId, &receipt.LocationId, &receipt.Qty, &receipt.ReceivedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+func NewTransactionRepository(db *sql.DB) TransactionRepository {
+	return &transactionRepository{
+		db: db,
+	}
+}
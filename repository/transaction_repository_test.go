@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactionRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.TransactionRepository
+}
+
+func (s *TransactionRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewTransactionRepository(db)
+}
+
+func (s *TransactionRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func goodsReceipt() ([]model.GoodsReceipt, []model.Asset) {
+	receipts := []model.GoodsReceipt{{Id: "r1", TransactionDetailId: "td1", AssetId: "a1", LocationId: "l1", Qty: 1, ReceivedAt: time.Now()}}
+	assets := []model.Asset{{
+		Id:          "a1",
+		CategoryId:  "c1",
+		Qty:         1,
+		CreatedAt:   time.Now(),
+		AssetDetail: []model.AssetDetail{{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}},
+	}}
+	return receipts, assets
+}
+
+func (s *TransactionRepositorySuite) expectAsset() {
+	s.mock.ExpectExec("INSERT INTO asset").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT tag_prefix,tag_with_year,tag_padding FROM asset_categories").
+		WillReturnRows(sqlmock.NewRows([]string{"tag_prefix", "tag_with_year", "tag_padding"}).AddRow("", false, 4))
+	s.mock.ExpectExec("INSERT INTO asset_details").WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (s *TransactionRepositorySuite) TestCreate_Success() {
+	payload := model.Transaction{
+		Id:                "t1",
+		VendorId:          "v1",
+		Status:            model.TransactionOrdered,
+		TotalPrice:        200,
+		TransactionDetail: []model.TransactionDetail{{Id: "td1", TransactionId: "t1", CategoryId: "c1", Name: "Laptop", UnitPrice: 100, Qty: 2}},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO transactions").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO transaction_detail").WithArgs("td1", "t1", "c1", "Laptop", "", float64(100), 2, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.NoError(s.T(), s.repo.Create(payload))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TransactionRepositorySuite) TestReceiveGoods_Partial() {
+	receipts, assets := goodsReceipt()
+
+	s.mock.ExpectBegin()
+	s.expectAsset()
+	s.mock.ExpectExec("UPDATE transaction_detail SET received_qty").WithArgs(1, "td1", "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO goods_receipt").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery("SELECT COALESCE").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"ordered", "received"}).AddRow(2, 1))
+	s.mock.ExpectExec("UPDATE transactions SET status").WithArgs(model.TransactionPartiallyReceived, "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.NoError(s.T(), s.repo.ReceiveGoods("t1", receipts, assets))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TransactionRepositorySuite) TestReceiveGoods_ExceedsOrderedQty() {
+	receipts, assets := goodsReceipt()
+
+	s.mock.ExpectBegin()
+	s.expectAsset()
+	s.mock.ExpectExec("UPDATE transaction_detail SET received_qty").WithArgs(1, "td1", "t1").WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.ReceiveGoods("t1", receipts, assets)
+	assert.ErrorIs(s.T(), err, repository.ErrChanged)
+	assert.EqualError(s.T(), err, "transaction detail td1 changed since it was checked, received qty exceeds ordered qty")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TransactionRepositorySuite) TestReceiveGoods_Empty() {
+	err := s.repo.ReceiveGoods("t1", nil, nil)
+	assert.EqualError(s.T(), err, "goods receipt has no lines")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TransactionRepositorySuite))
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"asetku-bukan-asetmu/model"
+	"database/sql"
+	"time"
+)
+
+type WarrantyRepository interface {
+	Create(bodyRequest model.Warranty) error
+	Update(bodyRequest model.Warranty) error
+	Get(id string) (model.Warranty, error)
+	ListByAsset(assetId string) ([]model.Warranty, error)
+	ListByVendor(vendorId string) ([]model.Warranty, error)
+	ListExpiring(from, to time.Time) ([]model.Warranty, error)
+	CreateClaim(bodyRequest model.WarrantyClaim) error
+	UpdateClaim(bodyRequest model.WarrantyClaim) error
+	GetClaim(id string) (model.WarrantyClaim, error)
+	ListClaims(warrantyId string) ([]model.WarrantyClaim, error)
+}
+
+type warrantyRepository struct {
+	db *sql.DB
+}
+
+const warrantySelect = "SELECT id,asset_id,asset_detail_id,vendor_id,start_date,end_date,coverage,claim_contact,created_at FROM warranties"
+
+const warrantyClaimSelect = "SELECT id,warranty_id,asset_detail_id,work_order_id,issue,claim_number,status,resolution,claimed_at,resolved_at FROM warranty_claims"
+
+func (w *warrantyRepository) Create(bodyRequest model.Warranty) error {
+	_, err := w.db.Exec("INSERT INTO warranties(id,asset_id,asset_detail_id,vendor_id,start_date,end_date,coverage,claim_contact,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9)", bodyRequest.Id, bodyRequest.AssetId, bodyRequest.AssetDetailId, bodyRequest.VendorId, bodyRequest.StartDate, bodyRequest.EndDate, bodyRequest.Coverage, bodyRequest.ClaimContact, bodyRequest.CreatedAt)
+	return err
+}
+
+func (w *warrantyRepository) Update(bodyRequest model.Warranty) error {
+	_, err := w.db.Exec("UPDATE warranties SET asset_detail_id=$1, vendor_id=$2, start_date=$3, end_date=$4, coverage=$5, claim_contact=$6 WHERE id=$7", bodyRequest.AssetDetailId, bodyRequest.VendorId, bodyRequest.StartDate, bodyRequest.EndDate, bodyRequest.Coverage, bodyRequest.ClaimContact, bodyRequest.Id)
+	return err
+}
+
+func (w *warrantyRepository) Get(id string) (model.Warranty, error) {
+	return scanWarranty(w.db.QueryRow(warrantySelect+" WHERE id=$1", id))
+}
+
+func (w *warrantyRepository) ListByAsset(assetId string) ([]model.Warranty, error) {
+	return w.list(warrantySelect+" WHERE asset_id=$1 ORDER BY end_date DESC", assetId)
+}
+
+func (w *warrantyRepository) ListByVendor(vendorId string) ([]model.Warranty, error) {
+	return w.list(warrantySelect+" WHERE vendor_id=$1 ORDER BY end_date DESC", vendorId)
+}
+
+// ListExpiring lists warranties ending between from and to, both days
+// included, the soonest first.
+func (w *warrantyRepository) ListExpiring(from, to time.Time) ([]model.Warranty, error) {
+	return w.list(warrantySelect+" WHERE end_date BETWEEN $1 AND $2 ORDER BY end_date,asset_id", from, to)
+}
+
+func (w *warrantyRepository) CreateClaim(bodyRequest model.WarrantyClaim) error {
+	_, err := w.db.Exec("INSERT INTO warranty_claims(id,warranty_id,asset_detail_id,work_order_id,issue,claim_number,status,resolution,claimed_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9)", bodyRequest.Id, bodyRequest.WarrantyId, bodyRequest.AssetDetailId, bodyRequest.WorkOrderId, bodyRequest.Issue, bodyRequest.ClaimNumber, bodyRequest.Status, bodyRequest.Resolution, bodyRequest.ClaimedAt)
+	return err
+}
+
+func (w *warrantyRepository) UpdateClaim(bodyRequest model.WarrantyClaim) error {
+	_, err := w.db.Exec("UPDATE warranty_claims SET work_order_id=$1, claim_number=$2, status=$3, resolution=$4, resolved_at=$5 WHERE id=$6", bodyRequest.WorkOrderId, bodyRequest.ClaimNumber, bodyRequest.Status, bodyRequest.Resolution, bodyRequest.ResolvedAt, bodyRequest.Id)
+	return err
+}
+
+func (w *warrantyRepository) GetClaim(id string) (model.WarrantyClaim, error) {
+	return scanWarrantyClaim(w.db.QueryRow(warrantyClaimSelect+" WHERE id=$1", id))
+}
+
+func (w *warrantyRepository) ListClaims(warrantyId string) ([]model.WarrantyClaim, error) {
+	rows, err := w.db.Query(warrantyClaimSelect+" WHERE warranty_id=$1 ORDER BY claimed_at DESC", warrantyId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claims []model.WarrantyClaim
+	for rows.Next() {
+		claim, err := scanWarrantyClaim(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		claims = append(claims, claim)
+	}
+
+	return claims, rows.Err()
+}
+
+func (w *warrantyRepository) list(query string, args ...any) ([]model.Warranty, error) {
+	rows, err := w.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warranties []model.Warranty
+	for rows.Next() {
+		warranty, err := scanWarranty(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		warranties = append(warranties, warranty)
+	}
+
+	return warranties, rows.Err()
+}
+
+func scanWarranty(row interface{ Scan(dest ...any) error }) (model.Warranty, error) {
+	var warranty model.Warranty
+	var coverage sql.NullString
+	err := row.Scan(&warranty.Id, &warranty.AssetId, &warranty.AssetDetailId, &warranty.VendorId, &warranty.StartDate, &warranty.EndDate, &coverage, &warranty.ClaimContact, &warranty.CreatedAt)
+	if err != nil {
+		return model.Warranty{}, err
+	}
+	warranty.Coverage = coverage.String
+
+	return warranty, nil
+}
+
+func scanWarrantyClaim(row interface{ Scan(dest ...any) error }) (model.WarrantyClaim, error) {
+	var claim model.WarrantyClaim
+	err := row.Scan(&claim.Id, &claim.WarrantyId, &claim.AssetDetailId, &claim.WorkOrderId, &claim.Issue, &claim.ClaimNumber, &claim.Status, &claim.Resolution, &claim.ClaimedAt, &claim.ResolvedAt)
+	if err != nil {
+		return model.WarrantyClaim{}, err
+	}
+
+	return claim, nil
+}
+
+func NewWarrantyRepository(db *sql.DB) WarrantyRepository {
+	return &warrantyRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,171 @@
+package repository_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var warrantyColumns = []string{"id", "asset_id", "asset_detail_id", "vendor_id", "start_date", "end_date", "coverage", "claim_contact", "created_at"}
+
+var warrantyClaimColumns = []string{"id", "warranty_id", "asset_detail_id", "work_order_id", "issue", "claim_number", "status", "resolution", "claimed_at", "resolved_at"}
+
+type WarrantyRepositorySuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo repository.WarrantyRepository
+}
+
+func (s *WarrantyRepositorySuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(s.T(), err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = repository.NewWarrantyRepository(db)
+}
+
+func (s *WarrantyRepositorySuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *WarrantyRepositorySuite) TestCreate_Success() {
+	warranty := model.Warranty{Id: "w1", AssetId: "a1", VendorId: "v1", StartDate: time.Now(), EndDate: time.Now().AddDate(1, 0, 0), Coverage: "parts", CreatedAt: time.Now()}
+	s.mock.ExpectExec("INSERT INTO warranties").
+		WithArgs("w1", "a1", warranty.AssetDetailId, "v1", warranty.StartDate, warranty.EndDate, "parts", "", warranty.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(s.T(), s.repo.Create(warranty))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *WarrantyRepositorySuite) TestUpdate_Success() {
+	warranty := model.Warranty{Id: "w1", VendorId: "v2", StartDate: time.Now(), EndDate: time.Now().AddDate(2, 0, 0), ClaimContact: "support@acme.test"}
+	s.mock.ExpectExec("UPDATE warranties SET").
+		WithArgs(warranty.AssetDetailId, "v2", warranty.StartDate, warranty.EndDate, "", "support@acme.test", "w1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(s.T(), s.repo.Update(warranty))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *WarrantyRepositorySuite) TestGet_NullCoverage() {
+	rows := sqlmock.NewRows(warrantyColumns).AddRow("w1", "a1", "u1", "v1", time.Now(), time.Now(), nil, "", time.Now())
+	s.mock.ExpectQuery("FROM warranties WHERE id=\\$1").WithArgs("w1").WillReturnRows(rows)
+
+	warranty, err := s.repo.Get("w1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "u1", *warranty.AssetDetailId)
+	assert.Empty(s.T(), warranty.Coverage)
+}
+
+func (s *WarrantyRepositorySuite) TestGet_NotFound() {
+	s.mock.ExpectQuery("FROM warranties WHERE id=\\$1").WithArgs("w9").WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.Get("w9")
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+}
+
+func (s *WarrantyRepositorySuite) TestListByAsset_Success() {
+	rows := sqlmock.NewRows(warrantyColumns).
+		AddRow("w2", "a1", nil, "v1", time.Now(), time.Now().AddDate(1, 0, 0), "parts", "", time.Now()).
+		AddRow("w1", "a1", "u1", "v2", time.Now(), time.Now(), "labour", "", time.Now())
+	s.mock.ExpectQuery("FROM warranties WHERE asset_id=\\$1 ORDER BY end_date DESC").WithArgs("a1").WillReturnRows(rows)
+
+	warranties, err := s.repo.ListByAsset("a1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), warranties, 2)
+	assert.Nil(s.T(), warranties[0].AssetDetailId)
+	assert.Equal(s.T(), "labour", warranties[1].Coverage)
+}
+
+func (s *WarrantyRepositorySuite) TestListByVendor_Fail() {
+	s.mock.ExpectQuery("FROM warranties WHERE vendor_id=\\$1").WithArgs("v1").WillReturnError(errors.New("error"))
+
+	_, err := s.repo.ListByVendor("v1")
+	assert.Error(s.T(), err)
+}
+
+func (s *WarrantyRepositorySuite) TestListExpiring_Success() {
+	from := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 30)
+	rows := sqlmock.NewRows(warrantyColumns).AddRow("w1", "a1", nil, "v1", from.AddDate(-1, 0, 0), from.AddDate(0, 0, 10), "parts", "", from)
+	s.mock.ExpectQuery("FROM warranties WHERE end_date BETWEEN \\$1 AND \\$2 ORDER BY end_date,asset_id").WithArgs(from, to).WillReturnRows(rows)
+
+	warranties, err := s.repo.ListExpiring(from, to)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), warranties, 1)
+}
+
+func (s *WarrantyRepositorySuite) TestListExpiring_ScanFail() {
+	rows := sqlmock.NewRows([]string{"id"}).AddRow("w1")
+	s.mock.ExpectQuery("FROM warranties WHERE end_date BETWEEN").WillReturnRows(rows)
+
+	_, err := s.repo.ListExpiring(time.Now(), time.Now())
+	assert.Error(s.T(), err)
+}
+
+func (s *WarrantyRepositorySuite) TestCreateClaim_Success() {
+	claim := model.WarrantyClaim{Id: "c1", WarrantyId: "w1", Issue: "screen flickers", Status: model.ClaimSubmitted, ClaimedAt: time.Now()}
+	s.mock.ExpectExec("INSERT INTO warranty_claims").
+		WithArgs("c1", "w1", claim.AssetDetailId, claim.WorkOrderId, "screen flickers", "", model.ClaimSubmitted, "", claim.ClaimedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(s.T(), s.repo.CreateClaim(claim))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *WarrantyRepositorySuite) TestUpdateClaim_Success() {
+	workOrderId := "wo1"
+	resolvedAt := time.Now()
+	claim := model.WarrantyClaim{Id: "c1", WorkOrderId: &workOrderId, ClaimNumber: "RMA-1", Status: model.ClaimResolved, Resolution: "replaced", ResolvedAt: resolvedAt}
+	s.mock.ExpectExec("UPDATE warranty_claims SET").
+		WithArgs(&workOrderId, "RMA-1", model.ClaimResolved, "replaced", resolvedAt, "c1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(s.T(), s.repo.UpdateClaim(claim))
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *WarrantyRepositorySuite) TestGetClaim_Success() {
+	rows := sqlmock.NewRows(warrantyClaimColumns).AddRow("c1", "w1", "u1", "wo1", "screen flickers", "RMA-1", model.ClaimApproved, "", time.Now(), nil)
+	s.mock.ExpectQuery("FROM warranty_claims WHERE id=\\$1").WithArgs("c1").WillReturnRows(rows)
+
+	claim, err := s.repo.GetClaim("c1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ClaimApproved, claim.Status)
+	assert.Equal(s.T(), "wo1", *claim.WorkOrderId)
+	assert.Nil(s.T(), claim.ResolvedAt)
+}
+
+func (s *WarrantyRepositorySuite) TestListClaims_Success() {
+	rows := sqlmock.NewRows(warrantyClaimColumns).
+		AddRow("c2", "w1", nil, nil, "battery", "", model.ClaimSubmitted, "", time.Now(), nil).
+		AddRow("c1", "w1", "u1", "wo1", "screen", "RMA-1", model.ClaimResolved, "replaced", time.Now(), time.Now())
+	s.mock.ExpectQuery("FROM warranty_claims WHERE warranty_id=\\$1 ORDER BY claimed_at DESC").WithArgs("w1").WillReturnRows(rows)
+
+	claims, err := s.repo.ListClaims("w1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), claims, 2)
+	assert.Nil(s.T(), claims[0].WorkOrderId)
+	assert.NotNil(s.T(), claims[1].ResolvedAt)
+}
+
+func (s *WarrantyRepositorySuite) TestListClaims_Fail() {
+	s.mock.ExpectQuery("FROM warranty_claims").WillReturnError(errors.New("error"))
+
+	_, err := s.repo.ListClaims("w1")
+	assert.Error(s.T(), err)
+}
+
+func TestWarrantyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WarrantyRepositorySuite))
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type AssetAssignmentUsecase interface {
+	CheckoutAsset(bodyRequest model.AssetAssignment) (model.AssetAssignment, error)
+	CheckinAsset(bodyRequest model.AssetCheckin) error
+	ShowEmployeeHoldings(employeeId string) ([]dto.AssetAssignmentDTO, error)
+	ShowAssetHoldings(assetId string) ([]dto.AssetAssignmentDTO, error)
+}
+
+type assetAssignmentUsecase struct {
+	repo         repository.AssetAssignmentRepository
+	emplUsecase  EmployeeUseCase
+	assetUsecase AssetUsecase
+	locUsecase   AssetLocationUsecase
+}
+
+// CheckoutAsset hands the unit to the employee, a reservation the employee
+// holds for the asset at that moment is consumed by it.
+func (a *assetAssignmentUsecase) CheckoutAsset(bodyRequest model.AssetAssignment) (model.AssetAssignment, error) {
+	if !bodyRequest.DueAt.After(bodyRequest.AssignedAt) {
+		return model.AssetAssignment{}, newError(ErrInvalid, "due date must be after assignment date")
+	}
+
+	if _, err := a.emplUsecase.FindEmployeeById(bodyRequest.EmployeeId); err != nil {
+		return model.AssetAssignment{}, newError(ErrNotFound, "employee with id %s is not found", bodyRequest.EmployeeId)
+	}
+
+	unit, err := a.assetUsecase.GetAssetUnit(bodyRequest.AssetDetailId)
+	if err != nil {
+		return model.AssetAssignment{}, err
+	}
+
+	if unit.RemovedAt != nil {
+		return model.AssetAssignment{}, newError(ErrConflict, "asset unit %s is already retired", unit.Id)
+	}
+
+	if err := ValidateStatusTransition(unit.Status, model.StatusAssigned); err != nil {
+		return model.AssetAssignment{}, newError(ErrConflict, "asset unit %s can't be checked out : %s", unit.Id, err.Error())
+	}
+
+	bodyRequest.AssetId = unit.AssetId
+	bodyRequest.ReservationId, err = a.repo.Checkout(bodyRequest, unit)
+	if errors.Is(err, repository.ErrChanged) || errors.Is(err, repository.ErrReserved) {
+		return model.AssetAssignment{}, newError(ErrConflict, "failed to checkout asset : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetAssignment{}, fmt.Errorf("failed to checkout asset : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (a *assetAssignmentUsecase) CheckinAsset(bodyRequest model.AssetCheckin) error {
+	assignment, err := a.repo.Get(bodyRequest.AssignmentId)
+	if err != nil {
+		return newError(ErrNotFound, "assignment with id %s is not found", bodyRequest.AssignmentId)
+	}
+
+	if assignment.ReturnedAt != nil {
+		return newError(ErrConflict, "assignment %s is already returned", assignment.Id)
+	}
+
+	unit, err := a.assetUsecase.GetAssetUnit(assignment.AssetDetailId)
+	if err != nil {
+		return err
+	}
+
+	// Staying in storage is a legal transition, so a unit that was never
+	// handed out has to be caught before it
+	if unit.Status != model.StatusAssigned {
+		return newError(ErrInvalid, "asset unit %s is %s, it was never checked out", unit.Id, unit.Status)
+	}
+
+	if err := ValidateStatusTransition(unit.Status, model.StatusInStorage); err != nil {
+		return newError(ErrConflict, "asset unit %s can't be checked in : %s", unit.Id, err.Error())
+	}
+
+	// Keep the unit where it was handed out unless a storage location is given
+	if bodyRequest.LocationId == "" {
+		bodyRequest.LocationId = unit.LocationId
+	} else if _, err := a.locUsecase.SearchLocationById(bodyRequest.LocationId); err != nil {
+		return newError(ErrNotFound, "location with id %s is not found", bodyRequest.LocationId)
+	}
+
+	err = a.repo.Checkin(bodyRequest, unit.Id)
+	if errors.Is(err, repository.ErrChanged) {
+		return newError(ErrConflict, "failed to checkin asset : %s", err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to checkin asset : %s", err.Error())
+	}
+
+	return nil
+}
+
+func (a *assetAssignmentUsecase) ShowEmployeeHoldings(employeeId string) ([]dto.AssetAssignmentDTO, error) {
+	if _, err := a.emplUsecase.FindEmployeeById(employeeId); err != nil {
+		return nil, newError(ErrNotFound, "employee with id %s is not found", employeeId)
+	}
+
+	assignments, err := a.repo.ListActiveByEmployee(employeeId)
+	if err != nil {
+		return nil, fmt.Errorf("error get employee holdings : %s", err.Error())
+	}
+
+	return a.toResponses(assignments)
+}
+
+func (a *assetAssignmentUsecase) ShowAssetHoldings(assetId string) ([]dto.AssetAssignmentDTO, error) {
+	assignments, err := a.repo.ListActiveByAsset(assetId)
+	if err != nil {
+		return nil, fmt.Errorf("error get asset holdings : %s", err.Error())
+	}
+
+	return a.toResponses(assignments)
+}
+
+func (a *assetAssignmentUsecase) toResponses(assignments []model.AssetAssignment) ([]dto.AssetAssignmentDTO, error) {
+	now := time.Now()
+	employees := make(map[string]model.Employee)
+	assetNames := make(map[string]string)
+
+	responses := make([]dto.AssetAssignmentDTO, 0, len(assignments))
+	for _, assignment := range assignments {
+		employee, ok := employees[assignment.EmployeeId]
+		if !ok {
+			var err error
+			employee, err = a.emplUsecase.FindEmployeeById(assignment.EmployeeId)
+			if err != nil {
+				return nil, err
+			}
+			employees[assignment.EmployeeId] = employee
+		}
+
+		assetName, ok := assetNames[assignment.AssetId]
+		if !ok {
+			asset, err := a.assetUsecase.GetDetailAsset(assignment.AssetId)
+			if err != nil {
+				return nil, err
+			}
+			assetName = asset.Name
+			assetNames[assignment.AssetId] = assetName
+		}
+
+		var response dto.AssetAssignmentDTO
+		response.Id = assignment.Id
+		response.AssetId = assignment.AssetId
+		response.AssetName = assetName
+		response.AssetDetailId = assignment.AssetDetailId
+		response.Employee = employee
+		response.AssignedAt = assignment.AssignedAt
+		response.DueAt = assignment.DueAt
+		response.Overdue = now.After(assignment.DueAt)
+		response.Note = assignment.Note
+		response.ReservationId = assignment.ReservationId
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func NewAssetAssignmentUsecase(repo repository.AssetAssignmentRepository, employeeUsecase EmployeeUseCase, assetUsecase AssetUsecase, locationUsecase AssetLocationUsecase) AssetAssignmentUsecase {
+	return &assetAssignmentUsecase{
+		repo:         repo,
+		emplUsecase:  employeeUsecase,
+		assetUsecase: assetUsecase,
+		locUsecase:   locationUsecase,
+	}
+}
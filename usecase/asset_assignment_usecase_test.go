@@ -0,0 +1,187 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAssignmentRepository struct {
+	mock.Mock
+}
+
+func (r *mockAssignmentRepository) Checkout(bodyRequest model.AssetAssignment, expected model.AssetDetail) (*string, error) {
+	args := r.Called(bodyRequest, expected)
+	return args.Get(0).(*string), args.Error(1)
+}
+
+func (r *mockAssignmentRepository) Checkin(bodyRequest model.AssetCheckin, unitId string) error {
+	args := r.Called(bodyRequest, unitId)
+	return args.Error(0)
+}
+
+func (r *mockAssignmentRepository) Get(id string) (model.AssetAssignment, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetAssignment), args.Error(1)
+}
+
+func (r *mockAssignmentRepository) ListActiveByEmployee(employeeId string) ([]model.AssetAssignment, error) {
+	args := r.Called(employeeId)
+	return args.Get(0).([]model.AssetAssignment), args.Error(1)
+}
+
+func (r *mockAssignmentRepository) ListActiveByAsset(assetId string) ([]model.AssetAssignment, error) {
+	args := r.Called(assetId)
+	return args.Get(0).([]model.AssetAssignment), args.Error(1)
+}
+
+func (r *mockAssignmentRepository) CountActiveByEmployee(employeeId string) (int, error) {
+	args := r.Called(employeeId)
+	return args.Int(0), args.Error(1)
+}
+
+type AssetAssignmentUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockAssignmentRepository
+	mockAssetRepo *mockAssetRepository
+	mockEmployee  *mockEmployeeUsecase
+	mockLocation  *mockLocationUsecase
+	usecase       usecase.AssetAssignmentUsecase
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockAssignmentRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockEmployee = new(mockEmployeeUsecase)
+	s.mockLocation = new(mockLocationUsecase)
+	assetUsecase := usecase.NewAssetUsecase(s.mockAssetRepo, s.mockLocation, nil, nil, time.Minute)
+	s.usecase = usecase.NewAssetAssignmentUsecase(s.mockRepo, s.mockEmployee, assetUsecase, s.mockLocation)
+
+	s.mockEmployee.On("FindEmployeeById", "e1").Return(model.Employee{Id: "e1"}, nil)
+	s.mockEmployee.On("FindEmployeeById", "e9").Return(model.Employee{}, sql.ErrNoRows)
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1", Name: "Store"}, nil)
+	s.mockLocation.On("SearchLocationById", "l9").Return(model.AssetLocation{}, sql.ErrNoRows)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckoutAsset_Success() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}, nil)
+	s.mockRepo.On("Checkout", mock.MatchedBy(func(assignment model.AssetAssignment) bool {
+		return assignment.AssetId == "a1" && assignment.EmployeeId == "e1"
+	}), mock.Anything).Return((*string)(nil), nil)
+
+	now := time.Now()
+	assignment, err := s.usecase.CheckoutAsset(model.AssetAssignment{AssetDetailId: "u1", EmployeeId: "e1", AssignedAt: now, DueAt: now.Add(time.Hour)})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "a1", assignment.AssetId)
+	assert.Nil(s.T(), assignment.ReservationId)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckoutAsset_Invalid() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusInStorage}, nil)
+	s.mockAssetRepo.On("GetUnit", "u2").Return(model.AssetDetail{Id: "u2", AssetId: "a1", Status: model.StatusAssigned}, nil)
+
+	now := time.Now()
+	cases := map[string]model.AssetAssignment{
+		"due date must be after assignment date": {AssetDetailId: "u1", EmployeeId: "e1", AssignedAt: now, DueAt: now},
+		"employee with id e9 is not found":       {AssetDetailId: "u1", EmployeeId: "e9", AssignedAt: now, DueAt: now.Add(time.Hour)},
+		"asset unit u2 can't be checked out":     {AssetDetailId: "u2", EmployeeId: "e1", AssignedAt: now, DueAt: now.Add(time.Hour)},
+	}
+
+	for expected, assignment := range cases {
+		_, err := s.usecase.CheckoutAsset(assignment)
+		assert.ErrorContains(s.T(), err, expected)
+	}
+	s.mockRepo.AssertNotCalled(s.T(), "Checkout", mock.Anything, mock.Anything)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckoutAsset_ErrorKind() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusInStorage}, nil)
+	s.mockAssetRepo.On("GetUnit", "u2").Return(model.AssetDetail{Id: "u2", AssetId: "a1", Status: model.StatusAssigned}, nil)
+	s.mockAssetRepo.On("GetUnit", "u9").Return(model.AssetDetail{}, sql.ErrNoRows)
+
+	now := time.Now()
+	cases := map[string]error{
+		"u1": usecase.ErrInvalid,
+		"u2": usecase.ErrConflict,
+		"u9": usecase.ErrNotFound,
+	}
+	for unitId, kind := range cases {
+		dueAt := now.Add(time.Hour)
+		if unitId == "u1" {
+			dueAt = now
+		}
+
+		_, err := s.usecase.CheckoutAsset(model.AssetAssignment{AssetDetailId: unitId, EmployeeId: "e1", AssignedAt: now, DueAt: dueAt})
+		assert.ErrorIs(s.T(), err, kind, unitId)
+	}
+
+	_, err := s.usecase.CheckoutAsset(model.AssetAssignment{AssetDetailId: "u1", EmployeeId: "e9", AssignedAt: now, DueAt: now.Add(time.Hour)})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckoutAsset_UnitChanged() {
+	unit := model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusInStorage}
+	s.mockAssetRepo.On("GetUnit", "u1").Return(unit, nil)
+	s.mockRepo.On("Checkout", mock.Anything, unit).Return((*string)(nil), fmt.Errorf("asset unit u1 %w, try again", repository.ErrChanged))
+
+	now := time.Now()
+	_, err := s.usecase.CheckoutAsset(model.AssetAssignment{AssetDetailId: "u1", EmployeeId: "e1", AssignedAt: now, DueAt: now.Add(time.Hour)})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckinAsset_KeepsLocation() {
+	s.mockRepo.On("Get", "as1").Return(model.AssetAssignment{Id: "as1", AssetDetailId: "u1"}, nil)
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusAssigned}, nil)
+	s.mockRepo.On("Checkin", mock.MatchedBy(func(checkin model.AssetCheckin) bool {
+		return checkin.LocationId == "l1"
+	}), "u1").Return(nil)
+
+	err := s.usecase.CheckinAsset(model.AssetCheckin{AssignmentId: "as1", ReturnedAt: time.Now()})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckinAsset_AlreadyReturned() {
+	s.mockRepo.On("Get", "as1").Return(model.AssetAssignment{Id: "as1", AssetDetailId: "u1", ReturnedAt: time.Now()}, nil)
+
+	err := s.usecase.CheckinAsset(model.AssetCheckin{AssignmentId: "as1"})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "assignment as1 is already returned")
+	s.mockRepo.AssertNotCalled(s.T(), "Checkin", mock.Anything, mock.Anything)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckinAsset_UnknownLocation() {
+	s.mockRepo.On("Get", "as1").Return(model.AssetAssignment{Id: "as1", AssetDetailId: "u1"}, nil)
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusAssigned}, nil)
+
+	err := s.usecase.CheckinAsset(model.AssetCheckin{AssignmentId: "as1", LocationId: "l9"})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.ErrorContains(s.T(), err, "location with id l9 is not found")
+	s.mockRepo.AssertNotCalled(s.T(), "Checkin", mock.Anything, mock.Anything)
+}
+
+func (s *AssetAssignmentUsecaseTestSuite) TestCheckinAsset_NeverCheckedOut() {
+	s.mockRepo.On("Get", "as1").Return(model.AssetAssignment{Id: "as1", AssetDetailId: "u1"}, nil)
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}, nil)
+	s.mockRepo.On("Get", "as9").Return(model.AssetAssignment{}, sql.ErrNoRows)
+
+	err := s.usecase.CheckinAsset(model.AssetCheckin{AssignmentId: "as1"})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.EqualError(s.T(), err, "asset unit u1 is in-storage, it was never checked out")
+
+	err = s.usecase.CheckinAsset(model.AssetCheckin{AssignmentId: "as9"})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "Checkin", mock.Anything, mock.Anything)
+}
+
+func TestAssetAssignmentUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetAssignmentUsecaseTestSuite))
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"asetku-bukan-asetmu/utils/storage"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MaxAttachmentSize is the largest attachment accepted, in bytes.
+const MaxAttachmentSize = 20 << 20
+
+const assetAttachmentDir = "attachments"
+
+// attachment types accepted after sniffing the content, office documents are
+// zip files underneath
+var attachmentTypes = map[string]bool{
+	"application/pdf":           true,
+	"application/zip":           true,
+	"image/jpeg":                true,
+	"image/png":                 true,
+	"image/gif":                 true,
+	"image/webp":                true,
+	"text/plain; charset=utf-8": true,
+}
+
+type AssetAttachmentUsecase interface {
+	UploadAttachment(bodyRequest dto.AttachmentUploadDTO) (model.AssetAttachment, error)
+	ShowAttachments(assetId string) ([]model.AssetAttachment, error)
+	OpenAttachment(assetId, id string) (model.AssetAttachment, storage.Object, error)
+	DeleteAttachment(assetId, id string) error
+}
+
+type assetAttachmentUsecase struct {
+	repo        repository.AssetAttachmentRepository
+	assetRepo   repository.AssetRepository
+	fileStorage storage.Storage
+}
+
+// UploadAttachment stores the file and records it with its SHA-256 checksum.
+// The stored file is removed again when the record can't be saved.
+func (a *assetAttachmentUsecase) UploadAttachment(bodyRequest dto.AttachmentUploadDTO) (model.AssetAttachment, error) {
+	if a.fileStorage == nil {
+		return model.AssetAttachment{}, fmt.Errorf("file storage is not configured")
+	}
+
+	attachmentType := model.AttachmentType(bodyRequest.Type)
+	if !attachmentType.IsValid() {
+		return model.AssetAttachment{}, newError(ErrInvalid, "unknown attachment type %s", bodyRequest.Type)
+	}
+
+	if _, err := a.assetRepo.Detail(bodyRequest.AssetId); err != nil {
+		return model.AssetAttachment{}, newError(ErrNotFound, "asset with id %s is not found", bodyRequest.AssetId)
+	}
+
+	attachment := model.AssetAttachment{
+		Id:         common.GenerateUUID(),
+		AssetId:    bodyRequest.AssetId,
+		Title:      bodyRequest.Title,
+		Type:       attachmentType,
+		UploadedBy: bodyRequest.UploadedBy,
+		UploadedAt: time.Now(),
+	}
+
+	if bodyRequest.AssetDetailId != "" {
+		unit, err := a.assetRepo.GetUnit(bodyRequest.AssetDetailId)
+		if err != nil {
+			return model.AssetAttachment{}, newError(ErrNotFound, "asset unit with id %s is not found", bodyRequest.AssetDetailId)
+		}
+		if unit.AssetId != bodyRequest.AssetId {
+			return model.AssetAttachment{}, newError(ErrInvalid, "asset unit %s doesn't belong to asset %s", unit.Id, bodyRequest.AssetId)
+		}
+		attachment.AssetDetailId = &unit.Id
+	}
+
+	if bodyRequest.File.Size > MaxAttachmentSize {
+		return model.AssetAttachment{}, newError(ErrTooLarge, "attachment can't be larger than %d MB", MaxAttachmentSize>>20)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(bodyRequest.File.Content, MaxAttachmentSize+1))
+	if err != nil {
+		return model.AssetAttachment{}, fmt.Errorf("failed to read attachment : %s", err.Error())
+	}
+	if len(content) > MaxAttachmentSize {
+		return model.AssetAttachment{}, newError(ErrTooLarge, "attachment can't be larger than %d MB", MaxAttachmentSize>>20)
+	}
+	if len(content) == 0 {
+		return model.AssetAttachment{}, newError(ErrInvalid, "attachment is empty")
+	}
+
+	attachment.ContentType = http.DetectContentType(content)
+	if !attachmentTypes[attachment.ContentType] {
+		return model.AssetAttachment{}, newError(ErrUnsupported, "file type %s is not allowed", attachment.ContentType)
+	}
+
+	checksum := sha256.Sum256(content)
+	attachment.Checksum = hex.EncodeToString(checksum[:])
+	attachment.Size = int64(len(content))
+	attachment.FileName = attachmentFileName(bodyRequest.File.FileName)
+	attachment.StorageKey = path.Join(assetAttachmentDir, attachment.AssetId, attachment.Id+strings.ToLower(filepath.Ext(attachment.FileName)))
+
+	err = a.fileStorage.Put(attachment.StorageKey, bytes.NewReader(content), attachment.Size, attachment.ContentType)
+	if err != nil {
+		return model.AssetAttachment{}, fmt.Errorf("failed to store attachment : %s", err.Error())
+	}
+
+	if err := a.repo.Create(attachment); err != nil {
+		a.fileStorage.Delete(attachment.StorageKey)
+		return model.AssetAttachment{}, fmt.Errorf("failed to save attachment : %s", err.Error())
+	}
+
+	return attachment, nil
+}
+
+func (a *assetAttachmentUsecase) ShowAttachments(assetId string) ([]model.AssetAttachment, error) {
+	if _, err := a.assetRepo.Detail(assetId); err != nil {
+		return nil, newError(ErrNotFound, "asset with id %s is not found", assetId)
+	}
+
+	attachments, err := a.repo.ListByAsset(assetId)
+	if err != nil {
+		return nil, fmt.Errorf("error get attachments : %s", err.Error())
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachment returns the record with its stored file, the caller closes
+// the object body.
+func (a *assetAttachmentUsecase) OpenAttachment(assetId, id string) (model.AssetAttachment, storage.Object, error) {
+	attachment, err := a.findAttachment(assetId, id)
+	if err != nil {
+		return model.AssetAttachment{}, storage.Object{}, err
+	}
+
+	if a.fileStorage == nil {
+		return model.AssetAttachment{}, storage.Object{}, fmt.Errorf("file storage is not configured")
+	}
+
+	object, err := a.fileStorage.Get(attachment.StorageKey)
+	if err != nil {
+		return model.AssetAttachment{}, storage.Object{}, fmt.Errorf("failed to open attachment : %s", err.Error())
+	}
+
+	return attachment, object, nil
+}
+
+// DeleteAttachment removes the record first, a file left behind by a failed
+// storage delete is harmless while a record without its file is not.
+func (a *assetAttachmentUsecase) DeleteAttachment(assetId, id string) error {
+	attachment, err := a.findAttachment(assetId, id)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repo.Delete(attachment.Id); err != nil {
+		return fmt.Errorf("failed to delete attachment : %s", err.Error())
+	}
+
+	if a.fileStorage != nil {
+		a.fileStorage.Delete(attachment.StorageKey)
+	}
+
+	return nil
+}
+
+func (a *assetAttachmentUsecase) findAttachment(assetId, id string) (model.AssetAttachment, error) {
+	attachment, err := a.repo.Get(id)
+	if err != nil || attachment.AssetId != assetId {
+		return model.AssetAttachment{}, newError(ErrNotFound, "attachment with id %s is not found", id)
+	}
+
+	return attachment, nil
+}
+
+// attachmentFileName keeps only the base name the client sent, it is shown on
+// download and never used as a storage path.
+func attachmentFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+func NewAssetAttachmentUsecase(repo repository.AssetAttachmentRepository, assetRepo repository.AssetRepository, fileStorage storage.Storage) AssetAttachmentUsecase {
+	return &assetAttachmentUsecase{
+		repo:        repo,
+		assetRepo:   assetRepo,
+		fileStorage: fileStorage,
+	}
+}
@@ -0,0 +1,183 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"asetku-bukan-asetmu/utils/storage"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (r *mockAttachmentRepository) Create(bodyRequest model.AssetAttachment) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockAttachmentRepository) ListByAsset(assetId string) ([]model.AssetAttachment, error) {
+	args := r.Called(assetId)
+	return args.Get(0).([]model.AssetAttachment), args.Error(1)
+}
+
+func (r *mockAttachmentRepository) Get(id string) (model.AssetAttachment, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetAttachment), args.Error(1)
+}
+
+func (r *mockAttachmentRepository) Delete(id string) error {
+	args := r.Called(id)
+	return args.Error(0)
+}
+
+func (r *mockAssetRepository) GetUnit(id string) (model.AssetDetail, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetDetail), args.Error(1)
+}
+
+type AssetAttachmentTestSuite struct {
+	suite.Suite
+	mockRepo      *mockAttachmentRepository
+	mockAssetRepo *mockAssetRepository
+	filePath      string
+	usecase       usecase.AssetAttachmentUsecase
+}
+
+func (s *AssetAttachmentTestSuite) SetupTest() {
+	s.mockRepo = new(mockAttachmentRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.filePath = s.T().TempDir()
+	fileStorage, err := storage.NewLocal(s.filePath, "secret")
+	assert.NoError(s.T(), err)
+	s.usecase = usecase.NewAssetAttachmentUsecase(s.mockRepo, s.mockAssetRepo, fileStorage)
+}
+
+func pdfDocument() []byte {
+	return []byte("%PDF-1.4\n%%EOF\n")
+}
+
+func attachmentUpload(content []byte) dto.AttachmentUploadDTO {
+	return dto.AttachmentUploadDTO{
+		AssetId:    "a1",
+		Title:      "Purchase invoice",
+		Type:       string(model.AttachmentInvoice),
+		UploadedBy: "budi",
+		File:       dto.FileUploadDTO{FileName: "..\\..\\invoice.PDF", Size: int64(len(content)), Content: bytes.NewReader(content)},
+	}
+}
+
+func (s *AssetAttachmentTestSuite) TestUploadAttachment_Success() {
+	content := pdfDocument()
+	checksum := sha256.Sum256(content)
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockAssetRepo.On("GetUnit", "d1").Return(model.AssetDetail{Id: "d1", AssetId: "a1"}, nil)
+	s.mockRepo.On("Create", mock.Anything).Return(nil)
+
+	bodyRequest := attachmentUpload(content)
+	bodyRequest.AssetDetailId = "d1"
+	attachment, err := s.usecase.UploadAttachment(bodyRequest)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "invoice.PDF", attachment.FileName)
+	assert.Equal(s.T(), "application/pdf", attachment.ContentType)
+	assert.Equal(s.T(), hex.EncodeToString(checksum[:]), attachment.Checksum)
+	assert.Equal(s.T(), int64(len(content)), attachment.Size)
+	assert.Equal(s.T(), "d1", *attachment.AssetDetailId)
+	assert.True(s.T(), strings.HasPrefix(attachment.StorageKey, "attachments/a1/"))
+	assert.True(s.T(), strings.HasSuffix(attachment.StorageKey, ".pdf"))
+
+	stored, _ := os.ReadFile(filepath.Join(s.filePath, filepath.FromSlash(attachment.StorageKey)))
+	assert.Equal(s.T(), content, stored)
+}
+
+func (s *AssetAttachmentTestSuite) TestUploadAttachment_UnitOfOtherAsset() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockAssetRepo.On("GetUnit", "d2").Return(model.AssetDetail{Id: "d2", AssetId: "a2"}, nil)
+
+	bodyRequest := attachmentUpload(pdfDocument())
+	bodyRequest.AssetDetailId = "d2"
+	_, err := s.usecase.UploadAttachment(bodyRequest)
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetAttachmentTestSuite) TestUploadAttachment_RejectsExecutable() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+
+	_, err := s.usecase.UploadAttachment(attachmentUpload([]byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00")))
+	assert.ErrorIs(s.T(), err, usecase.ErrUnsupported)
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetAttachmentTestSuite) TestUploadAttachment_InvalidType() {
+	bodyRequest := attachmentUpload(pdfDocument())
+	bodyRequest.Type = "receipt"
+	_, err := s.usecase.UploadAttachment(bodyRequest)
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+}
+
+func (s *AssetAttachmentTestSuite) TestUploadAttachment_SaveFailRemovesFile() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockRepo.On("Create", mock.Anything).Return(errors.New("error"))
+
+	_, err := s.usecase.UploadAttachment(attachmentUpload(pdfDocument()))
+	assert.Error(s.T(), err)
+
+	entries, _ := os.ReadDir(filepath.Join(s.filePath, "attachments", "a1"))
+	assert.Empty(s.T(), entries)
+}
+
+func (s *AssetAttachmentTestSuite) TestOpenAttachment_Success() {
+	content := pdfDocument()
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockRepo.On("Create", mock.Anything).Return(nil)
+	attachment, err := s.usecase.UploadAttachment(attachmentUpload(content))
+	assert.NoError(s.T(), err)
+
+	s.mockRepo.On("Get", attachment.Id).Return(attachment, nil)
+	found, object, err := s.usecase.OpenAttachment("a1", attachment.Id)
+	assert.NoError(s.T(), err)
+	defer object.Body.Close()
+
+	data, _ := io.ReadAll(object.Body)
+	assert.Equal(s.T(), attachment.Id, found.Id)
+	assert.Equal(s.T(), content, data)
+}
+
+func (s *AssetAttachmentTestSuite) TestOpenAttachment_OtherAsset() {
+	s.mockRepo.On("Get", "f1").Return(model.AssetAttachment{Id: "f1", AssetId: "a2"}, nil)
+
+	_, _, err := s.usecase.OpenAttachment("a1", "f1")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+}
+
+func (s *AssetAttachmentTestSuite) TestDeleteAttachment_Success() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockRepo.On("Create", mock.Anything).Return(nil)
+	attachment, err := s.usecase.UploadAttachment(attachmentUpload(pdfDocument()))
+	assert.NoError(s.T(), err)
+
+	s.mockRepo.On("Get", attachment.Id).Return(attachment, nil)
+	s.mockRepo.On("Delete", attachment.Id).Return(nil)
+	assert.NoError(s.T(), s.usecase.DeleteAttachment("a1", attachment.Id))
+
+	_, err = os.Stat(filepath.Join(s.filePath, filepath.FromSlash(attachment.StorageKey)))
+	assert.True(s.T(), os.IsNotExist(err))
+}
+
+func TestAssetAttachmentTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetAttachmentTestSuite))
+}
@@ -3,13 +3,18 @@ package usecase
 import (
 	"asetku-bukan-asetmu/model"
 	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"asetku-bukan-asetmu/utils/depreciation"
+	"asetku-bukan-asetmu/utils/tag"
 	"fmt"
+	"strings"
 )
 
 type AssetCategoriesUseCase interface {
 	RegisterNewAssetCategories(payload model.AssetCategories) error
 	FindAllAssetCategoriesList() ([]model.AssetCategories, error)
 	FindAssetCategoriesById(id string) (model.AssetCategories, error)
+	CategorySchema(id string) ([]model.CategoryAttribute, error)
 	UpdateAssetCategories(payload model.AssetCategories) error
 	DeleteAssetCategories(id string) error
 	// FindAllAssetCategorie(requesPaging dto.PaginationParam, byNameEmpl string) ([]model.Employee, dto.Paging, error)
@@ -25,7 +30,27 @@ func (a *assetcategoriesUseCase) RegisterNewAssetCategories(payload model.AssetC
 		return fmt.Errorf("name is required")
 	}
 
-	err := a.repo.Create(payload)
+	payload, err := validateDepreciationConfig(payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = a.validateTagConfig(payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = a.validateParent(payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = a.validateAttributes(payload, nil)
+	if err != nil {
+		return err
+	}
+
+	err = a.repo.Create(payload)
 	if err != nil {
 		return fmt.Errorf("failed to create add category : %s", err.Error())
 	}
@@ -37,11 +62,38 @@ func (a *assetcategoriesUseCase) FindAllAssetCategoriesList() ([]model.AssetCate
 }
 
 func (a *assetcategoriesUseCase) FindAssetCategoriesById(id string) (model.AssetCategories, error) {
-	return a.repo.Get(id)
+	category, err := a.repo.Get(id)
+	if err != nil {
+		return model.AssetCategories{}, err
+	}
+
+	category.Attributes, err = a.repo.Attributes(id)
+	if err != nil {
+		return model.AssetCategories{}, fmt.Errorf("error get category attributes : %s", err.Error())
+	}
+
+	return category, nil
+}
+
+// CategorySchema returns every attribute an asset of the category can hold,
+// the ones inherited from the parents come first.
+func (a *assetcategoriesUseCase) CategorySchema(id string) ([]model.CategoryAttribute, error) {
+	var schema []model.CategoryAttribute
+	for categoryId := &id; categoryId != nil; {
+		category, err := a.FindAssetCategoriesById(*categoryId)
+		if err != nil {
+			return nil, err
+		}
+
+		schema = append(category.Attributes, schema...)
+		categoryId = category.ParentId
+	}
+
+	return schema, nil
 }
 
 func (a *assetcategoriesUseCase) UpdateAssetCategories(payload model.AssetCategories) error {
-	_, err := a.FindAssetCategoriesById(payload.Id)
+	existing, err := a.FindAssetCategoriesById(payload.Id)
 	if err != nil {
 		return fmt.Errorf("can't find category id")
 	}
@@ -50,6 +102,26 @@ func (a *assetcategoriesUseCase) UpdateAssetCategories(payload model.AssetCatego
 		return fmt.Errorf("name is required")
 	}
 
+	payload, err = validateDepreciationConfig(payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = a.validateTagConfig(payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = a.validateParent(payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = a.validateAttributes(payload, existing.Attributes)
+	if err != nil {
+		return err
+	}
+
 	err = a.repo.Update(payload)
 	if err != nil {
 		return fmt.Errorf("failed to update category : %s", err.Error())
@@ -72,6 +144,172 @@ func (a *assetcategoriesUseCase) DeleteAssetCategories(id string) error {
 	return nil
 }
 
+// validateDepreciationConfig checks the commercial method and, when set, the
+// fiscal group and the fiscal method allowed for that group.
+func validateDepreciationConfig(payload model.AssetCategories) (model.AssetCategories, error) {
+	method, err := depreciation.ParseMethod(payload.DepreciationMethod)
+	if err != nil {
+		return model.AssetCategories{}, err
+	}
+	payload.DepreciationMethod = string(method)
+
+	fiscalMethod, err := depreciation.ParseMethod(payload.FiscalMethod)
+	if err != nil {
+		return model.AssetCategories{}, err
+	}
+	payload.FiscalMethod = string(fiscalMethod)
+
+	if payload.FiscalGroup == "" {
+		return payload, nil
+	}
+
+	group, err := depreciation.ParseFiscalGroup(payload.FiscalGroup)
+	if err != nil {
+		return model.AssetCategories{}, err
+	}
+
+	rule, _ := depreciation.FiscalRuleOf(group)
+	if fiscalMethod == depreciation.DoubleDeclining && rule.DecliningRate == 0 {
+		return model.AssetCategories{}, fmt.Errorf("fiscal group %s only allows the %s method", group, depreciation.StraightLine)
+	}
+
+	return payload, nil
+}
+
+// validateTagConfig normalizes the tag prefix and makes sure no other
+// category hands out tags with the same prefix.
+func (a *assetcategoriesUseCase) validateTagConfig(payload model.AssetCategories) (model.AssetCategories, error) {
+	prefix, err := tag.NormalizePrefix(payload.TagPrefix)
+	if err != nil {
+		return model.AssetCategories{}, err
+	}
+	payload.TagPrefix = prefix
+
+	if payload.TagPadding == 0 {
+		payload.TagPadding = tag.DefaultPadding
+	}
+
+	if prefix == "" {
+		return payload, nil
+	}
+
+	categories, err := a.repo.List()
+	if err != nil {
+		return model.AssetCategories{}, fmt.Errorf("failed to check tag prefix : %s", err.Error())
+	}
+
+	for _, category := range categories {
+		if category.Id != payload.Id && category.TagPrefix == prefix {
+			return model.AssetCategories{}, fmt.Errorf("tag prefix %s is already used by category %s", prefix, category.Name)
+		}
+	}
+
+	return payload, nil
+}
+
+// validateParent makes sure the parent category exists and that the
+// category isn't nested under itself or one of its own sub categories.
+func (a *assetcategoriesUseCase) validateParent(payload model.AssetCategories) (model.AssetCategories, error) {
+	if payload.ParentId != nil && *payload.ParentId == "" {
+		payload.ParentId = nil
+	}
+
+	for parentId := payload.ParentId; parentId != nil; {
+		if *parentId == payload.Id {
+			return model.AssetCategories{}, fmt.Errorf("category can't be nested under itself or one of its sub categories")
+		}
+
+		parent, err := a.repo.Get(*parentId)
+		if err != nil {
+			return model.AssetCategories{}, fmt.Errorf("parent category with id %s is not found", *parentId)
+		}
+		parentId = parent.ParentId
+	}
+
+	return payload, nil
+}
+
+// validateAttributes checks the attribute definitions of a category against
+// each other, against the inherited ones and against the existing ones that
+// are kept. Attributes that already belong to the category keep their id,
+// every other one gets a new id.
+func (a *assetcategoriesUseCase) validateAttributes(payload model.AssetCategories, existing []model.CategoryAttribute) (model.AssetCategories, error) {
+	names := make(map[string]bool)
+	if payload.ParentId != nil {
+		inherited, err := a.CategorySchema(*payload.ParentId)
+		if err != nil {
+			return model.AssetCategories{}, fmt.Errorf("error get parent attributes : %s", err.Error())
+		}
+
+		for _, attribute := range inherited {
+			names[strings.ToLower(attribute.Name)] = true
+		}
+	}
+
+	owned := make(map[string]bool, len(existing))
+	for _, attribute := range existing {
+		owned[attribute.Id] = true
+	}
+
+	removed := make(map[string]bool, len(payload.RemovedAttributeIds))
+	for _, id := range payload.RemovedAttributeIds {
+		if !owned[id] {
+			return model.AssetCategories{}, fmt.Errorf("category %s has no attribute with id %s", payload.Id, id)
+		}
+		removed[id] = true
+	}
+
+	sent := make(map[string]bool, len(payload.Attributes))
+	for _, attribute := range payload.Attributes {
+		if removed[attribute.Id] {
+			return model.AssetCategories{}, fmt.Errorf("attribute %s can't be changed and removed at once", attribute.Name)
+		}
+		sent[attribute.Id] = true
+	}
+
+	// Existing attributes that are neither sent nor removed keep their names
+	for _, attribute := range existing {
+		if !sent[attribute.Id] && !removed[attribute.Id] {
+			names[strings.ToLower(attribute.Name)] = true
+		}
+	}
+
+	attributes := make([]model.CategoryAttribute, 0, len(payload.Attributes))
+	for _, attribute := range payload.Attributes {
+		attribute.Name = strings.TrimSpace(attribute.Name)
+		if attribute.Name == "" {
+			return model.AssetCategories{}, fmt.Errorf("attribute name is required")
+		}
+
+		key := strings.ToLower(attribute.Name)
+		if names[key] {
+			return model.AssetCategories{}, fmt.Errorf("attribute %s is already defined", attribute.Name)
+		}
+		names[key] = true
+
+		if !attribute.Type.IsValid() {
+			return model.AssetCategories{}, fmt.Errorf("attribute %s must be text, number, date, enum or boolean", attribute.Name)
+		}
+
+		if attribute.Type == model.AttributeEnum && len(attribute.Options) == 0 {
+			return model.AssetCategories{}, fmt.Errorf("enum attribute %s needs at least one option", attribute.Name)
+		}
+		if attribute.Type != model.AttributeEnum && len(attribute.Options) > 0 {
+			return model.AssetCategories{}, fmt.Errorf("only enum attributes have options")
+		}
+
+		if !owned[attribute.Id] {
+			attribute.Id = common.GenerateUUID()
+		}
+		attribute.CategoryId = payload.Id
+
+		attributes = append(attributes, attribute)
+	}
+	payload.Attributes = attributes
+
+	return payload, nil
+}
+
 // func (a *assetcategoriesUseCase) FindAllAssetCategories(requesPaging dto.PaginationParam, byNameEmpl string) ([]model.AssetCategories, dto.Paging, error){
 // 	return a.repo.Paging(requesPaging,byNameEmpl)
 // }
@@ -0,0 +1,252 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockCategoryRepository struct {
+	mock.Mock
+}
+
+func (r *mockCategoryRepository) Create(payload model.AssetCategories) error {
+	args := r.Called(payload)
+	return args.Error(0)
+}
+
+func (r *mockCategoryRepository) List() ([]model.AssetCategories, error) {
+	args := r.Called()
+	return args.Get(0).([]model.AssetCategories), args.Error(1)
+}
+
+func (r *mockCategoryRepository) Get(id string) (model.AssetCategories, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetCategories), args.Error(1)
+}
+
+func (r *mockCategoryRepository) Update(payload model.AssetCategories) error {
+	args := r.Called(payload)
+	return args.Error(0)
+}
+
+func (r *mockCategoryRepository) Delete(id string) error {
+	args := r.Called(id)
+	return args.Error(0)
+}
+
+func (r *mockCategoryRepository) Attributes(categoryId string) ([]model.CategoryAttribute, error) {
+	args := r.Called(categoryId)
+	return args.Get(0).([]model.CategoryAttribute), args.Error(1)
+}
+
+func (r *mockAssetRepository) Create(bodyRequest model.Asset) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockAssetRepository) AttributeValues(assetId string) ([]model.AssetAttributeValue, error) {
+	args := r.Called(assetId)
+	return args.Get(0).([]model.AssetAttributeValue), args.Error(1)
+}
+
+type AssetCategoriesUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockCategoryRepository
+	mockAssetRepo *mockAssetRepository
+	mockLocation  *mockLocationUsecase
+	usecase       usecase.AssetCategoriesUseCase
+	assetUsecase  usecase.AssetUsecase
+}
+
+// SetupTest registers an IT category with a serial number and a laptop
+// sub category that adds RAM, a CPU and an optional condition.
+func (s *AssetCategoriesUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockCategoryRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockLocation = new(mockLocationUsecase)
+	s.usecase = usecase.NewAssetCategoriesUseCase(s.mockRepo)
+	s.assetUsecase = usecase.NewAssetUsecase(s.mockAssetRepo, s.mockLocation, s.usecase, nil, 0)
+
+	s.mockRepo.On("Get", "it").Return(model.AssetCategories{Id: "it", Name: "IT"}, nil)
+	s.mockRepo.On("Attributes", "it").Return([]model.CategoryAttribute{
+		{Id: "serial", CategoryId: "it", Name: "serialNumber", Type: model.AttributeText, Required: true},
+	}, nil)
+	s.mockRepo.On("Get", "laptop").Return(model.AssetCategories{Id: "laptop", Name: "Laptop", ParentId: strPtr("it")}, nil)
+	s.mockRepo.On("Attributes", "laptop").Return([]model.CategoryAttribute{
+		{Id: "ram", CategoryId: "laptop", Name: "ram", Type: model.AttributeNumber, Required: true},
+		{Id: "cpu", CategoryId: "laptop", Name: "cpu", Type: model.AttributeEnum, Options: []string{"i5", "i7"}},
+		{Id: "purchased", CategoryId: "laptop", Name: "warrantyUntil", Type: model.AttributeDate},
+	}, nil)
+	s.mockRepo.On("Get", "missing").Return(model.AssetCategories{}, sql.ErrNoRows)
+	s.mockRepo.On("List").Return([]model.AssetCategories{}, nil)
+
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1"}, nil)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestCategorySchema_InheritsParents() {
+	schema, err := s.usecase.CategorySchema("laptop")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), schema, 4)
+	assert.Equal(s.T(), "serialNumber", schema[0].Name)
+	assert.Equal(s.T(), "ram", schema[1].Name)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestRegisterNewAssetCategories_NestedWithAttributes() {
+	s.mockRepo.On("Create", mock.MatchedBy(func(payload model.AssetCategories) bool {
+		return *payload.ParentId == "laptop" && payload.Attributes[0].Id != "" && payload.Attributes[0].CategoryId == "gaming"
+	})).Return(nil)
+
+	err := s.usecase.RegisterNewAssetCategories(model.AssetCategories{Id: "gaming", Name: "Gaming Laptop", ParentId: strPtr("laptop"), Attributes: []model.CategoryAttribute{
+		{Name: "gpu", Type: model.AttributeText},
+	}})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestRegisterNewAssetCategories_InheritedNameTaken() {
+	err := s.usecase.RegisterNewAssetCategories(model.AssetCategories{Id: "gaming", Name: "Gaming Laptop", ParentId: strPtr("laptop"), Attributes: []model.CategoryAttribute{
+		{Name: "SerialNumber", Type: model.AttributeText},
+	}})
+	assert.ErrorContains(s.T(), err, "attribute SerialNumber is already defined")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestRegisterNewAssetCategories_EnumWithoutOptions() {
+	err := s.usecase.RegisterNewAssetCategories(model.AssetCategories{Id: "chair", Name: "Chair", Attributes: []model.CategoryAttribute{
+		{Name: "color", Type: model.AttributeEnum},
+	}})
+	assert.ErrorContains(s.T(), err, "enum attribute color needs at least one option")
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestUpdateAssetCategories_NestedUnderSubCategory() {
+	err := s.usecase.UpdateAssetCategories(model.AssetCategories{Id: "it", Name: "IT", ParentId: strPtr("laptop")})
+	assert.ErrorContains(s.T(), err, "can't be nested under itself")
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestUpdateAssetCategories_KeepsOmittedAttributes() {
+	s.mockRepo.On("Update", mock.MatchedBy(func(payload model.AssetCategories) bool {
+		return len(payload.Attributes) == 1 && payload.Attributes[0].Id == "cpu" && payload.RemovedAttributeIds[0] == "purchased"
+	})).Return(nil)
+
+	err := s.usecase.UpdateAssetCategories(model.AssetCategories{Id: "laptop", Name: "Laptop", ParentId: strPtr("it"), Attributes: []model.CategoryAttribute{
+		{Id: "cpu", Name: "cpu", Type: model.AttributeEnum, Options: []string{"i5", "i7", "m1"}},
+	}, RemovedAttributeIds: []string{"purchased"}})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestUpdateAssetCategories_KeptNameTaken() {
+	err := s.usecase.UpdateAssetCategories(model.AssetCategories{Id: "laptop", Name: "Laptop", ParentId: strPtr("it"), Attributes: []model.CategoryAttribute{
+		{Name: "RAM", Type: model.AttributeText},
+	}})
+	assert.ErrorContains(s.T(), err, "attribute RAM is already defined")
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestUpdateAssetCategories_RemoveUnknownAttribute() {
+	err := s.usecase.UpdateAssetCategories(model.AssetCategories{Id: "laptop", Name: "Laptop", ParentId: strPtr("it"), RemovedAttributeIds: []string{"serial"}})
+	assert.ErrorContains(s.T(), err, "category laptop has no attribute with id serial")
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestCreateNewAsset_StoresAttributeValues() {
+	s.mockAssetRepo.On("Create", mock.MatchedBy(func(asset model.Asset) bool {
+		return len(asset.AttributeValues) == 3 && asset.AttributeValues[1].Value == "16"
+	})).Return(nil)
+
+	err := s.assetUsecase.CreateNewAsset(model.Asset{Id: "a1", CategoryId: "laptop", Name: "Laptop", Qty: 1, LocationId: "l1", Attributes: map[string]any{
+		"serialNumber": "SN-1",
+		"ram":          float64(16),
+		"cpu":          "i7",
+	}})
+	assert.NoError(s.T(), err)
+	s.mockAssetRepo.AssertCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestCreateNewAsset_MissingRequiredAttribute() {
+	err := s.assetUsecase.CreateNewAsset(model.Asset{Id: "a1", CategoryId: "laptop", Name: "Laptop", Qty: 1, LocationId: "l1", Attributes: map[string]any{
+		"ram": float64(16),
+	}})
+	assert.ErrorContains(s.T(), err, "attribute serialNumber is required")
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestCreateNewAsset_InvalidAttributeValues() {
+	cases := map[string]map[string]any{
+		"attribute ram must be a number":              {"serialNumber": "SN-1", "ram": "16GB"},
+		"attribute cpu must be one of [i5 i7]":        {"serialNumber": "SN-1", "ram": float64(8), "cpu": "m1"},
+		"attribute warrantyUntil must be a date":      {"serialNumber": "SN-1", "ram": float64(8), "warrantyUntil": "next year"},
+		"category laptop has no attribute screenSize": {"serialNumber": "SN-1", "ram": float64(8), "screenSize": float64(14)},
+		"attribute serialNumber must be a string":     {"serialNumber": float64(1), "ram": float64(8)},
+	}
+
+	for expected, attributes := range cases {
+		err := s.assetUsecase.CreateNewAsset(model.Asset{Id: "a1", CategoryId: "laptop", Name: "Laptop", Qty: 1, LocationId: "l1", Attributes: attributes})
+		assert.ErrorContains(s.T(), err, expected)
+	}
+	s.mockAssetRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestPatchAsset_CategoryChangeNeedsAttributes() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", CategoryId: "it", Name: "Laptop"}, nil)
+	s.mockAssetRepo.On("AttributeValues", "a1").Return([]model.AssetAttributeValue{{AssetId: "a1", AttributeId: "serial", Value: "SN-1"}}, nil)
+
+	err := s.assetUsecase.PatchAsset("a1", dto.AssetPatchDTO{CategoryId: strPtr("laptop")})
+	assert.ErrorContains(s.T(), err, "attribute ram is required")
+	s.mockAssetRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestPatchAsset_CategoryChangeCarriesValues() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", CategoryId: "it", Name: "Laptop"}, nil)
+	s.mockAssetRepo.On("AttributeValues", "a1").Return([]model.AssetAttributeValue{{AssetId: "a1", AttributeId: "serial", Value: "SN-1"}}, nil)
+	s.mockAssetRepo.On("Update", mock.MatchedBy(func(asset model.Asset) bool {
+		return asset.CategoryId == "laptop" && len(asset.AttributeValues) == 2 &&
+			asset.AttributeValues[0].Value == "SN-1" && asset.AttributeValues[1].Value == "16"
+	})).Return(nil)
+
+	err := s.assetUsecase.PatchAsset("a1", dto.AssetPatchDTO{CategoryId: strPtr("laptop"), Attributes: map[string]any{"ram": float64(16)}})
+	assert.NoError(s.T(), err)
+	s.mockAssetRepo.AssertCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestUpdateAsset_DropsValuesOutsideNewCategory() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", CategoryId: "laptop", Name: "Laptop"}, nil)
+	s.mockAssetRepo.On("AttributeValues", "a1").Return([]model.AssetAttributeValue{
+		{AssetId: "a1", AttributeId: "serial", Value: "SN-1"},
+		{AssetId: "a1", AttributeId: "ram", Value: "16"},
+	}, nil)
+	s.mockAssetRepo.On("Update", mock.MatchedBy(func(asset model.Asset) bool {
+		return asset.CategoryId == "it" && len(asset.AttributeValues) == 1 && asset.AttributeValues[0].AttributeId == "serial"
+	})).Return(nil)
+
+	err := s.assetUsecase.UpdateAsset("a1", dto.AssetUpdateDTO{CategoryId: "it", Name: "Laptop"})
+	assert.NoError(s.T(), err)
+	s.mockAssetRepo.AssertCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestUpdateAsset_KeepsValuesWithoutChange() {
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", CategoryId: "laptop", Name: "Laptop"}, nil)
+	s.mockAssetRepo.On("Update", mock.MatchedBy(func(asset model.Asset) bool {
+		return asset.Name == "Work Laptop" && asset.AttributeValues == nil
+	})).Return(nil)
+
+	err := s.assetUsecase.UpdateAsset("a1", dto.AssetUpdateDTO{CategoryId: "laptop", Name: "Work Laptop"})
+	assert.NoError(s.T(), err)
+	s.mockAssetRepo.AssertNotCalled(s.T(), "AttributeValues", "a1")
+}
+
+func (s *AssetCategoriesUsecaseTestSuite) TestCreateNewAsset_UnknownCategory() {
+	err := s.assetUsecase.CreateNewAsset(model.Asset{Id: "a1", CategoryId: "missing", Name: "Chair", Qty: 1, LocationId: "l1"})
+	assert.ErrorContains(s.T(), err, "category with id missing is not found")
+}
+
+func TestAssetCategoriesUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetCategoriesUsecaseTestSuite))
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"asetku-bukan-asetmu/utils/depreciation"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AssetDisposalUsecase interface {
+	RequestDisposal(bodyRequest model.AssetDisposal) (model.AssetDisposal, error)
+	GetDisposal(id string) (model.AssetDisposal, error)
+	ShowDisposals(status string) ([]model.AssetDisposal, error)
+	ApproveDisposal(id string, bodyRequest dto.DisposalDecisionDTO) (model.AssetDisposal, error)
+	RejectDisposal(id string, bodyRequest dto.DisposalDecisionDTO) (model.AssetDisposal, error)
+}
+
+type assetDisposalUsecase struct {
+	repo        repository.AssetDisposalRepository
+	assetRepo   repository.AssetRepository
+	ctgrUsecase AssetCategoriesUseCase
+}
+
+// RequestDisposal records a pending disposal, the units stay in service
+// until it is approved. Sales, trade-ins and donations name the buyer or
+// recipient, donations bring no proceeds.
+func (a *assetDisposalUsecase) RequestDisposal(bodyRequest model.AssetDisposal) (model.AssetDisposal, error) {
+	if !bodyRequest.Method.IsValid() {
+		return model.AssetDisposal{}, newError(ErrInvalid, "unknown disposal method %s", bodyRequest.Method)
+	}
+
+	bodyRequest.Counterparty = strings.TrimSpace(bodyRequest.Counterparty)
+	if bodyRequest.Method != model.DisposalScrap && bodyRequest.Counterparty == "" {
+		return model.AssetDisposal{}, newError(ErrInvalid, "%s needs a buyer or recipient", bodyRequest.Method)
+	}
+
+	if bodyRequest.Method == model.DisposalDonation && bodyRequest.Proceeds != 0 {
+		return model.AssetDisposal{}, newError(ErrInvalid, "donation can't have proceeds")
+	}
+
+	asset, err := a.assetRepo.Detail(bodyRequest.AssetId)
+	if err != nil {
+		return model.AssetDisposal{}, newError(ErrNotFound, "asset with id %s is not found", bodyRequest.AssetId)
+	}
+
+	bodyRequest.DisposedAt = dateOf(bodyRequest.DisposedAt)
+	if bodyRequest.DisposedAt.After(dateOf(time.Now())) {
+		return model.AssetDisposal{}, newError(ErrInvalid, "disposal date can't be in the future")
+	}
+	if bodyRequest.DisposedAt.Before(dateOf(asset.CreatedAt)) {
+		return model.AssetDisposal{}, newError(ErrInvalid, "disposal date can't be before the asset was acquired")
+	}
+
+	if _, err := a.disposableUnits(bodyRequest); err != nil {
+		return model.AssetDisposal{}, err
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.Status = model.DisposalPending
+	bodyRequest.DecidedBy = ""
+	bodyRequest.DecidedAt = nil
+	bodyRequest.DecisionNote = ""
+	bodyRequest.BookValue = nil
+	bodyRequest.GainLoss = nil
+	bodyRequest.CreatedAt = time.Now()
+
+	if err := a.repo.Create(bodyRequest); err != nil {
+		return model.AssetDisposal{}, fmt.Errorf("failed to request disposal : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (a *assetDisposalUsecase) GetDisposal(id string) (model.AssetDisposal, error) {
+	disposal, err := a.repo.Get(id)
+	if err != nil {
+		return model.AssetDisposal{}, newError(ErrNotFound, "disposal with id %s is not found", id)
+	}
+
+	return disposal, nil
+}
+
+func (a *assetDisposalUsecase) ShowDisposals(status string) ([]model.AssetDisposal, error) {
+	disposalStatus := model.DisposalStatus(status)
+	switch disposalStatus {
+	case "", model.DisposalPending, model.DisposalApproved, model.DisposalRejected:
+	default:
+		return nil, newError(ErrInvalid, "unknown disposal status %s", status)
+	}
+
+	disposals, err := a.repo.List(disposalStatus)
+	if err != nil {
+		return nil, fmt.Errorf("error get disposals : %s", err.Error())
+	}
+
+	return disposals, nil
+}
+
+// ApproveDisposal disposes the units for good and books the gain or loss,
+// the proceeds against the commercial book value on the disposal date. The
+// approver can't be the one who requested it.
+func (a *assetDisposalUsecase) ApproveDisposal(id string, bodyRequest dto.DisposalDecisionDTO) (model.AssetDisposal, error) {
+	disposal, err := a.pendingDisposal(id, bodyRequest)
+	if err != nil {
+		return model.AssetDisposal{}, err
+	}
+
+	units, err := a.disposableUnits(disposal)
+	if err != nil {
+		return model.AssetDisposal{}, err
+	}
+
+	asset, err := a.assetRepo.Detail(disposal.AssetId)
+	if err != nil {
+		return model.AssetDisposal{}, newError(ErrNotFound, "asset with id %s is not found", disposal.AssetId)
+	}
+
+	category, err := a.ctgrUsecase.FindAssetCategoriesById(asset.CategoryId)
+	if err != nil {
+		return model.AssetDisposal{}, fmt.Errorf("error get category : %s", err.Error())
+	}
+
+	unitBookValue, err := bookValueAt(asset, category, disposal.DisposedAt)
+	if err != nil {
+		return model.AssetDisposal{}, fmt.Errorf("error calculate book value : %s", err.Error())
+	}
+
+	bookValue := depreciation.Round(unitBookValue * float64(len(units)))
+	gainLoss := depreciation.Round(disposal.Proceeds - bookValue)
+	disposal.BookValue = &bookValue
+	disposal.GainLoss = &gainLoss
+
+	err = a.repo.Approve(disposal, units)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.AssetDisposal{}, newError(ErrConflict, "failed to approve disposal : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetDisposal{}, fmt.Errorf("failed to approve disposal : %s", err.Error())
+	}
+
+	disposal.Status = model.DisposalApproved
+	return disposal, nil
+}
+
+func (a *assetDisposalUsecase) RejectDisposal(id string, bodyRequest dto.DisposalDecisionDTO) (model.AssetDisposal, error) {
+	disposal, err := a.pendingDisposal(id, bodyRequest)
+	if err != nil {
+		return model.AssetDisposal{}, err
+	}
+
+	err = a.repo.Reject(disposal)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.AssetDisposal{}, newError(ErrConflict, "failed to reject disposal : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetDisposal{}, fmt.Errorf("failed to reject disposal : %s", err.Error())
+	}
+
+	disposal.Status = model.DisposalRejected
+	return disposal, nil
+}
+
+func (a *assetDisposalUsecase) pendingDisposal(id string, bodyRequest dto.DisposalDecisionDTO) (model.AssetDisposal, error) {
+	disposal, err := a.repo.Get(id)
+	if err != nil {
+		return model.AssetDisposal{}, newError(ErrNotFound, "disposal with id %s is not found", id)
+	}
+
+	if disposal.Status != model.DisposalPending {
+		return model.AssetDisposal{}, newError(ErrConflict, "disposal %s is already %s", id, disposal.Status)
+	}
+
+	if strings.EqualFold(strings.TrimSpace(bodyRequest.Actor), strings.TrimSpace(disposal.RequestedBy)) {
+		return model.AssetDisposal{}, newError(ErrForbidden, "disposal can't be decided by the one who requested it")
+	}
+
+	disposal.DecidedBy = bodyRequest.Actor
+	disposal.DecidedAt = time.Now()
+	disposal.DecisionNote = bodyRequest.Note
+	return disposal, nil
+}
+
+// disposableUnits loads the units of a disposal and checks each of them can
+// still be disposed.
+func (a *assetDisposalUsecase) disposableUnits(disposal model.AssetDisposal) ([]model.AssetDetail, error) {
+	selected := make(map[string]bool, len(disposal.AssetDetailIds))
+	units := make([]model.AssetDetail, 0, len(disposal.AssetDetailIds))
+	for _, unitId := range disposal.AssetDetailIds {
+		if selected[unitId] {
+			return nil, newError(ErrInvalid, "asset unit %s is selected more than once", unitId)
+		}
+		selected[unitId] = true
+
+		unit, err := a.assetRepo.GetUnit(unitId)
+		if err != nil {
+			return nil, newError(ErrNotFound, "asset unit with id %s is not found", unitId)
+		}
+
+		if unit.AssetId != disposal.AssetId {
+			return nil, newError(ErrInvalid, "asset unit %s doesn't belong to asset %s", unitId, disposal.AssetId)
+		}
+
+		if unit.RemovedAt != nil {
+			return nil, newError(ErrConflict, "asset unit %s is already retired", unitId)
+		}
+
+		if err := ValidateStatusTransition(unit.Status, model.StatusDisposed); err != nil {
+			return nil, newError(ErrConflict, "asset unit %s can't be disposed : %s", unitId, err.Error())
+		}
+
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+// bookValueAt is the commercial book value of one unit, assets that are not
+// depreciated keep their cost.
+func bookValueAt(asset model.Asset, category model.AssetCategories, at time.Time) (float64, error) {
+	_, _, schedule, err := commercialSchedule(asset, category)
+	if err != nil {
+		return 0, err
+	}
+
+	if schedule == nil {
+		return asset.Cost, nil
+	}
+
+	return depreciation.BookValueAt(asset.Cost, schedule, at), nil
+}
+
+func NewAssetDisposalUsecase(repo repository.AssetDisposalRepository, assetRepo repository.AssetRepository, categoryUsecase AssetCategoriesUseCase) AssetDisposalUsecase {
+	return &assetDisposalUsecase{
+		repo:        repo,
+		assetRepo:   assetRepo,
+		ctgrUsecase: categoryUsecase,
+	}
+}
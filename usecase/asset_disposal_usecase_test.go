@@ -0,0 +1,156 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockDisposalRepository struct {
+	mock.Mock
+}
+
+func (r *mockDisposalRepository) Create(bodyRequest model.AssetDisposal) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockDisposalRepository) Get(id string) (model.AssetDisposal, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetDisposal), args.Error(1)
+}
+
+func (r *mockDisposalRepository) List(status model.DisposalStatus) ([]model.AssetDisposal, error) {
+	args := r.Called(status)
+	return args.Get(0).([]model.AssetDisposal), args.Error(1)
+}
+
+func (r *mockDisposalRepository) ListApproved(from, to time.Time) ([]model.AssetDisposal, error) {
+	args := r.Called(from, to)
+	return args.Get(0).([]model.AssetDisposal), args.Error(1)
+}
+
+func (r *mockDisposalRepository) Approve(bodyRequest model.AssetDisposal, units []model.AssetDetail) error {
+	args := r.Called(bodyRequest, units)
+	return args.Error(0)
+}
+
+func (r *mockDisposalRepository) Reject(bodyRequest model.AssetDisposal) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+type AssetDisposalUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockDisposalRepository
+	mockAssetRepo *mockAssetRepository
+	mockCategory  *mockCategoryUsecase
+	usecase       usecase.AssetDisposalUsecase
+}
+
+func (s *AssetDisposalUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockDisposalRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockCategory = new(mockCategoryUsecase)
+	s.usecase = usecase.NewAssetDisposalUsecase(s.mockRepo, s.mockAssetRepo, s.mockCategory)
+
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", CategoryId: "c1", Cost: 1000, CreatedAt: daysFromToday(-400)}, nil)
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusInStorage}, nil)
+	s.mockAssetRepo.On("GetUnit", "u2").Return(model.AssetDetail{Id: "u2", AssetId: "a1", Status: model.StatusPlaced}, nil)
+	s.mockCategory.On("FindAssetCategoriesById", "c1").Return(model.AssetCategories{Id: "c1", DepreciationMethod: "straight-line"}, nil)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestRequestDisposal_Success() {
+	s.mockRepo.On("Create", mock.MatchedBy(func(disposal model.AssetDisposal) bool {
+		return disposal.Id != "" && disposal.Status == model.DisposalPending
+	})).Return(nil)
+
+	disposal, err := s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u1", "u2"}, Method: model.DisposalSale, DisposedAt: time.Now(), Proceeds: 1500, Counterparty: "PT Maju", RequestedBy: "rina"})
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), disposal.BookValue)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestRequestDisposal_Invalid() {
+	_, err := s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u1"}, Method: model.DisposalSale, DisposedAt: time.Now(), RequestedBy: "rina"})
+	assert.ErrorContains(s.T(), err, "needs a buyer or recipient")
+
+	_, err = s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u1"}, Method: model.DisposalDonation, DisposedAt: time.Now(), Proceeds: 10, Counterparty: "Yayasan", RequestedBy: "rina"})
+	assert.ErrorContains(s.T(), err, "donation can't have proceeds")
+
+	_, err = s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u1"}, Method: model.DisposalScrap, DisposedAt: time.Now().AddDate(0, 0, 2), RequestedBy: "rina"})
+	assert.ErrorContains(s.T(), err, "can't be in the future")
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestRequestDisposal_AssignedUnit() {
+	s.mockAssetRepo.On("GetUnit", "u3").Return(model.AssetDetail{Id: "u3", AssetId: "a1", Status: model.StatusAssigned}, nil)
+
+	_, err := s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u3"}, Method: model.DisposalScrap, DisposedAt: time.Now(), RequestedBy: "rina"})
+	assert.ErrorContains(s.T(), err, "asset unit u3 can't be disposed")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestRequestDisposal_ForeignUnit() {
+	s.mockAssetRepo.On("GetUnit", "u4").Return(model.AssetDetail{Id: "u4", AssetId: "a2", Status: model.StatusInStorage}, nil)
+	s.mockAssetRepo.On("GetUnit", "u9").Return(model.AssetDetail{}, errors.New("sql: no rows in result set"))
+
+	_, err := s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u4"}, Method: model.DisposalScrap, DisposedAt: time.Now(), RequestedBy: "rina"})
+	assert.ErrorContains(s.T(), err, "doesn't belong to asset a1")
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+
+	_, err = s.usecase.RequestDisposal(model.AssetDisposal{AssetId: "a1", AssetDetailIds: []string{"u9"}, Method: model.DisposalScrap, DisposedAt: time.Now(), RequestedBy: "rina"})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestApproveDisposal_GainLoss() {
+	s.mockRepo.On("Get", "d1").Return(model.AssetDisposal{Id: "d1", AssetId: "a1", AssetDetailIds: []string{"u1", "u2"}, Method: model.DisposalSale, DisposedAt: daysFromToday(0), Proceeds: 1500, Status: model.DisposalPending, RequestedBy: "rina"}, nil)
+	s.mockRepo.On("Approve", mock.Anything, mock.Anything).Return(nil)
+
+	// without useful life the asset isn't depreciated, two units are booked at 2000
+	disposal, err := s.usecase.ApproveDisposal("d1", dto.DisposalDecisionDTO{Actor: "budi"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.DisposalApproved, disposal.Status)
+	assert.Equal(s.T(), 2000.0, *disposal.BookValue)
+	assert.Equal(s.T(), -500.0, *disposal.GainLoss)
+	assert.Equal(s.T(), "budi", disposal.DecidedBy)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestApproveDisposal_SelfApproval() {
+	s.mockRepo.On("Get", "d1").Return(model.AssetDisposal{Id: "d1", AssetId: "a1", AssetDetailIds: []string{"u1"}, Status: model.DisposalPending, RequestedBy: "rina"}, nil)
+
+	_, err := s.usecase.ApproveDisposal("d1", dto.DisposalDecisionDTO{Actor: "Rina"})
+	assert.ErrorContains(s.T(), err, "can't be decided by the one who requested it")
+	assert.ErrorIs(s.T(), err, usecase.ErrForbidden)
+	s.mockRepo.AssertNotCalled(s.T(), "Approve", mock.Anything, mock.Anything)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestRejectDisposal_AlreadyDecided() {
+	s.mockRepo.On("Get", "d1").Return(model.AssetDisposal{Id: "d1", Status: model.DisposalApproved, RequestedBy: "rina"}, nil)
+
+	_, err := s.usecase.RejectDisposal("d1", dto.DisposalDecisionDTO{Actor: "budi"})
+	assert.ErrorContains(s.T(), err, "already approved")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *AssetDisposalUsecaseTestSuite) TestApproveDisposal_DecidedMeanwhile() {
+	s.mockRepo.On("Get", "d1").Return(model.AssetDisposal{Id: "d1", AssetId: "a1", AssetDetailIds: []string{"u1"}, Method: model.DisposalScrap, DisposedAt: daysFromToday(0), Status: model.DisposalPending, RequestedBy: "rina"}, nil)
+	s.mockRepo.On("Approve", mock.Anything, mock.Anything).Return(fmt.Errorf("disposal d1 %w, it is already rejected", repository.ErrChanged))
+
+	_, err := s.usecase.ApproveDisposal("d1", dto.DisposalDecisionDTO{Actor: "budi"})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func TestAssetDisposalUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetDisposalUsecaseTestSuite))
+}
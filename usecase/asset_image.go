@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/utils/common"
+	"asetku-bukan-asetmu/utils/imaging"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MaxAssetImageSize is the largest asset image accepted, in bytes.
+const MaxAssetImageSize = 5 << 20
+
+// AssetImageUrlPrefix is where uploaded images are served, ImageUrl values
+// with this prefix point to files in the configured storage.
+const AssetImageUrlPrefix = "/api/v1/asset/image/"
+
+const assetImageDir = "assets"
+
+// allowed image types, sniffed from the content
+var assetImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// UploadAssetImage stores a new image for the asset and points ImageUrl to
+// it. The type is sniffed from the content rather than trusted from the
+// client. The image is re-encoded so EXIF data such as GPS coordinates is
+// not kept, and a thumbnail is stored next to it for each of
+// imaging.ThumbnailSizes. The previous uploaded image is removed once the
+// asset is saved.
+func (a *assetUsecase) UploadAssetImage(id string, image dto.FileUploadDTO) (string, error) {
+	if a.fileStorage == nil {
+		return "", fmt.Errorf("file storage is not configured")
+	}
+
+	asset, err := a.repo.Detail(id)
+	if err != nil {
+		return "", newError(ErrNotFound, "asset with id %s is not found", id)
+	}
+
+	if image.Size > MaxAssetImageSize {
+		return "", newError(ErrTooLarge, "image can't be larger than %d MB", MaxAssetImageSize>>20)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(image.Content, MaxAssetImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image : %s", err.Error())
+	}
+	if len(content) > MaxAssetImageSize {
+		return "", newError(ErrTooLarge, "image can't be larger than %d MB", MaxAssetImageSize>>20)
+	}
+
+	contentType := http.DetectContentType(content)
+	if !assetImageTypes[contentType] {
+		return "", newError(ErrUnsupported, "file type %s is not allowed, use jpeg, png, gif or webp", contentType)
+	}
+
+	img, format, err := imaging.Decode(content)
+	if err != nil {
+		return "", newError(ErrInvalid, "%s", err.Error())
+	}
+
+	name := asset.Id + "-" + common.GenerateUUID() + format.Extension()
+	if err := a.storeImage(name, img, format); err != nil {
+		return "", err
+	}
+
+	for _, size := range imaging.ThumbnailSizes {
+		err := a.storeImage(thumbnailName(name, size), imaging.Thumbnail(img, size), format)
+		if err != nil {
+			a.removeImageFile(AssetImageUrlPrefix + name)
+			return "", err
+		}
+	}
+
+	previousImage := asset.ImageUrl
+	asset.ImageUrl = AssetImageUrlPrefix + name
+	asset.ImageThumbnails = true
+	if err := a.repo.Update(asset); err != nil {
+		a.removeImageFile(asset.ImageUrl)
+		return "", fmt.Errorf("failed to update asset : %s", err.Error())
+	}
+
+	a.removeImageFile(previousImage)
+	return asset.ImageUrl, nil
+}
+
+// RemoveAssetImage clears ImageUrl and deletes the uploaded file, if any.
+func (a *assetUsecase) RemoveAssetImage(id string) error {
+	asset, err := a.repo.Detail(id)
+	if err != nil {
+		return newError(ErrNotFound, "asset with id %s is not found", id)
+	}
+
+	previousImage := asset.ImageUrl
+	asset.ImageUrl = ""
+	asset.ImageThumbnails = false
+	if err := a.repo.Update(asset); err != nil {
+		return fmt.Errorf("failed to update asset : %s", err.Error())
+	}
+
+	a.removeImageFile(previousImage)
+	return nil
+}
+
+// AssetImageURL gives a time-limited link to a served image name. Names are
+// plain file names, anything that could leave the image directory is
+// rejected.
+func (a *assetUsecase) AssetImageURL(name string) (string, error) {
+	if a.fileStorage == nil || name == "" || strings.ContainsAny(name, "/\\") || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("image %s is not found", name)
+	}
+
+	link, err := a.fileStorage.SignedURL(path.Join(assetImageDir, name), a.urlExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign image url : %s", err.Error())
+	}
+
+	return link, nil
+}
+
+// AssetThumbnailUrls lists the thumbnails of an image uploaded through the
+// API once they were stored, external image URLs have none.
+func AssetThumbnailUrls(imageUrl string, stored bool) []dto.AssetThumbnailDTO {
+	name, ok := uploadedImageName(imageUrl)
+	if !ok || !stored {
+		return nil
+	}
+
+	thumbnails := make([]dto.AssetThumbnailDTO, 0, len(imaging.ThumbnailSizes))
+	for _, size := range imaging.ThumbnailSizes {
+		thumbnails = append(thumbnails, dto.AssetThumbnailDTO{
+			Size: size,
+			Url:  AssetImageUrlPrefix + thumbnailName(name, size),
+		})
+	}
+	return thumbnails
+}
+
+func (a *assetUsecase) storeImage(name string, img image.Image, format imaging.Format) error {
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format); err != nil {
+		return fmt.Errorf("failed to encode image : %s", err.Error())
+	}
+
+	err := a.fileStorage.Put(path.Join(assetImageDir, name), &buf, int64(buf.Len()), format.ContentType())
+	if err != nil {
+		return fmt.Errorf("failed to store image : %s", err.Error())
+	}
+	return nil
+}
+
+// thumbnailName derives the file name of a thumbnail, photo.jpg at size 128
+// becomes photo_128.jpg.
+func thumbnailName(name string, size int) string {
+	extension := path.Ext(name)
+	return strings.TrimSuffix(name, extension) + "_" + strconv.Itoa(size) + extension
+}
+
+// uploadedImageName returns the stored file name of an image uploaded
+// through the API.
+func uploadedImageName(imageUrl string) (string, bool) {
+	if !strings.HasPrefix(imageUrl, AssetImageUrlPrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(imageUrl, AssetImageUrlPrefix)
+	if name == "" || strings.ContainsAny(name, "/\\") {
+		return "", false
+	}
+	return name, true
+}
+
+// removeImageFile deletes a file previously uploaded through the API along
+// with its thumbnails. External URLs are left alone and a failed cleanup
+// doesn't fail the request.
+func (a *assetUsecase) removeImageFile(imageUrl string) {
+	name, ok := uploadedImageName(imageUrl)
+	if a.fileStorage == nil || !ok {
+		return
+	}
+
+	a.fileStorage.Delete(path.Join(assetImageDir, name))
+	for _, size := range imaging.ThumbnailSizes {
+		a.fileStorage.Delete(path.Join(assetImageDir, thumbnailName(name, size)))
+	}
+}
@@ -0,0 +1,173 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"asetku-bukan-asetmu/utils/storage"
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAssetRepository only implements what these tests need, any other
+// call panics on the nil embedded interface.
+type mockAssetRepository struct {
+	mock.Mock
+	repository.AssetRepository
+}
+
+func (r *mockAssetRepository) Detail(id string) (model.Asset, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.Asset), args.Error(1)
+}
+
+func (r *mockAssetRepository) Update(bodyRequest model.Asset) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+type AssetImageTestSuite struct {
+	suite.Suite
+	mockRepo *mockAssetRepository
+	filePath string
+	usecase  usecase.AssetUsecase
+}
+
+func (s *AssetImageTestSuite) SetupTest() {
+	s.mockRepo = new(mockAssetRepository)
+	s.filePath = s.T().TempDir()
+	fileStorage, err := storage.NewLocal(s.filePath, "secret")
+	assert.NoError(s.T(), err)
+	s.usecase = usecase.NewAssetUsecase(s.mockRepo, nil, nil, fileStorage, time.Minute)
+}
+
+func pngImage() []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2)))
+	return buf.Bytes()
+}
+
+func (s *AssetImageTestSuite) TestUploadAssetImage_ReplacesOldFile() {
+	oldFile := filepath.Join(s.filePath, "assets", "a1-old.png")
+	assert.NoError(s.T(), os.MkdirAll(filepath.Dir(oldFile), 0755))
+	assert.NoError(s.T(), os.WriteFile(oldFile, pngImage(), 0644))
+
+	asset := model.Asset{Id: "a1", Name: "Laptop", ImageUrl: usecase.AssetImageUrlPrefix + "a1-old.png"}
+	s.mockRepo.On("Detail", "a1").Return(asset, nil)
+	s.mockRepo.On("Update", mock.MatchedBy(func(updated model.Asset) bool {
+		return strings.HasPrefix(updated.ImageUrl, usecase.AssetImageUrlPrefix+"a1-") && strings.HasSuffix(updated.ImageUrl, ".png") && updated.ImageThumbnails
+	})).Return(nil)
+
+	content := pngImage()
+	imageUrl, err := s.usecase.UploadAssetImage("a1", dto.FileUploadDTO{FileName: "photo.jpg", Size: int64(len(content)), Content: bytes.NewReader(content)})
+	assert.NoError(s.T(), err)
+
+	name := strings.TrimPrefix(imageUrl, usecase.AssetImageUrlPrefix)
+	stored, _ := os.ReadFile(filepath.Join(s.filePath, "assets", name))
+	assert.Equal(s.T(), content, stored)
+
+	link, err := s.usecase.AssetImageURL(name)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), strings.HasPrefix(link, storage.LocalURLPrefix+"assets/"+name+"?"))
+
+	_, err = os.Stat(oldFile)
+	assert.True(s.T(), os.IsNotExist(err))
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+// jpegWithGPS is a 600x300 photo carrying an EXIF segment with a GPS tag.
+func jpegWithGPS() []byte {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 600, 300)), nil)
+	content := buf.Bytes()
+
+	payload := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x88\x25\x00\x04\x00\x00\x00\x01\x00\x00\x00\x1a\x00\x00\x00\x00GPS")
+	segment := []byte{0xFF, 0xE1, 0, byte(len(payload) + 2)}
+	return append(append(append([]byte{}, content[:2]...), append(segment, payload...)...), content[2:]...)
+}
+
+func (s *AssetImageTestSuite) TestUploadAssetImage_StripsExifAndCreatesThumbnails() {
+	s.mockRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockRepo.On("Update", mock.Anything).Return(nil)
+
+	content := jpegWithGPS()
+	imageUrl, err := s.usecase.UploadAssetImage("a1", dto.FileUploadDTO{FileName: "photo.jpg", Size: int64(len(content)), Content: bytes.NewReader(content)})
+	assert.NoError(s.T(), err)
+
+	name := strings.TrimPrefix(imageUrl, usecase.AssetImageUrlPrefix)
+	stored, err := os.ReadFile(filepath.Join(s.filePath, "assets", name))
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), bytes.Contains(stored, []byte("Exif")))
+	assert.False(s.T(), bytes.Contains(stored, []byte("GPS")))
+
+	thumbnails := usecase.AssetThumbnailUrls(imageUrl, true)
+	assert.Len(s.T(), thumbnails, 3)
+	for _, thumbnail := range thumbnails {
+		file, err := os.Open(filepath.Join(s.filePath, "assets", strings.TrimPrefix(thumbnail.Url, usecase.AssetImageUrlPrefix)))
+		assert.NoError(s.T(), err)
+		config, err := jpeg.DecodeConfig(file)
+		file.Close()
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), thumbnail.Size, config.Width)
+		assert.Equal(s.T(), thumbnail.Size/2, config.Height)
+	}
+
+	s.mockRepo.On("Detail", "a1").Unset()
+	s.mockRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", ImageUrl: imageUrl}, nil)
+	assert.NoError(s.T(), s.usecase.RemoveAssetImage("a1"))
+
+	entries, _ := os.ReadDir(filepath.Join(s.filePath, "assets"))
+	assert.Empty(s.T(), entries)
+}
+
+func (s *AssetImageTestSuite) TestAssetThumbnailUrls_ExternalImage() {
+	assert.Nil(s.T(), usecase.AssetThumbnailUrls("https://example.com/photo.jpg", true))
+	assert.Nil(s.T(), usecase.AssetThumbnailUrls("", false))
+}
+
+func (s *AssetImageTestSuite) TestAssetThumbnailUrls_NotStored() {
+	assert.Nil(s.T(), usecase.AssetThumbnailUrls(usecase.AssetImageUrlPrefix+"a1-old.png", false))
+}
+
+func (s *AssetImageTestSuite) TestUploadAssetImage_RejectsType() {
+	s.mockRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+
+	content := []byte("#!/bin/sh\necho not an image\n")
+	_, err := s.usecase.UploadAssetImage("a1", dto.FileUploadDTO{FileName: "photo.png", Size: int64(len(content)), Content: bytes.NewReader(content)})
+	assert.ErrorIs(s.T(), err, usecase.ErrUnsupported)
+	assert.ErrorContains(s.T(), err, "is not allowed")
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetImageTestSuite) TestUploadAssetImage_RejectsSize() {
+	s.mockRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+
+	content := append(pngImage(), make([]byte, usecase.MaxAssetImageSize)...)
+	_, err := s.usecase.UploadAssetImage("a1", dto.FileUploadDTO{FileName: "photo.png", Size: 0, Content: bytes.NewReader(content)})
+	assert.ErrorIs(s.T(), err, usecase.ErrTooLarge)
+	assert.ErrorContains(s.T(), err, "larger than")
+}
+
+func (s *AssetImageTestSuite) TestAssetImageURL_RejectsTraversal() {
+	_, err := s.usecase.AssetImageURL("../secret.png")
+	assert.Error(s.T(), err)
+
+	_, err = s.usecase.AssetImageURL("..")
+	assert.Error(s.T(), err)
+}
+
+func TestAssetImageTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetImageTestSuite))
+}
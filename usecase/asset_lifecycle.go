@@ -0,0 +1,66 @@
+package usecase
+
+import "asetku-bukan-asetmu/model"
+
+// assetStatusTransitions lists, for every lifecycle state, the states a unit
+// may legally move to next. Staying in in-storage or placed is allowed so a
+// unit can be relocated without changing its state. Disposed is terminal.
+var assetStatusTransitions = map[model.AssetStatus][]model.AssetStatus{
+	model.StatusInStorage: {
+		model.StatusInStorage,
+		model.StatusPlaced,
+		model.StatusAssigned,
+		model.StatusInMaintenance,
+		model.StatusInTransit,
+		model.StatusLost,
+		model.StatusDisposed,
+	},
+	model.StatusPlaced: {
+		model.StatusInStorage,
+		model.StatusPlaced,
+		model.StatusAssigned,
+		model.StatusInMaintenance,
+		model.StatusInTransit,
+		model.StatusLost,
+		model.StatusDisposed,
+	},
+	model.StatusAssigned: {
+		model.StatusInStorage,
+		model.StatusPlaced,
+		model.StatusInMaintenance,
+		model.StatusLost,
+	},
+	model.StatusInMaintenance: {
+		model.StatusInStorage,
+		model.StatusPlaced,
+		model.StatusDisposed,
+	},
+	model.StatusInTransit: {
+		model.StatusInStorage,
+		model.StatusPlaced,
+		model.StatusLost,
+	},
+	model.StatusLost: {
+		model.StatusInStorage,
+		model.StatusDisposed,
+	},
+	model.StatusDisposed: {},
+}
+
+func ValidateStatusTransition(from, to model.AssetStatus) error {
+	if !from.IsValid() {
+		return newError(ErrInvalid, "unknown current status %d", int(from))
+	}
+
+	if !to.IsValid() {
+		return newError(ErrInvalid, "unknown target status %d", int(to))
+	}
+
+	for _, allowed := range assetStatusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return newError(ErrConflict, "illegal status transition from %s to %s", from, to)
+}
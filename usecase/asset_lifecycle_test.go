@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AssetLifecycleTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AssetLifecycleTestSuite) TestValidateStatusTransition_Success() {
+	assert.NoError(suite.T(), usecase.ValidateStatusTransition(model.StatusInStorage, model.StatusPlaced))
+	assert.NoError(suite.T(), usecase.ValidateStatusTransition(model.StatusPlaced, model.StatusInStorage))
+	assert.NoError(suite.T(), usecase.ValidateStatusTransition(model.StatusAssigned, model.StatusInStorage))
+	assert.NoError(suite.T(), usecase.ValidateStatusTransition(model.StatusLost, model.StatusDisposed))
+}
+
+func (suite *AssetLifecycleTestSuite) TestValidateStatusTransition_Illegal() {
+	err := usecase.ValidateStatusTransition(model.StatusDisposed, model.StatusInStorage)
+	assert.ErrorIs(suite.T(), err, usecase.ErrConflict)
+	assert.Equal(suite.T(), "illegal status transition from disposed to in-storage", err.Error())
+
+	err = usecase.ValidateStatusTransition(model.StatusInTransit, model.StatusAssigned)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *AssetLifecycleTestSuite) TestValidateStatusTransition_Unknown() {
+	err := usecase.ValidateStatusTransition(model.AssetStatus(99), model.StatusPlaced)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalid)
+	assert.Equal(suite.T(), "unknown current status 99", err.Error())
+
+	err = usecase.ValidateStatusTransition(model.StatusPlaced, model.AssetStatus(0))
+	assert.Equal(suite.T(), "unknown target status 0", err.Error())
+}
+
+func (suite *AssetLifecycleTestSuite) TestParseAssetStatus() {
+	status, err := model.ParseAssetStatus("in-maintenance")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), model.StatusInMaintenance, status)
+
+	_, err = model.ParseAssetStatus("broken")
+	assert.Error(suite.T(), err)
+}
+
+func TestAssetLifecycleTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetLifecycleTestSuite))
+}
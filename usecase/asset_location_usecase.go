@@ -2,25 +2,30 @@ package usecase
 
 import (
 	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
 	"asetku-bukan-asetmu/repository"
+	"errors"
 	"fmt"
+	"strings"
 )
 
 type AssetLocationUsecase interface {
 	RegisterNewLocation(bodyRequest model.AssetLocation) error
 	SearchLocationById(id string) (model.AssetLocation, error)
 	ShowAllLocation() ([]model.AssetLocation, error)
+	ShowLocationTree(id string) ([]dto.LocationTreeDTO, error)
 	EditExistedLocation(bodyRequest model.AssetLocation) error
 	DeleteSelectedLocation(id string) error
 }
 
 type assetLocationUsecase struct {
-	repo repository.AssetLocationRepo
+	repo            repository.AssetLocationRepo
+	employeeUsecase EmployeeUseCase
 }
 
 func (loc *assetLocationUsecase) RegisterNewLocation(bodyRequest model.AssetLocation) error {
-	if bodyRequest.Name == "" {
-		return fmt.Errorf("location name is required")
+	if err := loc.validateLocation(&bodyRequest); err != nil {
+		return err
 	}
 
 	err := loc.repo.Create(bodyRequest)
@@ -32,21 +37,67 @@ func (loc *assetLocationUsecase) RegisterNewLocation(bodyRequest model.AssetLoca
 }
 
 func (loc *assetLocationUsecase) SearchLocationById(id string) (model.AssetLocation, error) {
-	return loc.repo.Get(id)
+	location, err := loc.repo.Get(id)
+	if err != nil {
+		return model.AssetLocation{}, err
+	}
+
+	if location.Id == "" {
+		return model.AssetLocation{}, newError(ErrNotFound, "location with id %s is not found", id)
+	}
+
+	return location, nil
 }
 
 func (loc *assetLocationUsecase) ShowAllLocation() ([]model.AssetLocation, error) {
 	return loc.repo.List()
 }
 
+// ShowLocationTree returns the subtree under a location, or every top level
+// location when id is empty. Units counts the units placed directly in a
+// location and TotalUnits adds those of everything below it.
+func (loc *assetLocationUsecase) ShowLocationTree(id string) ([]dto.LocationTreeDTO, error) {
+	if id != "" {
+		if _, err := loc.SearchLocationById(id); err != nil {
+			return nil, err
+		}
+	}
+
+	locations, err := loc.repo.Subtree(id)
+	if err != nil {
+		return nil, fmt.Errorf("error get location tree : %s", err.Error())
+	}
+
+	children := make(map[string][]model.LocationUnitCount)
+	var roots []model.LocationUnitCount
+	for _, location := range locations {
+		if location.ParentId == nil || location.Id == id {
+			roots = append(roots, location)
+			continue
+		}
+		children[*location.ParentId] = append(children[*location.ParentId], location)
+	}
+
+	tree := make([]dto.LocationTreeDTO, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, locationNode(root, children))
+	}
+
+	return tree, nil
+}
+
 func (loc *assetLocationUsecase) EditExistedLocation(bodyRequest model.AssetLocation) error {
 	_, err := loc.SearchLocationById(bodyRequest.Id)
 	if err != nil {
 		return fmt.Errorf("can't find location id")
 	}
 
-	if bodyRequest.Name == "" {
-		return fmt.Errorf("location name is required")
+	if err := loc.validateLocation(&bodyRequest); err != nil {
+		return err
+	}
+
+	if err := loc.validateSubtree(bodyRequest); err != nil {
+		return err
 	}
 
 	err = loc.repo.Update(bodyRequest)
@@ -57,13 +108,35 @@ func (loc *assetLocationUsecase) EditExistedLocation(bodyRequest model.AssetLoca
 	return nil
 }
 
+// DeleteSelectedLocation only deletes empty leaf locations, child locations
+// and units have to be moved out first.
 func (loc *assetLocationUsecase) DeleteSelectedLocation(id string) error {
 	_, err := loc.SearchLocationById(id)
 	if err != nil {
-		return fmt.Errorf("can't find location id")
+		return newError(ErrNotFound, "can't find location id")
+	}
+
+	children, err := loc.repo.CountChildren(id)
+	if err != nil {
+		return fmt.Errorf("failed to delete location : %s", err.Error())
+	}
+	if children > 0 {
+		return newError(ErrConflict, "location %s still has %d child locations", id, children)
 	}
 
+	units, err := loc.repo.CountUnits(id)
+	if err != nil {
+		return fmt.Errorf("failed to delete location : %s", err.Error())
+	}
+	if units > 0 {
+		return newError(ErrConflict, "location %s still has %d asset units", id, units)
+	}
+
+	// Transfers, movements and the like keep pointing at the location
 	err = loc.repo.Delete(id)
+	if errors.Is(err, repository.ErrInUse) {
+		return newError(ErrConflict, "location %s can't be deleted, it is %s", id, err.Error())
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete location : %s", err.Error())
 	}
@@ -71,8 +144,100 @@ func (loc *assetLocationUsecase) DeleteSelectedLocation(id string) error {
 	return nil
 }
 
-func NewAssetLocationUsecase(repository repository.AssetLocationRepo) AssetLocationUsecase {
+func (loc *assetLocationUsecase) validateLocation(bodyRequest *model.AssetLocation) error {
+	if bodyRequest.Name == "" {
+		return fmt.Errorf("location name is required")
+	}
+
+	// Same default as the column, a location without a type is a room
+	if bodyRequest.Type == "" {
+		bodyRequest.Type = model.LocationRoom
+	}
+	if !bodyRequest.Type.IsValid() {
+		return fmt.Errorf("location type must be site, building, floor or room")
+	}
+
+	bodyRequest.Code = strings.TrimSpace(bodyRequest.Code)
+	if bodyRequest.CustodianId != nil && *bodyRequest.CustodianId == "" {
+		bodyRequest.CustodianId = nil
+	}
+	if bodyRequest.CustodianId != nil {
+		if _, err := loc.employeeUsecase.FindEmployeeById(*bodyRequest.CustodianId); err != nil {
+			return fmt.Errorf("custodian with id %s is not found", *bodyRequest.CustodianId)
+		}
+	}
+
+	if bodyRequest.ParentId != nil && *bodyRequest.ParentId == "" {
+		bodyRequest.ParentId = nil
+	}
+	if bodyRequest.ParentId == nil {
+		return nil
+	}
+
+	if *bodyRequest.ParentId == bodyRequest.Id {
+		return fmt.Errorf("location can't be its own parent")
+	}
+
+	parent, err := loc.SearchLocationById(*bodyRequest.ParentId)
+	if err != nil {
+		return fmt.Errorf("parent location with id %s is not found", *bodyRequest.ParentId)
+	}
+
+	if !parent.Type.CanContain(bodyRequest.Type) {
+		return fmt.Errorf("a %s can't be inside a %s", bodyRequest.Type, parent.Type)
+	}
+
+	return nil
+}
+
+// validateSubtree keeps an edited location consistent with what is below
+// it, the new parent can't be one of its descendants and its children must
+// still fit under its new type.
+func (loc *assetLocationUsecase) validateSubtree(bodyRequest model.AssetLocation) error {
+	descendants, err := loc.repo.Subtree(bodyRequest.Id)
+	if err != nil {
+		return fmt.Errorf("error get location tree : %s", err.Error())
+	}
+
+	for _, descendant := range descendants {
+		if descendant.Id == bodyRequest.Id {
+			continue
+		}
+
+		if bodyRequest.ParentId != nil && descendant.Id == *bodyRequest.ParentId {
+			return fmt.Errorf("location can't be moved under its own descendant %s", descendant.Id)
+		}
+
+		if *descendant.ParentId == bodyRequest.Id && !bodyRequest.Type.CanContain(descendant.Type) {
+			return fmt.Errorf("a %s can't hold its child %s %s", bodyRequest.Type, descendant.Type, descendant.Id)
+		}
+	}
+
+	return nil
+}
+
+// locationNode builds the tree below a location and rolls the unit counts
+// up from its children.
+func locationNode(location model.LocationUnitCount, children map[string][]model.LocationUnitCount) dto.LocationTreeDTO {
+	node := dto.LocationTreeDTO{
+		AssetLocation: location.AssetLocation,
+		Units:         location.Units,
+		TotalUnits:    location.Units,
+		Children:      []dto.LocationTreeDTO{},
+	}
+
+	for _, child := range children[location.Id] {
+		childNode := locationNode(child, children)
+		node.TotalUnits += childNode.TotalUnits
+		node.Children = append(node.Children, childNode)
+	}
+
+	return node
+}
+
+func NewAssetLocationUsecase(repository repository.AssetLocationRepo, employeeUsecase EmployeeUseCase) AssetLocationUsecase {
 	return &assetLocationUsecase{
-		repo: repository,
+		repo:            repository,
+		employeeUsecase: employeeUsecase,
 	}
 }
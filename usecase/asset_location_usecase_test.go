@@ -0,0 +1,205 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAssetLocationRepo struct {
+	mock.Mock
+}
+
+func (r *mockAssetLocationRepo) Create(bodyRequest model.AssetLocation) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockAssetLocationRepo) List() ([]model.AssetLocation, error) {
+	args := r.Called()
+	return args.Get(0).([]model.AssetLocation), args.Error(1)
+}
+
+func (r *mockAssetLocationRepo) Get(id string) (model.AssetLocation, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetLocation), args.Error(1)
+}
+
+func (r *mockAssetLocationRepo) Update(bodyRequest model.AssetLocation) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockAssetLocationRepo) Delete(id string) error {
+	args := r.Called(id)
+	return args.Error(0)
+}
+
+func (r *mockAssetLocationRepo) Subtree(id string) ([]model.LocationUnitCount, error) {
+	args := r.Called(id)
+	return args.Get(0).([]model.LocationUnitCount), args.Error(1)
+}
+
+func (r *mockAssetLocationRepo) CountChildren(id string) (int, error) {
+	args := r.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *mockAssetLocationRepo) CountUnits(id string) (int, error) {
+	args := r.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+type AssetLocationUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo     *mockAssetLocationRepo
+	mockEmployee *mockEmployeeUsecase
+	usecase      usecase.AssetLocationUsecase
+}
+
+func (s *AssetLocationUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockAssetLocationRepo)
+	s.mockEmployee = new(mockEmployeeUsecase)
+	s.usecase = usecase.NewAssetLocationUsecase(s.mockRepo, s.mockEmployee)
+
+	s.mockRepo.On("Get", "site").Return(model.AssetLocation{Id: "site", Name: "HQ", Type: model.LocationSite}, nil)
+	s.mockRepo.On("Get", "floor").Return(model.AssetLocation{Id: "floor", Name: "Floor 1", ParentId: strPtr("site"), Type: model.LocationFloor}, nil)
+	s.mockRepo.On("Get", "missing").Return(model.AssetLocation{}, nil)
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestRegisterNewLocation_Success() {
+	s.mockRepo.On("Create", mock.MatchedBy(func(location model.AssetLocation) bool {
+		return *location.ParentId == "floor" && location.Code == "R-101"
+	})).Return(nil)
+
+	err := s.usecase.RegisterNewLocation(model.AssetLocation{Id: "room", Name: "Room 101", ParentId: strPtr("floor"), Type: model.LocationRoom, Code: " R-101 "})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestRegisterNewLocation_DefaultsToRoom() {
+	s.mockRepo.On("Create", mock.MatchedBy(func(location model.AssetLocation) bool {
+		return location.Type == model.LocationRoom
+	})).Return(nil)
+
+	err := s.usecase.RegisterNewLocation(model.AssetLocation{Id: "room", Name: "Room 102", ParentId: strPtr("floor")})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestRegisterNewLocation_WrongLevel() {
+	err := s.usecase.RegisterNewLocation(model.AssetLocation{Id: "b1", Name: "Tower", ParentId: strPtr("floor"), Type: model.LocationBuilding})
+	assert.ErrorContains(s.T(), err, "a building can't be inside a floor")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestRegisterNewLocation_ParentNotFound() {
+	err := s.usecase.RegisterNewLocation(model.AssetLocation{Id: "room", Name: "Room", ParentId: strPtr("missing"), Type: model.LocationRoom})
+	assert.ErrorContains(s.T(), err, "parent location with id missing is not found")
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestRegisterNewLocation_CustodianNotFound() {
+	s.mockEmployee.On("FindEmployeeById", "e9").Return(model.Employee{}, errors.New("sql: no rows in result set"))
+
+	err := s.usecase.RegisterNewLocation(model.AssetLocation{Id: "room", Name: "Room", ParentId: strPtr("floor"), Type: model.LocationRoom, CustodianId: strPtr("e9")})
+	assert.ErrorContains(s.T(), err, "custodian with id e9 is not found")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestEditExistedLocation_MoveUnderDescendant() {
+	s.mockRepo.On("Subtree", "site").Return([]model.LocationUnitCount{
+		{AssetLocation: model.AssetLocation{Id: "site", Type: model.LocationSite}},
+		{AssetLocation: model.AssetLocation{Id: "floor", ParentId: strPtr("site"), Type: model.LocationFloor}},
+	}, nil)
+
+	err := s.usecase.EditExistedLocation(model.AssetLocation{Id: "site", Name: "HQ", ParentId: strPtr("floor"), Type: model.LocationSite})
+	assert.Error(s.T(), err)
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestEditExistedLocation_ChildNoLongerFits() {
+	s.mockRepo.On("Subtree", "site").Return([]model.LocationUnitCount{
+		{AssetLocation: model.AssetLocation{Id: "site", Type: model.LocationSite}},
+		{AssetLocation: model.AssetLocation{Id: "floor", ParentId: strPtr("site"), Type: model.LocationFloor}},
+	}, nil)
+
+	err := s.usecase.EditExistedLocation(model.AssetLocation{Id: "site", Name: "HQ", Type: model.LocationRoom})
+	assert.ErrorContains(s.T(), err, "a room can't hold its child floor floor")
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestDeleteSelectedLocation_HasChildren() {
+	s.mockRepo.On("CountChildren", "site").Return(1, nil)
+
+	err := s.usecase.DeleteSelectedLocation("site")
+	assert.ErrorContains(s.T(), err, "location site still has 1 child locations")
+	s.mockRepo.AssertNotCalled(s.T(), "Delete", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestDeleteSelectedLocation_HasUnits() {
+	s.mockRepo.On("CountChildren", "floor").Return(0, nil)
+	s.mockRepo.On("CountUnits", "floor").Return(2, nil)
+
+	err := s.usecase.DeleteSelectedLocation("floor")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "location floor still has 2 asset units")
+	s.mockRepo.AssertNotCalled(s.T(), "Delete", mock.Anything)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestDeleteSelectedLocation_UsedByTransfer() {
+	s.mockRepo.On("CountChildren", "floor").Return(0, nil)
+	s.mockRepo.On("CountUnits", "floor").Return(0, nil)
+	s.mockRepo.On("Delete", "floor").Return(fmt.Errorf("%w by asset_transfers", repository.ErrInUse))
+
+	err := s.usecase.DeleteSelectedLocation("floor")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.EqualError(s.T(), err, "location floor can't be deleted, it is still in use by asset_transfers")
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestDeleteSelectedLocation_Success() {
+	s.mockRepo.On("CountChildren", "floor").Return(0, nil)
+	s.mockRepo.On("CountUnits", "floor").Return(0, nil)
+	s.mockRepo.On("Delete", "floor").Return(nil)
+
+	err := s.usecase.DeleteSelectedLocation("floor")
+	assert.NoError(s.T(), err)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestShowLocationTree_RollsUpUnits() {
+	s.mockRepo.On("Subtree", "site").Return([]model.LocationUnitCount{
+		{AssetLocation: model.AssetLocation{Id: "site", Type: model.LocationSite}, Units: 1},
+		{AssetLocation: model.AssetLocation{Id: "floor", ParentId: strPtr("site"), Type: model.LocationFloor}},
+		{AssetLocation: model.AssetLocation{Id: "r1", ParentId: strPtr("floor"), Type: model.LocationRoom}, Units: 2},
+		{AssetLocation: model.AssetLocation{Id: "r2", ParentId: strPtr("floor"), Type: model.LocationRoom}, Units: 3},
+	}, nil)
+
+	tree, err := s.usecase.ShowLocationTree("site")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), tree, 1)
+	assert.Equal(s.T(), 1, tree[0].Units)
+	assert.Equal(s.T(), 6, tree[0].TotalUnits)
+	assert.Equal(s.T(), 5, tree[0].Children[0].TotalUnits)
+	assert.Len(s.T(), tree[0].Children[0].Children, 2)
+}
+
+func (s *AssetLocationUsecaseTestSuite) TestShowLocationTree_NotFound() {
+	_, err := s.usecase.ShowLocationTree("missing")
+	assert.ErrorContains(s.T(), err, "location with id missing is not found")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "Subtree", mock.Anything)
+}
+
+func TestAssetLocationUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetLocationUsecaseTestSuite))
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"fmt"
+)
+
+type AssetMovementUsecase interface {
+	ShowAssetHistory(assetId, unitId string) ([]dto.AssetMovementDTO, error)
+	ShowLocationHistory(locationId string) ([]dto.AssetMovementDTO, error)
+}
+
+type assetMovementUsecase struct {
+	repo       repository.AssetMovementRepository
+	locUsecase AssetLocationUsecase
+}
+
+func (a *assetMovementUsecase) ShowAssetHistory(assetId, unitId string) ([]dto.AssetMovementDTO, error) {
+	var (
+		movements []model.AssetMovement
+		err       error
+	)
+
+	if unitId != "" {
+		movements, err = a.repo.ListByUnit(unitId)
+	} else {
+		movements, err = a.repo.ListByAsset(assetId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get asset history : %s", err.Error())
+	}
+
+	responses := make([]dto.AssetMovementDTO, 0, len(movements))
+	for _, movement := range movements {
+		if movement.AssetId != assetId {
+			return nil, newError(ErrNotFound, "asset unit %s doesn't belong to asset %s", unitId, assetId)
+		}
+
+		responses = append(responses, toMovementResponse(movement))
+	}
+
+	return responses, nil
+}
+
+func (a *assetMovementUsecase) ShowLocationHistory(locationId string) ([]dto.AssetMovementDTO, error) {
+	if _, err := a.locUsecase.SearchLocationById(locationId); err != nil {
+		return nil, newError(ErrNotFound, "location with id %s is not found", locationId)
+	}
+
+	movements, err := a.repo.ListByLocation(locationId)
+	if err != nil {
+		return nil, fmt.Errorf("error get location history : %s", err.Error())
+	}
+
+	responses := make([]dto.AssetMovementDTO, 0, len(movements))
+	for _, movement := range movements {
+		responses = append(responses, toMovementResponse(movement))
+	}
+
+	return responses, nil
+}
+
+func toMovementResponse(movement model.AssetMovement) dto.AssetMovementDTO {
+	return dto.AssetMovementDTO{
+		Id:             movement.Id,
+		AssetDetailId:  movement.AssetDetailId,
+		AssetId:        movement.AssetId,
+		FromLocationId: movement.FromLocationId,
+		ToLocationId:   movement.ToLocationId,
+		FromStatus:     movement.FromStatus.String(),
+		ToStatus:       movement.ToStatus.String(),
+		BatchId:        movement.BatchId,
+		Actor:          movement.Actor,
+		MovedAt:        movement.MovedAt,
+	}
+}
+
+func NewAssetMovementUsecase(repo repository.AssetMovementRepository, locationUsecase AssetLocationUsecase) AssetMovementUsecase {
+	return &assetMovementUsecase{
+		repo:       repo,
+		locUsecase: locationUsecase,
+	}
+}
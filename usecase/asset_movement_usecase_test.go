@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockMovementRepository struct {
+	mock.Mock
+}
+
+func (r *mockMovementRepository) ListByAsset(assetId string) ([]model.AssetMovement, error) {
+	args := r.Called(assetId)
+	return args.Get(0).([]model.AssetMovement), args.Error(1)
+}
+
+func (r *mockMovementRepository) ListByUnit(assetDetailId string) ([]model.AssetMovement, error) {
+	args := r.Called(assetDetailId)
+	return args.Get(0).([]model.AssetMovement), args.Error(1)
+}
+
+func (r *mockMovementRepository) ListByLocation(locationId string) ([]model.AssetMovement, error) {
+	args := r.Called(locationId)
+	return args.Get(0).([]model.AssetMovement), args.Error(1)
+}
+
+type AssetMovementUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo     *mockMovementRepository
+	mockLocation *mockLocationUsecase
+	usecase      usecase.AssetMovementUsecase
+}
+
+func (s *AssetMovementUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockMovementRepository)
+	s.mockLocation = new(mockLocationUsecase)
+	s.usecase = usecase.NewAssetMovementUsecase(s.mockRepo, s.mockLocation)
+
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1", Name: "Office"}, nil)
+	s.mockLocation.On("SearchLocationById", "l9").Return(model.AssetLocation{}, sql.ErrNoRows)
+}
+
+func (s *AssetMovementUsecaseTestSuite) TestShowAssetHistory_ByAsset() {
+	s.mockRepo.On("ListByAsset", "a1").Return([]model.AssetMovement{
+		{Id: "m1", AssetDetailId: "u1", AssetId: "a1", FromStatus: model.StatusInStorage, ToStatus: model.StatusPlaced},
+	}, nil)
+
+	history, err := s.usecase.ShowAssetHistory("a1", "")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), history, 1)
+	assert.Equal(s.T(), "in-storage", history[0].FromStatus)
+	assert.Equal(s.T(), "placed", history[0].ToStatus)
+	s.mockRepo.AssertNotCalled(s.T(), "ListByUnit", mock.Anything)
+}
+
+func (s *AssetMovementUsecaseTestSuite) TestShowAssetHistory_UnitOfOtherAsset() {
+	s.mockRepo.On("ListByUnit", "u2").Return([]model.AssetMovement{{Id: "m2", AssetDetailId: "u2", AssetId: "a2"}}, nil)
+
+	_, err := s.usecase.ShowAssetHistory("a1", "u2")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.EqualError(s.T(), err, "asset unit u2 doesn't belong to asset a1")
+}
+
+func (s *AssetMovementUsecaseTestSuite) TestShowAssetHistory_Fail() {
+	s.mockRepo.On("ListByAsset", "a1").Return([]model.AssetMovement(nil), errors.New("connection refused"))
+
+	_, err := s.usecase.ShowAssetHistory("a1", "")
+	assert.EqualError(s.T(), err, "error get asset history : connection refused")
+}
+
+func (s *AssetMovementUsecaseTestSuite) TestShowLocationHistory_Success() {
+	s.mockRepo.On("ListByLocation", "l1").Return([]model.AssetMovement{{Id: "m1", ToLocationId: "l1"}}, nil)
+
+	history, err := s.usecase.ShowLocationHistory("l1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "l1", history[0].ToLocationId)
+}
+
+func (s *AssetMovementUsecaseTestSuite) TestShowLocationHistory_UnknownLocation() {
+	_, err := s.usecase.ShowLocationHistory("l9")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "ListByLocation", mock.Anything)
+}
+
+func TestAssetMovementUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetMovementUsecaseTestSuite))
+}
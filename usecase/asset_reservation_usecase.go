@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type AssetReservationUsecase interface {
+	ReserveAsset(bodyRequest model.AssetReservation) (model.AssetReservation, error)
+	GetReservation(id string) (model.AssetReservation, error)
+	ShowAssetReservations(assetId string) ([]model.AssetReservation, error)
+	ShowEmployeeReservations(employeeId string) ([]model.AssetReservation, error)
+	CancelReservation(id string) error
+}
+
+type assetReservationUsecase struct {
+	repo        repository.AssetReservationRepository
+	assetRepo   repository.AssetRepository
+	emplUsecase EmployeeUseCase
+}
+
+// ReserveAsset books units of an asset for a time window. Whether enough
+// units are free is decided by the repository under the asset lock, this
+// only checks the request itself.
+func (a *assetReservationUsecase) ReserveAsset(bodyRequest model.AssetReservation) (model.AssetReservation, error) {
+	now := time.Now()
+	if !bodyRequest.EndAt.After(bodyRequest.StartAt) {
+		return model.AssetReservation{}, newError(ErrInvalid, "reservation must end after it starts")
+	}
+
+	if !bodyRequest.EndAt.After(now) {
+		return model.AssetReservation{}, newError(ErrInvalid, "reservation can't end in the past")
+	}
+
+	if _, err := a.emplUsecase.FindEmployeeById(bodyRequest.EmployeeId); err != nil {
+		return model.AssetReservation{}, newError(ErrNotFound, "employee with id %s is not found", bodyRequest.EmployeeId)
+	}
+
+	if _, err := a.assetRepo.Detail(bodyRequest.AssetId); err != nil {
+		return model.AssetReservation{}, newError(ErrNotFound, "asset with id %s is not found", bodyRequest.AssetId)
+	}
+
+	if len(bodyRequest.AssetDetailIds) > 0 {
+		if err := a.validateReservedUnits(bodyRequest); err != nil {
+			return model.AssetReservation{}, err
+		}
+		bodyRequest.Qty = len(bodyRequest.AssetDetailIds)
+	}
+
+	if bodyRequest.Qty <= 0 {
+		return model.AssetReservation{}, newError(ErrInvalid, "qty must be greater than zero")
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.ConsumedQty = 0
+	bodyRequest.Status = model.ReservationActive
+	bodyRequest.CreatedAt = now
+
+	err := a.repo.Create(bodyRequest)
+	if errors.Is(err, repository.ErrReserved) {
+		return model.AssetReservation{}, newError(ErrConflict, "failed to reserve asset : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetReservation{}, fmt.Errorf("failed to reserve asset : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (a *assetReservationUsecase) GetReservation(id string) (model.AssetReservation, error) {
+	reservation, err := a.repo.Get(id)
+	if err != nil {
+		return model.AssetReservation{}, newError(ErrNotFound, "reservation with id %s is not found", id)
+	}
+
+	return reservation, nil
+}
+
+// ShowAssetReservations lists the reservations of an asset that haven't
+// ended yet, the booking calendar of the asset.
+func (a *assetReservationUsecase) ShowAssetReservations(assetId string) ([]model.AssetReservation, error) {
+	reservations, err := a.repo.ListByAsset(assetId, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error get asset reservations : %s", err.Error())
+	}
+
+	return reservations, nil
+}
+
+func (a *assetReservationUsecase) ShowEmployeeReservations(employeeId string) ([]model.AssetReservation, error) {
+	if _, err := a.emplUsecase.FindEmployeeById(employeeId); err != nil {
+		return nil, newError(ErrNotFound, "employee with id %s is not found", employeeId)
+	}
+
+	reservations, err := a.repo.ListByEmployee(employeeId, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error get employee reservations : %s", err.Error())
+	}
+
+	return reservations, nil
+}
+
+func (a *assetReservationUsecase) CancelReservation(id string) error {
+	reservation, err := a.GetReservation(id)
+	if err != nil {
+		return err
+	}
+
+	if reservation.Status != model.ReservationActive {
+		return newError(ErrConflict, "reservation %s is already %s", id, reservation.Status)
+	}
+
+	err = a.repo.Cancel(id)
+	if errors.Is(err, repository.ErrChanged) {
+		return newError(ErrConflict, "failed to cancel reservation : %s", err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel reservation : %s", err.Error())
+	}
+
+	return nil
+}
+
+func (a *assetReservationUsecase) validateReservedUnits(bodyRequest model.AssetReservation) error {
+	if bodyRequest.Qty != 0 && bodyRequest.Qty != len(bodyRequest.AssetDetailIds) {
+		return newError(ErrInvalid, "qty doesn't match the number of selected asset units")
+	}
+
+	selected := make(map[string]bool, len(bodyRequest.AssetDetailIds))
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		if selected[unitId] {
+			return newError(ErrInvalid, "asset unit %s is selected more than once", unitId)
+		}
+		selected[unitId] = true
+
+		unit, err := a.assetRepo.GetUnit(unitId)
+		if err != nil {
+			return newError(ErrNotFound, "asset unit with id %s is not found", unitId)
+		}
+
+		if unit.AssetId != bodyRequest.AssetId {
+			return newError(ErrInvalid, "asset unit %s doesn't belong to asset %s", unitId, bodyRequest.AssetId)
+		}
+
+		if unit.RemovedAt != nil || unit.Status == model.StatusLost || unit.Status == model.StatusDisposed {
+			return newError(ErrConflict, "asset unit %s can't be reserved, it is %s", unitId, unit.Status)
+		}
+	}
+
+	return nil
+}
+
+func NewAssetReservationUsecase(repo repository.AssetReservationRepository, assetRepo repository.AssetRepository, employeeUsecase EmployeeUseCase) AssetReservationUsecase {
+	return &assetReservationUsecase{
+		repo:        repo,
+		assetRepo:   assetRepo,
+		emplUsecase: employeeUsecase,
+	}
+}
@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockReservationRepository struct {
+	mock.Mock
+}
+
+func (r *mockReservationRepository) Create(bodyRequest model.AssetReservation) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockReservationRepository) Get(id string) (model.AssetReservation, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetReservation), args.Error(1)
+}
+
+func (r *mockReservationRepository) ListByAsset(assetId string, from time.Time) ([]model.AssetReservation, error) {
+	args := r.Called(assetId, from)
+	return args.Get(0).([]model.AssetReservation), args.Error(1)
+}
+
+func (r *mockReservationRepository) ListByEmployee(employeeId string, from time.Time) ([]model.AssetReservation, error) {
+	args := r.Called(employeeId, from)
+	return args.Get(0).([]model.AssetReservation), args.Error(1)
+}
+
+func (r *mockReservationRepository) Cancel(id string) error {
+	args := r.Called(id)
+	return args.Error(0)
+}
+
+// mockEmployeeUsecase only answers employee lookups.
+type mockEmployeeUsecase struct {
+	mock.Mock
+	usecase.EmployeeUseCase
+}
+
+func (e *mockEmployeeUsecase) FindEmployeeById(id string) (model.Employee, error) {
+	args := e.Called(id)
+	return args.Get(0).(model.Employee), args.Error(1)
+}
+
+type AssetReservationUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockReservationRepository
+	mockAssetRepo *mockAssetRepository
+	mockEmployee  *mockEmployeeUsecase
+	usecase       usecase.AssetReservationUsecase
+}
+
+func (s *AssetReservationUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockReservationRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockEmployee = new(mockEmployeeUsecase)
+	s.usecase = usecase.NewAssetReservationUsecase(s.mockRepo, s.mockAssetRepo, s.mockEmployee)
+
+	s.mockEmployee.On("FindEmployeeById", "e1").Return(model.Employee{Id: "e1"}, nil)
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+}
+
+func tomorrow(hour int) time.Time {
+	now := time.Now().AddDate(0, 0, 1)
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.Local)
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_SelectedUnits() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusInStorage}, nil)
+	s.mockAssetRepo.On("GetUnit", "u2").Return(model.AssetDetail{Id: "u2", AssetId: "a1", Status: model.StatusAssigned}, nil)
+	s.mockRepo.On("Create", mock.MatchedBy(func(reservation model.AssetReservation) bool {
+		return reservation.Qty == 2 && reservation.Status == model.ReservationActive && reservation.Id != ""
+	})).Return(nil)
+
+	reservation, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", AssetDetailIds: []string{"u1", "u2"}, StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, reservation.Qty)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_InvalidWindow() {
+	_, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", Qty: 1, StartAt: tomorrow(12), EndAt: tomorrow(9)})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "must end after it starts")
+
+	_, err = s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", Qty: 1, StartAt: time.Now().Add(-2 * time.Hour), EndAt: time.Now().Add(-time.Hour)})
+	assert.ErrorContains(s.T(), err, "in the past")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_UnitOfOtherAsset() {
+	s.mockAssetRepo.On("GetUnit", "u9").Return(model.AssetDetail{Id: "u9", AssetId: "a2", Status: model.StatusInStorage}, nil)
+
+	_, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", AssetDetailIds: []string{"u9"}, StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.ErrorContains(s.T(), err, "doesn't belong to asset a1")
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_LostUnit() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusLost}, nil)
+
+	_, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", AssetDetailIds: []string{"u1"}, StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.ErrorContains(s.T(), err, "can't be reserved")
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_QtyRequired() {
+	_, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.ErrorContains(s.T(), err, "qty must be greater than zero")
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_Overlap() {
+	s.mockRepo.On("Create", mock.Anything).Return(fmt.Errorf("asset unit u1 is already %w from 09:00 to 12:00", repository.ErrReserved))
+
+	_, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e1", Qty: 1, StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "already reserved")
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestReserveAsset_NotFound() {
+	s.mockEmployee.On("FindEmployeeById", "e9").Return(model.Employee{}, sql.ErrNoRows)
+	s.mockAssetRepo.On("Detail", "a9").Return(model.Asset{}, sql.ErrNoRows)
+
+	_, err := s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a1", EmployeeId: "e9", Qty: 1, StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+
+	_, err = s.usecase.ReserveAsset(model.AssetReservation{AssetId: "a9", EmployeeId: "e1", Qty: 1, StartAt: tomorrow(9), EndAt: tomorrow(12)})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetReservationUsecaseTestSuite) TestCancelReservation_NotActive() {
+	s.mockRepo.On("Get", "r1").Return(model.AssetReservation{Id: "r1", Status: model.ReservationFulfilled}, nil)
+	s.mockRepo.On("Get", "r2").Return(model.AssetReservation{Id: "r2", Status: model.ReservationActive}, nil)
+	s.mockRepo.On("Cancel", "r2").Return(fmt.Errorf("reservation r2 %w, it is no longer active", repository.ErrChanged))
+
+	err := s.usecase.CancelReservation("r1")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.EqualError(s.T(), err, "reservation r1 is already fulfilled")
+	s.mockRepo.AssertNotCalled(s.T(), "Cancel", "r1")
+
+	err = s.usecase.CancelReservation("r2")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func TestAssetReservationUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetReservationUsecaseTestSuite))
+}
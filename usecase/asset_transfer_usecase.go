@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AssetTransferUsecase interface {
+	RequestTransfer(bodyRequest model.AssetTransfer) (model.AssetTransfer, error)
+	GetTransfer(id string) (model.AssetTransfer, error)
+	ShowTransfers(status string) ([]model.AssetTransfer, error)
+	ApproveTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error)
+	RejectTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error)
+	CancelTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error)
+	DispatchTransfer(id string, bodyRequest dto.TransferDispatchDTO) (model.AssetTransfer, error)
+	ReceiveTransfer(id string, bodyRequest dto.TransferReceiptDTO) (model.AssetTransfer, error)
+}
+
+type assetTransferUsecase struct {
+	repo       repository.AssetTransferRepository
+	assetRepo  repository.AssetRepository
+	locUsecase AssetLocationUsecase
+}
+
+// RequestTransfer opens a transfer for units currently at the source. The
+// destination needs a custodian, only they can confirm the receipt.
+func (a *assetTransferUsecase) RequestTransfer(bodyRequest model.AssetTransfer) (model.AssetTransfer, error) {
+	if bodyRequest.SourceLocationId == bodyRequest.DestinationLocationId {
+		return model.AssetTransfer{}, newError(ErrInvalid, "source and destination must be different locations")
+	}
+
+	if _, err := a.locUsecase.SearchLocationById(bodyRequest.SourceLocationId); err != nil {
+		return model.AssetTransfer{}, newError(ErrNotFound, "location with id %s is not found", bodyRequest.SourceLocationId)
+	}
+
+	destination, err := a.locUsecase.SearchLocationById(bodyRequest.DestinationLocationId)
+	if err != nil {
+		return model.AssetTransfer{}, newError(ErrNotFound, "location with id %s is not found", bodyRequest.DestinationLocationId)
+	}
+	if destination.CustodianId == nil {
+		return model.AssetTransfer{}, newError(ErrInvalid, "location %s has no custodian to confirm the receipt", destination.Name)
+	}
+
+	units, err := a.transferableUnits(bodyRequest)
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	for _, unit := range units {
+		transferId, err := a.repo.ActiveTransferOf(unit.Id)
+		if err != nil {
+			return model.AssetTransfer{}, fmt.Errorf("failed to check open transfers : %s", err.Error())
+		}
+		if transferId != "" {
+			return model.AssetTransfer{}, newError(ErrConflict, "asset unit %s is already on transfer %s", unit.Id, transferId)
+		}
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.Status = model.TransferPending
+	bodyRequest.CreatedAt = time.Now()
+	bodyRequest.Units = make([]model.TransferUnit, 0, len(units))
+	for _, unit := range units {
+		bodyRequest.Units = append(bodyRequest.Units, model.TransferUnit{
+			TransferId:    bodyRequest.Id,
+			AssetDetailId: unit.Id,
+			AssetId:       unit.AssetId,
+			Status:        model.TransferUnitPending,
+		})
+	}
+
+	err = a.repo.Create(bodyRequest)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.AssetTransfer{}, newError(ErrConflict, "failed to request transfer : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetTransfer{}, fmt.Errorf("failed to request transfer : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (a *assetTransferUsecase) GetTransfer(id string) (model.AssetTransfer, error) {
+	transfer, err := a.repo.Get(id)
+	if err != nil {
+		return model.AssetTransfer{}, newError(ErrNotFound, "transfer with id %s is not found", id)
+	}
+
+	return transfer, nil
+}
+
+func (a *assetTransferUsecase) ShowTransfers(status string) ([]model.AssetTransfer, error) {
+	transferStatus := model.TransferStatus(status)
+	if status != "" && !transferStatus.IsValid() {
+		return nil, newError(ErrInvalid, "unknown transfer status %s", status)
+	}
+
+	transfers, err := a.repo.List(transferStatus)
+	if err != nil {
+		return nil, fmt.Errorf("error get list transfer : %s", err.Error())
+	}
+
+	return transfers, nil
+}
+
+// ApproveTransfer lets the units be dispatched, the approver can't be the
+// one who requested the transfer.
+func (a *assetTransferUsecase) ApproveTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error) {
+	return a.decide(id, bodyRequest, model.TransferApproved, model.TransferPending)
+}
+
+func (a *assetTransferUsecase) RejectTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error) {
+	return a.decide(id, bodyRequest, model.TransferRejected, model.TransferPending)
+}
+
+// CancelTransfer withdraws a transfer that hasn't been dispatched yet.
+func (a *assetTransferUsecase) CancelTransfer(id string, bodyRequest dto.TransferDecisionDTO) (model.AssetTransfer, error) {
+	return a.decide(id, bodyRequest, model.TransferCancelled, model.TransferPending, model.TransferApproved)
+}
+
+// DispatchTransfer sends the units of an approved transfer on their way,
+// they are in-transit until the destination receives them.
+func (a *assetTransferUsecase) DispatchTransfer(id string, bodyRequest dto.TransferDispatchDTO) (model.AssetTransfer, error) {
+	transfer, err := a.GetTransfer(id)
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	if transfer.Status != model.TransferApproved {
+		return model.AssetTransfer{}, newError(ErrConflict, "transfer %s is %s, only approved transfers can be dispatched", id, transfer.Status)
+	}
+
+	units, err := a.transferableUnits(transfer)
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	transfer.DispatchedBy = bodyRequest.Actor
+	dispatchedAt := time.Now()
+	err = a.repo.Dispatch(transfer, units, dispatchedAt)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.AssetTransfer{}, newError(ErrConflict, "failed to dispatch transfer : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetTransfer{}, fmt.Errorf("failed to dispatch transfer : %s", err.Error())
+	}
+
+	return a.GetTransfer(id)
+}
+
+// ReceiveTransfer records what arrived at the destination. Only the
+// custodian of the destination may confirm, units not listed stay in-transit
+// for a later receipt.
+func (a *assetTransferUsecase) ReceiveTransfer(id string, bodyRequest dto.TransferReceiptDTO) (model.AssetTransfer, error) {
+	transfer, err := a.GetTransfer(id)
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	if transfer.Status != model.TransferInTransit && transfer.Status != model.TransferPartiallyReceived {
+		return model.AssetTransfer{}, newError(ErrConflict, "transfer %s is %s, it can't be received", id, transfer.Status)
+	}
+
+	destination, err := a.locUsecase.SearchLocationById(transfer.DestinationLocationId)
+	if err != nil {
+		return model.AssetTransfer{}, fmt.Errorf("error get location : %s", err.Error())
+	}
+	if destination.CustodianId == nil || *destination.CustodianId != bodyRequest.CustodianId {
+		return model.AssetTransfer{}, newError(ErrForbidden, "receipt must be confirmed by the custodian of %s", destination.Name)
+	}
+
+	lines := make(map[string]model.TransferUnit, len(transfer.Units))
+	for _, unit := range transfer.Units {
+		lines[unit.AssetDetailId] = unit
+	}
+
+	received := make([]model.TransferUnit, 0, len(bodyRequest.Units))
+	for _, receipt := range bodyRequest.Units {
+		line, ok := lines[receipt.AssetDetailId]
+		if !ok {
+			return model.AssetTransfer{}, newError(ErrInvalid, "asset unit %s is not part of transfer %s", receipt.AssetDetailId, id)
+		}
+		if line.Status != model.TransferUnitInTransit {
+			return model.AssetTransfer{}, newError(ErrConflict, "asset unit %s is already %s", receipt.AssetDetailId, line.Status)
+		}
+		delete(lines, receipt.AssetDetailId)
+
+		line.Status = model.TransferUnitReceived
+		if receipt.Missing {
+			line.Status = model.TransferUnitMissing
+		}
+		line.Discrepancy = strings.TrimSpace(receipt.Discrepancy)
+
+		received = append(received, line)
+	}
+
+	if _, err := a.repo.Receive(transfer, received, bodyRequest.CustodianId, time.Now()); err != nil {
+		return model.AssetTransfer{}, fmt.Errorf("failed to receive transfer : %s", err.Error())
+	}
+
+	return a.GetTransfer(id)
+}
+
+func (a *assetTransferUsecase) decide(id string, bodyRequest dto.TransferDecisionDTO, to model.TransferStatus, from ...model.TransferStatus) (model.AssetTransfer, error) {
+	transfer, err := a.GetTransfer(id)
+	if err != nil {
+		return model.AssetTransfer{}, err
+	}
+
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || transfer.Status == status
+	}
+	if !allowed {
+		return model.AssetTransfer{}, newError(ErrConflict, "transfer %s is %s, it can't be %s", id, transfer.Status, to)
+	}
+
+	if to != model.TransferCancelled && strings.EqualFold(strings.TrimSpace(bodyRequest.Actor), strings.TrimSpace(transfer.RequestedBy)) {
+		return model.AssetTransfer{}, newError(ErrForbidden, "transfer can't be decided by the one who requested it")
+	}
+
+	transfer.Status = to
+	transfer.DecidedBy = bodyRequest.Actor
+	transfer.DecidedAt = time.Now()
+	transfer.Note = bodyRequest.Note
+
+	err = a.repo.Decide(transfer, from...)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.AssetTransfer{}, newError(ErrConflict, "failed to update transfer : %s", err.Error())
+	}
+	if err != nil {
+		return model.AssetTransfer{}, fmt.Errorf("failed to update transfer : %s", err.Error())
+	}
+
+	return transfer, nil
+}
+
+// transferableUnits loads the units of a transfer and checks each of them
+// is at the source and can go in-transit.
+func (a *assetTransferUsecase) transferableUnits(transfer model.AssetTransfer) ([]model.AssetDetail, error) {
+	selected := make(map[string]bool, len(transfer.AssetDetailIds))
+	units := make([]model.AssetDetail, 0, len(transfer.AssetDetailIds))
+	for _, unitId := range transfer.AssetDetailIds {
+		if selected[unitId] {
+			return nil, newError(ErrInvalid, "asset unit %s is selected more than once", unitId)
+		}
+		selected[unitId] = true
+
+		unit, err := a.assetRepo.GetUnit(unitId)
+		if err != nil {
+			return nil, newError(ErrNotFound, "asset unit with id %s is not found", unitId)
+		}
+
+		if unit.RemovedAt != nil {
+			return nil, newError(ErrConflict, "asset unit %s is already retired", unitId)
+		}
+
+		if unit.LocationId != transfer.SourceLocationId {
+			return nil, newError(ErrConflict, "asset unit %s is not at location %s", unitId, transfer.SourceLocationId)
+		}
+
+		if err := ValidateStatusTransition(unit.Status, model.StatusInTransit); err != nil {
+			return nil, newError(ErrConflict, "asset unit %s can't be transferred : %s", unitId, err.Error())
+		}
+
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+func NewAssetTransferUsecase(repo repository.AssetTransferRepository, assetRepo repository.AssetRepository, locUsecase AssetLocationUsecase) AssetTransferUsecase {
+	return &assetTransferUsecase{
+		repo:       repo,
+		assetRepo:  assetRepo,
+		locUsecase: locUsecase,
+	}
+}
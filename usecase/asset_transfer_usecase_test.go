@@ -0,0 +1,182 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAssetTransferRepository struct {
+	mock.Mock
+}
+
+func (r *mockAssetTransferRepository) Create(bodyRequest model.AssetTransfer) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockAssetTransferRepository) Get(id string) (model.AssetTransfer, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.AssetTransfer), args.Error(1)
+}
+
+func (r *mockAssetTransferRepository) List(status model.TransferStatus) ([]model.AssetTransfer, error) {
+	args := r.Called(status)
+	return args.Get(0).([]model.AssetTransfer), args.Error(1)
+}
+
+func (r *mockAssetTransferRepository) ActiveTransferOf(unitId string) (string, error) {
+	args := r.Called(unitId)
+	return args.String(0), args.Error(1)
+}
+
+func (r *mockAssetTransferRepository) Decide(bodyRequest model.AssetTransfer, from ...model.TransferStatus) error {
+	args := r.Called(bodyRequest, from)
+	return args.Error(0)
+}
+
+func (r *mockAssetTransferRepository) Dispatch(bodyRequest model.AssetTransfer, units []model.AssetDetail, dispatchedAt time.Time) error {
+	args := r.Called(bodyRequest, units, dispatchedAt)
+	return args.Error(0)
+}
+
+func (r *mockAssetTransferRepository) Receive(bodyRequest model.AssetTransfer, units []model.TransferUnit, receivedBy string, receivedAt time.Time) (model.TransferStatus, error) {
+	args := r.Called(bodyRequest, units, receivedBy, receivedAt)
+	return args.Get(0).(model.TransferStatus), args.Error(1)
+}
+
+type AssetTransferUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockAssetTransferRepository
+	mockAssetRepo *mockAssetRepository
+	mockLocation  *mockLocationUsecase
+	usecase       usecase.AssetTransferUsecase
+}
+
+func (s *AssetTransferUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockAssetTransferRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockLocation = new(mockLocationUsecase)
+	s.usecase = usecase.NewAssetTransferUsecase(s.mockRepo, s.mockAssetRepo, s.mockLocation)
+
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1", Name: "Warehouse"}, nil)
+	s.mockLocation.On("SearchLocationById", "l2").Return(model.AssetLocation{Id: "l2", Name: "Branch", CustodianId: stringPtr("e1")}, nil)
+}
+
+func inTransitTransfer() model.AssetTransfer {
+	return model.AssetTransfer{
+		Id:                    "t1",
+		SourceLocationId:      "l1",
+		DestinationLocationId: "l2",
+		AssetDetailIds:        []string{"u1", "u2"},
+		Status:                model.TransferInTransit,
+		RequestedBy:           "Budi",
+		Units: []model.TransferUnit{
+			{TransferId: "t1", AssetDetailId: "u1", Status: model.TransferUnitInTransit},
+			{TransferId: "t1", AssetDetailId: "u2", Status: model.TransferUnitInTransit},
+		},
+	}
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestRequestTransfer_Success() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}, nil)
+	s.mockRepo.On("ActiveTransferOf", "u1").Return("", nil)
+	s.mockRepo.On("Create", mock.Anything).Return(nil)
+
+	transfer, err := s.usecase.RequestTransfer(model.AssetTransfer{SourceLocationId: "l1", DestinationLocationId: "l2", AssetDetailIds: []string{"u1"}, RequestedBy: "Budi"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.TransferPending, transfer.Status)
+	assert.Equal(s.T(), "a1", transfer.Units[0].AssetId)
+	assert.Equal(s.T(), model.TransferUnitPending, transfer.Units[0].Status)
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestRequestTransfer_Invalid() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l2", Status: model.StatusInStorage}, nil)
+	s.mockAssetRepo.On("GetUnit", "u3").Return(model.AssetDetail{Id: "u3", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}, nil)
+	s.mockRepo.On("ActiveTransferOf", "u3").Return("t0", nil)
+
+	cases := map[string]model.AssetTransfer{
+		"source and destination must be different": {SourceLocationId: "l1", DestinationLocationId: "l1", AssetDetailIds: []string{"u1"}},
+		"has no custodian":                         {SourceLocationId: "l2", DestinationLocationId: "l1", AssetDetailIds: []string{"u1"}},
+		"asset unit u1 is not at location l1":      {SourceLocationId: "l1", DestinationLocationId: "l2", AssetDetailIds: []string{"u1"}},
+		"asset unit u3 is already on transfer t0":  {SourceLocationId: "l1", DestinationLocationId: "l2", AssetDetailIds: []string{"u3"}},
+	}
+
+	for expected, transfer := range cases {
+		_, err := s.usecase.RequestTransfer(transfer)
+		assert.ErrorContains(s.T(), err, expected)
+	}
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestRequestTransfer_UnitTakenMeanwhile() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage}, nil)
+	s.mockRepo.On("ActiveTransferOf", "u1").Return("", nil)
+	s.mockRepo.On("Create", mock.Anything).Return(fmt.Errorf("asset unit u1 %w, it is on transfer t0 now", repository.ErrChanged))
+
+	_, err := s.usecase.RequestTransfer(model.AssetTransfer{SourceLocationId: "l1", DestinationLocationId: "l2", AssetDetailIds: []string{"u1"}, RequestedBy: "Budi"})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "it is on transfer t0 now")
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestApproveTransfer_ByRequester() {
+	s.mockRepo.On("Get", "t1").Return(model.AssetTransfer{Id: "t1", Status: model.TransferPending, RequestedBy: "Budi"}, nil)
+
+	_, err := s.usecase.ApproveTransfer("t1", dto.TransferDecisionDTO{Actor: " budi "})
+	assert.ErrorIs(s.T(), err, usecase.ErrForbidden)
+	assert.ErrorContains(s.T(), err, "can't be decided by the one who requested it")
+	s.mockRepo.AssertNotCalled(s.T(), "Decide", mock.Anything, mock.Anything)
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestReceiveTransfer_WrongCustodian() {
+	s.mockRepo.On("Get", "t1").Return(inTransitTransfer(), nil)
+
+	_, err := s.usecase.ReceiveTransfer("t1", dto.TransferReceiptDTO{CustodianId: "e2", Units: []dto.TransferReceiptUnitDTO{{AssetDetailId: "u1"}}})
+	assert.ErrorIs(s.T(), err, usecase.ErrForbidden)
+	assert.ErrorContains(s.T(), err, "must be confirmed by the custodian of Branch")
+	s.mockRepo.AssertNotCalled(s.T(), "Receive", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestReceiveTransfer_Partial() {
+	s.mockRepo.On("Get", "t1").Return(inTransitTransfer(), nil)
+	s.mockRepo.On("Receive", mock.Anything, []model.TransferUnit{
+		{TransferId: "t1", AssetDetailId: "u1", Status: model.TransferUnitMissing, Discrepancy: "not in the box"},
+	}, "e1", mock.Anything).Return(model.TransferPartiallyReceived, nil)
+
+	_, err := s.usecase.ReceiveTransfer("t1", dto.TransferReceiptDTO{CustodianId: "e1", Units: []dto.TransferReceiptUnitDTO{
+		{AssetDetailId: "u1", Missing: true, Discrepancy: " not in the box "},
+	}})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestReceiveTransfer_UnitNotInTransfer() {
+	s.mockRepo.On("Get", "t1").Return(inTransitTransfer(), nil)
+
+	_, err := s.usecase.ReceiveTransfer("t1", dto.TransferReceiptDTO{CustodianId: "e1", Units: []dto.TransferReceiptUnitDTO{{AssetDetailId: "u9"}}})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "asset unit u9 is not part of transfer t1")
+}
+
+func (s *AssetTransferUsecaseTestSuite) TestReceiveTransfer_AlreadyReceived() {
+	transfer := inTransitTransfer()
+	transfer.Status = model.TransferPartiallyReceived
+	transfer.Units[0].Status = model.TransferUnitReceived
+	s.mockRepo.On("Get", "t1").Return(transfer, nil)
+
+	_, err := s.usecase.ReceiveTransfer("t1", dto.TransferReceiptDTO{CustodianId: "e1", Units: []dto.TransferReceiptUnitDTO{{AssetDetailId: "u1"}}})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "asset unit u1 is already received")
+}
+
+func TestAssetTransferUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetTransferUsecaseTestSuite))
+}
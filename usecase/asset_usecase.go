@@ -5,27 +5,71 @@ import (
 	"asetku-bukan-asetmu/model/dto"
 	"asetku-bukan-asetmu/repository"
 	"asetku-bukan-asetmu/utils/common"
+	"asetku-bukan-asetmu/utils/depreciation"
+	"asetku-bukan-asetmu/utils/storage"
+	"errors"
 	"fmt"
+	"time"
 )
 
 type AssetUsecase interface {
 	CreateNewAsset(bodyRequest model.Asset) error
+	PrepareNewAsset(bodyRequest model.Asset) (model.Asset, error)
 	ShowAllAsset() ([]dto.AssetDTO, error)
 	GetDetailAsset(id string) (dto.AssetDTO, error)
+	GetAssetUnit(id string) (model.AssetDetail, error)
+	GetAssetUnitByTag(tag string) (dto.AssetUnitDTO, error)
 	UpdateAssetLocation(bodyRequest model.AssetPlacement) ([]string, error)
+	ReturnAssetUnits(bodyRequest model.AssetPlacement) ([]string, error)
+	UpdateAsset(id string, bodyRequest dto.AssetUpdateDTO) error
+	PatchAsset(id string, bodyRequest dto.AssetPatchDTO) error
+	DeleteAsset(id string) error
+	RetireAssetUnit(bodyRequest model.AssetRetirement) error
+	UploadAssetImage(id string, image dto.FileUploadDTO) (string, error)
+	RemoveAssetImage(id string) error
+	AssetImageURL(name string) (string, error)
 }
 
 type assetUsecase struct {
 	repo        repository.AssetRepository
 	locUsecase  AssetLocationUsecase
 	ctgrUsecase AssetCategoriesUseCase
+	fileStorage storage.Storage
+	urlExpiry   time.Duration
 }
 
 func (a *assetUsecase) CreateNewAsset(bodyRequest model.Asset) error {
+	bodyRequest, err := a.PrepareNewAsset(bodyRequest)
+	if err != nil {
+		return err
+	}
+
+	err = a.repo.Create(bodyRequest)
+	if err != nil {
+		return fmt.Errorf("failed to register new asset : %v", err)
+	}
+
+	return nil
+}
+
+// PrepareNewAsset validates a new asset and fills in its units and attribute
+// values without storing it, for callers that save it in their own
+// transaction.
+func (a *assetUsecase) PrepareNewAsset(bodyRequest model.Asset) (model.Asset, error) {
+	if bodyRequest.SalvageValue > bodyRequest.Cost {
+		return model.Asset{}, fmt.Errorf("salvage value can't be greater than cost")
+	}
+
 	// Check location id
 	location, err := a.locUsecase.SearchLocationById(bodyRequest.LocationId)
 	if err != nil {
-		return fmt.Errorf("location with id %s is not found", bodyRequest.LocationId)
+		return model.Asset{}, fmt.Errorf("location with id %s is not found", bodyRequest.LocationId)
+	}
+
+	// Check category attributes
+	bodyRequest.AttributeValues, err = a.attributeValues(bodyRequest)
+	if err != nil {
+		return model.Asset{}, err
 	}
 
 	// Create asset detail
@@ -35,19 +79,14 @@ func (a *assetUsecase) CreateNewAsset(bodyRequest model.Asset) error {
 		assetDetail.Id = common.GenerateUUID()
 		assetDetail.AssetId = bodyRequest.Id
 		assetDetail.LocationId = location.Id
-		assetDetail.Status = 1
+		assetDetail.Status = model.StatusInStorage
 
 		assetDetails = append(assetDetails, assetDetail)
 	}
 	// Fill asset detail
 	bodyRequest.AssetDetail = assetDetails
 
-	err = a.repo.Create(bodyRequest)
-	if err != nil {
-		return fmt.Errorf("failed to register new asset : %v", err)
-	}
-
-	return nil
+	return bodyRequest, nil
 }
 
 func (a *assetUsecase) ShowAllAsset() ([]dto.AssetDTO, error) {
@@ -69,6 +108,7 @@ func (a *assetUsecase) ShowAllAsset() ([]dto.AssetDTO, error) {
 		assetRow.Name = asset.Name
 		assetRow.Description = asset.Description
 		assetRow.ImageUrl = asset.ImageUrl
+		assetRow.Thumbnails = AssetThumbnailUrls(asset.ImageUrl, asset.ImageThumbnails)
 		assetRow.Qty = asset.Qty
 		assetRow.Category = category
 
@@ -107,8 +147,10 @@ func (a *assetUsecase) GetDetailAsset(id string) (dto.AssetDTO, error) {
 		}
 
 		detailResponse.Id = detail.Id
-		detailResponse.Status = detail.Status
+		detailResponse.Tag = detail.Tag
+		detailResponse.Status = detail.Status.String()
 		detailResponse.UpdatedAt = detail.UpdatedAt
+		detailResponse.RemovedAt = detail.RemovedAt
 		detailResponse.Location = location
 
 		assetDetailResponse = append(assetDetailResponse, detailResponse)
@@ -120,43 +162,485 @@ func (a *assetUsecase) GetDetailAsset(id string) (dto.AssetDTO, error) {
 	assetResponse.Name = asset.Name
 	assetResponse.Description = asset.Description
 	assetResponse.ImageUrl = asset.ImageUrl
+	assetResponse.Thumbnails = AssetThumbnailUrls(asset.ImageUrl, asset.ImageThumbnails)
 	assetResponse.Qty = asset.Qty
 	assetResponse.Category = category
+	assetResponse.Cost = asset.Cost
+	assetResponse.SalvageValue = asset.SalvageValue
+	assetResponse.UsefulLife = asset.UsefulLife
 	assetResponse.AssetDetail = assetDetailResponse
 
+	assetResponse.Attributes, err = a.assetAttributes(asset)
+	if err != nil {
+		return dto.AssetDTO{}, fmt.Errorf("error get asset attributes : %s", err.Error())
+	}
+
+	assetResponse.Depreciation, err = a.depreciate(asset, category, time.Now())
+	if err != nil {
+		return dto.AssetDTO{}, fmt.Errorf("error calculate depreciation : %s", err.Error())
+	}
+
 	return assetResponse, nil
 }
 
+// attributeValues checks the attribute values of an asset against the
+// schema of its category and returns them the way they are stored.
+func (a *assetUsecase) attributeValues(bodyRequest model.Asset) ([]model.AssetAttributeValue, error) {
+	schema, err := a.ctgrUsecase.CategorySchema(bodyRequest.CategoryId)
+	if err != nil {
+		return nil, newError(ErrNotFound, "category with id %s is not found", bodyRequest.CategoryId)
+	}
+
+	known := make(map[string]bool, len(schema))
+	values := make([]model.AssetAttributeValue, 0, len(schema))
+	for _, attribute := range schema {
+		known[attribute.Name] = true
+
+		value := bodyRequest.Attributes[attribute.Name]
+		if value == nil || value == "" {
+			if attribute.Required {
+				return nil, newError(ErrInvalid, "attribute %s is required", attribute.Name)
+			}
+			continue
+		}
+
+		encoded, err := attribute.Encode(value)
+		if err != nil {
+			return nil, newError(ErrInvalid, "%s", err.Error())
+		}
+
+		values = append(values, model.AssetAttributeValue{AssetId: bodyRequest.Id, AttributeId: attribute.Id, Value: encoded})
+	}
+
+	for name := range bodyRequest.Attributes {
+		if !known[name] {
+			return nil, newError(ErrInvalid, "category %s has no attribute %s", bodyRequest.CategoryId, name)
+		}
+	}
+
+	return values, nil
+}
+
+// assetAttributes reads the stored attribute values of an asset back by
+// name. Values of attributes no longer in the category schema are left out.
+func (a *assetUsecase) assetAttributes(asset model.Asset) (map[string]any, error) {
+	values, err := a.repo.AttributeValues(asset.Id)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	schema, err := a.ctgrUsecase.CategorySchema(asset.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]string, len(values))
+	for _, value := range values {
+		stored[value.AttributeId] = value.Value
+	}
+
+	attributes := make(map[string]any, len(values))
+	for _, attribute := range schema {
+		if value, ok := stored[attribute.Id]; ok {
+			attributes[attribute.Name] = attribute.Decode(value)
+		}
+	}
+
+	return attributes, nil
+}
+
+// depreciate builds the schedule of a single unit, assets without cost or
+// useful life are skipped.
+func (a *assetUsecase) depreciate(asset model.Asset, category model.AssetCategories, at time.Time) (*dto.AssetDepreciationDTO, error) {
+	method, usefulLife, schedule, err := commercialSchedule(asset, category)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+
+	bookValue := depreciation.BookValueAt(asset.Cost, schedule, at)
+
+	return &dto.AssetDepreciationDTO{
+		Method:         method,
+		UsefulLife:     usefulLife,
+		BookValue:      bookValue,
+		TotalBookValue: bookValue * float64(asset.Qty),
+		Schedule:       schedule,
+	}, nil
+}
+
+// commercialSchedule resolves the method and useful life of an asset, where
+// the asset's own useful life wins over the category default.
+func commercialSchedule(asset model.Asset, category model.AssetCategories) (depreciation.Method, int, []depreciation.Entry, error) {
+	usefulLife := asset.UsefulLife
+	if usefulLife == 0 {
+		usefulLife = category.UsefulLife
+	}
+
+	if asset.Cost == 0 || usefulLife == 0 {
+		return "", 0, nil, nil
+	}
+
+	method, err := depreciation.ParseMethod(category.DepreciationMethod)
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	schedule, err := depreciation.Schedule(method, asset.Cost, asset.SalvageValue, usefulLife, asset.CreatedAt)
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	return method, usefulLife, schedule, nil
+}
+
+func (a *assetUsecase) GetAssetUnit(id string) (model.AssetDetail, error) {
+	unit, err := a.repo.GetUnit(id)
+	if err != nil {
+		return model.AssetDetail{}, newError(ErrNotFound, "asset unit with id %s is not found", id)
+	}
+
+	return unit, nil
+}
+
+func (a *assetUsecase) GetAssetUnitByTag(tag string) (dto.AssetUnitDTO, error) {
+	unit, err := a.repo.GetUnitByTag(tag)
+	if err != nil {
+		return dto.AssetUnitDTO{}, fmt.Errorf("asset unit with tag %s is not found", tag)
+	}
+
+	asset, err := a.repo.Detail(unit.AssetId)
+	if err != nil {
+		return dto.AssetUnitDTO{}, fmt.Errorf("error get asset : %s", err.Error())
+	}
+
+	location, err := a.locUsecase.SearchLocationById(unit.LocationId)
+	if err != nil {
+		return dto.AssetUnitDTO{}, fmt.Errorf("error get location : %s", err.Error())
+	}
+
+	var unitResponse dto.AssetUnitDTO
+	unitResponse.AssetId = asset.Id
+	unitResponse.AssetName = asset.Name
+	unitResponse.Id = unit.Id
+	unitResponse.Tag = unit.Tag
+	unitResponse.Status = unit.Status.String()
+	unitResponse.Location = location
+	unitResponse.UpdatedAt = unit.UpdatedAt
+	unitResponse.RemovedAt = unit.RemovedAt
+
+	return unitResponse, nil
+}
+
 func (a *assetUsecase) UpdateAssetLocation(bodyRequest model.AssetPlacement) ([]string, error) {
-	currentQty, err := a.repo.CountCurrentQty(bodyRequest.AsssetId, bodyRequest.Qty, bodyRequest.CurrentStatus)
+	if err := ValidateStatusTransition(bodyRequest.CurrentStatus, bodyRequest.TargetStatus); err != nil {
+		return nil, err
+	}
+
+	// Placement only moves units between storage and their place of use,
+	// assigning, losing and disposing have their own records
+	switch bodyRequest.TargetStatus {
+	case model.StatusAssigned, model.StatusLost, model.StatusDisposed:
+		return nil, newError(ErrInvalid, "asset units can't be placed as %s", bodyRequest.TargetStatus)
+	}
+
+	if bodyRequest.CurrentStatus == model.StatusAssigned {
+		return nil, newError(ErrConflict, "assigned asset units must be checked in before they are placed")
+	}
+
+	// Units go in and out of maintenance through their work orders only
+	if bodyRequest.CurrentStatus == model.StatusInMaintenance || bodyRequest.TargetStatus == model.StatusInMaintenance {
+		return nil, newError(ErrConflict, "asset units in maintenance can't be placed until their work order is closed")
+	}
+
+	// Same for transit, units leave and arrive through their transfer
+	if bodyRequest.CurrentStatus == model.StatusInTransit || bodyRequest.TargetStatus == model.StatusInTransit {
+		return nil, newError(ErrConflict, "asset units in transit can only be moved by their transfer")
+	}
+
+	if len(bodyRequest.AssetDetailIds) > 0 {
+		if err := a.validatePlacementUnits(bodyRequest); err != nil {
+			return nil, err
+		}
+		bodyRequest.Qty = len(bodyRequest.AssetDetailIds)
+	}
+
+	if bodyRequest.Qty <= 0 {
+		return nil, newError(ErrInvalid, "qty must be greater than zero")
+	}
+
+	if _, err := a.locUsecase.SearchLocationById(bodyRequest.LocationId); err != nil {
+		return nil, newError(ErrNotFound, "location with id %s is not found", bodyRequest.LocationId)
+	}
+
+	// Every unit moved by this request shares one batch id in the history
+	bodyRequest.BatchId = common.GenerateUUID()
+
+	assetId, err := a.repo.PlaceUnits(bodyRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update asset placement : %s", err.Error())
+	}
+
+	// Selected units were checked above, so a short result means another
+	// request moved one of them in the meantime
+	if len(bodyRequest.AssetDetailIds) > 0 && len(assetId) == 0 {
+		return nil, newError(ErrConflict, "some of the selected asset units are no longer %s", bodyRequest.CurrentStatus)
+	}
+
+	return assetId, nil
+}
+
+// ReturnAssetUnits moves placed units back to a storage location.
+func (a *assetUsecase) ReturnAssetUnits(bodyRequest model.AssetPlacement) ([]string, error) {
+	location, err := a.locUsecase.SearchLocationById(bodyRequest.LocationId)
 	if err != nil {
-		return nil, fmt.Errorf("error check availability asset : %s", err.Error())
+		return nil, newError(ErrNotFound, "location with id %s is not found", bodyRequest.LocationId)
+	}
+
+	if !location.Storage {
+		return nil, newError(ErrInvalid, "location %s is not a storage location", location.Name)
 	}
 
-	if !(currentQty > bodyRequest.Qty) {
-		return nil, nil
+	bodyRequest.TargetStatus = model.StatusInStorage
+	return a.UpdateAssetLocation(bodyRequest)
+}
+
+func (a *assetUsecase) validatePlacementUnits(bodyRequest model.AssetPlacement) error {
+	if bodyRequest.Qty != 0 && bodyRequest.Qty != len(bodyRequest.AssetDetailIds) {
+		return newError(ErrInvalid, "qty doesn't match the number of selected asset units")
+	}
+
+	selected := make(map[string]bool, len(bodyRequest.AssetDetailIds))
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		if selected[unitId] {
+			return newError(ErrInvalid, "asset unit %s is selected more than once", unitId)
+		}
+		selected[unitId] = true
+
+		unit, err := a.GetAssetUnit(unitId)
+		if err != nil {
+			return err
+		}
+
+		if unit.AssetId != bodyRequest.AsssetId {
+			return newError(ErrInvalid, "asset unit %s doesn't belong to asset %s", unitId, bodyRequest.AsssetId)
+		}
+
+		if unit.RemovedAt != nil {
+			return newError(ErrConflict, "asset unit %s is already retired", unitId)
+		}
+
+		if unit.Status != bodyRequest.CurrentStatus {
+			return newError(ErrConflict, "asset unit %s is %s, not %s", unitId, unit.Status, bodyRequest.CurrentStatus)
+		}
 	}
 
-	assetId, err := a.repo.GetAvailabilityId(bodyRequest.Qty, bodyRequest.CurrentStatus, bodyRequest.AsssetId)
+	return nil
+}
+
+func (a *assetUsecase) UpdateAsset(id string, bodyRequest dto.AssetUpdateDTO) error {
+	asset, err := a.repo.Detail(id)
 	if err != nil {
-		return nil, nil
+		return newError(ErrNotFound, "asset with id %s is not found", id)
 	}
+	previousImage := asset.ImageUrl
+	previousCategory := asset.CategoryId
 
-	for index, id := range assetId {
-		bodyRequest.Id = id
-		err := a.repo.UpdateLocation(bodyRequest)
+	asset.CategoryId = bodyRequest.CategoryId
+	asset.Name = bodyRequest.Name
+	asset.Description = bodyRequest.Description
+	asset.ImageUrl = bodyRequest.ImageUrl
+	asset.Cost = bodyRequest.Cost
+	asset.SalvageValue = bodyRequest.SalvageValue
+	asset.UsefulLife = bodyRequest.UsefulLife
+	asset.Attributes = bodyRequest.Attributes
+
+	if asset.Attributes == nil && asset.CategoryId != previousCategory {
+		asset.Attributes, err = a.carriedAttributes(asset, previousCategory)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update asset in looping index(%d) : %s", index, err.Error())
+			return err
 		}
 	}
 
-	return assetId, nil
+	return a.saveAsset(asset, previousImage)
+}
+
+func (a *assetUsecase) PatchAsset(id string, bodyRequest dto.AssetPatchDTO) error {
+	asset, err := a.repo.Detail(id)
+	if err != nil {
+		return newError(ErrNotFound, "asset with id %s is not found", id)
+	}
+	previousImage := asset.ImageUrl
+	previousCategory := asset.CategoryId
+
+	if bodyRequest.CategoryId != nil {
+		asset.CategoryId = *bodyRequest.CategoryId
+	}
+	if bodyRequest.Name != nil {
+		asset.Name = *bodyRequest.Name
+	}
+	if bodyRequest.Description != nil {
+		asset.Description = *bodyRequest.Description
+	}
+	if bodyRequest.ImageUrl != nil {
+		asset.ImageUrl = *bodyRequest.ImageUrl
+	}
+	if bodyRequest.Cost != nil {
+		asset.Cost = *bodyRequest.Cost
+	}
+	if bodyRequest.SalvageValue != nil {
+		asset.SalvageValue = *bodyRequest.SalvageValue
+	}
+	if bodyRequest.UsefulLife != nil {
+		asset.UsefulLife = *bodyRequest.UsefulLife
+	}
+
+	if bodyRequest.Attributes != nil || asset.CategoryId != previousCategory {
+		asset.Attributes, err = a.carriedAttributes(asset, previousCategory)
+		if err != nil {
+			return err
+		}
+
+		for name, value := range bodyRequest.Attributes {
+			asset.Attributes[name] = value
+		}
+	}
+
+	return a.saveAsset(asset, previousImage)
+}
+
+// carriedAttributes reads the attribute values an asset holds under its
+// previous category, keeping the ones its current category has as well.
+func (a *assetUsecase) carriedAttributes(asset model.Asset, previousCategory string) (map[string]any, error) {
+	stored, err := a.assetAttributes(model.Asset{Id: asset.Id, CategoryId: previousCategory})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset attributes : %s", err.Error())
+	}
+
+	schema, err := a.ctgrUsecase.CategorySchema(asset.CategoryId)
+	if err != nil {
+		return nil, newError(ErrNotFound, "category with id %s is not found", asset.CategoryId)
+	}
+
+	attributes := make(map[string]any, len(stored))
+	for _, attribute := range schema {
+		if value, ok := stored[attribute.Name]; ok {
+			attributes[attribute.Name] = value
+		}
+	}
+
+	return attributes, nil
+}
+
+// saveAsset validates and stores an edited asset. When the image was replaced
+// the previously uploaded file is removed.
+func (a *assetUsecase) saveAsset(asset model.Asset, previousImage string) error {
+	if asset.Name == "" {
+		return newError(ErrInvalid, "asset name is required")
+	}
+
+	if asset.SalvageValue > asset.Cost {
+		return newError(ErrInvalid, "salvage value can't be greater than cost")
+	}
+
+	if _, err := a.ctgrUsecase.FindAssetCategoriesById(asset.CategoryId); err != nil {
+		return newError(ErrNotFound, "category with id %s is not found", asset.CategoryId)
+	}
+
+	// Attribute values are checked again when they were sent or the category
+	// changed, otherwise the stored ones are left as they are
+	if asset.Attributes != nil {
+		values, err := a.attributeValues(asset)
+		if err != nil {
+			return err
+		}
+		asset.AttributeValues = values
+	}
+
+	// Thumbnails belong to the uploaded image, a replaced image has none
+	if asset.ImageUrl != previousImage {
+		asset.ImageThumbnails = false
+	}
+
+	err := a.repo.Update(asset)
+	if err != nil {
+		return fmt.Errorf("failed to update asset : %s", err.Error())
+	}
+
+	if asset.ImageUrl != previousImage {
+		a.removeImageFile(previousImage)
+	}
+
+	return nil
+}
+
+func (a *assetUsecase) DeleteAsset(id string) error {
+	asset, err := a.repo.Detail(id)
+	if err != nil {
+		return newError(ErrNotFound, "asset with id %s is not found", id)
+	}
+
+	units, err := a.repo.AssetDetail(id)
+	if err != nil {
+		return fmt.Errorf("error get asset detail : %s", err.Error())
+	}
+
+	for _, unit := range units {
+		if unit.Status == model.StatusAssigned || unit.Status == model.StatusInTransit {
+			return newError(ErrConflict, "asset unit %s is %s, it can't be deleted", unit.Id, unit.Status)
+		}
+	}
+
+	// Assets with history are kept, their units are disposed instead
+	err = a.repo.Delete(id)
+	if errors.Is(err, repository.ErrInUse) {
+		return newError(ErrConflict, "asset %s can't be deleted, it is %s, dispose its units instead", id, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete asset : %s", err.Error())
+	}
+
+	a.removeImageFile(asset.ImageUrl)
+	return nil
+}
+
+func (a *assetUsecase) RetireAssetUnit(bodyRequest model.AssetRetirement) error {
+	for _, unitId := range bodyRequest.AssetDetailIds {
+		unit, err := a.GetAssetUnit(unitId)
+		if err != nil {
+			return err
+		}
+
+		if unit.AssetId != bodyRequest.AssetId {
+			return newError(ErrInvalid, "asset unit %s doesn't belong to asset %s", unitId, bodyRequest.AssetId)
+		}
+
+		if unit.RemovedAt != nil {
+			return newError(ErrConflict, "asset unit %s is already retired", unitId)
+		}
+
+		// Only stored units are free of assignments, work orders and transfers
+		if unit.Status != model.StatusInStorage {
+			return newError(ErrConflict, "asset unit %s is %s, only units in storage can be retired", unitId, unit.Status)
+		}
+	}
+
+	// Every unit retired by this request shares one batch id in the history
+	bodyRequest.BatchId = common.GenerateUUID()
+
+	err := a.repo.RetireUnits(bodyRequest)
+	if err != nil {
+		return fmt.Errorf("failed to retire asset unit : %s", err.Error())
+	}
+
+	return nil
 }
 
-func NewAssetUsecase(repo repository.AssetRepository, locationUsecase AssetLocationUsecase, categoryUsecase AssetCategoriesUseCase) AssetUsecase {
+func NewAssetUsecase(repo repository.AssetRepository, locationUsecase AssetLocationUsecase, categoryUsecase AssetCategoriesUseCase, fileStorage storage.Storage, urlExpiry time.Duration) AssetUsecase {
 	return &assetUsecase{
 		repo:        repo,
 		locUsecase:  locationUsecase,
 		ctgrUsecase: categoryUsecase,
+		fileStorage: fileStorage,
+		urlExpiry:   urlExpiry,
 	}
 }
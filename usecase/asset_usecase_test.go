@@ -0,0 +1,169 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+func (r *mockAssetRepository) AssetDetail(assetId string) ([]model.AssetDetail, error) {
+	args := r.Called(assetId)
+	return args.Get(0).([]model.AssetDetail), args.Error(1)
+}
+
+func (r *mockAssetRepository) Delete(id string) error {
+	args := r.Called(id)
+	return args.Error(0)
+}
+
+func (r *mockAssetRepository) RetireUnits(bodyRequest model.AssetRetirement) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockAssetRepository) PlaceUnits(bodyRequest model.AssetPlacement) ([]string, error) {
+	args := r.Called(bodyRequest)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type AssetUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo     *mockAssetRepository
+	mockLocation *mockLocationUsecase
+	usecase      usecase.AssetUsecase
+}
+
+func (s *AssetUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockAssetRepository)
+	s.mockLocation = new(mockLocationUsecase)
+	s.usecase = usecase.NewAssetUsecase(s.mockRepo, s.mockLocation, nil, nil, time.Minute)
+
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1", Name: "Office"}, nil)
+}
+
+func (s *AssetUsecaseTestSuite) TestUpdateAssetLocation_Success() {
+	s.mockRepo.On("PlaceUnits", mock.MatchedBy(func(placement model.AssetPlacement) bool {
+		return placement.BatchId != "" && placement.Qty == 2
+	})).Return([]string{"u1", "u2"}, nil)
+
+	placedId, err := s.usecase.UpdateAssetLocation(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusInStorage, TargetStatus: model.StatusPlaced, LocationId: "l1", Qty: 2})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"u1", "u2"}, placedId)
+}
+
+func (s *AssetUsecaseTestSuite) TestUpdateAssetLocation_OtherWorkflows() {
+	cases := map[string]model.AssetPlacement{
+		"can't be placed as assigned": {CurrentStatus: model.StatusInStorage, TargetStatus: model.StatusAssigned},
+		"can't be placed as lost":     {CurrentStatus: model.StatusPlaced, TargetStatus: model.StatusLost},
+		"can't be placed as disposed": {CurrentStatus: model.StatusInStorage, TargetStatus: model.StatusDisposed},
+		"must be checked in":          {CurrentStatus: model.StatusAssigned, TargetStatus: model.StatusPlaced},
+	}
+
+	for expected, placement := range cases {
+		placement.AsssetId = "a1"
+		placement.LocationId = "l1"
+		placement.Qty = 1
+		_, err := s.usecase.UpdateAssetLocation(placement)
+		assert.ErrorContains(s.T(), err, expected)
+	}
+	s.mockRepo.AssertNotCalled(s.T(), "PlaceUnits", mock.Anything)
+}
+
+func (s *AssetUsecaseTestSuite) TestUpdateAssetLocation_ErrorKind() {
+	s.mockRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusPlaced}, nil)
+	s.mockLocation.On("SearchLocationById", "l9").Return(model.AssetLocation{}, sql.ErrNoRows)
+
+	_, err := s.usecase.UpdateAssetLocation(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusDisposed, TargetStatus: model.StatusPlaced, LocationId: "l1", Qty: 1})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+
+	_, err = s.usecase.UpdateAssetLocation(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusInStorage, TargetStatus: model.StatusPlaced, LocationId: "l1", AssetDetailIds: []string{"u1"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.EqualError(s.T(), err, "asset unit u1 is placed, not in-storage")
+
+	_, err = s.usecase.UpdateAssetLocation(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusInStorage, TargetStatus: model.StatusPlaced, LocationId: "l1", Qty: 0})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+
+	_, err = s.usecase.UpdateAssetLocation(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusInStorage, TargetStatus: model.StatusPlaced, LocationId: "l9", Qty: 1})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "PlaceUnits", mock.Anything)
+}
+
+func (s *AssetUsecaseTestSuite) TestReturnAssetUnits_Success() {
+	s.mockLocation.On("SearchLocationById", "w1").Return(model.AssetLocation{Id: "w1", Name: "Warehouse", Storage: true}, nil)
+	s.mockRepo.On("PlaceUnits", mock.MatchedBy(func(placement model.AssetPlacement) bool {
+		return placement.TargetStatus == model.StatusInStorage && placement.LocationId == "w1"
+	})).Return([]string{"u1"}, nil)
+
+	returnedId, err := s.usecase.ReturnAssetUnits(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusPlaced, LocationId: "w1", Qty: 1})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"u1"}, returnedId)
+}
+
+func (s *AssetUsecaseTestSuite) TestReturnAssetUnits_NotStorage() {
+	_, err := s.usecase.ReturnAssetUnits(model.AssetPlacement{AsssetId: "a1", CurrentStatus: model.StatusPlaced, LocationId: "l1", Qty: 1})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.EqualError(s.T(), err, "location Office is not a storage location")
+	s.mockRepo.AssertNotCalled(s.T(), "PlaceUnits", mock.Anything)
+}
+
+func (s *AssetUsecaseTestSuite) TestDeleteAsset_WithHistory() {
+	s.mockRepo.On("Detail", "a1").Return(model.Asset{Id: "a1"}, nil)
+	s.mockRepo.On("AssetDetail", "a1").Return([]model.AssetDetail{{Id: "u1", Status: model.StatusInStorage}}, nil)
+	s.mockRepo.On("Delete", "a1").Return(fmt.Errorf("%w by asset_movements", repository.ErrInUse))
+
+	err := s.usecase.DeleteAsset("a1")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.EqualError(s.T(), err, "asset a1 can't be deleted, it is still in use by asset_movements, dispose its units instead")
+}
+
+func (s *AssetUsecaseTestSuite) TestDeleteAsset_NotFound() {
+	s.mockRepo.On("Detail", "a9").Return(model.Asset{}, sql.ErrNoRows)
+
+	err := s.usecase.DeleteAsset("a9")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "Delete", mock.Anything)
+}
+
+func (s *AssetUsecaseTestSuite) TestUpdateAsset_NotFound() {
+	s.mockRepo.On("Detail", "a9").Return(model.Asset{}, sql.ErrNoRows)
+
+	err := s.usecase.UpdateAsset("a9", dto.AssetUpdateDTO{CategoryId: "c1", Name: "Laptop"})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *AssetUsecaseTestSuite) TestRetireAssetUnit_Success() {
+	s.mockRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusInStorage}, nil)
+	s.mockRepo.On("RetireUnits", mock.MatchedBy(func(retirement model.AssetRetirement) bool {
+		return retirement.BatchId != "" && retirement.AssetId == "a1"
+	})).Return(nil)
+
+	err := s.usecase.RetireAssetUnit(model.AssetRetirement{AssetId: "a1", AssetDetailIds: []string{"u1"}, RemovedAt: time.Now()})
+	assert.NoError(s.T(), err)
+}
+
+func (s *AssetUsecaseTestSuite) TestRetireAssetUnit_NotInStorage() {
+	s.mockRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusAssigned}, nil)
+	s.mockRepo.On("GetUnit", "u9").Return(model.AssetDetail{}, sql.ErrNoRows)
+
+	err := s.usecase.RetireAssetUnit(model.AssetRetirement{AssetId: "a1", AssetDetailIds: []string{"u1"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.EqualError(s.T(), err, "asset unit u1 is assigned, only units in storage can be retired")
+
+	err = s.usecase.RetireAssetUnit(model.AssetRetirement{AssetId: "a1", AssetDetailIds: []string{"u9"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "RetireUnits", mock.Anything)
+}
+
+func TestAssetUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AssetUsecaseTestSuite))
+}
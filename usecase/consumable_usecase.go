@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ConsumableUsecase interface {
+	RegisterConsumable(bodyRequest model.Consumable) (model.Consumable, error)
+	UpdateConsumable(id string, bodyRequest model.Consumable) error
+	GetConsumable(id string) (dto.ConsumableDTO, error)
+	ShowAllConsumables() ([]model.ConsumableLevel, error)
+	ShowLowStock() ([]model.ConsumableLevel, error)
+	RecordTransaction(consumableId string, bodyRequest model.ConsumableTransaction) (model.ConsumableTransaction, error)
+	ShowTransactions(consumableId string) ([]model.ConsumableTransaction, error)
+}
+
+type consumableUsecase struct {
+	repo            repository.ConsumableRepository
+	locUsecase      AssetLocationUsecase
+	employeeUsecase EmployeeUseCase
+}
+
+func (c *consumableUsecase) RegisterConsumable(bodyRequest model.Consumable) (model.Consumable, error) {
+	if err := validateConsumable(&bodyRequest); err != nil {
+		return model.Consumable{}, err
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.CreatedAt = time.Now()
+
+	if err := c.repo.Create(bodyRequest); err != nil {
+		return model.Consumable{}, fmt.Errorf("failed to register consumable : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (c *consumableUsecase) UpdateConsumable(id string, bodyRequest model.Consumable) error {
+	if _, err := c.repo.Get(id); err != nil {
+		return newError(ErrNotFound, "consumable with id %s is not found", id)
+	}
+
+	bodyRequest.Id = id
+	if err := validateConsumable(&bodyRequest); err != nil {
+		return err
+	}
+
+	if err := c.repo.Update(bodyRequest); err != nil {
+		return fmt.Errorf("failed to update consumable : %s", err.Error())
+	}
+
+	return nil
+}
+
+func (c *consumableUsecase) GetConsumable(id string) (dto.ConsumableDTO, error) {
+	consumable, err := c.repo.Get(id)
+	if err != nil {
+		return dto.ConsumableDTO{}, newError(ErrNotFound, "consumable with id %s is not found", id)
+	}
+
+	stock, err := c.repo.Stock(id)
+	if err != nil {
+		return dto.ConsumableDTO{}, fmt.Errorf("error get consumable stock : %s", err.Error())
+	}
+
+	response := dto.ConsumableDTO{
+		ConsumableLevel: model.ConsumableLevel{Consumable: consumable},
+		Stock:           make([]dto.ConsumableStockDTO, 0, len(stock)),
+	}
+	for _, row := range stock {
+		location, err := c.locUsecase.SearchLocationById(row.LocationId)
+		if err != nil {
+			return dto.ConsumableDTO{}, fmt.Errorf("error get location : %s", err.Error())
+		}
+
+		response.TotalQty += row.Qty
+		response.Stock = append(response.Stock, dto.ConsumableStockDTO{Location: location, Qty: row.Qty})
+	}
+	response.LowStock = response.TotalQty < consumable.ReorderPoint
+
+	return response, nil
+}
+
+func (c *consumableUsecase) ShowAllConsumables() ([]model.ConsumableLevel, error) {
+	consumables, err := c.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("error get list consumable : %s", err.Error())
+	}
+
+	return consumables, nil
+}
+
+// ShowLowStock lists the consumables that need to be reordered.
+func (c *consumableUsecase) ShowLowStock() ([]model.ConsumableLevel, error) {
+	consumables, err := c.repo.LowStock()
+	if err != nil {
+		return nil, fmt.Errorf("error get low stock consumables : %s", err.Error())
+	}
+
+	return consumables, nil
+}
+
+// RecordTransaction receives, issues or adjusts the stock of a consumable at
+// one location. Only issues may name the employee taking the items, and
+// adjustments need a note explaining the difference.
+func (c *consumableUsecase) RecordTransaction(consumableId string, bodyRequest model.ConsumableTransaction) (model.ConsumableTransaction, error) {
+	if !bodyRequest.Type.IsValid() {
+		return model.ConsumableTransaction{}, newError(ErrInvalid, "transaction type must be receive, issue or adjust")
+	}
+
+	if _, err := c.repo.Get(consumableId); err != nil {
+		return model.ConsumableTransaction{}, newError(ErrNotFound, "consumable with id %s is not found", consumableId)
+	}
+
+	if _, err := c.locUsecase.SearchLocationById(bodyRequest.LocationId); err != nil {
+		return model.ConsumableTransaction{}, newError(ErrNotFound, "location with id %s is not found", bodyRequest.LocationId)
+	}
+
+	switch bodyRequest.Type {
+	case model.ConsumableAdjust:
+		if bodyRequest.Qty < 0 {
+			return model.ConsumableTransaction{}, newError(ErrInvalid, "counted qty can't be negative")
+		}
+		if strings.TrimSpace(bodyRequest.Note) == "" {
+			return model.ConsumableTransaction{}, newError(ErrInvalid, "note is required for a stock adjustment")
+		}
+	default:
+		if bodyRequest.Qty <= 0 {
+			return model.ConsumableTransaction{}, newError(ErrInvalid, "qty must be greater than 0")
+		}
+	}
+
+	if bodyRequest.EmployeeId != nil && *bodyRequest.EmployeeId == "" {
+		bodyRequest.EmployeeId = nil
+	}
+	if bodyRequest.EmployeeId != nil {
+		if bodyRequest.Type != model.ConsumableIssue {
+			return model.ConsumableTransaction{}, newError(ErrInvalid, "only issued stock goes to an employee")
+		}
+		if _, err := c.employeeUsecase.FindEmployeeById(*bodyRequest.EmployeeId); err != nil {
+			return model.ConsumableTransaction{}, newError(ErrNotFound, "employee with id %s is not found", *bodyRequest.EmployeeId)
+		}
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.ConsumableId = consumableId
+	bodyRequest.CreatedAt = time.Now()
+
+	transaction, err := c.repo.ApplyTransaction(bodyRequest)
+	if errors.Is(err, repository.ErrNotEnoughStock) {
+		return model.ConsumableTransaction{}, newError(ErrConflict, "failed to %s stock : %s", bodyRequest.Type, err.Error())
+	}
+	if err != nil {
+		return model.ConsumableTransaction{}, fmt.Errorf("failed to %s stock : %s", bodyRequest.Type, err.Error())
+	}
+
+	return transaction, nil
+}
+
+func (c *consumableUsecase) ShowTransactions(consumableId string) ([]model.ConsumableTransaction, error) {
+	if _, err := c.repo.Get(consumableId); err != nil {
+		return nil, newError(ErrNotFound, "consumable with id %s is not found", consumableId)
+	}
+
+	transactions, err := c.repo.ListTransactions(consumableId)
+	if err != nil {
+		return nil, fmt.Errorf("error get consumable transactions : %s", err.Error())
+	}
+
+	return transactions, nil
+}
+
+func validateConsumable(bodyRequest *model.Consumable) error {
+	bodyRequest.Name = strings.TrimSpace(bodyRequest.Name)
+	bodyRequest.Unit = strings.TrimSpace(bodyRequest.Unit)
+
+	if bodyRequest.Name == "" {
+		return newError(ErrInvalid, "name is required")
+	}
+	if bodyRequest.Unit == "" {
+		return newError(ErrInvalid, "unit of measure is required")
+	}
+	if bodyRequest.ReorderPoint < 0 {
+		return newError(ErrInvalid, "reorder point can't be negative")
+	}
+
+	return nil
+}
+
+func NewConsumableUsecase(repo repository.ConsumableRepository, locUsecase AssetLocationUsecase, employeeUsecase EmployeeUseCase) ConsumableUsecase {
+	return &consumableUsecase{
+		repo:            repo,
+		locUsecase:      locUsecase,
+		employeeUsecase: employeeUsecase,
+	}
+}
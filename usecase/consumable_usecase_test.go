@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockConsumableRepository struct {
+	mock.Mock
+}
+
+func (r *mockConsumableRepository) Create(bodyRequest model.Consumable) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockConsumableRepository) Update(bodyRequest model.Consumable) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockConsumableRepository) Get(id string) (model.Consumable, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.Consumable), args.Error(1)
+}
+
+func (r *mockConsumableRepository) List() ([]model.ConsumableLevel, error) {
+	args := r.Called()
+	return args.Get(0).([]model.ConsumableLevel), args.Error(1)
+}
+
+func (r *mockConsumableRepository) LowStock() ([]model.ConsumableLevel, error) {
+	args := r.Called()
+	return args.Get(0).([]model.ConsumableLevel), args.Error(1)
+}
+
+func (r *mockConsumableRepository) Stock(consumableId string) ([]model.ConsumableStock, error) {
+	args := r.Called(consumableId)
+	return args.Get(0).([]model.ConsumableStock), args.Error(1)
+}
+
+func (r *mockConsumableRepository) ApplyTransaction(bodyRequest model.ConsumableTransaction) (model.ConsumableTransaction, error) {
+	args := r.Called(bodyRequest)
+	return args.Get(0).(model.ConsumableTransaction), args.Error(1)
+}
+
+func (r *mockConsumableRepository) ListTransactions(consumableId string) ([]model.ConsumableTransaction, error) {
+	args := r.Called(consumableId)
+	return args.Get(0).([]model.ConsumableTransaction), args.Error(1)
+}
+
+type ConsumableUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo     *mockConsumableRepository
+	mockLocation *mockLocationUsecase
+	mockEmployee *mockEmployeeUsecase
+	usecase      usecase.ConsumableUsecase
+}
+
+func (s *ConsumableUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockConsumableRepository)
+	s.mockLocation = new(mockLocationUsecase)
+	s.mockEmployee = new(mockEmployeeUsecase)
+	s.usecase = usecase.NewConsumableUsecase(s.mockRepo, s.mockLocation, s.mockEmployee)
+
+	s.mockRepo.On("Get", "c1").Return(model.Consumable{Id: "c1", Name: "Paper", Unit: "sheet", ReorderPoint: 1000}, nil)
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1", Name: "Store"}, nil)
+	s.mockLocation.On("SearchLocationById", "l2").Return(model.AssetLocation{Id: "l2", Name: "Office"}, nil)
+	s.mockEmployee.On("FindEmployeeById", "e1").Return(model.Employee{Id: "e1"}, nil)
+	s.mockEmployee.On("FindEmployeeById", "e9").Return(model.Employee{}, sql.ErrNoRows)
+}
+
+func (s *ConsumableUsecaseTestSuite) TestRegisterConsumable_UnitRequired() {
+	_, err := s.usecase.RegisterConsumable(model.Consumable{Name: "Cable", Unit: " "})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "unit of measure is required")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ConsumableUsecaseTestSuite) TestGetConsumable_SumsStock() {
+	s.mockRepo.On("Stock", "c1").Return([]model.ConsumableStock{
+		{ConsumableId: "c1", LocationId: "l1", Qty: 500},
+		{ConsumableId: "c1", LocationId: "l2", Qty: 250},
+	}, nil)
+
+	consumable, err := s.usecase.GetConsumable("c1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), float64(750), consumable.TotalQty)
+	assert.True(s.T(), consumable.LowStock)
+	assert.Equal(s.T(), "Office", consumable.Stock[1].Location.Name)
+}
+
+func (s *ConsumableUsecaseTestSuite) TestRecordTransaction_Issue() {
+	employeeId := "e1"
+	s.mockRepo.On("ApplyTransaction", mock.MatchedBy(func(transaction model.ConsumableTransaction) bool {
+		return transaction.Id != "" && transaction.ConsumableId == "c1" && transaction.Type == model.ConsumableIssue
+	})).Return(model.ConsumableTransaction{Id: "t1", Delta: -20, Balance: 480}, nil)
+
+	transaction, err := s.usecase.RecordTransaction("c1", model.ConsumableTransaction{LocationId: "l1", Type: model.ConsumableIssue, Qty: 20, EmployeeId: &employeeId})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), float64(480), transaction.Balance)
+}
+
+func (s *ConsumableUsecaseTestSuite) TestRecordTransaction_Invalid() {
+	employeeId := "e1"
+	unknownEmployee := "e9"
+	cases := map[string]model.ConsumableTransaction{
+		"transaction type must be":              {LocationId: "l1", Type: "transfer", Qty: 1},
+		"qty must be greater than 0":            {LocationId: "l1", Type: model.ConsumableReceive},
+		"note is required":                      {LocationId: "l1", Type: model.ConsumableAdjust, Qty: 10},
+		"only issued stock goes to an employee": {LocationId: "l1", Type: model.ConsumableReceive, Qty: 5, EmployeeId: &employeeId},
+		"employee with id e9 is not found":      {LocationId: "l1", Type: model.ConsumableIssue, Qty: 5, EmployeeId: &unknownEmployee},
+	}
+
+	for expected, transaction := range cases {
+		_, err := s.usecase.RecordTransaction("c1", transaction)
+		assert.ErrorContains(s.T(), err, expected)
+		assert.True(s.T(), errors.Is(err, usecase.ErrInvalid) || errors.Is(err, usecase.ErrNotFound), expected)
+	}
+	s.mockRepo.AssertNotCalled(s.T(), "ApplyTransaction", mock.Anything)
+}
+
+func (s *ConsumableUsecaseTestSuite) TestRecordTransaction_NotEnoughStock() {
+	s.mockRepo.On("ApplyTransaction", mock.Anything).Return(model.ConsumableTransaction{}, fmt.Errorf("%w, only 3 left at location l1", repository.ErrNotEnoughStock))
+
+	_, err := s.usecase.RecordTransaction("c1", model.ConsumableTransaction{LocationId: "l1", Type: model.ConsumableIssue, Qty: 5})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "only 3 left at location l1")
+}
+
+func TestConsumableUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsumableUsecaseTestSuite))
+}
@@ -16,7 +16,8 @@ type EmployeeUseCase interface {
 }
 
 type employeeUseCase struct {
-	repo repository.EmployeeRepository
+	repo           repository.EmployeeRepository
+	assignmentRepo repository.AssetAssignmentRepository
 }
 
 func (e *employeeUseCase) RegisterNewEmployee(payload model.Employee) error {
@@ -53,6 +54,16 @@ func (e *employeeUseCase) DeleteEmployee(id string) error {
 	if err != nil {
 		return err
 	}
+
+	holdings, err := e.assignmentRepo.CountActiveByEmployee(id)
+	if err != nil {
+		return fmt.Errorf("error check employee holdings : %s", err.Error())
+	}
+
+	if holdings > 0 {
+		return fmt.Errorf("employee still holds %d asset unit(s), check them in before deleting", holdings)
+	}
+
 	return e.repo.Delete(id)
 }
 
@@ -60,8 +71,9 @@ func (e *employeeUseCase) DeleteEmployee(id string) error {
 // 	return e.repo.Paging(requesPaging, byNameEmpl)
 // }
 
-func NewEmployeeUseCase(empRepo repository.EmployeeRepository) EmployeeUseCase {
+func NewEmployeeUseCase(empRepo repository.EmployeeRepository, assignmentRepo repository.AssetAssignmentRepository) EmployeeUseCase {
 	return &employeeUseCase{
-		repo: empRepo,
+		repo:           empRepo,
+		assignmentRepo: assignmentRepo,
 	}
 }
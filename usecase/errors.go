@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors of the usecases can match one of these kinds with errors.Is, so the
+// delivery layer can answer with the right status without reading messages.
+var (
+	ErrInvalid     = errors.New("invalid request")
+	ErrNotFound    = errors.New("not found")
+	ErrForbidden   = errors.New("forbidden")
+	ErrConflict    = errors.New("conflict")
+	ErrTooLarge    = errors.New("too large")
+	ErrUnsupported = errors.New("unsupported media type")
+)
+
+type usecaseError struct {
+	kind    error
+	message string
+}
+
+func (e *usecaseError) Error() string {
+	return e.message
+}
+
+func (e *usecaseError) Is(target error) bool {
+	return target == e.kind
+}
+
+// newError formats an error message and tags it with one of the kinds above.
+func newError(kind error, format string, args ...any) error {
+	return &usecaseError{kind: kind, message: fmt.Sprintf(format, args...)}
+}
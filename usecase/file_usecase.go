@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/utils/storage"
+	"errors"
+	"fmt"
+)
+
+type FileUsecase interface {
+	OpenSignedFile(key, expires, signature string) (storage.Object, error)
+}
+
+type fileUsecase struct {
+	fileStorage storage.Storage
+}
+
+// OpenSignedFile serves links signed by a storage that relies on the API to
+// deliver its files, such as local storage. Other backends hand out links to
+// their own server, so nothing is served here for them.
+func (f *fileUsecase) OpenSignedFile(key, expires, signature string) (storage.Object, error) {
+	verifier, ok := f.fileStorage.(storage.URLVerifier)
+	if !ok {
+		return storage.Object{}, fmt.Errorf("file %s is not found", key)
+	}
+
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return storage.Object{}, err
+	}
+
+	object, err := f.fileStorage.Get(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return storage.Object{}, fmt.Errorf("file %s is not found", key)
+	}
+	if err != nil {
+		return storage.Object{}, fmt.Errorf("failed to open file : %s", err.Error())
+	}
+
+	return object, nil
+}
+
+func NewFileUsecase(fileStorage storage.Storage) FileUsecase {
+	return &fileUsecase{
+		fileStorage: fileStorage,
+	}
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"archive/zip"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/label"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Label content modes: the bare unit tag or a link to the lookup endpoint
+const (
+	LabelContentTag = "tag"
+	LabelContentUrl = "url"
+)
+
+type LabelUsecase interface {
+	RenderUnitLabels(request dto.LabelRequestDTO) (dto.LabelFileDTO, error)
+	RenderAssetZPL(assetId string) (dto.LabelFileDTO, error)
+	RenderLocationZPL(locationId string) (dto.LabelFileDTO, error)
+}
+
+type labelUsecase struct {
+	assetRepo       repository.AssetRepository
+	ctgrUsecase     AssetCategoriesUseCase
+	locUsecase      AssetLocationUsecase
+	zplTemplatePath string
+}
+
+// RenderUnitLabels renders one label per unit. A PDF holds every label on
+// printable sheets, PNG and SVG give a single image or a zip of images.
+func (l *labelUsecase) RenderUnitLabels(request dto.LabelRequestDTO) (dto.LabelFileDTO, error) {
+	symbology, err := label.ParseSymbology(request.Symbology)
+	if err != nil {
+		return dto.LabelFileDTO{}, newError(ErrInvalid, "%s", err.Error())
+	}
+
+	format, err := label.ParseFormat(request.Format)
+	if err != nil {
+		return dto.LabelFileDTO{}, newError(ErrInvalid, "%s", err.Error())
+	}
+
+	labels, names, err := l.unitLabels(request)
+	if err != nil {
+		return dto.LabelFileDTO{}, err
+	}
+
+	var buffer bytes.Buffer
+	if format == label.PDF {
+		if err := label.WritePDF(&buffer, symbology, labels); err != nil {
+			return dto.LabelFileDTO{}, fmt.Errorf("failed to render label : %s", err.Error())
+		}
+
+		return dto.LabelFileDTO{FileName: "labels.pdf", ContentType: format.ContentType(), Data: buffer.Bytes()}, nil
+	}
+
+	if len(labels) == 1 {
+		if err := writeLabelImage(&buffer, symbology, format, labels[0]); err != nil {
+			return dto.LabelFileDTO{}, err
+		}
+
+		return dto.LabelFileDTO{FileName: names[0] + "." + string(format), ContentType: format.ContentType(), Data: buffer.Bytes()}, nil
+	}
+
+	archive := zip.NewWriter(&buffer)
+	for i, item := range labels {
+		file, err := archive.Create(names[i] + "." + string(format))
+		if err != nil {
+			return dto.LabelFileDTO{}, fmt.Errorf("failed to render label : %s", err.Error())
+		}
+
+		if err := writeLabelImage(file, symbology, format, item); err != nil {
+			return dto.LabelFileDTO{}, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return dto.LabelFileDTO{}, fmt.Errorf("failed to render label : %s", err.Error())
+	}
+
+	return dto.LabelFileDTO{FileName: "labels.zip", ContentType: "application/zip", Data: buffer.Bytes()}, nil
+}
+
+// RenderAssetZPL prints every unit of an asset that hasn't been retired.
+func (l *labelUsecase) RenderAssetZPL(assetId string) (dto.LabelFileDTO, error) {
+	if _, err := l.assetRepo.Detail(assetId); err != nil {
+		return dto.LabelFileDTO{}, newError(ErrNotFound, "asset with id %s is not found", assetId)
+	}
+
+	details, err := l.assetRepo.AssetDetail(assetId)
+	if err != nil {
+		return dto.LabelFileDTO{}, fmt.Errorf("error get asset detail : %s", err.Error())
+	}
+
+	units := make([]model.AssetDetail, 0, len(details))
+	for _, unit := range details {
+		if unit.RemovedAt == nil {
+			unit.AssetId = assetId
+			units = append(units, unit)
+		}
+	}
+
+	return l.renderZPL("asset-"+assetId, units)
+}
+
+// RenderLocationZPL prints every unit currently at a location.
+func (l *labelUsecase) RenderLocationZPL(locationId string) (dto.LabelFileDTO, error) {
+	if _, err := l.locUsecase.SearchLocationById(locationId); err != nil {
+		return dto.LabelFileDTO{}, newError(ErrNotFound, "location with id %s is not found", locationId)
+	}
+
+	units, err := l.assetRepo.LocationUnits(locationId)
+	if err != nil {
+		return dto.LabelFileDTO{}, fmt.Errorf("error get location units : %s", err.Error())
+	}
+
+	return l.renderZPL("location-"+locationId, units)
+}
+
+func (l *labelUsecase) renderZPL(name string, units []model.AssetDetail) (dto.LabelFileDTO, error) {
+	if len(units) == 0 {
+		return dto.LabelFileDTO{}, newError(ErrNotFound, "there is no asset unit to print")
+	}
+
+	tmpl, err := l.zplTemplate()
+	if err != nil {
+		return dto.LabelFileDTO{}, err
+	}
+
+	assets := make(map[string]model.Asset)
+	categories := make(map[string]string)
+	locations := make(map[string]string)
+	labels := make([]label.UnitLabel, 0, len(units))
+	for _, unit := range units {
+		asset, ok := assets[unit.AssetId]
+		if !ok {
+			asset, err = l.assetRepo.Detail(unit.AssetId)
+			if err != nil {
+				return dto.LabelFileDTO{}, fmt.Errorf("error get asset : %s", err.Error())
+			}
+			assets[unit.AssetId] = asset
+		}
+
+		category, ok := categories[asset.CategoryId]
+		if !ok {
+			found, err := l.ctgrUsecase.FindAssetCategoriesById(asset.CategoryId)
+			if err != nil {
+				return dto.LabelFileDTO{}, fmt.Errorf("error get category : %s", err.Error())
+			}
+			category = found.Name
+			categories[asset.CategoryId] = category
+		}
+
+		location, ok := locations[unit.LocationId]
+		if !ok {
+			found, err := l.locUsecase.SearchLocationById(unit.LocationId)
+			if err != nil {
+				return dto.LabelFileDTO{}, fmt.Errorf("error get location : %s", err.Error())
+			}
+			location = found.Name
+			locations[unit.LocationId] = location
+		}
+
+		code := unit.Tag
+		if code == "" {
+			code = unit.Id
+		}
+
+		labels = append(labels, label.UnitLabel{
+			AssetName: asset.Name,
+			Tag:       code,
+			Category:  category,
+			Location:  location,
+			Barcode:   code,
+		})
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Render(&buffer, labels); err != nil {
+		return dto.LabelFileDTO{}, err
+	}
+
+	return dto.LabelFileDTO{FileName: name + ".zpl", ContentType: "text/plain; charset=utf-8", Data: buffer.Bytes()}, nil
+}
+
+// zplTemplate reads the configured template on every call so it can be
+// adjusted without restarting the server.
+func (l *labelUsecase) zplTemplate() (*label.ZPLTemplate, error) {
+	if l.zplTemplatePath == "" {
+		return label.NewZPLTemplate(label.DefaultZPLTemplate)
+	}
+
+	text, err := os.ReadFile(l.zplTemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read label template : %s", err.Error())
+	}
+
+	return label.NewZPLTemplate(string(text))
+}
+
+// unitLabels resolves the content and caption of every requested unit along
+// with a file name for it. Units without a tag fall back to their id.
+func (l *labelUsecase) unitLabels(request dto.LabelRequestDTO) ([]label.Label, []string, error) {
+	content := strings.ToLower(request.Content)
+	if content == "" {
+		content = LabelContentTag
+	}
+	if content != LabelContentTag && content != LabelContentUrl {
+		return nil, nil, newError(ErrInvalid, "unknown label content %s", request.Content)
+	}
+
+	assets := make(map[string]model.Asset)
+	labels := make([]label.Label, 0, len(request.AssetDetailIds))
+	names := make([]string, 0, len(request.AssetDetailIds))
+	for _, unitId := range request.AssetDetailIds {
+		unit, err := l.assetRepo.GetUnit(unitId)
+		if err != nil {
+			return nil, nil, newError(ErrNotFound, "asset unit with id %s is not found", unitId)
+		}
+
+		asset, ok := assets[unit.AssetId]
+		if !ok {
+			asset, err = l.assetRepo.Detail(unit.AssetId)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error get asset : %s", err.Error())
+			}
+			assets[unit.AssetId] = asset
+		}
+
+		code := unit.Tag
+		if code == "" {
+			code = unit.Id
+		}
+
+		item := label.Label{Content: code, Caption: []string{asset.Name, code}}
+		if content == LabelContentUrl {
+			item.Content = unitLookupUrl(request.BaseUrl, unit)
+		}
+
+		labels = append(labels, item)
+		names = append(names, code)
+	}
+
+	return labels, names, nil
+}
+
+// unitLookupUrl links to the tag lookup, or to the asset detail for units
+// that have no tag yet.
+func unitLookupUrl(baseUrl string, unit model.AssetDetail) string {
+	baseUrl = strings.TrimRight(baseUrl, "/")
+	if unit.Tag == "" {
+		return baseUrl + "/api/v1/asset/detail/" + url.PathEscape(unit.AssetId)
+	}
+	return baseUrl + "/api/v1/asset/tag/" + url.PathEscape(unit.Tag)
+}
+
+func writeLabelImage(w io.Writer, symbology label.Symbology, format label.Format, item label.Label) error {
+	code, err := label.Encode(symbology, item.Content)
+	if err != nil {
+		return newError(ErrInvalid, "%s", err.Error())
+	}
+
+	if format == label.SVG {
+		err = label.WriteSVG(w, code)
+	} else {
+		err = label.WritePNG(w, code)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render label : %s", err.Error())
+	}
+
+	return nil
+}
+
+func NewLabelUsecase(assetRepo repository.AssetRepository, categoryUsecase AssetCategoriesUseCase, locationUsecase AssetLocationUsecase, zplTemplatePath string) LabelUsecase {
+	return &labelUsecase{
+		assetRepo:       assetRepo,
+		ctgrUsecase:     categoryUsecase,
+		locUsecase:      locationUsecase,
+		zplTemplatePath: zplTemplatePath,
+	}
+}
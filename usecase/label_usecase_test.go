@@ -0,0 +1,124 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LabelUsecaseTestSuite struct {
+	suite.Suite
+	mockAssetRepo *mockAssetRepository
+	mockCategory  *mockCategoryUsecase
+	mockLocation  *mockLocationUsecase
+	usecase       usecase.LabelUsecase
+}
+
+func (s *LabelUsecaseTestSuite) SetupTest() {
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockCategory = new(mockCategoryUsecase)
+	s.mockLocation = new(mockLocationUsecase)
+	s.usecase = usecase.NewLabelUsecase(s.mockAssetRepo, s.mockCategory, s.mockLocation, "")
+
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", Name: "Laptop", CategoryId: "c1"}, nil)
+	s.mockCategory.On("FindAssetCategoriesById", "c1").Return(model.AssetCategories{Id: "c1", Name: "Electronic"}, nil)
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1", Name: "Office"}, nil)
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderUnitLabels_Single() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Tag: "IT-LAP-0001"}, nil)
+
+	file, err := s.usecase.RenderUnitLabels(dto.LabelRequestDTO{AssetDetailIds: []string{"u1"}, Format: "svg"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "IT-LAP-0001.svg", file.FileName)
+	assert.Equal(s.T(), "image/svg+xml", file.ContentType)
+	assert.Contains(s.T(), string(file.Data), "<svg")
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderUnitLabels_Zip() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Tag: "IT-LAP-0001"}, nil)
+	s.mockAssetRepo.On("GetUnit", "u2").Return(model.AssetDetail{Id: "u2", AssetId: "a1"}, nil)
+
+	file, err := s.usecase.RenderUnitLabels(dto.LabelRequestDTO{AssetDetailIds: []string{"u1", "u2"}, Content: "url", BaseUrl: "https://asset.example.com"})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "labels.zip", file.FileName)
+
+	archive, err := zip.NewReader(bytes.NewReader(file.Data), int64(len(file.Data)))
+	require.NoError(s.T(), err)
+	names := []string{}
+	for _, entry := range archive.File {
+		names = append(names, entry.Name)
+	}
+	assert.Equal(s.T(), []string{"IT-LAP-0001.png", "u2.png"}, names)
+	s.mockAssetRepo.AssertNumberOfCalls(s.T(), "Detail", 1)
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderUnitLabels_Invalid() {
+	cases := map[string]dto.LabelRequestDTO{
+		"unknown symbology ean":  {AssetDetailIds: []string{"u1"}, Symbology: "ean"},
+		"unknown label format x": {AssetDetailIds: []string{"u1"}, Format: "x"},
+		"unknown label content":  {AssetDetailIds: []string{"u1"}, Content: "name"},
+	}
+
+	for expected, request := range cases {
+		_, err := s.usecase.RenderUnitLabels(request)
+		assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+		assert.ErrorContains(s.T(), err, expected)
+	}
+	s.mockAssetRepo.AssertNotCalled(s.T(), "GetUnit", "u1")
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderUnitLabels_UnitNotFound() {
+	s.mockAssetRepo.On("GetUnit", "u9").Return(model.AssetDetail{}, sql.ErrNoRows)
+
+	_, err := s.usecase.RenderUnitLabels(dto.LabelRequestDTO{AssetDetailIds: []string{"u9"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.EqualError(s.T(), err, "asset unit with id u9 is not found")
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderAssetZPL_SkipsRetiredUnits() {
+	removedAt := time.Now()
+	s.mockAssetRepo.On("AssetDetail", "a1").Return([]model.AssetDetail{
+		{Id: "u1", LocationId: "l1", Tag: "IT-LAP-0001"},
+		{Id: "u2", LocationId: "l1", Tag: "IT-LAP-0002", RemovedAt: &removedAt},
+	}, nil)
+
+	file, err := s.usecase.RenderAssetZPL("a1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "asset-a1.zpl", file.FileName)
+
+	zpl := string(file.Data)
+	assert.Equal(s.T(), 1, strings.Count(zpl, "^XA"))
+	assert.Contains(s.T(), zpl, "IT-LAP-0001")
+	assert.Contains(s.T(), zpl, "Office")
+	assert.NotContains(s.T(), zpl, "IT-LAP-0002")
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderAssetZPL_NotFound() {
+	s.mockAssetRepo.On("Detail", "a9").Return(model.Asset{}, sql.ErrNoRows)
+
+	_, err := s.usecase.RenderAssetZPL("a9")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+}
+
+func (s *LabelUsecaseTestSuite) TestRenderLocationZPL_Empty() {
+	s.mockAssetRepo.On("LocationUnits", "l1").Return([]model.AssetDetail{}, nil)
+
+	_, err := s.usecase.RenderLocationZPL("l1")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.EqualError(s.T(), err, "there is no asset unit to print")
+}
+
+func TestLabelUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(LabelUsecaseTestSuite))
+}
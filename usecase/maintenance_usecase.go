@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type MaintenanceUsecase interface {
+	OpenWorkOrder(bodyRequest model.WorkOrder) (model.WorkOrder, error)
+	UpdateWorkOrder(id string, bodyRequest dto.WorkOrderUpdateDTO) (model.WorkOrder, error)
+	GetWorkOrder(id string) (model.WorkOrder, error)
+	ShowWorkOrders(status string) ([]model.WorkOrder, error)
+	ShowUnitWorkOrders(unitId string) ([]model.WorkOrder, error)
+	CreateSchedule(bodyRequest model.MaintenanceSchedule) (model.MaintenanceSchedule, error)
+	UpdateSchedule(id string, bodyRequest model.MaintenanceSchedule) error
+	GetSchedule(id string) (model.MaintenanceSchedule, error)
+	ShowSchedules() ([]model.MaintenanceSchedule, error)
+	RunDueSchedules() (dto.ScheduleRunDTO, error)
+}
+
+type maintenanceUsecase struct {
+	repo          repository.MaintenanceRepository
+	assetRepo     repository.AssetRepository
+	ctgrUsecase   AssetCategoriesUseCase
+	vendorUsecase VendorUsecase
+}
+
+// OpenWorkOrder puts a unit in maintenance. Only units in storage or placed
+// can be opened on, an assigned unit has to be checked in first.
+func (m *maintenanceUsecase) OpenWorkOrder(bodyRequest model.WorkOrder) (model.WorkOrder, error) {
+	unit, err := m.assetRepo.GetUnit(bodyRequest.AssetDetailId)
+	if err != nil {
+		return model.WorkOrder{}, newError(ErrNotFound, "asset unit with id %s is not found", bodyRequest.AssetDetailId)
+	}
+
+	if unit.RemovedAt != nil {
+		return model.WorkOrder{}, newError(ErrConflict, "asset unit %s is already retired", unit.Id)
+	}
+
+	if unit.Status != model.StatusInStorage && unit.Status != model.StatusPlaced {
+		return model.WorkOrder{}, newError(ErrConflict, "asset unit %s is %s and can't go into maintenance", unit.Id, unit.Status)
+	}
+
+	switch bodyRequest.Type {
+	case "":
+		bodyRequest.Type = model.WorkOrderCorrective
+	case model.WorkOrderCorrective, model.WorkOrderPreventive:
+	default:
+		return model.WorkOrder{}, newError(ErrInvalid, "unknown work order type %s", bodyRequest.Type)
+	}
+
+	if bodyRequest.VendorId != nil && *bodyRequest.VendorId == "" {
+		bodyRequest.VendorId = nil
+	}
+	if err := m.validateVendor(bodyRequest.VendorId); err != nil {
+		return model.WorkOrder{}, err
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.AssetId = unit.AssetId
+	bodyRequest.ScheduleId = nil
+	bodyRequest.Status = model.WorkOrderOpen
+	bodyRequest.PriorStatus = unit.Status
+	bodyRequest.OpenedAt = time.Now()
+	bodyRequest.ClosedAt = nil
+
+	err = m.repo.OpenWorkOrder(bodyRequest)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.WorkOrder{}, newError(ErrConflict, "failed to open work order : %s", err.Error())
+	}
+	if err != nil {
+		return model.WorkOrder{}, fmt.Errorf("failed to open work order : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+// UpdateWorkOrder changes an open work order. Moving it to completed or
+// cancelled closes it and takes the unit out of maintenance.
+func (m *maintenanceUsecase) UpdateWorkOrder(id string, bodyRequest dto.WorkOrderUpdateDTO) (model.WorkOrder, error) {
+	workOrder, err := m.repo.GetWorkOrder(id)
+	if err != nil {
+		return model.WorkOrder{}, newError(ErrNotFound, "work order with id %s is not found", id)
+	}
+
+	if !workOrder.Status.IsOpen() {
+		return model.WorkOrder{}, newError(ErrConflict, "work order %s is already %s", id, workOrder.Status)
+	}
+
+	if !bodyRequest.Status.IsValid() {
+		return model.WorkOrder{}, newError(ErrInvalid, "unknown work order status %s", bodyRequest.Status)
+	}
+
+	if bodyRequest.Status != workOrder.Status && !workOrder.Status.CanMoveTo(bodyRequest.Status) {
+		return model.WorkOrder{}, newError(ErrConflict, "work order can't move from %s to %s", workOrder.Status, bodyRequest.Status)
+	}
+
+	if bodyRequest.Description != nil {
+		workOrder.Description = *bodyRequest.Description
+	}
+	if bodyRequest.Cost != nil {
+		workOrder.Cost = *bodyRequest.Cost
+	}
+	if bodyRequest.VendorId != nil {
+		workOrder.VendorId = bodyRequest.VendorId
+		if *bodyRequest.VendorId == "" {
+			workOrder.VendorId = nil
+		}
+		if err := m.validateVendor(workOrder.VendorId); err != nil {
+			return model.WorkOrder{}, err
+		}
+	}
+	if bodyRequest.Technician != nil {
+		workOrder.Technician = *bodyRequest.Technician
+	}
+	workOrder.Status = bodyRequest.Status
+
+	if workOrder.Status.IsOpen() {
+		err := m.repo.UpdateWorkOrder(workOrder)
+		if errors.Is(err, repository.ErrChanged) {
+			return model.WorkOrder{}, newError(ErrConflict, "failed to update work order : %s", err.Error())
+		}
+		if err != nil {
+			return model.WorkOrder{}, fmt.Errorf("failed to update work order : %s", err.Error())
+		}
+
+		return workOrder, nil
+	}
+
+	closedAt := time.Now()
+	closing := workOrder
+	closing.Actor = bodyRequest.Actor
+	err = m.repo.CloseWorkOrder(closing, closedAt)
+	if errors.Is(err, repository.ErrChanged) {
+		return model.WorkOrder{}, newError(ErrConflict, "failed to close work order : %s", err.Error())
+	}
+	if err != nil {
+		return model.WorkOrder{}, fmt.Errorf("failed to close work order : %s", err.Error())
+	}
+
+	workOrder.ClosedAt = closedAt
+	return workOrder, nil
+}
+
+func (m *maintenanceUsecase) GetWorkOrder(id string) (model.WorkOrder, error) {
+	workOrder, err := m.repo.GetWorkOrder(id)
+	if err != nil {
+		return model.WorkOrder{}, newError(ErrNotFound, "work order with id %s is not found", id)
+	}
+
+	return workOrder, nil
+}
+
+func (m *maintenanceUsecase) ShowWorkOrders(status string) ([]model.WorkOrder, error) {
+	workOrderStatus := model.WorkOrderStatus(status)
+	if status != "" && !workOrderStatus.IsValid() {
+		return nil, newError(ErrInvalid, "unknown work order status %s", status)
+	}
+
+	workOrders, err := m.repo.ListWorkOrders(workOrderStatus)
+	if err != nil {
+		return nil, fmt.Errorf("error get work orders : %s", err.Error())
+	}
+
+	return workOrders, nil
+}
+
+func (m *maintenanceUsecase) ShowUnitWorkOrders(unitId string) ([]model.WorkOrder, error) {
+	if _, err := m.assetRepo.GetUnit(unitId); err != nil {
+		return nil, newError(ErrNotFound, "asset unit with id %s is not found", unitId)
+	}
+
+	workOrders, err := m.repo.ListUnitWorkOrders(unitId)
+	if err != nil {
+		return nil, fmt.Errorf("error get asset unit work orders : %s", err.Error())
+	}
+
+	return workOrders, nil
+}
+
+func (m *maintenanceUsecase) CreateSchedule(bodyRequest model.MaintenanceSchedule) (model.MaintenanceSchedule, error) {
+	if bodyRequest.AssetId != nil && *bodyRequest.AssetId == "" {
+		bodyRequest.AssetId = nil
+	}
+	if bodyRequest.CategoryId != nil && *bodyRequest.CategoryId == "" {
+		bodyRequest.CategoryId = nil
+	}
+
+	switch {
+	case bodyRequest.AssetId != nil && bodyRequest.CategoryId != nil, bodyRequest.AssetId == nil && bodyRequest.CategoryId == nil:
+		return model.MaintenanceSchedule{}, newError(ErrInvalid, "schedule needs either an asset or a category")
+	case bodyRequest.AssetId != nil:
+		if _, err := m.assetRepo.Detail(*bodyRequest.AssetId); err != nil {
+			return model.MaintenanceSchedule{}, newError(ErrNotFound, "asset with id %s is not found", *bodyRequest.AssetId)
+		}
+	default:
+		if _, err := m.ctgrUsecase.FindAssetCategoriesById(*bodyRequest.CategoryId); err != nil {
+			return model.MaintenanceSchedule{}, newError(ErrNotFound, "category with id %s is not found", *bodyRequest.CategoryId)
+		}
+	}
+
+	if err := m.validateSchedule(&bodyRequest); err != nil {
+		return model.MaintenanceSchedule{}, err
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.Active = true
+	bodyRequest.LastRunAt = nil
+	bodyRequest.CreatedAt = time.Now()
+
+	if err := m.repo.CreateSchedule(bodyRequest); err != nil {
+		return model.MaintenanceSchedule{}, fmt.Errorf("failed to create maintenance schedule : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+// UpdateSchedule changes the plan of a schedule, what it covers stays the
+// same. Setting active to false pauses it.
+func (m *maintenanceUsecase) UpdateSchedule(id string, bodyRequest model.MaintenanceSchedule) error {
+	schedule, err := m.repo.GetSchedule(id)
+	if err != nil {
+		return newError(ErrNotFound, "maintenance schedule with id %s is not found", id)
+	}
+
+	bodyRequest.Id = schedule.Id
+	if err := m.validateSchedule(&bodyRequest); err != nil {
+		return err
+	}
+
+	if err := m.repo.UpdateSchedule(bodyRequest); err != nil {
+		return fmt.Errorf("failed to update maintenance schedule : %s", err.Error())
+	}
+
+	return nil
+}
+
+func (m *maintenanceUsecase) GetSchedule(id string) (model.MaintenanceSchedule, error) {
+	schedule, err := m.repo.GetSchedule(id)
+	if err != nil {
+		return model.MaintenanceSchedule{}, newError(ErrNotFound, "maintenance schedule with id %s is not found", id)
+	}
+
+	return schedule, nil
+}
+
+func (m *maintenanceUsecase) ShowSchedules() ([]model.MaintenanceSchedule, error) {
+	schedules, err := m.repo.ListSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("error get maintenance schedules : %s", err.Error())
+	}
+
+	return schedules, nil
+}
+
+// RunDueSchedules generates the work orders of every active schedule that
+// is due today or earlier, or that has deferred units. A failing schedule
+// doesn't stop the others, the errors are returned together.
+func (m *maintenanceUsecase) RunDueSchedules() (dto.ScheduleRunDTO, error) {
+	now := time.Now()
+	today := dateOf(now)
+
+	schedules, err := m.repo.DueSchedules(today)
+	if err != nil {
+		return dto.ScheduleRunDTO{}, fmt.Errorf("error get due maintenance schedules : %s", err.Error())
+	}
+
+	result := dto.ScheduleRunDTO{WorkOrders: []model.WorkOrder{}, DeferredUnits: []string{}}
+	var failures []error
+	for _, schedule := range schedules {
+		workOrders, deferred, err := m.repo.RunSchedule(schedule.Id, today, now)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("failed to run maintenance schedule %s : %s", schedule.Id, err.Error()))
+			continue
+		}
+
+		result.Schedules++
+		result.WorkOrders = append(result.WorkOrders, workOrders...)
+		result.DeferredUnits = append(result.DeferredUnits, deferred...)
+	}
+
+	return result, errors.Join(failures...)
+}
+
+func (m *maintenanceUsecase) validateSchedule(bodyRequest *model.MaintenanceSchedule) error {
+	if bodyRequest.IntervalDays <= 0 {
+		return newError(ErrInvalid, "interval days must be greater than zero")
+	}
+
+	if bodyRequest.VendorId != nil && *bodyRequest.VendorId == "" {
+		bodyRequest.VendorId = nil
+	}
+	if err := m.validateVendor(bodyRequest.VendorId); err != nil {
+		return err
+	}
+
+	bodyRequest.NextDueDate = dateOf(bodyRequest.NextDueDate)
+	return nil
+}
+
+func (m *maintenanceUsecase) validateVendor(vendorId *string) error {
+	if vendorId == nil {
+		return nil
+	}
+
+	if _, err := m.vendorUsecase.Get(*vendorId); err != nil {
+		return newError(ErrNotFound, "vendor with id %s is not found", *vendorId)
+	}
+
+	return nil
+}
+
+func NewMaintenanceUsecase(repo repository.MaintenanceRepository, assetRepo repository.AssetRepository, categoryUsecase AssetCategoriesUseCase, vendorUsecase VendorUsecase) MaintenanceUsecase {
+	return &maintenanceUsecase{
+		repo:          repo,
+		assetRepo:     assetRepo,
+		ctgrUsecase:   categoryUsecase,
+		vendorUsecase: vendorUsecase,
+	}
+}
@@ -0,0 +1,231 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockMaintenanceRepository struct {
+	mock.Mock
+}
+
+func (r *mockMaintenanceRepository) OpenWorkOrder(bodyRequest model.WorkOrder) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockMaintenanceRepository) UpdateWorkOrder(bodyRequest model.WorkOrder) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockMaintenanceRepository) CloseWorkOrder(bodyRequest model.WorkOrder, closedAt time.Time) error {
+	args := r.Called(bodyRequest, closedAt)
+	return args.Error(0)
+}
+
+func (r *mockMaintenanceRepository) GetWorkOrder(id string) (model.WorkOrder, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.WorkOrder), args.Error(1)
+}
+
+func (r *mockMaintenanceRepository) ListWorkOrders(status model.WorkOrderStatus) ([]model.WorkOrder, error) {
+	args := r.Called(status)
+	return args.Get(0).([]model.WorkOrder), args.Error(1)
+}
+
+func (r *mockMaintenanceRepository) ListUnitWorkOrders(assetDetailId string) ([]model.WorkOrder, error) {
+	args := r.Called(assetDetailId)
+	return args.Get(0).([]model.WorkOrder), args.Error(1)
+}
+
+func (r *mockMaintenanceRepository) CreateSchedule(bodyRequest model.MaintenanceSchedule) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockMaintenanceRepository) UpdateSchedule(bodyRequest model.MaintenanceSchedule) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockMaintenanceRepository) GetSchedule(id string) (model.MaintenanceSchedule, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.MaintenanceSchedule), args.Error(1)
+}
+
+func (r *mockMaintenanceRepository) ListSchedules() ([]model.MaintenanceSchedule, error) {
+	args := r.Called()
+	return args.Get(0).([]model.MaintenanceSchedule), args.Error(1)
+}
+
+func (r *mockMaintenanceRepository) DueSchedules(today time.Time) ([]model.MaintenanceSchedule, error) {
+	args := r.Called(today)
+	return args.Get(0).([]model.MaintenanceSchedule), args.Error(1)
+}
+
+func (r *mockMaintenanceRepository) RunSchedule(scheduleId string, today, openedAt time.Time) ([]model.WorkOrder, []string, error) {
+	args := r.Called(scheduleId, today, openedAt)
+	return args.Get(0).([]model.WorkOrder), args.Get(1).([]string), args.Error(2)
+}
+
+// mockCategoryUsecase only answers category lookups.
+type mockCategoryUsecase struct {
+	mock.Mock
+	usecase.AssetCategoriesUseCase
+}
+
+func (c *mockCategoryUsecase) FindAssetCategoriesById(id string) (model.AssetCategories, error) {
+	args := c.Called(id)
+	return args.Get(0).(model.AssetCategories), args.Error(1)
+}
+
+type MaintenanceUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockMaintenanceRepository
+	mockAssetRepo *mockAssetRepository
+	mockCategory  *mockCategoryUsecase
+	mockVendor    *mockVendorUsecase
+	usecase       usecase.MaintenanceUsecase
+}
+
+func (s *MaintenanceUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockMaintenanceRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockCategory = new(mockCategoryUsecase)
+	s.mockVendor = new(mockVendorUsecase)
+	s.usecase = usecase.NewMaintenanceUsecase(s.mockRepo, s.mockAssetRepo, s.mockCategory, s.mockVendor)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestOpenWorkOrder_Success() {
+	vendorId := "v1"
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusPlaced}, nil)
+	s.mockVendor.On("Get", "v1").Return(model.Vendor{Id: "v1"}, nil)
+	s.mockRepo.On("OpenWorkOrder", mock.MatchedBy(func(workOrder model.WorkOrder) bool {
+		return workOrder.Id != "" && workOrder.Status == model.WorkOrderOpen && workOrder.Type == model.WorkOrderCorrective
+	})).Return(nil)
+
+	workOrder, err := s.usecase.OpenWorkOrder(model.WorkOrder{AssetDetailId: "u1", Description: "fan noise", VendorId: &vendorId})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "a1", workOrder.AssetId)
+	assert.Equal(s.T(), model.StatusPlaced, workOrder.PriorStatus)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestOpenWorkOrder_UnknownVendor() {
+	vendorId := "v9"
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusPlaced}, nil)
+	s.mockVendor.On("Get", "v9").Return(model.Vendor{}, errors.New("not found"))
+
+	_, err := s.usecase.OpenWorkOrder(model.WorkOrder{AssetDetailId: "u1", Description: "fan noise", VendorId: &vendorId})
+	assert.ErrorContains(s.T(), err, "vendor with id v9 is not found")
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	s.mockRepo.AssertNotCalled(s.T(), "OpenWorkOrder", mock.Anything)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestUpdateWorkOrder_Close() {
+	s.mockRepo.On("GetWorkOrder", "wo1").Return(model.WorkOrder{Id: "wo1", AssetDetailId: "u1", Status: model.WorkOrderInProgress}, nil)
+	s.mockRepo.On("CloseWorkOrder", mock.MatchedBy(func(workOrder model.WorkOrder) bool {
+		return workOrder.Status == model.WorkOrderCompleted && workOrder.Cost == 150 && workOrder.Actor == "tech"
+	}), mock.Anything).Return(nil)
+
+	cost := 150.0
+	workOrder, err := s.usecase.UpdateWorkOrder("wo1", dto.WorkOrderUpdateDTO{Status: model.WorkOrderCompleted, Cost: &cost, Actor: "tech"})
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), workOrder.ClosedAt)
+	s.mockRepo.AssertNotCalled(s.T(), "UpdateWorkOrder", mock.Anything)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestUpdateWorkOrder_IllegalTransition() {
+	s.mockRepo.On("GetWorkOrder", "wo1").Return(model.WorkOrder{Id: "wo1", Status: model.WorkOrderInProgress}, nil)
+	s.mockRepo.On("GetWorkOrder", "wo2").Return(model.WorkOrder{Id: "wo2", Status: model.WorkOrderCompleted}, nil)
+
+	_, err := s.usecase.UpdateWorkOrder("wo1", dto.WorkOrderUpdateDTO{Status: model.WorkOrderOpen})
+	assert.ErrorContains(s.T(), err, "can't move from in-progress to open")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+
+	_, err = s.usecase.UpdateWorkOrder("wo2", dto.WorkOrderUpdateDTO{Status: model.WorkOrderCancelled})
+	assert.ErrorContains(s.T(), err, "already completed")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestUpdateWorkOrder_ClosedMeanwhile() {
+	s.mockRepo.On("GetWorkOrder", "wo1").Return(model.WorkOrder{Id: "wo1", AssetDetailId: "u1", Status: model.WorkOrderInProgress}, nil)
+	s.mockRepo.On("CloseWorkOrder", mock.Anything, mock.Anything).Return(fmt.Errorf("work order wo1 %w, it is already cancelled", repository.ErrChanged))
+
+	_, err := s.usecase.UpdateWorkOrder("wo1", dto.WorkOrderUpdateDTO{Status: model.WorkOrderCompleted})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestOpenWorkOrder_ErrorKind() {
+	s.mockAssetRepo.On("GetUnit", "u9").Return(model.AssetDetail{}, errors.New("sql: no rows in result set"))
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1", Status: model.StatusAssigned}, nil)
+
+	_, err := s.usecase.OpenWorkOrder(model.WorkOrder{AssetDetailId: "u9"})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+
+	_, err = s.usecase.OpenWorkOrder(model.WorkOrder{AssetDetailId: "u1"})
+	assert.ErrorContains(s.T(), err, "is assigned and can't go into maintenance")
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	s.mockRepo.AssertNotCalled(s.T(), "OpenWorkOrder", mock.Anything)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestCreateSchedule_NeedsOneTarget() {
+	assetId, categoryId := "a1", "c1"
+
+	_, err := s.usecase.CreateSchedule(model.MaintenanceSchedule{AssetId: &assetId, CategoryId: &categoryId, Description: "service", IntervalDays: 30})
+	assert.ErrorContains(s.T(), err, "either an asset or a category")
+
+	_, err = s.usecase.CreateSchedule(model.MaintenanceSchedule{Description: "service", IntervalDays: 30})
+	assert.ErrorContains(s.T(), err, "either an asset or a category")
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestCreateSchedule_Category() {
+	categoryId := "c1"
+	s.mockCategory.On("FindAssetCategoriesById", "c1").Return(model.AssetCategories{Id: "c1"}, nil)
+	s.mockRepo.On("CreateSchedule", mock.MatchedBy(func(schedule model.MaintenanceSchedule) bool {
+		return schedule.Active && schedule.NextDueDate.Hour() == 0
+	})).Return(nil)
+
+	schedule, err := s.usecase.CreateSchedule(model.MaintenanceSchedule{CategoryId: &categoryId, Description: "service", IntervalDays: 90, NextDueDate: time.Now()})
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), schedule.Id)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestRunDueSchedules() {
+	s.mockRepo.On("DueSchedules", daysFromToday(0)).Return([]model.MaintenanceSchedule{{Id: "ms1"}, {Id: "ms2"}}, nil)
+	s.mockRepo.On("RunSchedule", "ms1", daysFromToday(0), mock.Anything).Return([]model.WorkOrder{{Id: "wo1"}, {Id: "wo2"}}, []string{"u3"}, nil)
+	s.mockRepo.On("RunSchedule", "ms2", daysFromToday(0), mock.Anything).Return([]model.WorkOrder(nil), []string(nil), nil)
+
+	result, err := s.usecase.RunDueSchedules()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, result.Schedules)
+	assert.Len(s.T(), result.WorkOrders, 2)
+	assert.Equal(s.T(), []string{"u3"}, result.DeferredUnits)
+}
+
+func (s *MaintenanceUsecaseTestSuite) TestRunDueSchedules_KeepsGoingAfterFailure() {
+	s.mockRepo.On("DueSchedules", daysFromToday(0)).Return([]model.MaintenanceSchedule{{Id: "ms1"}, {Id: "ms2"}, {Id: "ms3"}}, nil)
+	s.mockRepo.On("RunSchedule", "ms1", daysFromToday(0), mock.Anything).Return([]model.WorkOrder(nil), []string(nil), errors.New("deadlock detected"))
+	s.mockRepo.On("RunSchedule", "ms2", daysFromToday(0), mock.Anything).Return([]model.WorkOrder{{Id: "wo1"}}, []string(nil), nil)
+	s.mockRepo.On("RunSchedule", "ms3", daysFromToday(0), mock.Anything).Return([]model.WorkOrder(nil), []string(nil), errors.New("connection reset"))
+
+	result, err := s.usecase.RunDueSchedules()
+	assert.EqualError(s.T(), err, "failed to run maintenance schedule ms1 : deadlock detected\nfailed to run maintenance schedule ms3 : connection reset")
+	assert.Equal(s.T(), 1, result.Schedules)
+	assert.Len(s.T(), result.WorkOrders, 1)
+}
+
+func TestMaintenanceUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceUsecaseTestSuite))
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"errors"
+	"fmt"
+)
+
+type ProcurementUsecase interface {
+	CreateNewTransaction(bodyRequest model.Transaction) (model.Transaction, error)
+	ShowAllTransaction() ([]model.Transaction, error)
+	GetDetailTransaction(id string) (model.Transaction, error)
+	ReceiveGoods(transactionId string, receipts []model.GoodsReceipt) ([]model.GoodsReceipt, error)
+	ShowAllReceipt(transactionId string) ([]model.GoodsReceipt, error)
+}
+
+type procurementUsecase struct {
+	repo          repository.TransactionRepository
+	vendorUsecase VendorUsecase
+	ctgrUsecase   AssetCategoriesUseCase
+	assetUsecase  AssetUsecase
+}
+
+func (p *procurementUsecase) CreateNewTransaction(bodyRequest model.Transaction) (model.Transaction, error) {
+	if _, err := p.vendorUsecase.Get(bodyRequest.VendorId); err != nil {
+		return model.Transaction{}, newError(ErrNotFound, "vendor with id %s is not found", bodyRequest.VendorId)
+	}
+
+	bodyRequest.Status = model.TransactionOrdered
+	bodyRequest.TotalPrice = 0
+	for i := range bodyRequest.TransactionDetail {
+		detail := &bodyRequest.TransactionDetail[i]
+		if _, err := p.ctgrUsecase.FindAssetCategoriesById(detail.CategoryId); err != nil {
+			return model.Transaction{}, newError(ErrNotFound, "category with id %s is not found", detail.CategoryId)
+		}
+
+		detail.Id = common.GenerateUUID()
+		detail.TransactionId = bodyRequest.Id
+		detail.ReceivedQty = 0
+		bodyRequest.TotalPrice += detail.UnitPrice * float64(detail.Qty)
+	}
+
+	err := p.repo.Create(bodyRequest)
+	if err != nil {
+		return model.Transaction{}, fmt.Errorf("failed to register new transaction : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (p *procurementUsecase) ShowAllTransaction() ([]model.Transaction, error) {
+	transactions, err := p.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("error get list transaction : %s", err.Error())
+	}
+
+	return transactions, nil
+}
+
+func (p *procurementUsecase) GetDetailTransaction(id string) (model.Transaction, error) {
+	transaction, err := p.repo.Get(id)
+	if err != nil {
+		return model.Transaction{}, newError(ErrNotFound, "transaction with id %s is not found", id)
+	}
+
+	return transaction, nil
+}
+
+// ReceiveGoods registers every received line as a new asset through
+// AssetUsecase and links the asset back to its transaction detail. The
+// assets and the receipt are stored in one transaction.
+func (p *procurementUsecase) ReceiveGoods(transactionId string, receipts []model.GoodsReceipt) ([]model.GoodsReceipt, error) {
+	if len(receipts) == 0 {
+		return nil, newError(ErrInvalid, "goods receipt must have at least one line")
+	}
+
+	transaction, err := p.GetDetailTransaction(transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]model.TransactionDetail, len(transaction.TransactionDetail))
+	for _, detail := range transaction.TransactionDetail {
+		details[detail.Id] = detail
+	}
+
+	// Validate the whole receipt before any asset gets created
+	pending := make(map[string]int)
+	for _, receipt := range receipts {
+		detail, ok := details[receipt.TransactionDetailId]
+		if !ok {
+			return nil, newError(ErrInvalid, "transaction detail %s is not part of transaction %s", receipt.TransactionDetailId, transactionId)
+		}
+
+		pending[detail.Id] += receipt.Qty
+		if detail.ReceivedQty+pending[detail.Id] > detail.Qty {
+			return nil, newError(ErrInvalid, "received qty for %s exceeds remaining qty %d", detail.Name, detail.Qty-detail.ReceivedQty)
+		}
+	}
+
+	received := make([]model.GoodsReceipt, 0, len(receipts))
+	assets := make([]model.Asset, 0, len(receipts))
+	for _, receipt := range receipts {
+		detail := details[receipt.TransactionDetailId]
+
+		asset := model.Asset{
+			Id:                  common.GenerateUUID(),
+			CategoryId:          detail.CategoryId,
+			TransactionDetailId: &detail.Id,
+			Name:                detail.Name,
+			Description:         detail.Description,
+			Qty:                 receipt.Qty,
+			Cost:                detail.UnitPrice,
+			LocationId:          receipt.LocationId,
+			CreatedAt:           receipt.ReceivedAt,
+			Attributes:          receipt.Attributes,
+		}
+
+		asset, err := p.assetUsecase.PrepareNewAsset(asset)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt.Id = common.GenerateUUID()
+		receipt.AssetId = asset.Id
+		received = append(received, receipt)
+		assets = append(assets, asset)
+	}
+
+	err = p.repo.ReceiveGoods(transactionId, received, assets)
+	if errors.Is(err, repository.ErrChanged) {
+		return nil, newError(ErrConflict, "failed to record goods receipt : %s", err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record goods receipt : %s", err.Error())
+	}
+
+	return received, nil
+}
+
+func (p *procurementUsecase) ShowAllReceipt(transactionId string) ([]model.GoodsReceipt, error) {
+	receipts, err := p.repo.ListReceipt(transactionId)
+	if err != nil {
+		return nil, fmt.Errorf("error get list goods receipt : %s", err.Error())
+	}
+
+	return receipts, nil
+}
+
+func NewProcurementUsecase(repo repository.TransactionRepository, vendorUsecase VendorUsecase, categoryUsecase AssetCategoriesUseCase, assetUsecase AssetUsecase) ProcurementUsecase {
+	return &procurementUsecase{
+		repo:          repo,
+		vendorUsecase: vendorUsecase,
+		ctgrUsecase:   categoryUsecase,
+		assetUsecase:  assetUsecase,
+	}
+}
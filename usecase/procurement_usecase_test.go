@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockTransactionRepository struct {
+	mock.Mock
+}
+
+func (r *mockTransactionRepository) Create(bodyRequest model.Transaction) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockTransactionRepository) List() ([]model.Transaction, error) {
+	args := r.Called()
+	return args.Get(0).([]model.Transaction), args.Error(1)
+}
+
+func (r *mockTransactionRepository) Get(id string) (model.Transaction, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.Transaction), args.Error(1)
+}
+
+func (r *mockTransactionRepository) ReceiveGoods(transactionId string, receipts []model.GoodsReceipt, assets []model.Asset) error {
+	args := r.Called(transactionId, receipts, assets)
+	return args.Error(0)
+}
+
+func (r *mockTransactionRepository) ListReceipt(transactionId string) ([]model.GoodsReceipt, error) {
+	args := r.Called(transactionId)
+	return args.Get(0).([]model.GoodsReceipt), args.Error(1)
+}
+
+// mockAssetUsecase only prepares new assets.
+type mockAssetUsecase struct {
+	mock.Mock
+	usecase.AssetUsecase
+}
+
+func (a *mockAssetUsecase) PrepareNewAsset(bodyRequest model.Asset) (model.Asset, error) {
+	args := a.Called(bodyRequest)
+	return args.Get(0).(model.Asset), args.Error(1)
+}
+
+type ProcurementUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo     *mockTransactionRepository
+	mockVendor   *mockVendorUsecase
+	mockCategory *mockCategoryUsecase
+	mockAsset    *mockAssetUsecase
+	usecase      usecase.ProcurementUsecase
+}
+
+func (s *ProcurementUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockTransactionRepository)
+	s.mockVendor = new(mockVendorUsecase)
+	s.mockCategory = new(mockCategoryUsecase)
+	s.mockAsset = new(mockAssetUsecase)
+	s.usecase = usecase.NewProcurementUsecase(s.mockRepo, s.mockVendor, s.mockCategory, s.mockAsset)
+
+	s.mockVendor.On("Get", "v1").Return(model.Vendor{Id: "v1"}, nil)
+	s.mockVendor.On("Get", "v9").Return(model.Vendor{}, sql.ErrNoRows)
+	s.mockCategory.On("FindAssetCategoriesById", "c1").Return(model.AssetCategories{Id: "c1"}, nil)
+	s.mockRepo.On("Get", "t1").Return(model.Transaction{Id: "t1", TransactionDetail: []model.TransactionDetail{
+		{Id: "td1", TransactionId: "t1", CategoryId: "c1", Name: "Laptop", UnitPrice: 100, Qty: 3, ReceivedQty: 1},
+	}}, nil)
+}
+
+func (s *ProcurementUsecaseTestSuite) TestCreateNewTransaction_TotalPrice() {
+	s.mockRepo.On("Create", mock.Anything).Return(nil)
+
+	transaction, err := s.usecase.CreateNewTransaction(model.Transaction{Id: "t2", VendorId: "v1", TransactionDetail: []model.TransactionDetail{
+		{CategoryId: "c1", Name: "Laptop", UnitPrice: 100, Qty: 2, ReceivedQty: 5},
+		{CategoryId: "c1", Name: "Mouse", UnitPrice: 12.5, Qty: 4},
+	}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.TransactionOrdered, transaction.Status)
+	assert.Equal(s.T(), float64(250), transaction.TotalPrice)
+	assert.Equal(s.T(), 0, transaction.TransactionDetail[0].ReceivedQty)
+	assert.Equal(s.T(), "t2", transaction.TransactionDetail[1].TransactionId)
+}
+
+func (s *ProcurementUsecaseTestSuite) TestCreateNewTransaction_UnknownVendor() {
+	_, err := s.usecase.CreateNewTransaction(model.Transaction{VendorId: "v9"})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.ErrorContains(s.T(), err, "vendor with id v9 is not found")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ProcurementUsecaseTestSuite) TestReceiveGoods_OneTransaction() {
+	s.mockAsset.On("PrepareNewAsset", mock.MatchedBy(func(asset model.Asset) bool {
+		return asset.CategoryId == "c1" && asset.Cost == 100 && *asset.TransactionDetailId == "td1"
+	})).Return(model.Asset{Id: "a1"}, nil).Twice()
+	s.mockRepo.On("ReceiveGoods", "t1", mock.MatchedBy(func(receipts []model.GoodsReceipt) bool {
+		return len(receipts) == 2 && receipts[0].AssetId == "a1" && receipts[0].Id != ""
+	}), []model.Asset{{Id: "a1"}, {Id: "a1"}}).Return(nil)
+
+	received, err := s.usecase.ReceiveGoods("t1", []model.GoodsReceipt{
+		{TransactionDetailId: "td1", LocationId: "l1", Qty: 1},
+		{TransactionDetailId: "td1", LocationId: "l2", Qty: 1},
+	})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), received, 2)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *ProcurementUsecaseTestSuite) TestReceiveGoods_ExceedsRemainingQty() {
+	_, err := s.usecase.ReceiveGoods("t1", []model.GoodsReceipt{
+		{TransactionDetailId: "td1", LocationId: "l1", Qty: 1},
+		{TransactionDetailId: "td1", LocationId: "l1", Qty: 2},
+	})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "exceeds remaining qty 2")
+	s.mockAsset.AssertNotCalled(s.T(), "PrepareNewAsset", mock.Anything)
+}
+
+func (s *ProcurementUsecaseTestSuite) TestReceiveGoods_Empty() {
+	_, err := s.usecase.ReceiveGoods("t1", []model.GoodsReceipt{})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	s.mockRepo.AssertNotCalled(s.T(), "Get", mock.Anything)
+	s.mockRepo.AssertNotCalled(s.T(), "ReceiveGoods", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ProcurementUsecaseTestSuite) TestReceiveGoods_ConcurrentReceipt() {
+	s.mockAsset.On("PrepareNewAsset", mock.Anything).Return(model.Asset{Id: "a1"}, nil)
+	s.mockRepo.On("ReceiveGoods", "t1", mock.Anything, mock.Anything).Return(fmt.Errorf("transaction detail td1 %w, received qty exceeds ordered qty", repository.ErrChanged))
+
+	_, err := s.usecase.ReceiveGoods("t1", []model.GoodsReceipt{{TransactionDetailId: "td1", LocationId: "l1", Qty: 2}})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *ProcurementUsecaseTestSuite) TestReceiveGoods_InvalidAssetStoresNothing() {
+	s.mockAsset.On("PrepareNewAsset", mock.Anything).Return(model.Asset{Id: "a1"}, nil).Once()
+	s.mockAsset.On("PrepareNewAsset", mock.Anything).Return(model.Asset{}, errors.New("location with id l9 is not found")).Once()
+
+	_, err := s.usecase.ReceiveGoods("t1", []model.GoodsReceipt{
+		{TransactionDetailId: "td1", LocationId: "l1", Qty: 1},
+		{TransactionDetailId: "td1", LocationId: "l9", Qty: 1},
+	})
+	assert.ErrorContains(s.T(), err, "location with id l9 is not found")
+	s.mockRepo.AssertNotCalled(s.T(), "ReceiveGoods", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcurementUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcurementUsecaseTestSuite))
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/depreciation"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type ReportUsecase interface {
+	DepreciationReport(fiscalYear int) (dto.DepreciationReportDTO, error)
+	DepreciationReportCSV(fiscalYear int) ([]byte, error)
+	DisposalReport(from, to time.Time) (dto.DisposalReportDTO, error)
+	DisposalReportCSV(from, to time.Time) ([]byte, error)
+}
+
+type reportUsecase struct {
+	assetRepo    repository.AssetRepository
+	disposalRepo repository.AssetDisposalRepository
+	ctgrUsecase  AssetCategoriesUseCase
+}
+
+// DepreciationReport produces the yearly fixed asset register with the
+// commercial and the fiscal book value of every asset acquired up to the end
+// of the fiscal year.
+func (r *reportUsecase) DepreciationReport(fiscalYear int) (dto.DepreciationReportDTO, error) {
+	if fiscalYear < 1900 || fiscalYear > 9999 {
+		return dto.DepreciationReportDTO{}, fmt.Errorf("fiscal year %d is not valid", fiscalYear)
+	}
+
+	assets, err := r.assetRepo.List()
+	if err != nil {
+		return dto.DepreciationReportDTO{}, fmt.Errorf("error get list asset : %s", err.Error())
+	}
+
+	yearStart := time.Date(fiscalYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(fiscalYear, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	report := dto.DepreciationReportDTO{FiscalYear: fiscalYear}
+	categories := make(map[string]model.AssetCategories)
+	for _, asset := range assets {
+		if asset.Cost == 0 || asset.CreatedAt.After(yearEnd) {
+			continue
+		}
+
+		category, ok := categories[asset.CategoryId]
+		if !ok {
+			category, err = r.ctgrUsecase.FindAssetCategoriesById(asset.CategoryId)
+			if err != nil {
+				return dto.DepreciationReportDTO{}, fmt.Errorf("error get category : %s", err.Error())
+			}
+			categories[asset.CategoryId] = category
+		}
+
+		units, err := r.assetRepo.AssetDetail(asset.Id)
+		if err != nil {
+			return dto.DepreciationReportDTO{}, fmt.Errorf("error get units of %s : %s", asset.Name, err.Error())
+		}
+
+		row, err := r.depreciationRow(asset, unitsHeldAt(units, yearEnd), category, fiscalYear, yearStart, yearEnd)
+		if err != nil {
+			return dto.DepreciationReportDTO{}, fmt.Errorf("error calculate depreciation of %s : %s", asset.Name, err.Error())
+		}
+
+		report.TotalCost += row.Cost
+		report.TotalCommercialClosing += row.CommercialClosing
+		report.TotalFiscalClosing += row.FiscalClosing
+		report.Rows = append(report.Rows, row)
+	}
+
+	return report, nil
+}
+
+// DepreciationReportCSV is the depreciation report as a CSV sheet, one line
+// per asset with amounts in two decimals.
+func (r *reportUsecase) DepreciationReportCSV(fiscalYear int) ([]byte, error) {
+	report, err := r.DepreciationReport(fiscalYear)
+	if err != nil {
+		return nil, err
+	}
+
+	return depreciationCSV(report)
+}
+
+func depreciationCSV(report dto.DepreciationReportDTO) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{
+		"No", "Asset Id", "Asset Name", "Category", "Fiscal Group", "Acquired At", "Qty", "Cost",
+		"Commercial Method", "Commercial Opening", "Commercial Depreciation", "Commercial Closing",
+		"Fiscal Method", "Fiscal Opening", "Fiscal Depreciation", "Fiscal Closing",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	money := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	for index, row := range report.Rows {
+		record := []string{
+			strconv.Itoa(index + 1), row.AssetId, row.AssetName, row.Category, row.FiscalGroup, row.AcquiredAt.Format("2006-01-02"), strconv.Itoa(row.Qty), money(row.Cost),
+			row.CommercialMethod, money(row.CommercialOpening), money(row.CommercialDepreciation), money(row.CommercialClosing),
+			row.FiscalMethod, money(row.FiscalOpening), money(row.FiscalDepreciation), money(row.FiscalClosing),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// depreciationRow values the held units of an asset, held is the number of
+// units at the end of the fiscal year rather than the current quantity.
+func (r *reportUsecase) depreciationRow(asset model.Asset, held int, category model.AssetCategories, fiscalYear int, yearStart, yearEnd time.Time) (dto.DepreciationReportRowDTO, error) {
+	qty := float64(held)
+	row := dto.DepreciationReportRowDTO{
+		AssetId:     asset.Id,
+		AssetName:   asset.Name,
+		Category:    category.Name,
+		FiscalGroup: category.FiscalGroup,
+		AcquiredAt:  asset.CreatedAt,
+		Qty:         held,
+		Cost:        asset.Cost * qty,
+	}
+
+	// Commercial values, assets without useful life are not depreciated
+	row.CommercialOpening = row.Cost
+	row.CommercialClosing = row.Cost
+	method, _, schedule, err := commercialSchedule(asset, category)
+	if err != nil {
+		return dto.DepreciationReportRowDTO{}, err
+	}
+
+	if schedule != nil {
+		row.CommercialMethod = string(method)
+		row.CommercialOpening = depreciation.BookValueAt(asset.Cost, schedule, yearStart.AddDate(0, 0, -1)) * qty
+		row.CommercialClosing = depreciation.BookValueAt(asset.Cost, schedule, yearEnd) * qty
+		row.CommercialDepreciation = depreciation.Round(row.CommercialOpening - row.CommercialClosing)
+	}
+
+	// Fiscal values follow the category's tax group
+	row.FiscalOpening = row.Cost
+	row.FiscalClosing = row.Cost
+	if category.FiscalGroup == "" {
+		return row, nil
+	}
+
+	fiscalMethod, err := depreciation.ParseMethod(category.FiscalMethod)
+	if err != nil {
+		return dto.DepreciationReportRowDTO{}, err
+	}
+
+	fiscalSchedule, err := depreciation.FiscalSchedule(depreciation.FiscalGroup(category.FiscalGroup), fiscalMethod, asset.Cost, asset.CreatedAt)
+	if err != nil {
+		return dto.DepreciationReportRowDTO{}, err
+	}
+
+	row.FiscalMethod = string(fiscalMethod)
+	for _, entry := range fiscalSchedule {
+		if entry.Year < fiscalYear {
+			row.FiscalOpening = entry.BookValue * qty
+			row.FiscalClosing = row.FiscalOpening
+		}
+
+		if entry.Year == fiscalYear {
+			row.FiscalClosing = entry.BookValue * qty
+		}
+	}
+	row.FiscalDepreciation = depreciation.Round(row.FiscalOpening - row.FiscalClosing)
+
+	return row, nil
+}
+
+// unitsHeldAt counts the units still held at the end of the given day. Units
+// are only added when the asset is created and leave for good when they are
+// retired or disposed, on the date kept in removed_at.
+func unitsHeldAt(units []model.AssetDetail, at time.Time) int {
+	held := 0
+	for _, unit := range units {
+		switch removedAt := unit.RemovedAt.(type) {
+		case nil:
+			held++
+		case time.Time:
+			if dateOf(removedAt).After(at) {
+				held++
+			}
+		case *time.Time:
+			if removedAt == nil || dateOf(*removedAt).After(at) {
+				held++
+			}
+		}
+	}
+
+	return held
+}
+
+// DisposalReport lists the approved disposals dated between from and to with
+// the gain or loss booked on each of them.
+func (r *reportUsecase) DisposalReport(from, to time.Time) (dto.DisposalReportDTO, error) {
+	from, to = dateOf(from), dateOf(to)
+	if to.Before(from) {
+		return dto.DisposalReportDTO{}, fmt.Errorf("report period can't end before it starts")
+	}
+
+	disposals, err := r.disposalRepo.ListApproved(from, to)
+	if err != nil {
+		return dto.DisposalReportDTO{}, fmt.Errorf("error get disposals : %s", err.Error())
+	}
+
+	report := dto.DisposalReportDTO{From: from, To: to, Rows: []dto.DisposalReportRowDTO{}}
+	assets := make(map[string]model.Asset)
+	categories := make(map[string]model.AssetCategories)
+	for _, disposal := range disposals {
+		asset, ok := assets[disposal.AssetId]
+		if !ok {
+			asset, err = r.assetRepo.Detail(disposal.AssetId)
+			if err != nil {
+				return dto.DisposalReportDTO{}, fmt.Errorf("error get asset : %s", err.Error())
+			}
+			assets[disposal.AssetId] = asset
+		}
+
+		category, ok := categories[asset.CategoryId]
+		if !ok {
+			category, err = r.ctgrUsecase.FindAssetCategoriesById(asset.CategoryId)
+			if err != nil {
+				return dto.DisposalReportDTO{}, fmt.Errorf("error get category : %s", err.Error())
+			}
+			categories[asset.CategoryId] = category
+		}
+
+		row := dto.DisposalReportRowDTO{
+			DisposalId:   disposal.Id,
+			AssetId:      asset.Id,
+			AssetName:    asset.Name,
+			Category:     category.Name,
+			Method:       string(disposal.Method),
+			DisposedAt:   disposal.DisposedAt,
+			Qty:          len(disposal.AssetDetailIds),
+			Counterparty: disposal.Counterparty,
+			ApprovedBy:   disposal.DecidedBy,
+			Proceeds:     disposal.Proceeds,
+		}
+		if disposal.BookValue != nil {
+			row.BookValue = *disposal.BookValue
+		}
+		if disposal.GainLoss != nil {
+			row.GainLoss = *disposal.GainLoss
+		}
+
+		report.TotalProceeds += row.Proceeds
+		report.TotalBookValue += row.BookValue
+		report.TotalGainLoss += row.GainLoss
+		report.Rows = append(report.Rows, row)
+	}
+
+	report.TotalProceeds = depreciation.Round(report.TotalProceeds)
+	report.TotalBookValue = depreciation.Round(report.TotalBookValue)
+	report.TotalGainLoss = depreciation.Round(report.TotalGainLoss)
+
+	return report, nil
+}
+
+// DisposalReportCSV is the disposal report as a CSV sheet, one line per
+// disposal with amounts in two decimals.
+func (r *reportUsecase) DisposalReportCSV(from, to time.Time) ([]byte, error) {
+	report, err := r.DisposalReport(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return disposalCSV(report)
+}
+
+func disposalCSV(report dto.DisposalReportDTO) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{
+		"No", "Disposal Id", "Asset Id", "Asset Name", "Category", "Method", "Disposed At", "Qty",
+		"Buyer Or Recipient", "Approved By", "Proceeds", "Book Value", "Gain Or Loss",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	money := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	for index, row := range report.Rows {
+		record := []string{
+			strconv.Itoa(index + 1), row.DisposalId, row.AssetId, row.AssetName, row.Category, row.Method, row.DisposedAt.Format("2006-01-02"), strconv.Itoa(row.Qty),
+			row.Counterparty, row.ApprovedBy, money(row.Proceeds), money(row.BookValue), money(row.GainLoss),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func NewReportUsecase(assetRepo repository.AssetRepository, disposalRepo repository.AssetDisposalRepository, categoryUsecase AssetCategoriesUseCase) ReportUsecase {
+	return &reportUsecase{
+		assetRepo:    assetRepo,
+		disposalRepo: disposalRepo,
+		ctgrUsecase:  categoryUsecase,
+	}
+}
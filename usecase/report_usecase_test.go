@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func (r *mockAssetRepository) List() ([]model.Asset, error) {
+	args := r.Called()
+	return args.Get(0).([]model.Asset), args.Error(1)
+}
+
+type ReportUsecaseTestSuite struct {
+	suite.Suite
+	mockAssetRepo    *mockAssetRepository
+	mockDisposalRepo *mockDisposalRepository
+	mockCategory     *mockCategoryUsecase
+	usecase          usecase.ReportUsecase
+}
+
+func (s *ReportUsecaseTestSuite) SetupTest() {
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockDisposalRepo = new(mockDisposalRepository)
+	s.mockCategory = new(mockCategoryUsecase)
+	s.usecase = usecase.NewReportUsecase(s.mockAssetRepo, s.mockDisposalRepo, s.mockCategory)
+
+	s.mockAssetRepo.On("List").Return([]model.Asset{
+		{Id: "a1", CategoryId: "c1", Name: "Laptop", Qty: 2, Cost: 1200, UsefulLife: 12, CreatedAt: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{Id: "a2", CategoryId: "c2", Name: "Desk", Qty: 1, Cost: 500, CreatedAt: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{Id: "a3", CategoryId: "c1", Name: "Tablet", Qty: 1, Cost: 800, CreatedAt: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{Id: "a4", CategoryId: "c2", Name: "Donated chair", Qty: 1, CreatedAt: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	s.mockAssetRepo.On("AssetDetail", "a1").Return([]model.AssetDetail{{Id: "u1"}, {Id: "u2"}}, nil)
+	s.mockAssetRepo.On("AssetDetail", "a2").Return([]model.AssetDetail{{Id: "u3"}}, nil)
+	s.mockAssetRepo.On("AssetDetail", "a3").Return([]model.AssetDetail{{Id: "u4"}}, nil)
+	s.mockCategory.On("FindAssetCategoriesById", "c1").Return(model.AssetCategories{Id: "c1", Name: "Computers", FiscalGroup: "kelompok-1"}, nil)
+	s.mockCategory.On("FindAssetCategoriesById", "c2").Return(model.AssetCategories{Id: "c2", Name: "Furniture"}, nil)
+}
+
+func (s *ReportUsecaseTestSuite) TestDepreciationReport_YearlyTotals() {
+	report, err := s.usecase.DepreciationReport(2024)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), report.Rows, 2)
+
+	laptop := report.Rows[0]
+	assert.Equal(s.T(), float64(2400), laptop.CommercialOpening)
+	assert.Equal(s.T(), float64(2400), laptop.CommercialDepreciation)
+	assert.Equal(s.T(), float64(0), laptop.CommercialClosing)
+	assert.Equal(s.T(), float64(600), laptop.FiscalDepreciation)
+	assert.Equal(s.T(), float64(1800), laptop.FiscalClosing)
+
+	assert.Equal(s.T(), float64(2900), report.TotalCost)
+	assert.Equal(s.T(), float64(500), report.TotalCommercialClosing)
+	assert.Equal(s.T(), float64(2300), report.TotalFiscalClosing)
+}
+
+func (s *ReportUsecaseTestSuite) TestDepreciationReport_NextYearOpensAtClosing() {
+	report, err := s.usecase.DepreciationReport(2025)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), report.Rows, 3)
+	assert.Equal(s.T(), float64(1800), report.Rows[0].FiscalOpening)
+	assert.Equal(s.T(), float64(1200), report.Rows[0].FiscalClosing)
+}
+
+func (s *ReportUsecaseTestSuite) TestDepreciationReport_UnitDisposedAfterYearEnd() {
+	assetRepo := new(mockAssetRepository)
+	report := usecase.NewReportUsecase(assetRepo, s.mockDisposalRepo, s.mockCategory)
+
+	// one of the two monitors was sold in 2025, the asset holds one unit today
+	assetRepo.On("List").Return([]model.Asset{
+		{Id: "a5", CategoryId: "c2", Name: "Monitor", Qty: 1, Cost: 300, CreatedAt: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	assetRepo.On("AssetDetail", "a5").Return([]model.AssetDetail{
+		{Id: "u5"},
+		{Id: "u6", RemovedAt: time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	register, err := report.DepreciationReport(2024)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, register.Rows[0].Qty)
+	assert.Equal(s.T(), float64(600), register.Rows[0].Cost)
+
+	register, err = report.DepreciationReport(2025)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, register.Rows[0].Qty)
+	assert.Equal(s.T(), float64(300), register.Rows[0].Cost)
+}
+
+func (s *ReportUsecaseTestSuite) TestDepreciationReport_InvalidYear() {
+	_, err := s.usecase.DepreciationReport(99)
+	assert.EqualError(s.T(), err, "fiscal year 99 is not valid")
+	s.mockAssetRepo.AssertNotCalled(s.T(), "List")
+}
+
+func (s *ReportUsecaseTestSuite) TestDepreciationReportCSV() {
+	content, err := s.usecase.DepreciationReportCSV(2024)
+	assert.NoError(s.T(), err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(s.T(), []string{
+		"No,Asset Id,Asset Name,Category,Fiscal Group,Acquired At,Qty,Cost,Commercial Method,Commercial Opening,Commercial Depreciation,Commercial Closing,Fiscal Method,Fiscal Opening,Fiscal Depreciation,Fiscal Closing",
+		"1,a1,Laptop,Computers,kelompok-1,2024-01-15,2,2400.00,straight-line,2400.00,2400.00,0.00,straight-line,2400.00,600.00,1800.00",
+		"2,a2,Desk,Furniture,,2023-06-01,1,500.00,,500.00,0.00,500.00,,500.00,0.00,500.00",
+	}, lines)
+}
+
+func (s *ReportUsecaseTestSuite) TestDisposalReportCSV() {
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	bookValue, gainLoss := 600.0, -100.0
+	s.mockDisposalRepo.On("ListApproved", from, to).Return([]model.AssetDisposal{
+		{Id: "d1", AssetId: "a1", AssetDetailIds: []string{"u1"}, Method: model.DisposalSale, DisposedAt: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC), Proceeds: 500, Counterparty: "PT Maju", DecidedBy: "budi", BookValue: &bookValue, GainLoss: &gainLoss},
+	}, nil)
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", CategoryId: "c1", Name: "Laptop"}, nil)
+
+	content, err := s.usecase.DisposalReportCSV(from, to)
+	assert.NoError(s.T(), err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(s.T(), []string{
+		"No,Disposal Id,Asset Id,Asset Name,Category,Method,Disposed At,Qty,Buyer Or Recipient,Approved By,Proceeds,Book Value,Gain Or Loss",
+		"1,d1,a1,Laptop,Computers,sale,2025-07-01,1,PT Maju,budi,500.00,600.00,-100.00",
+	}, lines)
+}
+
+func TestReportUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportUsecaseTestSuite))
+}
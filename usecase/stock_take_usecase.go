@@ -0,0 +1,350 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type StockTakeUsecase interface {
+	StartStockTake(bodyRequest model.StockTake) (model.StockTake, error)
+	ShowStockTakes() ([]model.StockTake, error)
+	GetStockTake(id string) (model.StockTake, error)
+	SubmitScans(id string, bodyRequest dto.StockTakeScanDTO) (dto.StockTakeScanResultDTO, error)
+	ReconcileStockTake(id string) (dto.StockTakeReconciliationDTO, error)
+	CloseStockTake(bodyRequest model.StockTakeClose) (dto.StockTakeReconciliationDTO, error)
+}
+
+type stockTakeUsecase struct {
+	repo       repository.StockTakeRepository
+	assetRepo  repository.AssetRepository
+	locUsecase AssetLocationUsecase
+}
+
+func (s *stockTakeUsecase) StartStockTake(bodyRequest model.StockTake) (model.StockTake, error) {
+	locationIds := make([]string, 0, len(bodyRequest.LocationIds))
+	selected := make(map[string]bool, len(bodyRequest.LocationIds))
+	for _, locationId := range bodyRequest.LocationIds {
+		if selected[locationId] {
+			continue
+		}
+		selected[locationId] = true
+
+		if _, err := s.locUsecase.SearchLocationById(locationId); err != nil {
+			return model.StockTake{}, newError(ErrNotFound, "location with id %s is not found", locationId)
+		}
+		locationIds = append(locationIds, locationId)
+	}
+
+	if len(locationIds) == 0 {
+		return model.StockTake{}, newError(ErrInvalid, "stock take needs at least one location")
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.LocationIds = locationIds
+	bodyRequest.Status = model.StockTakeOpen
+	bodyRequest.StartedAt = time.Now()
+	bodyRequest.ClosedBy = ""
+	bodyRequest.ClosedAt = nil
+
+	if err := s.repo.Create(bodyRequest); err != nil {
+		return model.StockTake{}, fmt.Errorf("failed to start stock take : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+func (s *stockTakeUsecase) ShowStockTakes() ([]model.StockTake, error) {
+	stockTakes, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("error get stock takes : %s", err.Error())
+	}
+
+	return stockTakes, nil
+}
+
+func (s *stockTakeUsecase) GetStockTake(id string) (model.StockTake, error) {
+	stockTake, err := s.repo.Get(id)
+	if err != nil {
+		return model.StockTake{}, newError(ErrNotFound, "stock take with id %s is not found", id)
+	}
+
+	return stockTake, nil
+}
+
+// SubmitScans records the tags read at one of the session locations. Tags
+// that don't belong to any unit are still recorded and reported back, so a
+// damaged or foreign label isn't silently dropped.
+func (s *stockTakeUsecase) SubmitScans(id string, bodyRequest dto.StockTakeScanDTO) (dto.StockTakeScanResultDTO, error) {
+	stockTake, err := s.openStockTake(id)
+	if err != nil {
+		return dto.StockTakeScanResultDTO{}, err
+	}
+
+	if !slices.Contains(stockTake.LocationIds, bodyRequest.LocationId) {
+		return dto.StockTakeScanResultDTO{}, newError(ErrInvalid, "location %s is not part of stock take %s", bodyRequest.LocationId, id)
+	}
+
+	scannedAt := time.Now()
+	result := dto.StockTakeScanResultDTO{UnknownTags: []string{}}
+	scans := make([]model.StockTakeScan, 0, len(bodyRequest.Tags))
+	seen := make(map[string]bool, len(bodyRequest.Tags))
+	for _, tag := range bodyRequest.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+
+		scan := model.StockTakeScan{
+			Id:          common.GenerateUUID(),
+			StockTakeId: id,
+			Tag:         tag,
+			LocationId:  bodyRequest.LocationId,
+			ScannedBy:   bodyRequest.ScannedBy,
+			ScannedAt:   scannedAt,
+		}
+
+		unit, err := s.assetRepo.GetUnitByTag(tag)
+		if err != nil {
+			result.UnknownTags = append(result.UnknownTags, tag)
+		} else {
+			scan.AssetDetailId = &unit.Id
+		}
+
+		scans = append(scans, scan)
+	}
+
+	if len(scans) == 0 {
+		return dto.StockTakeScanResultDTO{}, newError(ErrInvalid, "no tags to record")
+	}
+
+	err = s.repo.AddScans(id, scans)
+	if errors.Is(err, repository.ErrChanged) {
+		return dto.StockTakeScanResultDTO{}, newError(ErrConflict, "failed to record scans : %s", err.Error())
+	}
+	if err != nil {
+		return dto.StockTakeScanResultDTO{}, fmt.Errorf("failed to record scans : %s", err.Error())
+	}
+
+	result.Recorded = len(scans)
+	return result, nil
+}
+
+// ReconcileStockTake returns the stored reconciliation of a closed session,
+// or a preview from the current data while the session is still open.
+func (s *stockTakeUsecase) ReconcileStockTake(id string) (dto.StockTakeReconciliationDTO, error) {
+	stockTake, err := s.GetStockTake(id)
+	if err != nil {
+		return dto.StockTakeReconciliationDTO{}, err
+	}
+
+	scans, err := s.repo.Scans(id)
+	if err != nil {
+		return dto.StockTakeReconciliationDTO{}, fmt.Errorf("error get scans : %s", err.Error())
+	}
+
+	var results []model.StockTakeResult
+	if stockTake.Status == model.StockTakeClosed {
+		results, err = s.repo.Results(id)
+		if err != nil {
+			return dto.StockTakeReconciliationDTO{}, fmt.Errorf("error get stock take results : %s", err.Error())
+		}
+	} else {
+		results, err = s.reconcile(stockTake, scans)
+		if err != nil {
+			return dto.StockTakeReconciliationDTO{}, err
+		}
+	}
+
+	return reconciliationResponse(stockTake, results, scans), nil
+}
+
+// CloseStockTake freezes the reconciliation. With FixLocations units found
+// elsewhere are moved to where they were scanned, with MarkLost missing units
+// become lost.
+func (s *stockTakeUsecase) CloseStockTake(bodyRequest model.StockTakeClose) (dto.StockTakeReconciliationDTO, error) {
+	stockTake, err := s.openStockTake(bodyRequest.StockTakeId)
+	if err != nil {
+		return dto.StockTakeReconciliationDTO{}, err
+	}
+
+	scans, err := s.repo.Scans(stockTake.Id)
+	if err != nil {
+		return dto.StockTakeReconciliationDTO{}, fmt.Errorf("error get scans : %s", err.Error())
+	}
+
+	results, err := s.reconcile(stockTake, scans)
+	if err != nil {
+		return dto.StockTakeReconciliationDTO{}, err
+	}
+
+	for i, result := range results {
+		results[i].Action = stockTakeAction(bodyRequest, result)
+	}
+
+	err = s.repo.Close(bodyRequest, results)
+	if errors.Is(err, repository.ErrChanged) {
+		return dto.StockTakeReconciliationDTO{}, newError(ErrConflict, "failed to close stock take : %s", err.Error())
+	}
+	if err != nil {
+		return dto.StockTakeReconciliationDTO{}, fmt.Errorf("failed to close stock take : %s", err.Error())
+	}
+
+	stockTake.Status = model.StockTakeClosed
+	stockTake.ClosedBy = bodyRequest.Actor
+	stockTake.ClosedAt = bodyRequest.ClosedAt
+
+	return reconciliationResponse(stockTake, results, scans), nil
+}
+
+func (s *stockTakeUsecase) openStockTake(id string) (model.StockTake, error) {
+	stockTake, err := s.GetStockTake(id)
+	if err != nil {
+		return model.StockTake{}, err
+	}
+
+	if stockTake.Status != model.StockTakeOpen {
+		return model.StockTake{}, newError(ErrConflict, "stock take %s is already %s", id, stockTake.Status)
+	}
+
+	return stockTake, nil
+}
+
+// reconcile compares the units expected at the session locations with the
+// scans. Units in storage or placed are expected on site, units that are
+// assigned, in maintenance or in transit are only reported when scanned.
+func (s *stockTakeUsecase) reconcile(stockTake model.StockTake, scans []model.StockTakeScan) ([]model.StockTakeResult, error) {
+	expected := make(map[string]model.AssetDetail)
+	for _, locationId := range stockTake.LocationIds {
+		units, err := s.assetRepo.LocationUnits(locationId)
+		if err != nil {
+			return nil, fmt.Errorf("error get location units : %s", err.Error())
+		}
+
+		for _, unit := range units {
+			if unit.Status == model.StatusInStorage || unit.Status == model.StatusPlaced {
+				expected[unit.Id] = unit
+			}
+		}
+	}
+
+	results := make([]model.StockTakeResult, 0, len(expected))
+	scanned := make(map[string]bool, len(scans))
+	for _, scan := range scans {
+		if scan.AssetDetailId == nil {
+			continue
+		}
+		scanned[*scan.AssetDetailId] = true
+
+		unit, ok := expected[*scan.AssetDetailId]
+		if !ok {
+			var err error
+			unit, err = s.assetRepo.GetUnit(*scan.AssetDetailId)
+			if err != nil {
+				return nil, fmt.Errorf("error get asset unit : %s", err.Error())
+			}
+		}
+
+		result := stockTakeResult(stockTake.Id, unit, model.StockTakeFound)
+		result.ScannedLocationId = scan.LocationId
+		if unit.LocationId != scan.LocationId {
+			result.Result = model.StockTakeWrongLocation
+		}
+		results = append(results, result)
+	}
+
+	for _, unit := range expected {
+		if !scanned[unit.Id] {
+			results = append(results, stockTakeResult(stockTake.Id, unit, model.StockTakeMissing))
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].AssetId != results[j].AssetId {
+			return results[i].AssetId < results[j].AssetId
+		}
+		if results[i].Tag != results[j].Tag {
+			return results[i].Tag < results[j].Tag
+		}
+		return results[i].AssetDetailId < results[j].AssetDetailId
+	})
+
+	return results, nil
+}
+
+// stockTakeAction decides what closing does to a unit. Only units that are
+// expected on a shelf are relocated, a lost unit that turns up goes back to
+// storage. Units that are assigned, in maintenance or in transit keep their
+// records.
+func stockTakeAction(bodyRequest model.StockTakeClose, result model.StockTakeResult) model.StockTakeAction {
+	switch {
+	case result.Result == model.StockTakeWrongLocation && bodyRequest.FixLocations:
+		switch result.Status {
+		case model.StatusInStorage, model.StatusPlaced, model.StatusLost:
+			return model.StockTakeRelocated
+		}
+	case result.Result == model.StockTakeMissing && bodyRequest.MarkLost:
+		if ValidateStatusTransition(result.Status, model.StatusLost) == nil {
+			return model.StockTakeMarkedLost
+		}
+	}
+
+	return model.StockTakeNoAction
+}
+
+func stockTakeResult(stockTakeId string, unit model.AssetDetail, resultType model.StockTakeResultType) model.StockTakeResult {
+	return model.StockTakeResult{
+		StockTakeId:        stockTakeId,
+		AssetDetailId:      unit.Id,
+		AssetId:            unit.AssetId,
+		Tag:                unit.Tag,
+		Result:             resultType,
+		Status:             unit.Status,
+		ExpectedLocationId: unit.LocationId,
+	}
+}
+
+func reconciliationResponse(stockTake model.StockTake, results []model.StockTakeResult, scans []model.StockTakeScan) dto.StockTakeReconciliationDTO {
+	response := dto.StockTakeReconciliationDTO{
+		StockTake:     stockTake,
+		Found:         []model.StockTakeResult{},
+		Missing:       []model.StockTakeResult{},
+		WrongLocation: []model.StockTakeResult{},
+		UnknownTags:   []string{},
+	}
+
+	for _, result := range results {
+		switch result.Result {
+		case model.StockTakeFound:
+			response.Found = append(response.Found, result)
+		case model.StockTakeMissing:
+			response.Missing = append(response.Missing, result)
+		case model.StockTakeWrongLocation:
+			response.WrongLocation = append(response.WrongLocation, result)
+		}
+	}
+
+	for _, scan := range scans {
+		if scan.AssetDetailId == nil {
+			response.UnknownTags = append(response.UnknownTags, scan.Tag)
+		}
+	}
+
+	return response
+}
+
+func NewStockTakeUsecase(repo repository.StockTakeRepository, assetRepo repository.AssetRepository, locUsecase AssetLocationUsecase) StockTakeUsecase {
+	return &stockTakeUsecase{
+		repo:       repo,
+		assetRepo:  assetRepo,
+		locUsecase: locUsecase,
+	}
+}
@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/usecase"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockStockTakeRepository struct {
+	mock.Mock
+}
+
+func (r *mockStockTakeRepository) Create(bodyRequest model.StockTake) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockStockTakeRepository) List() ([]model.StockTake, error) {
+	args := r.Called()
+	return args.Get(0).([]model.StockTake), args.Error(1)
+}
+
+func (r *mockStockTakeRepository) Get(id string) (model.StockTake, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.StockTake), args.Error(1)
+}
+
+func (r *mockStockTakeRepository) AddScans(stockTakeId string, scans []model.StockTakeScan) error {
+	args := r.Called(stockTakeId, scans)
+	return args.Error(0)
+}
+
+func (r *mockStockTakeRepository) Scans(stockTakeId string) ([]model.StockTakeScan, error) {
+	args := r.Called(stockTakeId)
+	return args.Get(0).([]model.StockTakeScan), args.Error(1)
+}
+
+func (r *mockStockTakeRepository) Close(bodyRequest model.StockTakeClose, results []model.StockTakeResult) error {
+	args := r.Called(bodyRequest, results)
+	return args.Error(0)
+}
+
+func (r *mockStockTakeRepository) Results(stockTakeId string) ([]model.StockTakeResult, error) {
+	args := r.Called(stockTakeId)
+	return args.Get(0).([]model.StockTakeResult), args.Error(1)
+}
+
+func (r *mockAssetRepository) GetUnitByTag(tag string) (model.AssetDetail, error) {
+	args := r.Called(tag)
+	return args.Get(0).(model.AssetDetail), args.Error(1)
+}
+
+func (r *mockAssetRepository) LocationUnits(locationId string) ([]model.AssetDetail, error) {
+	args := r.Called(locationId)
+	return args.Get(0).([]model.AssetDetail), args.Error(1)
+}
+
+// mockLocationUsecase only answers location lookups.
+type mockLocationUsecase struct {
+	mock.Mock
+	usecase.AssetLocationUsecase
+}
+
+func (l *mockLocationUsecase) SearchLocationById(id string) (model.AssetLocation, error) {
+	args := l.Called(id)
+	return args.Get(0).(model.AssetLocation), args.Error(1)
+}
+
+type StockTakeUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockStockTakeRepository
+	mockAssetRepo *mockAssetRepository
+	mockLocation  *mockLocationUsecase
+	usecase       usecase.StockTakeUsecase
+}
+
+func (s *StockTakeUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockStockTakeRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockLocation = new(mockLocationUsecase)
+	s.usecase = usecase.NewStockTakeUsecase(s.mockRepo, s.mockAssetRepo, s.mockLocation)
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+// givenCount sets up a session at l1 with:
+// u1 scanned where it belongs, u2 not scanned, u3 assigned and not scanned,
+// u4 recorded at l9 but scanned at l1 and one tag nobody knows.
+func (s *StockTakeUsecaseTestSuite) givenCount() {
+	s.mockRepo.On("Get", "st1").Return(model.StockTake{Id: "st1", LocationIds: []string{"l1"}, Status: model.StockTakeOpen}, nil)
+	s.mockAssetRepo.On("LocationUnits", "l1").Return([]model.AssetDetail{
+		{Id: "u1", AssetId: "a1", LocationId: "l1", Status: model.StatusPlaced, Tag: "IT-0001"},
+		{Id: "u2", AssetId: "a1", LocationId: "l1", Status: model.StatusInStorage, Tag: "IT-0002"},
+		{Id: "u3", AssetId: "a1", LocationId: "l1", Status: model.StatusAssigned, Tag: "IT-0003"},
+	}, nil)
+	s.mockAssetRepo.On("GetUnit", "u4").Return(model.AssetDetail{Id: "u4", AssetId: "a2", LocationId: "l9", Status: model.StatusPlaced, Tag: "FN-0004"}, nil)
+	s.mockRepo.On("Scans", "st1").Return([]model.StockTakeScan{
+		{Tag: "IT-0001", AssetDetailId: stringPtr("u1"), LocationId: "l1"},
+		{Tag: "FN-0004", AssetDetailId: stringPtr("u4"), LocationId: "l1"},
+		{Tag: "XX-9999", LocationId: "l1"},
+	}, nil)
+}
+
+func (s *StockTakeUsecaseTestSuite) TestReconcileStockTake_Preview() {
+	s.givenCount()
+
+	reconciliation, err := s.usecase.ReconcileStockTake("st1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), reconciliation.Found, 1)
+	assert.Equal(s.T(), "u1", reconciliation.Found[0].AssetDetailId)
+	assert.Len(s.T(), reconciliation.Missing, 1)
+	assert.Equal(s.T(), "u2", reconciliation.Missing[0].AssetDetailId)
+	assert.Len(s.T(), reconciliation.WrongLocation, 1)
+	assert.Equal(s.T(), "l9", reconciliation.WrongLocation[0].ExpectedLocationId)
+	assert.Equal(s.T(), "l1", reconciliation.WrongLocation[0].ScannedLocationId)
+	assert.Equal(s.T(), []string{"XX-9999"}, reconciliation.UnknownTags)
+	s.mockRepo.AssertNotCalled(s.T(), "Results", mock.Anything)
+}
+
+func (s *StockTakeUsecaseTestSuite) TestCloseStockTake_FixAndMarkLost() {
+	s.givenCount()
+	closeRequest := model.StockTakeClose{StockTakeId: "st1", FixLocations: true, MarkLost: true, Actor: "auditor"}
+	s.mockRepo.On("Close", closeRequest, mock.MatchedBy(func(results []model.StockTakeResult) bool {
+		actions := map[string]model.StockTakeAction{}
+		for _, result := range results {
+			actions[result.AssetDetailId] = result.Action
+		}
+		return len(results) == 3 &&
+			actions["u1"] == model.StockTakeNoAction &&
+			actions["u2"] == model.StockTakeMarkedLost &&
+			actions["u4"] == model.StockTakeRelocated
+	})).Return(nil)
+
+	reconciliation, err := s.usecase.CloseStockTake(closeRequest)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.StockTakeClosed, reconciliation.StockTake.Status)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *StockTakeUsecaseTestSuite) TestCloseStockTake_ReportOnly() {
+	s.givenCount()
+	closeRequest := model.StockTakeClose{StockTakeId: "st1"}
+	s.mockRepo.On("Close", closeRequest, mock.MatchedBy(func(results []model.StockTakeResult) bool {
+		for _, result := range results {
+			if result.Action != model.StockTakeNoAction {
+				return false
+			}
+		}
+		return len(results) == 3
+	})).Return(nil)
+
+	_, err := s.usecase.CloseStockTake(closeRequest)
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *StockTakeUsecaseTestSuite) TestCloseStockTake_AlreadyClosed() {
+	s.mockRepo.On("Get", "st1").Return(model.StockTake{Id: "st1", Status: model.StockTakeClosed}, nil)
+
+	_, err := s.usecase.CloseStockTake(model.StockTakeClose{StockTakeId: "st1"})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "already closed")
+}
+
+func (s *StockTakeUsecaseTestSuite) TestSubmitScans_Closed() {
+	s.mockRepo.On("Get", "st1").Return(model.StockTake{Id: "st1", LocationIds: []string{"l1"}, Status: model.StockTakeClosed}, nil)
+
+	_, err := s.usecase.SubmitScans("st1", dto.StockTakeScanDTO{LocationId: "l1", Tags: []string{"IT-0001"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	s.mockRepo.AssertNotCalled(s.T(), "AddScans", mock.Anything, mock.Anything)
+}
+
+func (s *StockTakeUsecaseTestSuite) TestSubmitScans_ClosedMeanwhile() {
+	s.mockRepo.On("Get", "st1").Return(model.StockTake{Id: "st1", LocationIds: []string{"l1"}, Status: model.StockTakeOpen}, nil)
+	s.mockAssetRepo.On("GetUnitByTag", "IT-0001").Return(model.AssetDetail{Id: "u1"}, nil)
+	s.mockRepo.On("AddScans", "st1", mock.Anything).Return(fmt.Errorf("stock take st1 %w, it is already closed", repository.ErrChanged))
+
+	_, err := s.usecase.SubmitScans("st1", dto.StockTakeScanDTO{LocationId: "l1", Tags: []string{"IT-0001"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+}
+
+func (s *StockTakeUsecaseTestSuite) TestSubmitScans_Success() {
+	s.mockRepo.On("Get", "st1").Return(model.StockTake{Id: "st1", LocationIds: []string{"l1"}, Status: model.StockTakeOpen}, nil)
+	s.mockAssetRepo.On("GetUnitByTag", "IT-0001").Return(model.AssetDetail{Id: "u1"}, nil)
+	s.mockAssetRepo.On("GetUnitByTag", "XX-9999").Return(model.AssetDetail{}, errors.New("not found"))
+	s.mockRepo.On("AddScans", "st1", mock.MatchedBy(func(scans []model.StockTakeScan) bool {
+		return len(scans) == 2 && *scans[0].AssetDetailId == "u1" && scans[1].AssetDetailId == nil
+	})).Return(nil)
+
+	result, err := s.usecase.SubmitScans("st1", dto.StockTakeScanDTO{LocationId: "l1", Tags: []string{" IT-0001 ", "XX-9999", "IT-0001", ""}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, result.Recorded)
+	assert.Equal(s.T(), []string{"XX-9999"}, result.UnknownTags)
+}
+
+func (s *StockTakeUsecaseTestSuite) TestSubmitScans_LocationOutsideSession() {
+	s.mockRepo.On("Get", "st1").Return(model.StockTake{Id: "st1", LocationIds: []string{"l1"}, Status: model.StockTakeOpen}, nil)
+
+	_, err := s.usecase.SubmitScans("st1", dto.StockTakeScanDTO{LocationId: "l2", Tags: []string{"IT-0001"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "is not part of")
+	s.mockRepo.AssertNotCalled(s.T(), "AddScans", mock.Anything, mock.Anything)
+}
+
+func (s *StockTakeUsecaseTestSuite) TestStartStockTake_UnknownLocation() {
+	s.mockLocation.On("SearchLocationById", "l1").Return(model.AssetLocation{Id: "l1"}, nil)
+	s.mockLocation.On("SearchLocationById", "l2").Return(model.AssetLocation{}, errors.New("not found"))
+
+	_, err := s.usecase.StartStockTake(model.StockTake{LocationIds: []string{"l1", "l2"}})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.ErrorContains(s.T(), err, "l2")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func TestStockTakeUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(StockTakeUsecaseTestSuite))
+}
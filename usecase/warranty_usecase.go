@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/repository"
+	"asetku-bukan-asetmu/utils/common"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxWarrantyExpiryDays bounds the look-ahead of the expiring warranties list.
+const MaxWarrantyExpiryDays = 3650
+
+type WarrantyUsecase interface {
+	RegisterWarranty(bodyRequest model.Warranty) (model.Warranty, error)
+	UpdateWarranty(id string, bodyRequest model.Warranty) error
+	GetWarranty(id string) (dto.WarrantyDTO, error)
+	ShowAssetWarranties(assetId string) ([]dto.WarrantyDTO, error)
+	ShowVendorWarranties(vendorId string) ([]dto.WarrantyDTO, error)
+	ShowExpiringWarranties(days int) ([]dto.WarrantyDTO, error)
+	FileClaim(warrantyId string, bodyRequest model.WarrantyClaim) (model.WarrantyClaim, error)
+	UpdateClaim(claimId string, bodyRequest dto.WarrantyClaimUpdateDTO) (model.WarrantyClaim, error)
+}
+
+type warrantyUsecase struct {
+	repo            repository.WarrantyRepository
+	assetRepo       repository.AssetRepository
+	maintenanceRepo repository.MaintenanceRepository
+	vendorUsecase   VendorUsecase
+}
+
+func (w *warrantyUsecase) RegisterWarranty(bodyRequest model.Warranty) (model.Warranty, error) {
+	if err := w.validateWarranty(&bodyRequest); err != nil {
+		return model.Warranty{}, err
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.CreatedAt = time.Now()
+
+	if err := w.repo.Create(bodyRequest); err != nil {
+		return model.Warranty{}, fmt.Errorf("failed to register warranty : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+// UpdateWarranty changes the terms of a warranty, the asset it covers stays
+// the same.
+func (w *warrantyUsecase) UpdateWarranty(id string, bodyRequest model.Warranty) error {
+	warranty, err := w.repo.Get(id)
+	if err != nil {
+		return newError(ErrNotFound, "warranty with id %s is not found", id)
+	}
+
+	bodyRequest.Id = warranty.Id
+	bodyRequest.AssetId = warranty.AssetId
+	if err := w.validateWarranty(&bodyRequest); err != nil {
+		return err
+	}
+
+	if err := w.repo.Update(bodyRequest); err != nil {
+		return fmt.Errorf("failed to update warranty : %s", err.Error())
+	}
+
+	return nil
+}
+
+func (w *warrantyUsecase) GetWarranty(id string) (dto.WarrantyDTO, error) {
+	warranty, err := w.repo.Get(id)
+	if err != nil {
+		return dto.WarrantyDTO{}, newError(ErrNotFound, "warranty with id %s is not found", id)
+	}
+
+	responses, err := w.toResponses([]model.Warranty{warranty})
+	if err != nil {
+		return dto.WarrantyDTO{}, err
+	}
+
+	responses[0].Claims, err = w.repo.ListClaims(id)
+	if err != nil {
+		return dto.WarrantyDTO{}, fmt.Errorf("error get warranty claims : %s", err.Error())
+	}
+
+	return responses[0], nil
+}
+
+func (w *warrantyUsecase) ShowAssetWarranties(assetId string) ([]dto.WarrantyDTO, error) {
+	warranties, err := w.repo.ListByAsset(assetId)
+	if err != nil {
+		return nil, fmt.Errorf("error get asset warranties : %s", err.Error())
+	}
+
+	return w.toResponses(warranties)
+}
+
+func (w *warrantyUsecase) ShowVendorWarranties(vendorId string) ([]dto.WarrantyDTO, error) {
+	if _, err := w.vendorUsecase.Get(vendorId); err != nil {
+		return nil, newError(ErrNotFound, "vendor with id %s is not found", vendorId)
+	}
+
+	warranties, err := w.repo.ListByVendor(vendorId)
+	if err != nil {
+		return nil, fmt.Errorf("error get vendor warranties : %s", err.Error())
+	}
+
+	return w.toResponses(warranties)
+}
+
+// ShowExpiringWarranties lists warranties that end today or within the next
+// days, the soonest first.
+func (w *warrantyUsecase) ShowExpiringWarranties(days int) ([]dto.WarrantyDTO, error) {
+	if days < 0 || days > MaxWarrantyExpiryDays {
+		return nil, newError(ErrInvalid, "days must be between 0 and %d", MaxWarrantyExpiryDays)
+	}
+
+	today := dateOf(time.Now())
+	warranties, err := w.repo.ListExpiring(today, today.AddDate(0, 0, days))
+	if err != nil {
+		return nil, fmt.Errorf("error get expiring warranties : %s", err.Error())
+	}
+
+	return w.toResponses(warranties)
+}
+
+// FileClaim opens a claim under a warranty that is in force today. Claims on
+// a unit warranty are always for that unit.
+func (w *warrantyUsecase) FileClaim(warrantyId string, bodyRequest model.WarrantyClaim) (model.WarrantyClaim, error) {
+	warranty, err := w.repo.Get(warrantyId)
+	if err != nil {
+		return model.WarrantyClaim{}, newError(ErrNotFound, "warranty with id %s is not found", warrantyId)
+	}
+
+	now := time.Now()
+	today := dateOf(now)
+	if today.Before(dateOf(warranty.StartDate)) || today.After(dateOf(warranty.EndDate)) {
+		return model.WarrantyClaim{}, newError(ErrConflict, "warranty %s is not in force today", warrantyId)
+	}
+
+	if strings.TrimSpace(bodyRequest.Issue) == "" {
+		return model.WarrantyClaim{}, newError(ErrInvalid, "issue is required")
+	}
+
+	switch {
+	case warranty.AssetDetailId != nil:
+		if bodyRequest.AssetDetailId != nil && *bodyRequest.AssetDetailId != *warranty.AssetDetailId {
+			return model.WarrantyClaim{}, newError(ErrInvalid, "warranty %s only covers asset unit %s", warrantyId, *warranty.AssetDetailId)
+		}
+		bodyRequest.AssetDetailId = warranty.AssetDetailId
+	case bodyRequest.AssetDetailId != nil:
+		if err := w.validateWarrantyUnit(warranty.AssetId, *bodyRequest.AssetDetailId); err != nil {
+			return model.WarrantyClaim{}, err
+		}
+	}
+
+	bodyRequest.Id = common.GenerateUUID()
+	bodyRequest.WarrantyId = warranty.Id
+	bodyRequest.Status = model.ClaimSubmitted
+	bodyRequest.ClaimedAt = now
+	bodyRequest.ResolvedAt = nil
+
+	if err := w.repo.CreateClaim(bodyRequest); err != nil {
+		return model.WarrantyClaim{}, fmt.Errorf("failed to file claim : %s", err.Error())
+	}
+
+	return bodyRequest, nil
+}
+
+// UpdateClaim records the vendor's answer. A claim goes from submitted to
+// approved or rejected and from approved to resolved, keeping the same
+// status only updates the other fields.
+func (w *warrantyUsecase) UpdateClaim(claimId string, bodyRequest dto.WarrantyClaimUpdateDTO) (model.WarrantyClaim, error) {
+	claim, err := w.repo.GetClaim(claimId)
+	if err != nil {
+		return model.WarrantyClaim{}, newError(ErrNotFound, "claim with id %s is not found", claimId)
+	}
+
+	if !bodyRequest.Status.IsValid() {
+		return model.WarrantyClaim{}, newError(ErrInvalid, "unknown claim status %s", bodyRequest.Status)
+	}
+
+	if bodyRequest.Status != claim.Status {
+		if !claim.Status.CanMoveTo(bodyRequest.Status) {
+			return model.WarrantyClaim{}, newError(ErrConflict, "claim can't move from %s to %s", claim.Status, bodyRequest.Status)
+		}
+
+		claim.Status = bodyRequest.Status
+		if claim.Status == model.ClaimRejected || claim.Status == model.ClaimResolved {
+			claim.ResolvedAt = time.Now()
+		}
+	}
+
+	if bodyRequest.ClaimNumber != nil {
+		claim.ClaimNumber = *bodyRequest.ClaimNumber
+	}
+	if bodyRequest.WorkOrderId != nil {
+		claim.WorkOrderId = nil
+		if *bodyRequest.WorkOrderId != "" {
+			if err := w.validateClaimWorkOrder(claim, *bodyRequest.WorkOrderId); err != nil {
+				return model.WarrantyClaim{}, err
+			}
+			claim.WorkOrderId = bodyRequest.WorkOrderId
+		}
+	}
+	if bodyRequest.Resolution != nil {
+		claim.Resolution = *bodyRequest.Resolution
+	}
+
+	if err := w.repo.UpdateClaim(claim); err != nil {
+		return model.WarrantyClaim{}, fmt.Errorf("failed to update claim : %s", err.Error())
+	}
+
+	return claim, nil
+}
+
+// validateClaimWorkOrder checks that the repair tracked by the work order is
+// on the unit the claim is for, or on the covered asset for claims without a
+// unit.
+func (w *warrantyUsecase) validateClaimWorkOrder(claim model.WarrantyClaim, workOrderId string) error {
+	workOrder, err := w.maintenanceRepo.GetWorkOrder(workOrderId)
+	if err != nil {
+		return newError(ErrNotFound, "work order with id %s is not found", workOrderId)
+	}
+
+	if claim.AssetDetailId != nil {
+		if workOrder.AssetDetailId != *claim.AssetDetailId {
+			return newError(ErrInvalid, "work order %s is not for asset unit %s", workOrderId, *claim.AssetDetailId)
+		}
+		return nil
+	}
+
+	warranty, err := w.repo.Get(claim.WarrantyId)
+	if err != nil {
+		return newError(ErrNotFound, "warranty with id %s is not found", claim.WarrantyId)
+	}
+	if workOrder.AssetId != warranty.AssetId {
+		return newError(ErrInvalid, "work order %s is not for asset %s", workOrderId, warranty.AssetId)
+	}
+
+	return nil
+}
+
+func (w *warrantyUsecase) validateWarranty(bodyRequest *model.Warranty) error {
+	if bodyRequest.EndDate.Before(bodyRequest.StartDate) {
+		return newError(ErrInvalid, "warranty can't end before it starts")
+	}
+
+	if _, err := w.assetRepo.Detail(bodyRequest.AssetId); err != nil {
+		return newError(ErrNotFound, "asset with id %s is not found", bodyRequest.AssetId)
+	}
+
+	if _, err := w.vendorUsecase.Get(bodyRequest.VendorId); err != nil {
+		return newError(ErrNotFound, "vendor with id %s is not found", bodyRequest.VendorId)
+	}
+
+	if bodyRequest.AssetDetailId != nil && *bodyRequest.AssetDetailId == "" {
+		bodyRequest.AssetDetailId = nil
+	}
+	if bodyRequest.AssetDetailId != nil {
+		if err := w.validateWarrantyUnit(bodyRequest.AssetId, *bodyRequest.AssetDetailId); err != nil {
+			return err
+		}
+	}
+
+	bodyRequest.StartDate = dateOf(bodyRequest.StartDate)
+	bodyRequest.EndDate = dateOf(bodyRequest.EndDate)
+	return nil
+}
+
+func (w *warrantyUsecase) validateWarrantyUnit(assetId, unitId string) error {
+	unit, err := w.assetRepo.GetUnit(unitId)
+	if err != nil {
+		return newError(ErrNotFound, "asset unit with id %s is not found", unitId)
+	}
+
+	if unit.AssetId != assetId {
+		return newError(ErrInvalid, "asset unit %s doesn't belong to asset %s", unitId, assetId)
+	}
+
+	return nil
+}
+
+func (w *warrantyUsecase) toResponses(warranties []model.Warranty) ([]dto.WarrantyDTO, error) {
+	today := dateOf(time.Now())
+	assetNames := make(map[string]string)
+	vendorNames := make(map[string]string)
+
+	responses := make([]dto.WarrantyDTO, 0, len(warranties))
+	for _, warranty := range warranties {
+		assetName, ok := assetNames[warranty.AssetId]
+		if !ok {
+			asset, err := w.assetRepo.Detail(warranty.AssetId)
+			if err != nil {
+				return nil, fmt.Errorf("error get asset : %s", err.Error())
+			}
+			assetName = asset.Name
+			assetNames[warranty.AssetId] = assetName
+		}
+
+		vendorName, ok := vendorNames[warranty.VendorId]
+		if !ok {
+			vendor, err := w.vendorUsecase.Get(warranty.VendorId)
+			if err != nil {
+				return nil, fmt.Errorf("error get vendor : %s", err.Error())
+			}
+			vendorName = vendor.Name
+			vendorNames[warranty.VendorId] = vendorName
+		}
+
+		daysLeft := int(dateOf(warranty.EndDate).Sub(today).Hours() / 24)
+		responses = append(responses, dto.WarrantyDTO{
+			Warranty:   warranty,
+			AssetName:  assetName,
+			VendorName: vendorName,
+			DaysLeft:   daysLeft,
+			Expired:    daysLeft < 0,
+		})
+	}
+
+	return responses, nil
+}
+
+// dateOf keeps only the calendar date, warranty periods are whole days.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func NewWarrantyUsecase(repo repository.WarrantyRepository, assetRepo repository.AssetRepository, maintenanceRepo repository.MaintenanceRepository, vendorUsecase VendorUsecase) WarrantyUsecase {
+	return &warrantyUsecase{
+		repo:            repo,
+		assetRepo:       assetRepo,
+		maintenanceRepo: maintenanceRepo,
+		vendorUsecase:   vendorUsecase,
+	}
+}
@@ -0,0 +1,241 @@
+package usecase_test
+
+import (
+	"asetku-bukan-asetmu/model"
+	"asetku-bukan-asetmu/model/dto"
+	"asetku-bukan-asetmu/usecase"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockWarrantyRepository struct {
+	mock.Mock
+}
+
+func (r *mockWarrantyRepository) Create(bodyRequest model.Warranty) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockWarrantyRepository) Update(bodyRequest model.Warranty) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockWarrantyRepository) Get(id string) (model.Warranty, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.Warranty), args.Error(1)
+}
+
+func (r *mockWarrantyRepository) ListByAsset(assetId string) ([]model.Warranty, error) {
+	args := r.Called(assetId)
+	return args.Get(0).([]model.Warranty), args.Error(1)
+}
+
+func (r *mockWarrantyRepository) ListByVendor(vendorId string) ([]model.Warranty, error) {
+	args := r.Called(vendorId)
+	return args.Get(0).([]model.Warranty), args.Error(1)
+}
+
+func (r *mockWarrantyRepository) ListExpiring(from, to time.Time) ([]model.Warranty, error) {
+	args := r.Called(from, to)
+	return args.Get(0).([]model.Warranty), args.Error(1)
+}
+
+func (r *mockWarrantyRepository) CreateClaim(bodyRequest model.WarrantyClaim) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockWarrantyRepository) UpdateClaim(bodyRequest model.WarrantyClaim) error {
+	args := r.Called(bodyRequest)
+	return args.Error(0)
+}
+
+func (r *mockWarrantyRepository) GetClaim(id string) (model.WarrantyClaim, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.WarrantyClaim), args.Error(1)
+}
+
+func (r *mockWarrantyRepository) ListClaims(warrantyId string) ([]model.WarrantyClaim, error) {
+	args := r.Called(warrantyId)
+	return args.Get(0).([]model.WarrantyClaim), args.Error(1)
+}
+
+// mockVendorUsecase only answers vendor lookups.
+type mockVendorUsecase struct {
+	mock.Mock
+	usecase.VendorUsecase
+}
+
+func (v *mockVendorUsecase) Get(id string) (model.Vendor, error) {
+	args := v.Called(id)
+	return args.Get(0).(model.Vendor), args.Error(1)
+}
+
+type WarrantyUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mockWarrantyRepository
+	mockAssetRepo *mockAssetRepository
+	mockMaintRepo *mockMaintenanceRepository
+	mockVendor    *mockVendorUsecase
+	usecase       usecase.WarrantyUsecase
+}
+
+func (s *WarrantyUsecaseTestSuite) SetupTest() {
+	s.mockRepo = new(mockWarrantyRepository)
+	s.mockAssetRepo = new(mockAssetRepository)
+	s.mockMaintRepo = new(mockMaintenanceRepository)
+	s.mockVendor = new(mockVendorUsecase)
+	s.usecase = usecase.NewWarrantyUsecase(s.mockRepo, s.mockAssetRepo, s.mockMaintRepo, s.mockVendor)
+
+	s.mockAssetRepo.On("Detail", "a1").Return(model.Asset{Id: "a1", Name: "Laptop"}, nil)
+	s.mockVendor.On("Get", "v1").Return(model.Vendor{Id: "v1", Name: "Acme"}, nil)
+}
+
+func daysFromToday(days int) time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+}
+
+func (s *WarrantyUsecaseTestSuite) TestRegisterWarranty_Success() {
+	s.mockAssetRepo.On("GetUnit", "u1").Return(model.AssetDetail{Id: "u1", AssetId: "a1"}, nil)
+	s.mockRepo.On("Create", mock.MatchedBy(func(warranty model.Warranty) bool {
+		return warranty.Id != "" && *warranty.AssetDetailId == "u1"
+	})).Return(nil)
+
+	unitId := "u1"
+	_, err := s.usecase.RegisterWarranty(model.Warranty{AssetId: "a1", AssetDetailId: &unitId, VendorId: "v1", StartDate: daysFromToday(-10), EndDate: daysFromToday(355)})
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *WarrantyUsecaseTestSuite) TestRegisterWarranty_EndsBeforeStart() {
+	_, err := s.usecase.RegisterWarranty(model.Warranty{AssetId: "a1", VendorId: "v1", StartDate: daysFromToday(0), EndDate: daysFromToday(-1)})
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "can't end before it starts")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *WarrantyUsecaseTestSuite) TestRegisterWarranty_UnitOfOtherAsset() {
+	s.mockAssetRepo.On("GetUnit", "u9").Return(model.AssetDetail{Id: "u9", AssetId: "a2"}, nil)
+
+	unitId := "u9"
+	_, err := s.usecase.RegisterWarranty(model.Warranty{AssetId: "a1", AssetDetailId: &unitId, VendorId: "v1", StartDate: daysFromToday(0), EndDate: daysFromToday(30)})
+	assert.ErrorContains(s.T(), err, "doesn't belong to asset a1")
+}
+
+func (s *WarrantyUsecaseTestSuite) TestRegisterWarranty_NotFound() {
+	s.mockAssetRepo.On("Detail", "a9").Return(model.Asset{}, sql.ErrNoRows)
+	s.mockVendor.On("Get", "v9").Return(model.Vendor{}, sql.ErrNoRows)
+
+	_, err := s.usecase.RegisterWarranty(model.Warranty{AssetId: "a9", VendorId: "v1", StartDate: daysFromToday(0), EndDate: daysFromToday(30)})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+
+	_, err = s.usecase.RegisterWarranty(model.Warranty{AssetId: "a1", VendorId: "v9", StartDate: daysFromToday(0), EndDate: daysFromToday(30)})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.EqualError(s.T(), err, "vendor with id v9 is not found")
+	s.mockRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *WarrantyUsecaseTestSuite) TestShowExpiringWarranties() {
+	s.mockRepo.On("ListExpiring", daysFromToday(0), daysFromToday(30)).Return([]model.Warranty{
+		{Id: "w1", AssetId: "a1", VendorId: "v1", EndDate: daysFromToday(0)},
+		{Id: "w2", AssetId: "a1", VendorId: "v1", EndDate: daysFromToday(12)},
+	}, nil)
+
+	warranties, err := s.usecase.ShowExpiringWarranties(30)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), warranties, 2)
+	assert.Equal(s.T(), 0, warranties[0].DaysLeft)
+	assert.False(s.T(), warranties[0].Expired)
+	assert.Equal(s.T(), 12, warranties[1].DaysLeft)
+	assert.Equal(s.T(), "Laptop", warranties[1].AssetName)
+	assert.Equal(s.T(), "Acme", warranties[1].VendorName)
+	s.mockAssetRepo.AssertNumberOfCalls(s.T(), "Detail", 1)
+}
+
+func (s *WarrantyUsecaseTestSuite) TestShowExpiringWarranties_InvalidDays() {
+	_, err := s.usecase.ShowExpiringWarranties(-1)
+	assert.ErrorIs(s.T(), err, usecase.ErrInvalid)
+	assert.ErrorContains(s.T(), err, "days must be between")
+}
+
+func (s *WarrantyUsecaseTestSuite) TestFileClaim_UnitWarranty() {
+	unitId := "u1"
+	s.mockRepo.On("Get", "w1").Return(model.Warranty{Id: "w1", AssetId: "a1", AssetDetailId: &unitId, StartDate: daysFromToday(-30), EndDate: daysFromToday(30)}, nil)
+	s.mockRepo.On("CreateClaim", mock.MatchedBy(func(claim model.WarrantyClaim) bool {
+		return claim.Status == model.ClaimSubmitted && *claim.AssetDetailId == "u1"
+	})).Return(nil)
+
+	claim, err := s.usecase.FileClaim("w1", model.WarrantyClaim{Issue: "screen flickers"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "w1", claim.WarrantyId)
+
+	otherUnit := "u2"
+	_, err = s.usecase.FileClaim("w1", model.WarrantyClaim{Issue: "battery", AssetDetailId: &otherUnit})
+	assert.ErrorContains(s.T(), err, "only covers asset unit u1")
+}
+
+func (s *WarrantyUsecaseTestSuite) TestFileClaim_Expired() {
+	s.mockRepo.On("Get", "w1").Return(model.Warranty{Id: "w1", AssetId: "a1", StartDate: daysFromToday(-400), EndDate: daysFromToday(-35)}, nil)
+
+	_, err := s.usecase.FileClaim("w1", model.WarrantyClaim{Issue: "screen flickers"})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "not in force")
+	s.mockRepo.AssertNotCalled(s.T(), "CreateClaim", mock.Anything)
+}
+
+func (s *WarrantyUsecaseTestSuite) TestUpdateClaim_Transitions() {
+	s.mockRepo.On("GetClaim", "c1").Return(model.WarrantyClaim{Id: "c1", Status: model.ClaimApproved}, nil)
+	s.mockRepo.On("UpdateClaim", mock.Anything).Return(nil)
+
+	resolution := "replaced panel"
+	claim, err := s.usecase.UpdateClaim("c1", dto.WarrantyClaimUpdateDTO{Status: model.ClaimResolved, Resolution: &resolution})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ClaimResolved, claim.Status)
+	assert.NotNil(s.T(), claim.ResolvedAt)
+
+	_, err = s.usecase.UpdateClaim("c1", dto.WarrantyClaimUpdateDTO{Status: model.ClaimRejected})
+	assert.ErrorIs(s.T(), err, usecase.ErrConflict)
+	assert.ErrorContains(s.T(), err, "can't move from approved to rejected")
+}
+
+func (s *WarrantyUsecaseTestSuite) TestUpdateClaim_WorkOrderOfUnit() {
+	s.mockRepo.On("GetClaim", "c1").Return(model.WarrantyClaim{Id: "c1", WarrantyId: "w1", AssetDetailId: strPtr("u1"), Status: model.ClaimApproved}, nil)
+	s.mockRepo.On("UpdateClaim", mock.Anything).Return(nil)
+	s.mockMaintRepo.On("GetWorkOrder", "wo1").Return(model.WorkOrder{Id: "wo1", AssetId: "a1", AssetDetailId: "u1"}, nil)
+	s.mockMaintRepo.On("GetWorkOrder", "wo2").Return(model.WorkOrder{Id: "wo2", AssetId: "a1", AssetDetailId: "u2"}, nil)
+
+	claim, err := s.usecase.UpdateClaim("c1", dto.WarrantyClaimUpdateDTO{Status: model.ClaimApproved, WorkOrderId: strPtr("wo1")})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "wo1", *claim.WorkOrderId)
+
+	_, err = s.usecase.UpdateClaim("c1", dto.WarrantyClaimUpdateDTO{Status: model.ClaimApproved, WorkOrderId: strPtr("wo2")})
+	assert.EqualError(s.T(), err, "work order wo2 is not for asset unit u1")
+	s.mockRepo.AssertNumberOfCalls(s.T(), "UpdateClaim", 1)
+}
+
+func (s *WarrantyUsecaseTestSuite) TestUpdateClaim_WorkOrderOfOtherAsset() {
+	s.mockRepo.On("GetClaim", "c1").Return(model.WarrantyClaim{Id: "c1", WarrantyId: "w1", Status: model.ClaimSubmitted}, nil)
+	s.mockRepo.On("Get", "w1").Return(model.Warranty{Id: "w1", AssetId: "a1"}, nil)
+	s.mockMaintRepo.On("GetWorkOrder", "wo3").Return(model.WorkOrder{Id: "wo3", AssetId: "a2", AssetDetailId: "u9"}, nil)
+	s.mockMaintRepo.On("GetWorkOrder", "wo9").Return(model.WorkOrder{}, sql.ErrNoRows)
+
+	_, err := s.usecase.UpdateClaim("c1", dto.WarrantyClaimUpdateDTO{Status: model.ClaimSubmitted, WorkOrderId: strPtr("wo3")})
+	assert.EqualError(s.T(), err, "work order wo3 is not for asset a1")
+
+	_, err = s.usecase.UpdateClaim("c1", dto.WarrantyClaimUpdateDTO{Status: model.ClaimSubmitted, WorkOrderId: strPtr("wo9")})
+	assert.ErrorIs(s.T(), err, usecase.ErrNotFound)
+	assert.EqualError(s.T(), err, "work order with id wo9 is not found")
+	s.mockRepo.AssertNotCalled(s.T(), "UpdateClaim", mock.Anything)
+}
+
+func TestWarrantyUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WarrantyUsecaseTestSuite))
+}
@@ -7,15 +7,15 @@ const (
 	EMPLOYEE_UPDATE = "UPDATE employee SET name=$1,gender=$2,phone_number=$3, address=$4 WHERE id=$5"
 	EMPLOYEE_DELETE = "DELETE FROM employee WHERE id=$1"
 
-	ASSET_CATEGORIES_INSERT = "INSERT INTO asset_categories(id,name)VALUES($1, $2)"
-	ASSET_CATEGORIES_LIST   = "SELECT * FROM asset_categories"
-	ASSET_CATEGORIES_GET    = "SELECT * FROM asset_categories where id=$1"
-	ASSET_CATEGORIES_UPDATE = "UPDATE asset_categories SET name=$1 WHERE id=$2"
+	ASSET_CATEGORIES_INSERT = "INSERT INTO asset_categories(id,name,depreciation_method,useful_life,fiscal_group,fiscal_method,tag_prefix,tag_with_year,tag_padding,parent_id)VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"
+	ASSET_CATEGORIES_LIST   = "SELECT id,name,depreciation_method,useful_life,fiscal_group,fiscal_method,tag_prefix,tag_with_year,tag_padding,parent_id FROM asset_categories"
+	ASSET_CATEGORIES_GET    = "SELECT id,name,depreciation_method,useful_life,fiscal_group,fiscal_method,tag_prefix,tag_with_year,tag_padding,parent_id FROM asset_categories where id=$1"
+	ASSET_CATEGORIES_UPDATE = "UPDATE asset_categories SET name=$1,depreciation_method=$2,useful_life=$3,fiscal_group=$4,fiscal_method=$5,tag_prefix=$6,tag_with_year=$7,tag_padding=$8,parent_id=$9 WHERE id=$10"
 	ASSET_CATEGORIES_DELETE = "DELETE FROM asset_categories WHERE id=$1"
 
-	ASSET_LOCATION_INSERT = "INSERT INTO asset_location(id, name) VALUES ($1, $2);"
-	ASSET_LOCATION_LIST   = "SELECT id, name FROM asset_location;"
-	ASSET_LOCATION_SEARCH = "SELECT id, name FROM asset_location WHERE id=$1;"
-	ASSET_LOCATION_UPDATE = "UPDATE asset_location SET name=$2 WHERE id=$1;"
+	ASSET_LOCATION_INSERT = "INSERT INTO asset_location(id, name, parent_id, type, code, custodian_id, storage) VALUES ($1, $2, $3, $4, $5, $6, $7);"
+	ASSET_LOCATION_LIST   = "SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location;"
+	ASSET_LOCATION_SEARCH = "SELECT id, name, parent_id, type, code, custodian_id, storage FROM asset_location WHERE id=$1;"
+	ASSET_LOCATION_UPDATE = "UPDATE asset_location SET name=$2, parent_id=$3, type=$4, code=$5, custodian_id=$6, storage=$7 WHERE id=$1;"
 	ASSET_LOCATION_DELETE = "DELETE FROM asset_location WHERE id=$1;"
 )
@@ -0,0 +1,109 @@
+package depreciation
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type Method string
+
+const (
+	StraightLine    Method = "straight-line"
+	DoubleDeclining Method = "double-declining"
+)
+
+// Entry is one month of a depreciation schedule. Date is the last day of the
+// month the depreciation is booked in.
+type Entry struct {
+	Period       int       `json:"period"`
+	Date         time.Time `json:"date"`
+	Depreciation float64   `json:"depreciation"`
+	Accumulated  float64   `json:"accumulated"`
+	BookValue    float64   `json:"bookValue"`
+}
+
+// ParseMethod validates a method name, an empty name defaults to straight-line.
+func ParseMethod(name string) (Method, error) {
+	switch Method(name) {
+	case "", StraightLine:
+		return StraightLine, nil
+	case DoubleDeclining:
+		return DoubleDeclining, nil
+	}
+
+	return "", fmt.Errorf("unknown depreciation method %q", name)
+}
+
+// Schedule computes a monthly schedule that starts in the month of start and
+// depreciates cost down to salvage over lifeMonths. Double-declining switches
+// to straight-line once that gives the larger charge, so the asset always
+// reaches its salvage value in the last period.
+func Schedule(method Method, cost, salvage float64, lifeMonths int, start time.Time) ([]Entry, error) {
+	if cost < 0 || salvage < 0 {
+		return nil, fmt.Errorf("cost and salvage value can't be negative")
+	}
+
+	if salvage > cost {
+		return nil, fmt.Errorf("salvage value can't be greater than cost")
+	}
+
+	if lifeMonths <= 0 {
+		return nil, fmt.Errorf("useful life must be greater than zero")
+	}
+
+	if method != StraightLine && method != DoubleDeclining {
+		return nil, fmt.Errorf("unknown depreciation method %q", method)
+	}
+
+	firstMonth := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location())
+	straightLineCharge := Round((cost - salvage) / float64(lifeMonths))
+	decliningRate := 2 / float64(lifeMonths)
+
+	schedule := make([]Entry, 0, lifeMonths)
+	bookValue, accumulated := cost, 0.0
+	for period := 1; period <= lifeMonths; period++ {
+		remaining := lifeMonths - period + 1
+
+		var charge float64
+		switch {
+		case remaining == 1:
+			charge = bookValue - salvage
+		case method == StraightLine:
+			charge = straightLineCharge
+		default:
+			charge = math.Max(bookValue*decliningRate, (bookValue-salvage)/float64(remaining))
+		}
+		charge = Round(math.Min(charge, bookValue-salvage))
+
+		bookValue = Round(bookValue - charge)
+		accumulated = Round(accumulated + charge)
+		schedule = append(schedule, Entry{
+			Period:       period,
+			Date:         firstMonth.AddDate(0, period, -1),
+			Depreciation: charge,
+			Accumulated:  accumulated,
+			BookValue:    bookValue,
+		})
+	}
+
+	return schedule, nil
+}
+
+// BookValueAt returns the book value after every period booked on or before at.
+func BookValueAt(cost float64, schedule []Entry, at time.Time) float64 {
+	bookValue := cost
+	for _, entry := range schedule {
+		if entry.Date.After(at) {
+			break
+		}
+		bookValue = entry.BookValue
+	}
+
+	return bookValue
+}
+
+// Round rounds a currency amount to two decimals.
+func Round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,143 @@
+package depreciation_test
+
+import (
+	"asetku-bukan-asetmu/utils/depreciation"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DepreciationTestSuite struct {
+	suite.Suite
+	start time.Time
+}
+
+func (s *DepreciationTestSuite) SetupTest() {
+	s.start = time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *DepreciationTestSuite) TestStraightLine() {
+	schedule, err := depreciation.Schedule(depreciation.StraightLine, 12000, 0, 12, s.start)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), schedule, 12)
+
+	assert.Equal(s.T(), 1000.0, schedule[0].Depreciation)
+	assert.Equal(s.T(), 11000.0, schedule[0].BookValue)
+	assert.Equal(s.T(), time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC), schedule[0].Date)
+	assert.Equal(s.T(), 0.0, schedule[11].BookValue)
+	assert.Equal(s.T(), 12000.0, schedule[11].Accumulated)
+}
+
+func (s *DepreciationTestSuite) TestStraightLine_RemainderInLastPeriod() {
+	schedule, err := depreciation.Schedule(depreciation.StraightLine, 1000, 100, 7, s.start)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 128.57, schedule[0].Depreciation)
+	assert.Equal(s.T(), 128.58, schedule[6].Depreciation)
+	assert.Equal(s.T(), 100.0, schedule[6].BookValue)
+}
+
+func (s *DepreciationTestSuite) TestDoubleDeclining() {
+	schedule, err := depreciation.Schedule(depreciation.DoubleDeclining, 10000, 1000, 10, s.start)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), schedule, 10)
+
+	assert.Equal(s.T(), 2000.0, schedule[0].Depreciation)
+	assert.Equal(s.T(), 8000.0, schedule[0].BookValue)
+	assert.Equal(s.T(), 1600.0, schedule[1].Depreciation)
+	assert.Equal(s.T(), 1000.0, schedule[9].BookValue)
+	assert.Equal(s.T(), 9000.0, schedule[9].Accumulated)
+	for i := 1; i < len(schedule); i++ {
+		assert.LessOrEqual(s.T(), schedule[i].BookValue, schedule[i-1].BookValue)
+	}
+}
+
+func (s *DepreciationTestSuite) TestSchedule_Invalid() {
+	_, err := depreciation.Schedule(depreciation.StraightLine, 100, 200, 12, s.start)
+	assert.Error(s.T(), err)
+
+	_, err = depreciation.Schedule(depreciation.StraightLine, 100, 0, 0, s.start)
+	assert.Error(s.T(), err)
+
+	_, err = depreciation.Schedule(depreciation.Method("sum-of-years"), 100, 0, 12, s.start)
+	assert.Error(s.T(), err)
+}
+
+func (s *DepreciationTestSuite) TestBookValueAt() {
+	schedule, _ := depreciation.Schedule(depreciation.StraightLine, 12000, 0, 12, s.start)
+
+	assert.Equal(s.T(), 12000.0, depreciation.BookValueAt(12000, schedule, s.start))
+	assert.Equal(s.T(), 9000.0, depreciation.BookValueAt(12000, schedule, time.Date(2026, time.April, 10, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(s.T(), 0.0, depreciation.BookValueAt(12000, schedule, time.Date(2027, time.June, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func (s *DepreciationTestSuite) TestParseMethod() {
+	method, err := depreciation.ParseMethod("")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), depreciation.StraightLine, method)
+
+	_, err = depreciation.ParseMethod("linear")
+	assert.Error(s.T(), err)
+}
+
+func TestDepreciationTestSuite(t *testing.T) {
+	suite.Run(t, new(DepreciationTestSuite))
+}
+
+type FiscalDepreciationTestSuite struct {
+	suite.Suite
+	acquired time.Time
+}
+
+func (s *FiscalDepreciationTestSuite) SetupTest() {
+	s.acquired = time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *FiscalDepreciationTestSuite) TestStraightLine_Group1() {
+	schedule, err := depreciation.FiscalSchedule(depreciation.FiscalGroup1, depreciation.StraightLine, 12000000, s.acquired)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), schedule, 5)
+
+	assert.Equal(s.T(), 2026, schedule[0].Year)
+	assert.Equal(s.T(), 3, schedule[0].Months)
+	assert.Equal(s.T(), 750000.0, schedule[0].Depreciation)
+	assert.Equal(s.T(), 3000000.0, schedule[1].Depreciation)
+	assert.Equal(s.T(), 2030, schedule[4].Year)
+	assert.Equal(s.T(), 9, schedule[4].Months)
+	assert.Equal(s.T(), 2250000.0, schedule[4].Depreciation)
+	assert.Equal(s.T(), 0.0, schedule[4].BookValue)
+}
+
+func (s *FiscalDepreciationTestSuite) TestDeclining_Group1() {
+	schedule, err := depreciation.FiscalSchedule(depreciation.FiscalGroup1, depreciation.DoubleDeclining, 12000000, s.acquired)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1500000.0, schedule[0].Depreciation)
+	assert.Equal(s.T(), 5250000.0, schedule[1].Depreciation)
+	assert.Equal(s.T(), 0.0, schedule[len(schedule)-1].BookValue)
+	assert.Equal(s.T(), 12000000.0, schedule[len(schedule)-1].Accumulated)
+}
+
+func (s *FiscalDepreciationTestSuite) TestBuilding_OnlyStraightLine() {
+	_, err := depreciation.FiscalSchedule(depreciation.FiscalPermanentBuilding, depreciation.DoubleDeclining, 100, s.acquired)
+	assert.Error(s.T(), err)
+
+	schedule, err := depreciation.FiscalSchedule(depreciation.FiscalPermanentBuilding, depreciation.StraightLine, 1000000, s.acquired)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 21, len(schedule))
+}
+
+func (s *FiscalDepreciationTestSuite) TestParseFiscalGroup() {
+	group, err := depreciation.ParseFiscalGroup("kelompok-3")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), depreciation.FiscalGroup3, group)
+
+	_, err = depreciation.ParseFiscalGroup("kelompok-5")
+	assert.Error(s.T(), err)
+}
+
+func TestFiscalDepreciationTestSuite(t *testing.T) {
+	suite.Run(t, new(FiscalDepreciationTestSuite))
+}
@@ -0,0 +1,129 @@
+package depreciation
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FiscalGroup is an Indonesian tax asset group (kelompok harta) used for
+// fiscal depreciation under Pasal 11 UU PPh.
+type FiscalGroup string
+
+const (
+	FiscalGroup1               FiscalGroup = "kelompok-1"
+	FiscalGroup2               FiscalGroup = "kelompok-2"
+	FiscalGroup3               FiscalGroup = "kelompok-3"
+	FiscalGroup4               FiscalGroup = "kelompok-4"
+	FiscalPermanentBuilding    FiscalGroup = "bangunan-permanen"
+	FiscalNonPermanentBuilding FiscalGroup = "bangunan-tidak-permanen"
+)
+
+// FiscalRule holds the statutory useful life and yearly rates of a group.
+// Buildings may only use the straight-line method.
+type FiscalRule struct {
+	UsefulLife       int     `json:"usefulLife"`
+	StraightLineRate float64 `json:"straightLineRate"`
+	DecliningRate    float64 `json:"decliningRate"`
+}
+
+var fiscalRules = map[FiscalGroup]FiscalRule{
+	FiscalGroup1:               {UsefulLife: 4, StraightLineRate: 0.25, DecliningRate: 0.50},
+	FiscalGroup2:               {UsefulLife: 8, StraightLineRate: 0.125, DecliningRate: 0.25},
+	FiscalGroup3:               {UsefulLife: 16, StraightLineRate: 0.0625, DecliningRate: 0.125},
+	FiscalGroup4:               {UsefulLife: 20, StraightLineRate: 0.05, DecliningRate: 0.10},
+	FiscalPermanentBuilding:    {UsefulLife: 20, StraightLineRate: 0.05},
+	FiscalNonPermanentBuilding: {UsefulLife: 10, StraightLineRate: 0.10},
+}
+
+// FiscalEntry is one fiscal year of a fiscal depreciation schedule.
+type FiscalEntry struct {
+	Year         int     `json:"year"`
+	Months       int     `json:"months"`
+	Depreciation float64 `json:"depreciation"`
+	Accumulated  float64 `json:"accumulated"`
+	BookValue    float64 `json:"bookValue"`
+}
+
+func ParseFiscalGroup(name string) (FiscalGroup, error) {
+	group := FiscalGroup(name)
+	if _, ok := fiscalRules[group]; !ok {
+		return "", fmt.Errorf("unknown fiscal group %q", name)
+	}
+
+	return group, nil
+}
+
+func FiscalRuleOf(group FiscalGroup) (FiscalRule, error) {
+	rule, ok := fiscalRules[group]
+	if !ok {
+		return FiscalRule{}, fmt.Errorf("unknown fiscal group %q", group)
+	}
+
+	return rule, nil
+}
+
+// FiscalSchedule computes the yearly fiscal schedule of an asset acquired at
+// acquired. Depreciation starts in the month of acquisition, so the first
+// year is pro-rated by month, and whatever book value is left in the last
+// year of the useful life is written off at once.
+func FiscalSchedule(group FiscalGroup, method Method, cost float64, acquired time.Time) ([]FiscalEntry, error) {
+	rule, err := FiscalRuleOf(group)
+	if err != nil {
+		return nil, err
+	}
+
+	if method == "" {
+		method = StraightLine
+	}
+
+	if method == DoubleDeclining && rule.DecliningRate == 0 {
+		return nil, fmt.Errorf("fiscal group %s only allows the %s method", group, StraightLine)
+	}
+
+	if method != StraightLine && method != DoubleDeclining {
+		return nil, fmt.Errorf("unknown depreciation method %q", method)
+	}
+
+	if cost < 0 {
+		return nil, fmt.Errorf("cost can't be negative")
+	}
+
+	lifeMonths := rule.UsefulLife * 12
+	monthsLeft := lifeMonths
+	firstYearMonths := 12 - int(acquired.Month()) + 1
+
+	var schedule []FiscalEntry
+	bookValue, accumulated := cost, 0.0
+	for year := acquired.Year(); monthsLeft > 0; year++ {
+		months := 12
+		if year == acquired.Year() {
+			months = firstYearMonths
+		}
+		months = min(months, monthsLeft)
+		monthsLeft -= months
+
+		var charge float64
+		switch {
+		case monthsLeft == 0:
+			charge = bookValue
+		case method == StraightLine:
+			charge = cost * rule.StraightLineRate * float64(months) / 12
+		default:
+			charge = bookValue * rule.DecliningRate * float64(months) / 12
+		}
+		charge = Round(math.Min(charge, bookValue))
+
+		bookValue = Round(bookValue - charge)
+		accumulated = Round(accumulated + charge)
+		schedule = append(schedule, FiscalEntry{
+			Year:         year,
+			Months:       months,
+			Depreciation: charge,
+			Accumulated:  accumulated,
+			BookValue:    bookValue,
+		})
+	}
+
+	return schedule, nil
+}
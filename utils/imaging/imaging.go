@@ -0,0 +1,107 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels limits the decoded size of an image, a small file can still
+// declare huge dimensions and exhaust memory while decoding.
+const MaxPixels = 40_000_000
+
+// JPEGQuality is used for re-encoded photos and their thumbnails.
+const JPEGQuality = 85
+
+// ThumbnailSizes are the longest sides, in pixels, of generated thumbnails.
+var ThumbnailSizes = []int{128, 256, 512}
+
+// Format is the encoding an image is written with. Only JPEG and PNG are
+// written, everything else is converted to PNG.
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	PNG  Format = "png"
+)
+
+func (f Format) Extension() string {
+	if f == JPEG {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+func (f Format) ContentType() string {
+	if f == JPEG {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// Decode reads an image and turns it upright according to its EXIF
+// orientation. The returned image carries no metadata, so encoding it again
+// drops EXIF, GPS and any other embedded data. Photos keep JPEG, other types
+// are written as PNG and animated GIFs keep their first frame.
+func Decode(content []byte) (image.Image, Format, error) {
+	config, name, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("unsupported image : %s", err.Error())
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, "", fmt.Errorf("image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	var img image.Image
+	if name == "gif" {
+		img, err = gif.Decode(bytes.NewReader(content))
+	} else {
+		img, _, err = image.Decode(bytes.NewReader(content))
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image : %s", err.Error())
+	}
+
+	if name == "jpeg" {
+		return orient(img, Orientation(content)), JPEG, nil
+	}
+	return img, PNG, nil
+}
+
+// Encode writes the image in the given format.
+func Encode(w io.Writer, img image.Image, format Format) error {
+	if format == JPEG {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: JPEGQuality})
+	}
+	return png.Encode(w, img)
+}
+
+// Thumbnail scales the image down so its longest side is size pixels. Smaller
+// images are returned unchanged, they are never scaled up.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*size/width)
+		width = size
+	} else {
+		width = max(1, width*size/height)
+		height = size
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+	return thumbnail
+}
@@ -0,0 +1,160 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// photo is a 40x20 image with a red top-left corner, so rotations can be
+// told apart after decoding.
+func photo() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.RGBA{0, 0, 255, 255})
+		}
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+	return img
+}
+
+// exifSegment builds an APP1 segment holding a GPS pointer and the given
+// orientation, in the given byte order.
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+2*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 2)
+	// GPSInfo pointer, only there to show the metadata is dropped
+	order.PutUint16(tiff[10:], 0x8825)
+	order.PutUint16(tiff[12:], 4)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[22:], exifOrientationTag)
+	order.PutUint16(tiff[24:], tiffShortType)
+	order.PutUint32(tiff[26:], 1)
+	order.PutUint16(tiff[30:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func jpegWithExif(t *testing.T, order binary.ByteOrder, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, photo(), &jpeg.Options{Quality: 100}))
+	content := buf.Bytes()
+	return append(append(append([]byte{}, content[:2]...), exifSegment(order, orientation)...), content[2:]...)
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xC000 && g < 0x4000 && b < 0x4000
+}
+
+func TestOrientation(t *testing.T) {
+	assert.Equal(t, 6, Orientation(jpegWithExif(t, binary.LittleEndian, 6)))
+	assert.Equal(t, 8, Orientation(jpegWithExif(t, binary.BigEndian, 8)))
+	assert.Equal(t, 1, Orientation(jpegWithExif(t, binary.BigEndian, 42)))
+	assert.Equal(t, 1, Orientation([]byte("not a jpeg")))
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, photo(), nil))
+	assert.Equal(t, 1, Orientation(buf.Bytes()))
+}
+
+func TestDecode_RotatesAndStripsExif(t *testing.T) {
+	tests := []struct {
+		orientation   uint16
+		width, height int
+		redX, redY    int
+	}{
+		{1, 40, 20, 0, 0},
+		{2, 40, 20, 39, 0},
+		{3, 40, 20, 39, 19},
+		{4, 40, 20, 0, 19},
+		{5, 20, 40, 0, 0},
+		{6, 20, 40, 19, 0},
+		{7, 20, 40, 19, 39},
+		{8, 20, 40, 0, 39},
+	}
+
+	for _, test := range tests {
+		img, format, err := Decode(jpegWithExif(t, binary.LittleEndian, test.orientation))
+		require.NoError(t, err)
+		assert.Equal(t, JPEG, format)
+		assert.Equal(t, test.width, img.Bounds().Dx(), "orientation %d", test.orientation)
+		assert.Equal(t, test.height, img.Bounds().Dy(), "orientation %d", test.orientation)
+		assert.True(t, isRed(img.At(img.Bounds().Min.X+test.redX, img.Bounds().Min.Y+test.redY)), "orientation %d", test.orientation)
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, img, format))
+		assert.False(t, bytes.Contains(buf.Bytes(), []byte("Exif")))
+		assert.Equal(t, 1, Orientation(buf.Bytes()))
+	}
+}
+
+func TestDecode_ConvertsToPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, gif.Encode(&buf, photo(), nil))
+
+	img, format, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, PNG, format)
+	assert.Equal(t, ".png", format.Extension())
+	assert.Equal(t, 40, img.Bounds().Dx())
+}
+
+func TestDecode_TooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, gif.Encode(&buf, photo(), nil))
+	content := buf.Bytes()
+	// logical screen size declared in the header
+	binary.LittleEndian.PutUint16(content[6:], 65535)
+	binary.LittleEndian.PutUint16(content[8:], 65535)
+
+	_, _, err := Decode(content)
+	assert.Error(t, err)
+}
+
+func TestDecode_NotAnImage(t *testing.T) {
+	_, _, err := Decode([]byte("%PDF-1.4"))
+	assert.Error(t, err)
+}
+
+func TestThumbnail(t *testing.T) {
+	img := Thumbnail(photo(), 10)
+	assert.Equal(t, 10, img.Bounds().Dx())
+	assert.Equal(t, 5, img.Bounds().Dy())
+
+	img = Thumbnail(image.NewRGBA(image.Rect(0, 0, 30, 600)), 128)
+	assert.Equal(t, 6, img.Bounds().Dx())
+	assert.Equal(t, 128, img.Bounds().Dy())
+
+	small := photo()
+	assert.Same(t, small, Thumbnail(small, 128))
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, Thumbnail(photo(), 10), PNG))
+	decoded, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 10, decoded.Bounds().Dx())
+}
@@ -0,0 +1,210 @@
+package label
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Symbology is the kind of code printed on a label.
+type Symbology string
+
+const (
+	QR      Symbology = "qr"
+	Code128 Symbology = "code128"
+)
+
+// Format is the output format of rendered labels.
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+	PDF Format = "pdf"
+)
+
+// pixel sizes used for PNG output
+const (
+	qrModulePx      = 8
+	barModulePx     = 2
+	barHeightModule = 40
+)
+
+// Label is a single sticker: the encoded content and the caption lines
+// printed next to it.
+type Label struct {
+	Content string
+	Caption []string
+}
+
+// Code is an encoded symbol as a grid of dark and light modules, including
+// the quiet zone required around it.
+type Code struct {
+	Symbology Symbology
+	Cols      int
+	Rows      int
+	dark      []bool
+}
+
+func ParseSymbology(name string) (Symbology, error) {
+	switch Symbology(strings.ToLower(name)) {
+	case "", QR:
+		return QR, nil
+	case Code128:
+		return Code128, nil
+	}
+
+	return "", fmt.Errorf("unknown symbology %s", name)
+}
+
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case "", PNG:
+		return PNG, nil
+	case SVG:
+		return SVG, nil
+	case PDF:
+		return PDF, nil
+	}
+
+	return "", fmt.Errorf("unknown label format %s", name)
+}
+
+// ContentType is the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case SVG:
+		return "image/svg+xml"
+	case PDF:
+		return "application/pdf"
+	}
+	return "image/png"
+}
+
+// Encode builds the module grid of content. Code128 codes are a single row
+// of bars; their height is decided when rendering.
+func Encode(symbology Symbology, content string) (Code, error) {
+	var (
+		code  barcode.Barcode
+		quiet int
+		err   error
+	)
+
+	switch symbology {
+	case QR:
+		code, err = qr.Encode(content, qr.M, qr.Auto)
+		quiet = 4
+	case Code128:
+		code, err = code128.Encode(content)
+		quiet = 10
+	default:
+		return Code{}, fmt.Errorf("unknown symbology %s", symbology)
+	}
+	if err != nil {
+		return Code{}, fmt.Errorf("failed to encode %s : %s", content, err.Error())
+	}
+
+	bounds := code.Bounds()
+	result := Code{Symbology: symbology, Cols: bounds.Dx() + 2*quiet, Rows: 1}
+	rowQuiet := 0
+	if symbology == QR {
+		result.Rows = bounds.Dy() + 2*quiet
+		rowQuiet = quiet
+	}
+
+	result.dark = make([]bool, result.Cols*result.Rows)
+	for y := 0; y < result.Rows-2*rowQuiet; y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			r, _, _, _ := code.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			result.dark[(y+rowQuiet)*result.Cols+x+quiet] = r < 0x8000
+		}
+	}
+
+	return result, nil
+}
+
+// Dark reports whether the module at x, y is dark.
+func (c Code) Dark(x, y int) bool {
+	return c.dark[y*c.Cols+x]
+}
+
+// runs calls fn for every horizontal run of dark modules, which keeps the
+// vector outputs small.
+func (c Code) runs(fn func(x, y, length int)) {
+	for y := 0; y < c.Rows; y++ {
+		for x := 0; x < c.Cols; {
+			if !c.Dark(x, y) {
+				x++
+				continue
+			}
+
+			start := x
+			for x < c.Cols && c.Dark(x, y) {
+				x++
+			}
+			fn(start, y, x-start)
+		}
+	}
+}
+
+// height is the height of the symbol in modules, 1D codes are drawn as tall
+// bars.
+func (c Code) height() int {
+	if c.Symbology == Code128 {
+		return barHeightModule
+	}
+	return c.Rows
+}
+
+// WritePNG renders the code as a black and white PNG image.
+func WritePNG(w io.Writer, code Code) error {
+	scale := qrModulePx
+	if code.Symbology == Code128 {
+		scale = barModulePx
+	}
+
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, code.Cols*scale, code.height()*scale), palette)
+	for py := 0; py < img.Rect.Dy(); py++ {
+		y := py / scale
+		if code.Symbology == Code128 {
+			y = 0
+		}
+		for px := 0; px < img.Rect.Dx(); px++ {
+			if code.Dark(px/scale, y) {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// WriteSVG renders the code as an SVG drawing measured in modules.
+func WriteSVG(w io.Writer, code Code) error {
+	height := code.height()
+
+	var path strings.Builder
+	code.runs(func(x, y, length int) {
+		if code.Symbology == Code128 {
+			fmt.Fprintf(&path, "M%d 0h%dv%dh-%dz", x, length, height, length)
+			return
+		}
+		fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", x, y, length, length)
+	})
+
+	_, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">
+<rect width="%d" height="%d" fill="#fff"/>
+<path fill="#000" d="%s"/>
+</svg>
+`, code.Cols, height, code.Cols*4, height*4, code.Cols, height, path.String())
+	return err
+}
@@ -0,0 +1,81 @@
+package label_test
+
+import (
+	"asetku-bukan-asetmu/utils/label"
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LabelTestSuite struct {
+	suite.Suite
+}
+
+func (s *LabelTestSuite) TestEncodeQR() {
+	code, err := label.Encode(label.QR, "IT-LAP-2026-0001")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), code.Cols, code.Rows)
+
+	// Quiet zone stays light, the finder pattern starts right after it
+	assert.False(s.T(), code.Dark(0, 0))
+	assert.True(s.T(), code.Dark(4, 4))
+}
+
+func (s *LabelTestSuite) TestEncodeCode128() {
+	code, err := label.Encode(label.Code128, "IT-LAP-2026-0001")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, code.Rows)
+	assert.False(s.T(), code.Dark(0, 0))
+	assert.True(s.T(), code.Dark(10, 0))
+}
+
+func (s *LabelTestSuite) TestWritePNG() {
+	code, err := label.Encode(label.QR, "IT-LAP-2026-0001")
+	assert.NoError(s.T(), err)
+
+	var buf bytes.Buffer
+	assert.NoError(s.T(), label.WritePNG(&buf, code))
+
+	img, err := png.Decode(&buf)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), code.Cols*8, img.Bounds().Dx())
+}
+
+func (s *LabelTestSuite) TestWriteSVG() {
+	code, err := label.Encode(label.Code128, "FUR-0042")
+	assert.NoError(s.T(), err)
+
+	var buf bytes.Buffer
+	assert.NoError(s.T(), label.WriteSVG(&buf, code))
+	assert.Contains(s.T(), buf.String(), "<svg")
+	assert.Contains(s.T(), buf.String(), `<path fill="#000" d="M10 0h`)
+}
+
+func (s *LabelTestSuite) TestWritePDF() {
+	labels := make([]label.Label, 30)
+	for i := range labels {
+		labels[i] = label.Label{Content: "IT-LAP-2026-0001", Caption: []string{"Laptop (Dell)", "IT-LAP-2026-0001"}}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(s.T(), label.WritePDF(&buf, label.QR, labels))
+
+	doc := buf.String()
+	assert.True(s.T(), strings.HasPrefix(doc, "%PDF-1.4"))
+	assert.Contains(s.T(), doc, "/Count 2")
+	assert.Contains(s.T(), doc, `(Laptop \(Dell\)) Tj`)
+	assert.True(s.T(), strings.HasSuffix(doc, "%%EOF\n"))
+}
+
+func (s *LabelTestSuite) TestWritePDF_Empty() {
+	var buf bytes.Buffer
+	assert.Error(s.T(), label.WritePDF(&buf, label.QR, nil))
+}
+
+func TestLabelTestSuite(t *testing.T) {
+	suite.Run(t, new(LabelTestSuite))
+}
@@ -0,0 +1,158 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sheet layout in points: A4 paper with 3 x 8 labels of 70 x 37 mm, the
+// common office sticker sheet.
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	sheetColumns = 3
+	sheetRows    = 8
+	labelWidth   = pageWidth / sheetColumns
+	labelHeight  = pageHeight / sheetRows
+	labelPadding = 8.0
+	fontSize     = 8.0
+	lineHeight   = 10.0
+)
+
+// WritePDF lays the labels out on as many A4 sheets as needed. Codes are
+// drawn as vector rectangles and captions use the built-in Helvetica font,
+// so the document embeds nothing.
+func WritePDF(w io.Writer, symbology Symbology, labels []Label) error {
+	perPage := sheetColumns * sheetRows
+	var pages []string
+	for start := 0; start < len(labels); start += perPage {
+		end := min(start+perPage, len(labels))
+
+		var content strings.Builder
+		for i, item := range labels[start:end] {
+			x := float64(i%sheetColumns) * labelWidth
+			top := pageHeight - float64(i/sheetColumns)*labelHeight
+			if err := drawLabel(&content, symbology, item, x, top); err != nil {
+				return err
+			}
+		}
+		pages = append(pages, content.String())
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("no label to print")
+	}
+
+	return writeDocument(w, pages)
+}
+
+func drawLabel(content *strings.Builder, symbology Symbology, item Label, x, top float64) error {
+	code, err := Encode(symbology, item.Content)
+	if err != nil {
+		return err
+	}
+
+	var textX, textTop, textWidth float64
+	if symbology == QR {
+		// Square symbol on the left, caption on the right
+		side := labelHeight - 2*labelPadding
+		drawCode(content, code, x+labelPadding, top-labelPadding, side/float64(code.Cols), side/float64(code.Rows))
+		textX = x + labelPadding + side + 4
+		textTop = top - labelPadding
+		textWidth = labelWidth - side - 2*labelPadding - 4
+	} else {
+		// Bars across the label, caption below
+		width := labelWidth - 2*labelPadding
+		height := labelHeight - 2*labelPadding - float64(len(item.Caption))*lineHeight
+		drawCode(content, code, x+labelPadding, top-labelPadding, width/float64(code.Cols), height)
+		textX = x + labelPadding
+		textTop = top - labelPadding - height
+		textWidth = width
+	}
+
+	// Helvetica averages about half the font size per character
+	maxChars := int(textWidth / (fontSize * 0.55))
+	for i, line := range item.Caption {
+		baseline := textTop - float64(i+1)*lineHeight + 2
+		fmt.Fprintf(content, "BT /F1 %s Tf %s %s Td (%s) Tj ET\n", num(fontSize), num(textX), num(baseline), escapeText(line, maxChars))
+	}
+
+	return nil
+}
+
+// drawCode fills the dark modules of code starting at the top-left corner.
+// For 1D codes moduleHeight is the height of the whole bar.
+func drawCode(content *strings.Builder, code Code, left, top, moduleWidth, moduleHeight float64) {
+	content.WriteString("0 g\n")
+	code.runs(func(x, y, length int) {
+		fmt.Fprintf(content, "%s %s %s %s re\n", num(left+float64(x)*moduleWidth), num(top-float64(y+1)*moduleHeight), num(float64(length)*moduleWidth), num(moduleHeight))
+	})
+	content.WriteString("f\n")
+}
+
+func writeDocument(w io.Writer, pages []string) error {
+	var (
+		doc     bytes.Buffer
+		offsets []int
+	)
+	object := func(body string) {
+		offsets = append(offsets, doc.Len())
+		fmt.Fprintf(&doc, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	doc.WriteString("%PDF-1.4\n")
+
+	// 1 catalog, 2 page tree, 3 font, then a page and its content per sheet
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", num(pageWidth), num(pageHeight), 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := doc.Len()
+	fmt.Fprintf(&doc, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&doc, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&doc, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(doc.Bytes())
+	return err
+}
+
+// escapeText makes a caption safe for a PDF string literal. Characters the
+// standard font can't show are replaced and long lines are cut.
+func escapeText(text string, maxChars int) string {
+	var out strings.Builder
+	count := 0
+	for _, r := range text {
+		if count == maxChars {
+			break
+		}
+		count++
+
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteRune('\\')
+			out.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			out.WriteRune('?')
+		default:
+			out.WriteRune(r)
+		}
+	}
+
+	return out.String()
+}
+
+func num(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
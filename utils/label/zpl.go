@@ -0,0 +1,77 @@
+package label
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// DefaultZPLTemplate prints a 3 x 2 inch label at 203 dpi with the asset
+// name, category, location and a Code128 barcode of the tag. Text fields use
+// ^FH so escaped values can carry the ^ and ~ characters.
+const DefaultZPLTemplate = `^XA
+^CI28
+^PW609
+^LL406
+^FO30,25^A0N,34,34^FB550,1,0,L^FH^FD{{zpl .AssetName}}^FS
+^FO30,70^A0N,24,24^FB550,1,0,L^FH^FD{{zpl .Category}}^FS
+^FO30,100^A0N,24,24^FB550,1,0,L^FH^FD{{zpl .Location}}^FS
+^FO30,150^BY2^BCN,140,Y,N,N^FH^FD{{zpl .Barcode}}^FS
+^XZ
+`
+
+// UnitLabel holds the fields a ZPL template can print for one unit.
+type UnitLabel struct {
+	AssetName string
+	Tag       string
+	Category  string
+	Location  string
+	Barcode   string
+}
+
+// ZPLTemplate renders unit labels for Zebra compatible printers.
+type ZPLTemplate struct {
+	tmpl *template.Template
+}
+
+// NewZPLTemplate parses a label template written with text/template. The
+// zpl function escapes a value for a field started with ^FH.
+func NewZPLTemplate(text string) (*ZPLTemplate, error) {
+	tmpl, err := template.New("zpl").Funcs(template.FuncMap{"zpl": EscapeZPL}).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label template : %s", err.Error())
+	}
+
+	return &ZPLTemplate{tmpl: tmpl}, nil
+}
+
+// Render writes one label per unit, one after another, so the whole batch
+// can be sent to the printer in a single job.
+func (z *ZPLTemplate) Render(w io.Writer, labels []UnitLabel) error {
+	for _, item := range labels {
+		if err := z.tmpl.Execute(w, item); err != nil {
+			return fmt.Errorf("failed to render label %s : %s", item.Tag, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// EscapeZPL hex encodes the characters ZPL treats as commands, using the
+// default ^FH escape character _.
+func EscapeZPL(value string) string {
+	var out strings.Builder
+	for _, r := range value {
+		switch r {
+		case '^', '~', '_':
+			fmt.Fprintf(&out, "_%02X", r)
+		case '\r', '\n':
+			out.WriteRune(' ')
+		default:
+			out.WriteRune(r)
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,73 @@
+package label_test
+
+import (
+	"asetku-bukan-asetmu/utils/label"
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// assertGolden compares output with testdata/name, run the tests with
+// -update to accept new output.
+func assertGolden(t *testing.T, name string, output []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, output, 0644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(output))
+}
+
+func TestZPL_DefaultTemplate(t *testing.T) {
+	tmpl, err := label.NewZPLTemplate(label.DefaultZPLTemplate)
+	require.NoError(t, err)
+
+	labels := []label.UnitLabel{
+		{AssetName: "Laptop Dell Latitude 5440", Tag: "IT-LAP-2026-0001", Category: "Laptop", Location: "Gudang IT", Barcode: "IT-LAP-2026-0001"},
+		{AssetName: "Laptop Dell Latitude 5440", Tag: "IT-LAP-2026-0002", Category: "Laptop", Location: "Ruang Rapat", Barcode: "IT-LAP-2026-0002"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Render(&buf, labels))
+	assertGolden(t, "default.zpl.golden", buf.Bytes())
+}
+
+func TestZPL_EscapesFields(t *testing.T) {
+	tmpl, err := label.NewZPLTemplate(label.DefaultZPLTemplate)
+	require.NoError(t, err)
+
+	labels := []label.UnitLabel{
+		{AssetName: "Printer ^XZ~JR", Tag: "FUR_0001", Category: "Kursi\nKantor", Location: "Lantai 2", Barcode: "FUR_0001"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Render(&buf, labels))
+	assertGolden(t, "escaped.zpl.golden", buf.Bytes())
+}
+
+func TestZPL_CustomTemplate(t *testing.T) {
+	tmpl, err := label.NewZPLTemplate("^XA^FO10,10^BQN,2,4^FH^FDQA,{{zpl .Barcode}}^FS^FO10,120^A0N,20,20^FH^FD{{zpl .Tag}}^FS^XZ\n")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Render(&buf, []label.UnitLabel{{Tag: "FUR-0042", Barcode: "http://localhost/api/v1/asset/tag/FUR-0042"}}))
+	assertGolden(t, "custom.zpl.golden", buf.Bytes())
+}
+
+func TestZPL_InvalidTemplate(t *testing.T) {
+	_, err := label.NewZPLTemplate("^XA^FD{{zpl .Tag}^XZ")
+	assert.Error(t, err)
+
+	tmpl, err := label.NewZPLTemplate("^XA^FD{{.Serial}}^XZ")
+	require.NoError(t, err)
+	assert.Error(t, tmpl.Render(&bytes.Buffer{}, []label.UnitLabel{{Tag: "FUR-0042"}}))
+}
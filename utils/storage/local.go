@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// LocalURLPrefix is the API route that serves signed local files.
+const LocalURLPrefix = "/api/v1/file/"
+
+// Local stores files under a directory on the API host. Signed URLs point to
+// the API itself and carry an HMAC of the key and expiry time.
+type Local struct {
+	root       string
+	signingKey []byte
+}
+
+func NewLocal(root, signingKey string) (*Local, error) {
+	if root == "" {
+		return nil, fmt.Errorf("file path is required for local storage")
+	}
+	if signingKey == "" {
+		return nil, fmt.Errorf("signing key is required for local storage")
+	}
+
+	return &Local{root: root, signingKey: []byte(signingKey)}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid storage key %s", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes to a temporary file first so readers never see a partial file.
+func (l *Local) Put(key string, content io.Reader, size int64, contentType string) error {
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), target)
+}
+
+func (l *Local) Get(key string) (Object, error) {
+	target, err := l.path(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	file, err := os.Open(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return Object{}, err
+	}
+
+	return Object{Body: file, Size: info.Size(), ContentType: contentTypeOf(key)}, nil
+}
+
+func (l *Local) Delete(key string) error {
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) SignedURL(key string, expires time.Duration) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid storage key %s", key)
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", l.signature(key, expiresAt))
+
+	return LocalURLPrefix + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// Verify checks a signed URL produced by SignedURL.
+func (l *Local) Verify(key, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signed url")
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(l.signature(key, expires))) {
+		return fmt.Errorf("invalid signed url")
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return fmt.Errorf("signed url has expired")
+	}
+
+	return nil
+}
+
+func (l *Local) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,80 @@
+package storage_test
+
+import (
+	"asetku-bukan-asetmu/utils/storage"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LocalStorageTestSuite struct {
+	suite.Suite
+	root    string
+	storage *storage.Local
+}
+
+func (s *LocalStorageTestSuite) SetupTest() {
+	s.root = s.T().TempDir()
+
+	var err error
+	s.storage, err = storage.NewLocal(s.root, "secret")
+	require.NoError(s.T(), err)
+}
+
+func (s *LocalStorageTestSuite) TestPutGetDelete() {
+	assert.NoError(s.T(), s.storage.Put("assets/a1.png", strings.NewReader("png"), 3, "image/png"))
+
+	stored, err := os.ReadFile(filepath.Join(s.root, "assets", "a1.png"))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "png", string(stored))
+
+	object, err := s.storage.Get("assets/a1.png")
+	require.NoError(s.T(), err)
+	body, _ := io.ReadAll(object.Body)
+	object.Body.Close()
+	assert.Equal(s.T(), "png", string(body))
+	assert.Equal(s.T(), "image/png", object.ContentType)
+
+	assert.NoError(s.T(), s.storage.Delete("assets/a1.png"))
+	assert.NoError(s.T(), s.storage.Delete("assets/a1.png"))
+	_, err = s.storage.Get("assets/a1.png")
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *LocalStorageTestSuite) TestRejectsInvalidKey() {
+	for _, key := range []string{"", "../secret", "/etc/passwd", "assets//a1.png", "assets/./a1.png", `assets\a1.png`} {
+		assert.Error(s.T(), s.storage.Put(key, strings.NewReader("x"), 1, ""), key)
+	}
+}
+
+func (s *LocalStorageTestSuite) TestSignedURL() {
+	link, err := s.storage.SignedURL("assets/a1 photo.png", time.Minute)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), strings.HasPrefix(link, storage.LocalURLPrefix+"assets/a1%20photo.png?"))
+
+	parsed, err := url.Parse(link)
+	require.NoError(s.T(), err)
+	key := strings.TrimPrefix(parsed.Path, storage.LocalURLPrefix)
+	query := parsed.Query()
+
+	assert.NoError(s.T(), s.storage.Verify(key, query.Get("expires"), query.Get("signature")))
+	assert.Error(s.T(), s.storage.Verify("assets/a2.png", query.Get("expires"), query.Get("signature")))
+	assert.Error(s.T(), s.storage.Verify(key, query.Get("expires")+"0", query.Get("signature")))
+
+	expired, err := s.storage.SignedURL("assets/a1.png", -time.Minute)
+	require.NoError(s.T(), err)
+	parsed, _ = url.Parse(expired)
+	assert.ErrorContains(s.T(), s.storage.Verify("assets/a1.png", parsed.Query().Get("expires"), parsed.Query().Get("signature")), "expired")
+}
+
+func TestLocalStorageTestSuite(t *testing.T) {
+	suite.Run(t, new(LocalStorageTestSuite))
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config points to an S3-compatible bucket. Endpoint includes the scheme,
+// for example http://localhost:9000 for a local MinIO.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3 stores files in a bucket using path-style requests, which AWS and MinIO
+// both understand. Signed URLs are presigned GET requests to the bucket.
+type S3 struct {
+	endpoint *url.URL
+	bucket   string
+	signer   SignerV4
+	client   *http.Client
+}
+
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("endpoint, bucket, access key and secret key are required for s3 storage")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %s", cfg.Endpoint)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3{
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		signer:   SignerV4{AccessKey: cfg.AccessKey, SecretKey: cfg.SecretKey, Region: region},
+		client:   &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	if !ValidKey(key) {
+		return nil, fmt.Errorf("invalid storage key %s", key)
+	}
+
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.bucket + "/" + key
+	u.RawPath = canonicalPath(u.Path)
+	return &u, nil
+}
+
+func (s *S3) do(method, key string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	} else {
+		req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	}
+	s.signer.Sign(req, time.Now())
+
+	return s.client.Do(req)
+}
+
+// Put uploads the object in a single request. When size is unknown the
+// content is buffered first because S3 needs the length up front.
+func (s *S3) Put(key string, content io.Reader, size int64, contentType string) error {
+	if size < 0 {
+		buffered, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+		content, size = bytes.NewReader(buffered), int64(len(buffered))
+	}
+	if contentType == "" {
+		contentType = contentTypeOf(key)
+	}
+
+	resp, err := s.do(http.MethodPut, key, content, size, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkResponse(resp)
+}
+
+func (s *S3) Get(key string) (Object, error) {
+	resp, err := s.do(http.MethodGet, key, nil, 0, "")
+	if err != nil {
+		return Object{}, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return Object{}, err
+	}
+
+	return Object{Body: resp.Body, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// Delete succeeds for missing keys too, as S3 itself does.
+func (s *S3) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, key, nil, 0, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return checkResponse(resp)
+}
+
+func (s *S3) SignedURL(key string, expires time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	return s.signer.Presign(http.MethodGet, u, time.Now(), expires).String(), nil
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 responded %s : %s", resp.Status, strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a key has no stored object.
+var ErrNotFound = errors.New("object is not found")
+
+// Storage keeps uploaded files. Keys are slash separated paths such as
+// assets/a1-photo.png, the same key works on every backend.
+type Storage interface {
+	Put(key string, content io.Reader, size int64, contentType string) error
+	Get(key string) (Object, error)
+	Delete(key string) error
+	// SignedURL returns a download link for key that stops working after
+	// expires.
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// URLVerifier is implemented by backends whose signed URLs are served by the
+// API itself instead of by the storage server.
+type URLVerifier interface {
+	Verify(key, expires, signature string) error
+}
+
+// Object is a stored file opened for reading, the caller closes Body.
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// ValidKey rejects empty keys and keys that could step outside the storage
+// root, such as absolute paths or .. segments.
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contentTypeOf guesses the type of a key from its extension.
+func contentTypeOf(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,63 @@
+package tag
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultPadding is the counter width used when a category doesn't set one.
+const DefaultPadding = 4
+
+var prefixPattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+
+// Pattern describes how the unit tags of a category are built, for example
+// prefix IT-LAP with year and padding 4 gives IT-LAP-2026-0001.
+type Pattern struct {
+	Prefix   string
+	WithYear bool
+	Padding  int
+}
+
+// Enabled reports whether units of the category get a tag at all.
+func (p Pattern) Enabled() bool {
+	return p.Prefix != ""
+}
+
+// SequenceYear is the year a counter belongs to. Patterns without a year
+// share a single counter, stored under year 0.
+func (p Pattern) SequenceYear(at time.Time) int {
+	if !p.WithYear {
+		return 0
+	}
+	return at.Year()
+}
+
+// Format renders the tag of the given counter value.
+func (p Pattern) Format(year, counter int) string {
+	padding := p.Padding
+	if padding <= 0 {
+		padding = DefaultPadding
+	}
+
+	if p.WithYear {
+		return fmt.Sprintf("%s-%04d-%0*d", p.Prefix, year, padding, counter)
+	}
+	return fmt.Sprintf("%s-%0*d", p.Prefix, padding, counter)
+}
+
+// NormalizePrefix upper-cases a prefix and checks it only holds letters,
+// digits and single dashes between them.
+func NormalizePrefix(prefix string) (string, error) {
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return "", nil
+	}
+
+	if !prefixPattern.MatchString(prefix) {
+		return "", fmt.Errorf("tag prefix %s may only contain letters, digits and dashes", prefix)
+	}
+
+	return prefix, nil
+}
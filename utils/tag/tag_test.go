@@ -0,0 +1,40 @@
+package tag_test
+
+import (
+	"asetku-bukan-asetmu/utils/tag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	pattern := tag.Pattern{Prefix: "IT-LAP", WithYear: true, Padding: 4}
+	assert.Equal(t, "IT-LAP-2026-0001", pattern.Format(2026, 1))
+	assert.Equal(t, "IT-LAP-2026-12345", pattern.Format(2026, 12345))
+
+	pattern = tag.Pattern{Prefix: "FUR"}
+	assert.Equal(t, "FUR-0042", pattern.Format(0, 42))
+}
+
+func TestSequenceYear(t *testing.T) {
+	at := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2026, tag.Pattern{Prefix: "IT", WithYear: true}.SequenceYear(at))
+	assert.Equal(t, 0, tag.Pattern{Prefix: "IT"}.SequenceYear(at))
+}
+
+func TestNormalizePrefix(t *testing.T) {
+	prefix, err := tag.NormalizePrefix(" it-lap ")
+	assert.NoError(t, err)
+	assert.Equal(t, "IT-LAP", prefix)
+
+	prefix, err = tag.NormalizePrefix("")
+	assert.NoError(t, err)
+	assert.Empty(t, prefix)
+
+	_, err = tag.NormalizePrefix("IT--LAP")
+	assert.Error(t, err)
+
+	_, err = tag.NormalizePrefix("IT LAP")
+	assert.Error(t, err)
+}